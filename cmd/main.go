@@ -1,10 +1,27 @@
 package main
 
 import (
+	"encoding/json"
+	"flag"
 	"fmt"
+	"github.com/jlgore/hartea/internal/audit"
+	"github.com/jlgore/hartea/internal/config"
 	"github.com/jlgore/hartea/internal/har"
+	"github.com/jlgore/hartea/internal/remote"
+	"github.com/jlgore/hartea/internal/report"
+	"github.com/jlgore/hartea/internal/server"
+	"github.com/jlgore/hartea/internal/store"
 	"github.com/jlgore/hartea/internal/tui"
+	"github.com/jlgore/hartea/internal/workspace"
+	"io"
+	"net"
 	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -27,59 +44,1235 @@ func main() {
 		os.Exit(0)
 	}
 
-	if len(os.Args) < 2 {
-		fmt.Println("Hartea " + version)
-		fmt.Println("Advanced terminal-based HAR file analysis tool - Ahoy Matey!")
-		fmt.Println("")
-		fmt.Println("Usage: hartea <har-file1> [har-file2] ...")
-		fmt.Println("       hartea --version")
-		fmt.Println("")
-		fmt.Println("Examples:")
-		fmt.Println("  hartea example.har                    # Analyze single file")
-		fmt.Println("  hartea before.har after.har          # Compare two files")
-		fmt.Println("  hartea *.har                         # Analyze multiple files")
-		fmt.Println("")
-		fmt.Println("Features:")
-		fmt.Println("  • Interactive TUI with multiple view modes")
-		fmt.Println("  • Performance metrics and Core Web Vitals analysis")
-		fmt.Println("  • Multi-file comparison capabilities")
-		fmt.Println("  • Professional report export (JSON/CSV/HTML/PDF)")
-		fmt.Println("  • Chrome DevTools-style waterfall timeline")
-		fmt.Println("  • Advanced filtering and search")
+	if len(os.Args) >= 2 && os.Args[1] == "render" {
+		os.Exit(runRender(os.Args[2:]))
+	}
+
+	if len(os.Args) >= 2 && os.Args[1] == "export" {
+		os.Exit(runExport(os.Args[2:]))
+	}
+
+	if len(os.Args) >= 2 && os.Args[1] == "check" {
+		os.Exit(runCheck(os.Args[2:]))
+	}
+
+	if len(os.Args) >= 2 && os.Args[1] == "runs" {
+		os.Exit(runRuns(os.Args[2:]))
+	}
+
+	if len(os.Args) >= 2 && os.Args[1] == "record" {
+		os.Exit(runRecord(os.Args[2:]))
+	}
+
+	if len(os.Args) >= 2 && os.Args[1] == "sync" {
+		os.Exit(runSync(os.Args[2:]))
+	}
+
+	if len(os.Args) >= 2 && os.Args[1] == "serve" {
+		os.Exit(runServe(os.Args[2:]))
+	}
+
+	flags := flag.NewFlagSet("hartea", flag.ContinueOnError)
+	flags.Usage = printUsage
+	var options loadOptions
+	registerLoadFlags(flags, &options)
+	plain := flags.Bool("plain", false, "use ASCII markers instead of emoji and status icons")
+	noColor := flags.Bool("no-color", false, "show no colors, also set by the NO_COLOR environment variable")
+
+	if err := flags.Parse(reorderArgs(flags, os.Args[1:])); err != nil {
+		os.Exit(2)
+	}
+
+	// Without files the TUI starts in its file picker
+	harFiles, loaded, tuiOptions, err := loadSession(flags.Args(), os.Stdout, options)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	// The recent files are a convenience; failing to save them is not worth
+	// stopping for
+	config.AddRecent(loaded...)
+	tuiOptions.Plain, tuiOptions.NoColor = *plain, colorDisabled(*noColor)
+	if err := applyConfig(&tuiOptions); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	// Initialize and run TUI
+	model := tui.NewModel(harFiles, tuiOptions)
+	program := tea.NewProgram(model, tea.WithAltScreen())
+
+	if _, err := program.Run(); err != nil {
+		fmt.Printf("Error running program: %v\n", err)
 		os.Exit(1)
 	}
+}
+
+// applyConfig sets the TUI's key bindings, theme and time zone from the
+// config file, and turns on plain output when the config asks for it.
+func applyConfig(options *tui.Options) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	keys := tui.DefaultKeyMap()
+	if err := keys.Rebind(cfg.Keys); err != nil {
+		return fmt.Errorf("invalid key bindings in %s: %w", cfg.Path, err)
+	}
+	if _, ok := tui.ThemeByName(cfg.Theme); cfg.Theme != "" && !ok {
+		names := make([]string, len(tui.Themes))
+		for i, t := range tui.Themes {
+			names[i] = t.Name
+		}
+		return fmt.Errorf("unknown theme %q in %s; use one of %s", cfg.Theme, cfg.Path, strings.Join(names, ", "))
+	}
+	switch strings.ToLower(cfg.TimeZone) {
+	case "", "local":
+	case "utc":
+		options.UTC = true
+	default:
+		return fmt.Errorf("unknown time zone %q in %s; use local or utc", cfg.TimeZone, cfg.Path)
+	}
+	options.Keys, options.Theme = &keys, cfg.Theme
+	options.Plain = options.Plain || cfg.Plain
+	return nil
+}
+
+// colorDisabled reports whether output must not be colored, from the
+// --no-color flag or the NO_COLOR convention (https://no-color.org).
+func colorDisabled(flag bool) bool {
+	return flag || os.Getenv("NO_COLOR") != ""
+}
 
-	// Parse HAR files
+// sizeUnits picks binary or decimal size units from the --size-units flag or
+// the config file.
+func sizeUnits(flag string) (har.SizeUnits, error) {
+	units := flag
+	if units == "" {
+		cfg, err := config.Load()
+		if err != nil {
+			return "", err
+		}
+		units = cfg.SizeUnits
+	}
+	return har.ParseSizeUnits(units)
+}
+
+// plainOutput reports whether reports should use ASCII markers, from the
+// --plain flag or the config file.
+func plainOutput(flag bool) (bool, error) {
+	if flag {
+		return true, nil
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return false, err
+	}
+	return cfg.Plain, nil
+}
+
+func printUsage() {
+	fmt.Println("Hartea " + version)
+	fmt.Println("Advanced terminal-based HAR file analysis tool - Ahoy Matey!")
+	fmt.Println("")
+	fmt.Println("Usage: hartea [--dedupe] [--workspace name] [--lighthouse report.json] [--plain] [--no-color] [--size-units binary|decimal] [har-file1] [har-file2] ...")
+	fmt.Println("       hartea render <har-file> [har-file2] --view <view> [--width N] [--out file.txt|file.svg]")
+	fmt.Println("       hartea export <har-file> [har-file2] --format <format> [--out file]")
+	fmt.Println("       hartea check <har-file> [har-file2] [--workspace name] [--vendor-budget spec] [--slo spec] [--fail-on-insight severity] [--csp policy]")
+	fmt.Println("       hartea runs --set label=glob --set label2=glob [--json]")
+	fmt.Println("       hartea record <har-file> [har-file2] [--workspace name] [--label name]")
+	fmt.Println("       hartea sync pull|push [--remote s3://bucket/prefix|git-url]")
+	fmt.Println("       hartea serve [har-file] [har-file2] [--port 8787] [--host localhost]")
+	fmt.Println("       hartea --version")
+	fmt.Println("")
+	fmt.Println("Files are compared against the first one; pass --baseline path|N to pick another.")
+	fmt.Println("")
+	fmt.Println("Examples:")
+	fmt.Println("  hartea                                # Pick a file, or a recent one")
+	fmt.Println("  hartea example.har                    # Analyze single file")
+	fmt.Println("  hartea before.har after.har          # Compare two files")
+	fmt.Println("  hartea *.har                         # Analyze multiple files")
+	fmt.Println("  hartea --workspace checkout-flow new.har  # Compare against the workspace baseline")
+	fmt.Println("  hartea run.har --lighthouse run.lighthouse.json  # Add LCP/CLS/TBT lab metrics")
+	fmt.Println("  hartea run.har --tag-rule \"auth when url contains /oauth/\"  # Tag matching requests")
+	fmt.Println("  hartea session.har --segment-gap 5s  # Split a long capture at idle gaps")
+	fmt.Println("  hartea render a.har --view timeline --width 160 --out timeline.svg")
+	fmt.Println("  hartea export a.har --format prometheus --out metrics.prom")
+	fmt.Println("  hartea serve before.har after.har --port 8787")
+	fmt.Println("  hartea check run.har --vendor-budget \"doubleclick.net requests=5 bytes=100KB\"  # Fail CI over budget")
+	fmt.Println("  hartea check run.har --slo \"api latency=300ms target=95% errors=1%\"  # Fail CI when an SLO is missed")
+	fmt.Println("  hartea check before.har after.har --fail-on-insight critical  # Fail CI on critical regressions")
+	fmt.Println("  hartea check run.har --csp \"default-src 'self'; img-src *\"  # What a CSP would block")
+	fmt.Println("  hartea runs --set before='before-*.har' --set after='after-*.har'  # Compare repeated runs statistically")
+	fmt.Println("  hartea record nightly.har --label checkout  # Add to the metrics history for trends")
+	fmt.Println("  hartea sync pull --remote git@github.com:team/perf-baselines.git")
+	fmt.Println("")
+	fmt.Println("Features:")
+	fmt.Println("  • Interactive TUI with multiple view modes")
+	fmt.Println("  • Performance metrics and Core Web Vitals analysis")
+	fmt.Println("  • Multi-file comparison capabilities")
+	fmt.Println("  • Professional report export (JSON/CSV/HTML/PDF)")
+	fmt.Println("  • Chrome DevTools-style waterfall timeline")
+	fmt.Println("  • Advanced filtering and search")
+}
+
+type loadOptions struct {
+	dedupe        bool
+	workspace     string
+	baseline      string
+	lighthouse    stringList
+	tagRules      stringList
+	secretRules   stringList
+	blocklists    stringList
+	firstParty    string
+	exclude       stringList
+	env           string
+	vendorBudgets stringList
+	slos          stringList
+	noise         stringList
+	labels        stringList
+	segmentGap    time.Duration
+	sizeUnits     string
+}
+
+// registerLoadFlags declares the flags of every command that loads HAR files
+// through loadSession. A command that gives --label a meaning of its own,
+// like record, declares it first and keeps it.
+func registerLoadFlags(flags *flag.FlagSet, o *loadOptions) {
+	flags.BoolVar(&o.dedupe, "dedupe", false, "skip files whose content is identical to an earlier file")
+	flags.StringVar(&o.workspace, "workspace", "", "load labels, baseline, saved filters and budgets from a named workspace")
+	flags.StringVar(&o.baseline, "baseline", "", "compare against this file, given as a path or 1-based position, instead of the first")
+	if flags.Lookup("label") == nil {
+		flags.Var(&o.labels, "label", "name a file in headers, comparisons and reports, as file.har=label or in file order (repeatable)")
+	}
+	registerAnalysisFlags(flags, o)
+}
+
+// registerAnalysisFlags declares the load flags that shape how each file is
+// analyzed, leaving out those that choose, order or name the files.
+func registerAnalysisFlags(flags *flag.FlagSet, o *loadOptions) {
+	flags.Var(&o.lighthouse, "lighthouse", "merge lab metrics from a Lighthouse JSON report (repeatable; pairs with HAR files in order, or use file.har=report.json)")
+	flags.Var(&o.tagRules, "tag-rule", "tag matching requests, e.g. \"auth when url contains /oauth/\" (repeatable)")
+	flags.Var(&o.secretRules, "secret-rule", "add a secret detection pattern as name=regex (repeatable)")
+	flags.Var(&o.blocklists, "blocklist", "categorize third-party domains from an EasyList, hosts or domain list as category=path (repeatable)")
+	flags.StringVar(&o.firstParty, "first-party", "", "treat this domain and its subdomains as first party instead of detecting the page's site")
+	flags.StringVar(&o.env, "env", "", "environment of the captures: production, staging or local (detected from the page host by default)")
+	flags.Var(&o.exclude, "exclude", "leave a host (and its subdomains), exact URL, scheme:// or @extensions out of the analysis (repeatable)")
+	flags.Var(&o.noise, "noise", "count changes of a metric below this as noise, e.g. \"ttfb=20ms,5%\" (repeatable)")
+	flags.Var(&o.vendorBudgets, "vendor-budget", "limit a third-party vendor, e.g. \"analytics=google-analytics.com,googletagmanager.com requests=10 bytes=150KB\" (repeatable)")
+	flags.Var(&o.slos, "slo", "check requests against a service level objective, e.g. \"api latency=300ms target=95% errors=1% apdex=0.9\" (repeatable)")
+	flags.DurationVar(&o.segmentGap, "segment-gap", har.DefaultSegmentGap, "idle time that splits a capture into segments")
+	flags.StringVar(&o.sizeUnits, "size-units", "", "write sizes in binary (KiB, MiB) or decimal (kB, MB) units; defaults to the config file's, or binary")
+}
+
+// stringList is a repeatable string flag.
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// loadSession resolves the optional workspace, loads the HAR files with the
+// workspace baseline first, and builds the matching TUI options. It also
+// returns the paths of the files that were loaded, in order. Without paths
+// it loads nothing and only sets up the session for files opened later.
+func loadSession(paths []string, log io.Writer, options loadOptions) ([]*har.HAR, []string, tui.Options, error) {
+	// Positional labels and Lighthouse reports follow the files as given,
+	// before a baseline is moved to the front
+	var err error
+	if options.labels, err = pinToPaths("label", options.labels, paths); err != nil {
+		return nil, nil, tui.Options{}, err
+	}
+	if options.lighthouse, err = pinToPaths("lighthouse", options.lighthouse, paths); err != nil {
+		return nil, nil, tui.Options{}, err
+	}
+
+	var ws *workspace.Workspace
+	if options.workspace != "" {
+		ws, err = workspace.Load(options.workspace)
+		if err != nil {
+			return nil, nil, tui.Options{}, err
+		}
+		if _, err := os.Stat(ws.Path); os.IsNotExist(err) {
+			if err := ws.Save(); err != nil {
+				return nil, nil, tui.Options{}, err
+			}
+			fmt.Fprintf(log, "Created workspace %s at %s\n", ws.Name, ws.Path)
+		}
+		paths = withBaselineFirst(ws, paths)
+		if len(paths) == 0 {
+			return nil, nil, tui.Options{}, fmt.Errorf("Workspace %s has no baseline; pass HAR files or set \"baseline\" in %s", ws.Name, ws.Path)
+		}
+	}
+	if options.baseline != "" {
+		if paths, err = withChosenBaseline(paths, options.baseline); err != nil {
+			return nil, nil, tui.Options{}, err
+		}
+	}
+
+	// Blocklists and secret rules are registries, so each session starts
+	// from the built-in ones rather than the last session's
+	har.ResetBlocklist()
+	audit.ResetSecretPatterns()
+
+	// Invalid specs are reported before the slow work of loading files
+	vendorBudgets, err := parseVendorBudgets(ws, options.vendorBudgets)
+	if err != nil {
+		return nil, nil, tui.Options{}, err
+	}
+	slos, err := parseSLOs(ws, options.slos)
+	if err != nil {
+		return nil, nil, tui.Options{}, err
+	}
+
+	if err := loadBlocklists(ws, options.blocklists, log); err != nil {
+		return nil, nil, tui.Options{}, err
+	}
+
+	var settings har.Settings
+	settings.FirstParty = options.firstParty
+	if settings.FirstParty == "" && ws != nil {
+		settings.FirstParty = ws.FirstParty
+	}
+
+	env := options.env
+	if env == "" && ws != nil {
+		env = ws.Environment
+	}
+	if settings.Environment, err = har.ParseEnvironment(env); err != nil {
+		return nil, nil, tui.Options{}, err
+	}
+	if settings.SizeUnits, err = sizeUnits(options.sizeUnits); err != nil {
+		return nil, nil, tui.Options{}, err
+	}
+	if settings.Noise, err = noiseThresholds(ws, options.noise); err != nil {
+		return nil, nil, tui.Options{}, err
+	}
+
+	var harFiles []*har.HAR
+	var loaded []string
+	if len(paths) > 0 {
+		var err error
+		if harFiles, loaded, err = loadHARFiles(paths, log, options); err != nil {
+			return nil, nil, tui.Options{}, err
+		}
+	}
+
+	if err := mergeLighthouseReports(harFiles, loaded, options.lighthouse, log); err != nil {
+		return nil, nil, tui.Options{}, err
+	}
+
+	exclusions := []string(options.exclude)
+	if ws != nil {
+		exclusions = append(append([]string{}, ws.Exclude...), exclusions...)
+	}
+	for i, harFile := range harFiles {
+		if excluded := har.ExcludeEntries(harFile, exclusions); excluded > 0 {
+			fmt.Fprintf(log, "Excluded %d entries from %s\n", excluded, loaded[i])
+		}
+	}
+
+	for i, harFile := range harFiles {
+		har.TagInsecureEntries(harFile)
+		if extensions := har.TagExtensionEntries(harFile); extensions > 0 {
+			fmt.Fprintf(log, "Detected %d browser-extension requests in %s (exclude with --exclude %s or E in the TUI)\n", extensions, loaded[i], har.ExcludeExtensions)
+		}
+	}
+
+	rules := []string(options.tagRules)
+	if ws != nil {
+		rules = append(append([]string{}, ws.Tags...), rules...)
+	}
+	if err := applyTagRules(harFiles, rules); err != nil {
+		return nil, nil, tui.Options{}, err
+	}
+
+	if err := registerSecretRules(ws, options.secretRules); err != nil {
+		return nil, nil, tui.Options{}, err
+	}
+
+	fileNames, err := fileLabels(ws, loaded, options.labels)
+	if err != nil {
+		return nil, nil, tui.Options{}, err
+	}
+	tuiOptions := tui.Options{FileNames: fileNames, Settings: settings}
+	if ws != nil {
+		tuiOptions.WorkspaceName = ws.Name
+		tuiOptions.SavedFilters = ws.Filters
+		tuiOptions.Budgets = ws.Budgets
+		tuiOptions.OnExclude = ws.AddExclusion
+	}
+	tuiOptions.VendorBudgets = vendorBudgets
+	tuiOptions.SLOs = slos
+	tuiOptions.SegmentGap = options.segmentGap
+	tuiOptions.OpenFile = func(path string) (*har.HAR, error) {
+		return openSessionFile(path, exclusions, rules)
+	}
+
+	return harFiles, loaded, tuiOptions, nil
+}
+
+// openSessionFile loads a file opened from the TUI like the files on the
+// command line: validated, with the session's exclusions and tag rules.
+func openSessionFile(path string, exclusions, rules []string) (*har.HAR, error) {
+	harFiles, _, err := loadHARFiles([]string{path}, io.Discard, loadOptions{})
+	if err != nil {
+		return nil, err
+	}
+	har.ExcludeEntries(harFiles[0], exclusions)
+	har.TagInsecureEntries(harFiles[0])
+	har.TagExtensionEntries(harFiles[0])
+	if err := applyTagRules(harFiles, rules); err != nil {
+		return nil, err
+	}
+	return harFiles[0], nil
+}
+
+// applyTagRules tags the entries of every file with the workspace and --tag-rule rules.
+func applyTagRules(harFiles []*har.HAR, rules []string) error {
+	parsed := make([]har.TagRule, 0, len(rules))
+	for _, rule := range rules {
+		tagRule, err := har.ParseTagRule(rule)
+		if err != nil {
+			return err
+		}
+		parsed = append(parsed, tagRule)
+	}
+	if len(parsed) == 0 {
+		return nil
+	}
+
+	for _, harFile := range harFiles {
+		har.ApplyTagRules(harFile, parsed)
+	}
+	return nil
+}
+
+// parseVendorBudgets parses the workspace and --vendor-budget vendor budgets.
+func parseVendorBudgets(ws *workspace.Workspace, flagBudgets []string) ([]har.VendorBudget, error) {
+	specs := flagBudgets
+	if ws != nil {
+		specs = append(append([]string{}, ws.VendorBudgets...), specs...)
+	}
+
+	budgets := make([]har.VendorBudget, 0, len(specs))
+	for _, spec := range specs {
+		budget, err := har.ParseVendorBudget(spec)
+		if err != nil {
+			return nil, err
+		}
+		budgets = append(budgets, budget)
+	}
+	return budgets, nil
+}
+
+// parseSLOs parses the workspace and --slo service level objectives.
+func parseSLOs(ws *workspace.Workspace, flagSLOs []string) ([]har.SLO, error) {
+	specs := flagSLOs
+	if ws != nil {
+		specs = append(append([]string{}, ws.SLOs...), specs...)
+	}
+
+	slos := make([]har.SLO, 0, len(specs))
+	for _, spec := range specs {
+		slo, err := har.ParseSLO(spec)
+		if err != nil {
+			return nil, err
+		}
+		slos = append(slos, slo)
+	}
+	return slos, nil
+}
+
+// fileLabels names each loaded file for the header, comparisons and reports:
+// its --label, given as file.har=label or in file order, else its workspace
+// label, else its base name, or its path when base names repeat.
+func fileLabels(ws *workspace.Workspace, loaded []string, flagLabels []string) ([]string, error) {
+	bases := make(map[string]int)
+	for _, path := range loaded {
+		bases[filepath.Base(path)]++
+	}
+	names := make([]string, len(loaded))
+	for i, path := range loaded {
+		names[i] = filepath.Base(path)
+		if bases[names[i]] > 1 {
+			names[i] = path
+		}
+		if label, ok := ws.LabelFor(path); ok {
+			names[i] = label
+		}
+	}
+
+	next := 0
+	for _, label := range flagLabels {
+		if path, name, ok := strings.Cut(label, "="); ok {
+			index := slices.IndexFunc(loaded, func(loadedPath string) bool {
+				return filepath.Clean(loadedPath) == filepath.Clean(path) || filepath.Base(loadedPath) == path
+			})
+			if index < 0 {
+				return nil, fmt.Errorf("--label %s names a file that was not loaded", label)
+			}
+			names[index] = name
+			continue
+		}
+		if next >= len(loaded) {
+			return nil, fmt.Errorf("more --label values than files (%d)", len(loaded))
+		}
+		names[next] = label
+		next++
+	}
+	return names, nil
+}
+
+// noiseThresholds parses the workspace and --noise thresholds by metric ID;
+// flags override the workspace for the same metric.
+func noiseThresholds(ws *workspace.Workspace, flagThresholds []string) (map[string]har.NoiseThreshold, error) {
+	specs := flagThresholds
+	if ws != nil {
+		specs = append(append([]string{}, ws.Noise...), specs...)
+	}
+
+	thresholds := make(map[string]har.NoiseThreshold, len(specs))
+	for _, spec := range specs {
+		id, threshold, err := har.ParseNoiseThreshold(spec)
+		if err != nil {
+			return nil, err
+		}
+		thresholds[id] = threshold
+	}
+	return thresholds, nil
+}
+
+// loadBlocklists adds the workspace and --blocklist category=path lists to
+// the bundled third-party domain categories.
+func loadBlocklists(ws *workspace.Workspace, flagLists []string, log io.Writer) error {
+	var lists []string
+	if ws != nil {
+		categories := make([]string, 0, len(ws.Blocklists))
+		for category := range ws.Blocklists {
+			categories = append(categories, category)
+		}
+		sort.Strings(categories)
+		for _, category := range categories {
+			lists = append(lists, category+"="+ws.Blocklists[category])
+		}
+	}
+	lists = append(lists, flagLists...)
+
+	for _, list := range lists {
+		category, path, ok := strings.Cut(list, "=")
+		if !ok || category == "" || path == "" {
+			return fmt.Errorf("invalid blocklist %q: expected category=path", list)
+		}
+		added, err := har.LoadBlocklist(path, category)
+		if err != nil {
+			return fmt.Errorf("Error loading blocklist %s: %v", path, err)
+		}
+		fmt.Fprintf(log, "Loaded blocklist: %s (%d %s domains)\n", path, added, category)
+	}
+	return nil
+}
+
+// registerSecretRules adds the workspace and --secret-rule name=regex patterns
+// to the secret scan.
+func registerSecretRules(ws *workspace.Workspace, flagRules []string) error {
+	if ws != nil {
+		names := make([]string, 0, len(ws.Secrets))
+		for name := range ws.Secrets {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if err := audit.RegisterSecretPattern(name, ws.Secrets[name]); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, rule := range flagRules {
+		name, expression, ok := strings.Cut(rule, "=")
+		if !ok || name == "" {
+			return fmt.Errorf("invalid secret rule %q: expected name=regex", rule)
+		}
+		if err := audit.RegisterSecretPattern(name, expression); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeLighthouseReports applies each --lighthouse report to the HAR file it
+// names with "file.har=report.json", or otherwise to the HAR file at the same position.
+func mergeLighthouseReports(harFiles []*har.HAR, loaded []string, reports []string, log io.Writer) error {
+	for i, value := range reports {
+		target, report := i, value
+		if harPath, reportPath, ok := strings.Cut(value, "="); ok {
+			target, report = -1, reportPath
+			for j, path := range loaded {
+				if path == harPath {
+					target = j
+				}
+			}
+			if target < 0 {
+				return fmt.Errorf("Lighthouse report %s refers to %s, which was not loaded", reportPath, harPath)
+			}
+		} else if target >= len(harFiles) {
+			return fmt.Errorf("Lighthouse report %s has no matching HAR file", report)
+		}
+
+		lab, err := har.ParseLighthouseFile(report)
+		if err != nil {
+			return fmt.Errorf("Error parsing %s: %v", report, err)
+		}
+		har.ApplyLabMetrics(harFiles[target], lab)
+		fmt.Fprintf(log, "Merged Lighthouse report %s into %s\n", report, loaded[target])
+	}
+	return nil
+}
+
+// pinToPaths rewrites positional flag values as file=value for the file at
+// the same position in paths, so reordering the files cannot move them to
+// another file. Without paths the values pair with the workspace baseline and
+// are left as they are.
+func pinToPaths(name string, values, paths []string) (stringList, error) {
+	if len(paths) == 0 {
+		return values, nil
+	}
+
+	pinned := make(stringList, 0, len(values))
+	next := 0
+	for _, value := range values {
+		if strings.Contains(value, "=") {
+			pinned = append(pinned, value)
+			continue
+		}
+		if next >= len(paths) {
+			return nil, fmt.Errorf("more --%s values than files (%d)", name, len(paths))
+		}
+		pinned = append(pinned, paths[next]+"="+value)
+		next++
+	}
+	return pinned, nil
+}
+
+// withBaselineFirst moves the workspace baseline to the front of paths, adding
+// it when it was not given on the command line.
+func withBaselineFirst(ws *workspace.Workspace, paths []string) []string {
+	if ws.Baseline == "" {
+		return paths
+	}
+
+	baseline := ws.Baseline
+	rest := make([]string, 0, len(paths))
+	for _, path := range paths {
+		if ws.IsBaseline(path) {
+			baseline = path
+			continue
+		}
+		rest = append(rest, path)
+	}
+
+	return append([]string{baseline}, rest...)
+}
+
+// withChosenBaseline moves the --baseline file, given as a path or 1-based
+// position, to the front so comparisons are made against it.
+func withChosenBaseline(paths []string, baseline string) ([]string, error) {
+	index := -1
+	if position, err := strconv.Atoi(baseline); err == nil && position >= 1 && position <= len(paths) {
+		index = position - 1
+	}
+	for i, path := range paths {
+		if index < 0 && filepath.Clean(path) == filepath.Clean(baseline) {
+			index = i
+		}
+	}
+	if index < 0 {
+		return nil, fmt.Errorf("--baseline %s is neither one of the files nor a position from 1 to %d", baseline, len(paths))
+	}
+
+	reordered := append([]string{paths[index]}, paths[:index]...)
+	return append(reordered, paths[index+1:]...), nil
+}
+
+func loadHARFiles(paths []string, log io.Writer, options loadOptions) ([]*har.HAR, []string, error) {
 	parser := har.NewParser()
 	var harFiles []*har.HAR
+	var loaded []string
+	seen := make(map[string]string)
 
-	for _, filepath := range os.Args[1:] {
-		harFile, err := parser.ParseFile(filepath)
+	for _, filepath := range paths {
+		harFile, digest, err := parser.ParseFileWithDigest(filepath)
 		if err != nil {
-			fmt.Printf("Error parsing %s: %v\n", filepath, err)
-			os.Exit(1)
+			return nil, nil, fmt.Errorf("Error parsing %s: %v", filepath, err)
+		}
+
+		if original, ok := seen[digest]; ok {
+			if options.dedupe {
+				fmt.Fprintf(log, "Skipping %s: identical to %s\n", filepath, original)
+				continue
+			}
+			fmt.Fprintf(log, "Warning: %s is identical to %s; comparisons between them will show no change (use --dedupe to skip duplicates)\n", filepath, original)
+		} else {
+			seen[digest] = filepath
 		}
 
 		if err := parser.ValidateHAR(harFile); err != nil {
-			fmt.Printf("Invalid HAR file %s: %v\n", filepath, err)
-			os.Exit(1)
+			return nil, nil, fmt.Errorf("Invalid HAR file %s: %v", filepath, err)
 		}
 
 		harFiles = append(harFiles, harFile)
-		fmt.Printf("Loaded HAR file: %s (%d entries)\n", filepath, len(harFile.Log.Entries))
+		loaded = append(loaded, filepath)
+		fmt.Fprintf(log, "Loaded HAR file: %s (%d entries)\n", filepath, len(harFile.Log.Entries))
+		for _, warning := range parser.Warnings(harFile) {
+			fmt.Fprintf(log, "Warning: %s: %s\n", filepath, warning)
+		}
 	}
 
 	if len(harFiles) == 0 {
-		fmt.Println("No valid HAR files found")
-		os.Exit(1)
+		return nil, nil, fmt.Errorf("No valid HAR files found")
 	}
 
-	// Initialize and run TUI
-	model := tui.NewModel(harFiles)
-	program := tea.NewProgram(model, tea.WithAltScreen())
+	return harFiles, loaded, nil
+}
 
-	if _, err := program.Run(); err != nil {
-		fmt.Printf("Error running program: %v\n", err)
-		os.Exit(1)
+// runRender renders a TUI view offscreen and writes it as text or SVG.
+func runRender(args []string) int {
+	flags := flag.NewFlagSet("render", flag.ContinueOnError)
+	view := flags.String("view", "table", "view to render: table, detail, metrics, timeline, comparison, help, timing, security, concurrency, bandwidth, histogram, heatmap, status, endpoints, trends, whatif, initiators, errors")
+	width := flags.Int("width", 120, "terminal width in columns")
+	height := flags.Int("height", 40, "terminal height in rows")
+	out := flags.String("out", "", "output file (.txt or .svg); defaults to stdout")
+	color := flags.Bool("color", false, "keep ANSI colors in text output")
+	plain := flags.Bool("plain", false, "use ASCII markers instead of emoji and status icons")
+	noColor := flags.Bool("no-color", false, "never write ANSI styling, even with --color or to SVG; also set by NO_COLOR")
+	var options loadOptions
+	registerLoadFlags(flags, &options)
+
+	if err := flags.Parse(reorderArgs(flags, args)); err != nil {
+		return 2
+	}
+	if flags.NArg() == 0 && options.workspace == "" {
+		fmt.Fprintln(os.Stderr, "Usage: hartea render <har-file> [har-file2] --view <view> [--width N] [--height N] [--out file]")
+		return 2
+	}
+
+	harFiles, _, tuiOptions, err := loadSession(flags.Args(), os.Stderr, options)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	tuiOptions.Plain, tuiOptions.NoColor = *plain, colorDisabled(*noColor)
+	if err := applyConfig(&tuiOptions); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	svg := strings.EqualFold(filepath.Ext(*out), ".svg")
+	output, err := tui.RenderView(harFiles, tuiOptions, *view, *width, *height, (svg || *color) && !tuiOptions.NoColor)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error rendering view: %v\n", err)
+		return 1
+	}
+	if svg {
+		output = tui.ANSIToSVG(output)
+	} else if !strings.HasSuffix(output, "\n") {
+		output += "\n"
+	}
+
+	if *out == "" {
+		fmt.Print(output)
+		return 0
+	}
+	if err := os.WriteFile(*out, []byte(output), 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", *out, err)
+		return 1
+	}
+	fmt.Fprintf(os.Stderr, "Rendered %s view to %s\n", *view, *out)
+	return 0
+}
+
+// exportFormats maps --format names to their default extension.
+var exportFormats = map[string]string{
+	"json":          ".json",
+	"csv":           ".csv",
+	"entries-csv":   "-entries.csv",
+	"ndjson":        ".ndjson",
+	"html":          ".html",
+	"pdf":           ".pdf",
+	"prometheus":    ".prom",
+	"sarif":         ".sarif",
+	"findings":      "-findings.json",
+	"trends-csv":    "-trends.csv",
+	"trends-json":   "-trends.json",
+	"dot":           ".dot",
+	"mermaid":       "-sequence.md",
+	"waterfall-svg": "-waterfall.svg",
+	"waterfall-png": "-waterfall.png",
+	"trace":         "-trace.json",
+}
+
+// runExport writes a report without starting the TUI, e.g. from CI.
+func runExport(args []string) int {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	format := flags.String("format", "json", "report format: json, csv, entries-csv, ndjson, html, pdf, prometheus, sarif, findings, trends-csv, trends-json, dot, mermaid, waterfall-svg, waterfall-png, trace")
+	out := flags.String("out", "", "output file, or - for standard output; defaults to har-analysis<ext>")
+	includeEntries := flags.Bool("include-entries", false, "include entries in JSON output")
+	plain := flags.Bool("plain", false, "use ASCII markers instead of emoji in HTML output")
+	var options loadOptions
+	registerLoadFlags(flags, &options)
+
+	if err := flags.Parse(reorderArgs(flags, args)); err != nil {
+		return 2
+	}
+	extension, ok := exportFormats[*format]
+	if !ok || (flags.NArg() == 0 && options.workspace == "") {
+		fmt.Fprintln(os.Stderr, "Usage: hartea export <har-file> [har-file2] --format json|csv|entries-csv|ndjson|html|pdf|prometheus|sarif|findings|trends-csv|trends-json|dot|mermaid|waterfall-svg|waterfall-png|trace [--out file|-]")
+		return 2
+	}
+
+	harFiles, loaded, tuiOptions, err := loadSession(flags.Args(), os.Stderr, options)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	filename := *out
+	if filename == "" {
+		filename = "har-analysis" + extension
+	}
+	plainHTML, err := plainOutput(*plain)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	generator := report.NewGeneratorFromHAR(harFiles, tuiOptions.FileNames, tuiOptions.Settings)
+	generator.SetSources(loaded)
+	generator.SetSegmentGap(options.segmentGap)
+	generator.SetVendorBudgets(tuiOptions.VendorBudgets)
+	generator.SetSLOs(tuiOptions.SLOs)
+	generator.SetPlain(plainHTML)
+	if filename == "-" {
+		err = generator.Write(os.Stdout, *format, *includeEntries)
+	} else {
+		err = generator.Export(filename, *format, *includeEntries)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error exporting %s: %v\n", *format, err)
+		return 1
+	}
+
+	if filename != "-" {
+		fmt.Fprintf(os.Stderr, "Exported %s report to %s\n", *format, filename)
+	}
+	return 0
+}
+
+// runCheck evaluates the workspace metric budgets and the vendor budgets
+// against each HAR file and fails when any is exceeded, e.g. to gate CI.
+func runCheck(args []string) int {
+	flags := flag.NewFlagSet("check", flag.ContinueOnError)
+	var options loadOptions
+	registerLoadFlags(flags, &options)
+	failOnInsight := flags.String("fail-on-insight", "", "with two or more files, fail on comparison insights at least this severe: info, warning or critical")
+	cspText := flags.String("csp", "", "fail on requests and inline code of the page document that this candidate Content-Security-Policy would block")
+
+	if err := flags.Parse(reorderArgs(flags, args)); err != nil {
+		return 2
+	}
+	if flags.NArg() == 0 && options.workspace == "" {
+		fmt.Fprintln(os.Stderr, "Usage: hartea check <har-file> [har-file2] [--workspace name] [--vendor-budget spec] [--slo spec] [--fail-on-insight severity] [--csp policy]")
+		return 2
+	}
+	var failOn har.InsightSeverity
+	if *failOnInsight != "" {
+		severity, err := har.ParseInsightSeverity(*failOnInsight)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+		failOn = severity
+	}
+	var policy audit.CSPPolicy
+	if *cspText != "" {
+		parsed, err := audit.ParseCSP(*cspText)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+		policy = parsed
+	}
+
+	// Exit code 1 is kept for failed checks, so a broken invocation or an
+	// unreadable file is never mistaken for a regression
+	harFiles, loaded, tuiOptions, err := loadSession(flags.Args(), os.Stderr, options)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	if len(tuiOptions.Budgets) == 0 && len(tuiOptions.VendorBudgets) == 0 && len(tuiOptions.SLOs) == 0 && len(harFiles) < 2 && *cspText == "" {
+		fmt.Fprintln(os.Stderr, "Nothing to check: set \"budgets\", \"vendorBudgets\" or \"slos\" in the workspace, pass --vendor-budget, --slo or --csp, or compare two or more files")
+		return 2
+	}
+	for id := range tuiOptions.Budgets {
+		if _, ok := har.LookupMetric(id); !ok {
+			fmt.Fprintf(os.Stderr, "Warning: unknown budget metric %q\n", id)
+		}
+	}
+
+	failed := 0
+	result := func(pass bool) string {
+		if pass {
+			return "PASS"
+		}
+		failed++
+		return "FAIL"
+	}
+
+	allMetrics := make([]*har.Metrics, len(harFiles))
+	for i, harFile := range harFiles {
+		analyzer := har.NewAnalyzer(harFile, tuiOptions.Settings)
+		metrics := analyzer.CalculateMetrics()
+		allMetrics[i] = metrics
+		fmt.Println(loaded[i])
+		for _, descriptor := range har.MetricDescriptors() {
+			limit, ok := tuiOptions.Budgets[descriptor.ID]
+			if !ok {
+				continue
+			}
+			value := descriptor.Value(metrics)
+			fmt.Printf("  %s  %-24s %12s / %s\n", result(descriptor.WithinBudget(value, limit)), descriptor.Name, descriptor.Format(value, tuiOptions.Settings.SizeUnits), descriptor.Format(limit, tuiOptions.Settings.SizeUnits))
+		}
+		for _, scorecard := range analyzer.VendorScorecards(tuiOptions.VendorBudgets) {
+			fmt.Printf("  %s  %-24s %12s / %s\n", result(scorecard.Pass()), scorecard.Budget.Vendor, scorecard.Usage(tuiOptions.Settings.SizeUnits), scorecard.Budget.Limits(tuiOptions.Settings.SizeUnits))
+		}
+		for _, slo := range analyzer.EvaluateSLOs(tuiOptions.SLOs) {
+			for _, objective := range slo.Objectives {
+				fmt.Printf("  %s  %-24s %12s / %s\n", result(objective.Pass), slo.SLO.Name+" "+objective.Name, objective.Actual, objective.Target)
+			}
+			fmt.Printf("        %-24s %d request(s), Apdex %.2f (T=%.0fms)\n", "", slo.Requests, slo.Apdex, slo.SLO.ApdexThreshold())
+		}
+		if *cspText != "" {
+			csp := audit.SimulateCSP(harFile, policy)
+			if csp.Document == "" {
+				fmt.Printf("  %s  %-24s no HTML document to apply the policy to\n", result(true), "CSP")
+				continue
+			}
+			fmt.Printf("  %s  %-24s %d request(s) and %d inline resource(s) of %s\n", result(len(csp.Violations) == 0), "CSP", csp.Requests, csp.Inline, csp.Document)
+			for _, violation := range csp.Violations {
+				fmt.Printf("        blocked by %-15s %s\n", violation.Directive, violation)
+			}
+		}
+	}
+
+	if len(harFiles) > 1 {
+		fmt.Printf("Insights against %s\n", loaded[0])
+		for _, insight := range har.NewComparator(loaded, allMetrics, tuiOptions.Settings).Compare().Insights {
+			status := "    "
+			if failOn != "" {
+				status = result(!insight.Severity.AtLeast(failOn))
+			}
+			fmt.Printf("  %s  %-8s %s\n", status, insight.Severity, insight)
+		}
+	}
+
+	if failed > 0 {
+		fmt.Fprintf(os.Stderr, "%d check(s) failed\n", failed)
+		return 1
+	}
+	fmt.Fprintln(os.Stderr, "All checks passed")
+	return 0
+}
+
+// runRuns compares labeled sets of repeated captures of the same page by
+// their mean, median and spread, marking which differences are significant
+// rather than run-to-run noise.
+func runRuns(args []string) int {
+	flags := flag.NewFlagSet("runs", flag.ContinueOnError)
+	var sets stringList
+	flags.Var(&sets, "set", "a labeled set of runs as label=glob[,glob] (repeatable; the first set is the baseline)")
+	jsonOutput := flags.Bool("json", false, "write the comparisons as JSON")
+	var options loadOptions
+	registerAnalysisFlags(flags, &options)
+
+	if err := flags.Parse(reorderArgs(flags, args)); err != nil {
+		return 2
+	}
+	if len(sets) < 2 || flags.NArg() > 0 {
+		fmt.Fprintln(os.Stderr, "Usage: hartea runs --set label=glob --set label2=glob [--json]")
+		return 2
+	}
+
+	labels, counts, paths, err := expandRunSets(sets)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	harFiles, _, tuiOptions, err := loadSession(paths, io.Discard, options)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	runSets := make([]har.RunSet, len(labels))
+	next := 0
+	for i, label := range labels {
+		runSets[i].Label = label
+		for _, harFile := range harFiles[next : next+counts[i]] {
+			runSets[i].Metrics = append(runSets[i].Metrics, har.NewAnalyzer(harFile, tuiOptions.Settings).CalculateMetrics())
+		}
+		next += counts[i]
+	}
+
+	comparisons := make([]har.RunSetComparison, 0, len(runSets)-1)
+	for _, candidate := range runSets[1:] {
+		comparisons = append(comparisons, har.CompareRunSets(runSets[0], candidate))
+	}
+	if *jsonOutput {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(comparisons); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		return 0
+	}
+
+	for i, comparison := range comparisons {
+		fmt.Printf("%s (%d runs) vs %s (%d runs)\n", comparison.Candidate, len(runSets[i+1].Metrics), comparison.Baseline, len(runSets[0].Metrics))
+		fmt.Printf("  %-24s %-32s %-32s %9s %8s\n", "Metric", comparison.Baseline+" mean ± sd (median)", comparison.Candidate+" mean ± sd (median)", "Change", "p")
+		for _, difference := range comparison.Differences {
+			descriptor, _ := har.LookupMetric(difference.ID)
+			verdict := ""
+			if difference.Significant {
+				verdict = "worse"
+				if difference.Improvement {
+					verdict = "better"
+				} else if descriptor.Direction == har.Neutral {
+					verdict = "changed"
+				}
+			}
+			fmt.Printf("  %-24s %-32s %-32s %+8.1f%% %8.3f %-3s %s\n", difference.Name, formatRunStats(descriptor, difference.Baseline, tuiOptions.Settings.SizeUnits),
+				formatRunStats(descriptor, difference.Candidate, tuiOptions.Settings.SizeUnits), difference.Change, difference.PValue, difference.Significance(), verdict)
+		}
+		fmt.Println()
+	}
+	fmt.Printf("Significance (Welch's t-test): *** p < 0.001, ** p < 0.01, * p < %g, ns not significant\n", har.SignificanceLevel)
+	return 0
+}
+
+// expandRunSets resolves label=glob[,glob] specs into the labels, the number
+// of files of each set and all files in set order.
+func expandRunSets(specs []string) ([]string, []int, []string, error) {
+	var labels, paths []string
+	var counts []int
+	for _, spec := range specs {
+		label, patterns, ok := strings.Cut(spec, "=")
+		if !ok || label == "" || patterns == "" {
+			return nil, nil, nil, fmt.Errorf("invalid run set %q (want label=glob[,glob])", spec)
+		}
+		var matches []string
+		for _, pattern := range strings.Split(patterns, ",") {
+			found, err := filepath.Glob(pattern)
+			if err != nil {
+				return nil, nil, nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+			}
+			matches = append(matches, found...)
+		}
+		if len(matches) == 0 {
+			return nil, nil, nil, fmt.Errorf("run set %s matches no files", label)
+		}
+		labels = append(labels, label)
+		counts = append(counts, len(matches))
+		paths = append(paths, matches...)
+	}
+	return labels, counts, paths, nil
+}
+
+// formatRunStats shows a metric's mean, spread and median over a run set.
+// Counts get a decimal, since their means and deviations are fractional.
+func formatRunStats(descriptor har.MetricDescriptor, stats har.RunStats, units har.SizeUnits) string {
+	format := func(value float64) string { return descriptor.Format(value, units) }
+	if descriptor.Kind == har.CountMetric {
+		format = func(value float64) string { return strconv.FormatFloat(value, 'f', 1, 64) }
+	}
+	return fmt.Sprintf("%s ± %s (%s)", format(stats.Mean), format(stats.StdDev), format(stats.Median))
+}
+
+// runRecord appends a metrics snapshot of each HAR file to the history that
+// trend views and the Grafana datasource read from.
+func runRecord(args []string) int {
+	flags := flag.NewFlagSet("record", flag.ContinueOnError)
+	label := flags.String("label", "", "series label for every file; defaults to the workspace label or file name")
+	var options loadOptions
+	registerLoadFlags(flags, &options)
+
+	if err := flags.Parse(reorderArgs(flags, args)); err != nil {
+		return 2
+	}
+	if flags.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "Usage: hartea record <har-file> [har-file2] [--workspace name] [--label name]")
+		return 2
+	}
+
+	harFiles, loaded, tuiOptions, err := loadSession(flags.Args(), os.Stderr, options)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	history, err := store.Open(options.workspace)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	snapshots := make([]store.Snapshot, len(harFiles))
+	for i, harFile := range harFiles {
+		name := *label
+		if name == "" {
+			name = tuiOptions.FileNames[i]
+		}
+		// Unlabeled files are recorded under their base name without extension
+		if name == filepath.Base(loaded[i]) || name == loaded[i] {
+			name = strings.TrimSuffix(filepath.Base(loaded[i]), filepath.Ext(loaded[i]))
+		}
+		snapshots[i] = store.NewSnapshot(harFile, name, loaded[i], tuiOptions.Settings)
+	}
+
+	if err := history.Append(snapshots...); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	fmt.Fprintf(os.Stderr, "Recorded %d snapshot(s) to %s\n", len(snapshots), history.Path)
+	return 0
+}
+
+// runSync shares workspaces, baselines and metrics history with the team
+// through an S3 bucket or git repository.
+func runSync(args []string) int {
+	flags := flag.NewFlagSet("sync", flag.ContinueOnError)
+	remoteURL := flags.String("remote", os.Getenv("HARTEA_SYNC_REMOTE"), "s3://bucket/prefix or git repository URL (default $HARTEA_SYNC_REMOTE)")
+
+	if err := flags.Parse(reorderArgs(flags, args)); err != nil {
+		return 2
+	}
+	direction := flags.Arg(0)
+	if flags.NArg() != 1 || (direction != "pull" && direction != "push") {
+		fmt.Fprintln(os.Stderr, "Usage: hartea sync pull|push [--remote s3://bucket/prefix|git-url]")
+		return 2
+	}
+
+	backend, err := remote.Open(*remoteURL)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	workspaces, err := workspace.Dir()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	root := filepath.Dir(workspaces)
+
+	if direction == "pull" {
+		err = remote.Pull(backend, root)
+	} else {
+		err = remote.Push(backend, root)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Sync %s failed: %v\n", direction, err)
+		return 1
+	}
+
+	fmt.Fprintf(os.Stderr, "Sync %s with %s complete\n", direction, backend)
+	return 0
+}
+
+// runServe hosts the interactive HTML report and JSON API over HTTP.
+func runServe(args []string) int {
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+	port := flags.Int("port", 8787, "port to listen on")
+	host := flags.String("host", "localhost", "interface to bind; use 0.0.0.0 to share on the network")
+	var options loadOptions
+	registerLoadFlags(flags, &options)
+
+	if err := flags.Parse(reorderArgs(flags, args)); err != nil {
+		return 2
+	}
+
+	// Without HAR files only the Grafana datasource over the history is served
+	var harFiles []*har.HAR
+	var tuiOptions tui.Options
+	if flags.NArg() > 0 || options.workspace != "" {
+		var err error
+		harFiles, _, tuiOptions, err = loadSession(flags.Args(), os.Stderr, options)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	}
+
+	history, err := store.Open(options.workspace)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	srv, err := server.New(harFiles, tuiOptions.FileNames, tuiOptions.Settings, history)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error preparing report: %v\n", err)
+		return 1
+	}
+
+	addr := net.JoinHostPort(*host, strconv.Itoa(*port))
+	if len(harFiles) > 0 {
+		fmt.Fprintf(os.Stderr, "Serving report at http://%s/ (API: /api/metrics, /api/entries)\n", addr)
+	}
+	fmt.Fprintf(os.Stderr, "Grafana JSON datasource at http://%s/grafana (history: %s) - press Ctrl+C to stop\n", addr, history.Path)
+	if err := srv.ListenAndServe(addr); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// reorderArgs moves flags ahead of positional arguments so that
+// "hartea render file.har --view timeline" parses as expected.
+func reorderArgs(flags *flag.FlagSet, args []string) []string {
+	var flagArgs, positional []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			positional = append(positional, args[i+1:]...)
+			break
+		}
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			positional = append(positional, arg)
+			continue
+		}
+		flagArgs = append(flagArgs, arg)
+		name := strings.TrimLeft(arg, "-")
+		if strings.Contains(name, "=") {
+			continue
+		}
+		if f := flags.Lookup(name); f != nil {
+			if bf, ok := f.Value.(interface{ IsBoolFlag() bool }); ok && bf.IsBoolFlag() {
+				continue
+			}
+			if i+1 < len(args) {
+				flagArgs = append(flagArgs, args[i+1])
+				i++
+			}
+		}
 	}
+	return append(flagArgs, positional...)
 }
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// writeLighthouse writes a Lighthouse report with the given First Contentful
+// Paint and returns its path.
+func writeLighthouse(t *testing.T, name string, fcp float64) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	report := fmt.Sprintf(`{"lighthouseVersion": "12.0.0", "audits": {"first-contentful-paint": {"numericValue": %g}}}`, fcp)
+	if err := os.WriteFile(path, []byte(report), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestPositionalValuesFollowTheirFileWhenABaselineIsChosen(t *testing.T) {
+	first, second := "../example.har", "../example2.har"
+	options := loadOptions{
+		baseline:   "2",
+		labels:     stringList{"before", "after"},
+		lighthouse: stringList{writeLighthouse(t, "before.json", 1100), writeLighthouse(t, "after.json", 2200)},
+	}
+
+	harFiles, loaded, tuiOptions, err := loadSession([]string{first, second}, io.Discard, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{second, first}; !slices.Equal(loaded, want) {
+		t.Fatalf("loaded %v, want the chosen baseline first: %v", loaded, want)
+	}
+	if want := []string{"after", "before"}; !slices.Equal(tuiOptions.FileNames, want) {
+		t.Errorf("labels %v, want %v", tuiOptions.FileNames, want)
+	}
+	for i, want := range []float64{2200, 1100} {
+		if got := harFiles[i].Log.Pages[0].FirstContentfulPaint; got != want {
+			t.Errorf("%s has First Contentful Paint %g, want %g from its own Lighthouse report", loaded[i], got, want)
+		}
+	}
+}
+
+func TestPinToPaths(t *testing.T) {
+	paths := []string{"a.har", "b.har"}
+	tests := []struct {
+		values  []string
+		want    []string
+		wantErr bool
+	}{
+		{values: []string{"one", "two"}, want: []string{"a.har=one", "b.har=two"}},
+		{values: []string{"b.har=two", "one"}, want: []string{"b.har=two", "a.har=one"}},
+		{values: []string{"one", "two", "three"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := pinToPaths("label", tt.values, paths)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("pinToPaths(%v) = %v, want an error", tt.values, got)
+			}
+			continue
+		}
+		if err != nil || !slices.Equal(got, tt.want) {
+			t.Errorf("pinToPaths(%v) = %v, %v, want %v", tt.values, got, err, tt.want)
+		}
+	}
+
+	if got, err := pinToPaths("label", []string{"baseline"}, nil); err != nil || !slices.Equal(got, []string{"baseline"}) {
+		t.Errorf("pinToPaths without paths = %v, %v, want the values unchanged", got, err)
+	}
+}
@@ -0,0 +1,183 @@
+package audit
+
+import (
+	"fmt"
+	"github.com/jlgore/hartea/internal/har"
+	"strings"
+)
+
+// Severity follows the SARIF result levels.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityNote    Severity = "note"
+)
+
+type Rule struct {
+	ID          string
+	Name        string
+	Description string
+	Severity    Severity
+}
+
+// Finding is one rule violation, located by file and entry index.
+type Finding struct {
+	RuleID     string   `json:"rule_id"`
+	Severity   Severity `json:"severity"`
+	Message    string   `json:"message"`
+	File       int      `json:"file"`
+	EntryIndex int      `json:"entry_index"`
+	URL        string   `json:"url"` // credentials masked, see RedactURL
+}
+
+const (
+	RuleInsecureCookie        = "insecure-cookie"
+	RuleMissingSecurityHeader = "missing-security-header"
+	RuleSecretInURL           = "secret-in-url"
+	RuleSecretInHeader        = "secret-in-header"
+	RuleSecretInBody          = "secret-in-body"
+	RuleHSTSFirstVisit        = "hsts-first-visit"
+)
+
+var rules = []Rule{
+	{ID: RuleInsecureCookie, Name: "InsecureCookie", Severity: SeverityWarning,
+		Description: "Cookie set without the Secure or HttpOnly attribute"},
+	{ID: RuleMissingSecurityHeader, Name: "MissingSecurityHeader", Severity: SeverityWarning,
+		Description: "HTML document served without a recommended security header"},
+	{ID: RuleSecretInURL, Name: "SecretInURL", Severity: SeverityError,
+		Description: "Credential or token exposed in a request URL"},
+	{ID: RuleSecretInHeader, Name: "SecretInHeader", Severity: SeverityError,
+		Description: "Credential or token captured in a request or response header"},
+	{ID: RuleSecretInBody, Name: "SecretInBody", Severity: SeverityError,
+		Description: "Credential or token captured in a request or response body"},
+	{ID: RuleHSTSFirstVisit, Name: "HSTSFirstVisit", Severity: SeverityWarning,
+		Description: "First-party host can be reached over HTTP on first visit because no preload-ready HSTS policy covers it"},
+}
+
+// Rules returns every rule the audit can report, in a stable order.
+func Rules() []Rule {
+	result := make([]Rule, len(rules))
+	copy(result, rules)
+	return result
+}
+
+func LookupRule(id string) (Rule, bool) {
+	for _, rule := range rules {
+		if rule.ID == id {
+			return rule, true
+		}
+	}
+	return Rule{}, false
+}
+
+// Audit runs every check over every entry of the given files, telling first
+// from third parties by the settings.
+func Audit(harFiles []*har.HAR, settings har.Settings) []Finding {
+	var findings []Finding
+	for i, harFile := range harFiles {
+		for j, entry := range harFile.Log.Entries {
+			for _, check := range checks {
+				for _, finding := range check(entry) {
+					finding.File = i
+					finding.EntryIndex = j
+					finding.URL = RedactURL(entry.Request.URL)
+					if rule, ok := LookupRule(finding.RuleID); ok && finding.Severity == "" {
+						finding.Severity = rule.Severity
+					}
+					findings = append(findings, finding)
+				}
+			}
+		}
+		findings = append(findings, checkHSTS(i, harFile, settings)...)
+	}
+	return findings
+}
+
+// checkHSTS reports first-party hosts that are redirect-vulnerable on first
+// visit, at each host's first request.
+func checkHSTS(file int, harFile *har.HAR, settings har.Settings) []Finding {
+	var findings []Finding
+	for _, host := range har.NewAnalyzer(harFile, settings).HSTSReadiness() {
+		if !host.RedirectVulnerable {
+			continue
+		}
+		message := fmt.Sprintf("%s is not covered by a preload-ready HSTS policy (%s), so a first visit can be made over HTTP",
+			host.Host, strings.Join(host.Problems, ", "))
+		if host.PreloadReady() {
+			message = fmt.Sprintf("%s is preload-ready but only %s can be preloaded, and its policy was not captured",
+				host.Host, har.RegistrableDomain(host.Host))
+		}
+		findings = append(findings, Finding{
+			RuleID:     RuleHSTSFirstVisit,
+			Severity:   SeverityWarning,
+			Message:    message,
+			File:       file,
+			EntryIndex: host.EntryIndex,
+			URL:        RedactURL(harFile.Log.Entries[host.EntryIndex].Request.URL),
+		})
+	}
+	return findings
+}
+
+type check func(entry har.Entry) []Finding
+
+var checks = []check{
+	checkCookies,
+	checkSecurityHeaders,
+	checkURLSecrets,
+	checkHeaderSecrets,
+	checkBodySecrets,
+}
+
+func checkCookies(entry har.Entry) []Finding {
+	var findings []Finding
+	for _, cookie := range entry.Response.Cookies {
+		var missing []string
+		if !cookie.Secure {
+			missing = append(missing, "Secure")
+		}
+		if !cookie.HTTPOnly {
+			missing = append(missing, "HttpOnly")
+		}
+		if len(missing) > 0 {
+			findings = append(findings, Finding{
+				RuleID:  RuleInsecureCookie,
+				Message: fmt.Sprintf("Cookie %q is set without %s", cookie.Name, strings.Join(missing, " and ")),
+			})
+		}
+	}
+	return findings
+}
+
+var securityHeaders = []string{
+	"Content-Security-Policy",
+	"X-Content-Type-Options",
+	"X-Frame-Options",
+	"Strict-Transport-Security",
+}
+
+func checkSecurityHeaders(entry har.Entry) []Finding {
+	if !strings.Contains(entry.Response.Content.MimeType, "html") || entry.Response.Status < 200 || entry.Response.Status >= 300 {
+		return nil
+	}
+
+	var missing []string
+	for _, name := range securityHeaders {
+		if name == "Strict-Transport-Security" && !strings.HasPrefix(entry.Request.URL, "https://") {
+			continue
+		}
+		if _, ok := har.HeaderValue(entry.Response.Headers, name); !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	return []Finding{{
+		RuleID:  RuleMissingSecurityHeader,
+		Message: "Document is served without " + strings.Join(missing, ", "),
+	}}
+}
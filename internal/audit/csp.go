@@ -0,0 +1,511 @@
+package audit
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"fmt"
+	"github.com/jlgore/hartea/internal/har"
+	"hash"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// CSPPolicy is a parsed Content-Security-Policy: the source list of each
+// directive, keyed by lowercase directive name.
+type CSPPolicy struct {
+	Text       string
+	Directives map[string][]string
+}
+
+// ParseCSP parses a policy as sent in a Content-Security-Policy header.
+// Like browsers, it keeps the first of repeated directives.
+func ParseCSP(text string) (CSPPolicy, error) {
+	policy := CSPPolicy{Text: strings.TrimSpace(text), Directives: map[string][]string{}}
+	for _, directive := range strings.Split(text, ";") {
+		fields := strings.Fields(directive)
+		if len(fields) == 0 {
+			continue
+		}
+		name := strings.ToLower(fields[0])
+		if _, ok := policy.Directives[name]; !ok {
+			policy.Directives[name] = fields[1:]
+		}
+	}
+	if len(policy.Directives) == 0 {
+		return CSPPolicy{}, fmt.Errorf("invalid CSP %q: no directives", text)
+	}
+	return policy, nil
+}
+
+// governing returns the first directive of the fallback list the policy sets.
+func (p CSPPolicy) governing(fallbacks []string) (string, []string, bool) {
+	for _, name := range fallbacks {
+		if sources, ok := p.Directives[name]; ok {
+			return name, sources, true
+		}
+	}
+	return "", nil, false
+}
+
+// CSPViolation is a request or inline resource of the page document that the
+// policy would block.
+type CSPViolation struct {
+	EntryIndex int    `json:"entry_index"`
+	URL        string `json:"url"`
+	Resource   string `json:"resource"`
+	Directive  string `json:"directive"`
+	// Excerpt starts the blocked inline code; empty for requests
+	Excerpt string `json:"excerpt,omitempty"`
+	// Count is how many inline attributes of the kind were blocked
+	Count int `json:"count"`
+}
+
+func (v CSPViolation) String() string {
+	if v.Count > 1 {
+		return fmt.Sprintf("%d %ss", v.Count, v.Resource)
+	}
+	if v.Excerpt != "" {
+		return fmt.Sprintf("%s %q", v.Resource, v.Excerpt)
+	}
+	return v.Resource + " " + v.URL
+}
+
+// CSPResult is what a policy would block on one capture.
+type CSPResult struct {
+	Document   string         `json:"document"`
+	Requests   int            `json:"requests"`
+	Inline     int            `json:"inline"`
+	Violations []CSPViolation `json:"violations"`
+}
+
+// SimulateCSP evaluates a candidate policy as if the capture's page document
+// had sent it: every subresource request is matched against its fetch
+// directive, and inline scripts, styles, event handlers and style attributes
+// of the document body against 'unsafe-inline', nonces and hashes. Captures
+// cannot show eval() or requests the policy would have prevented in turn, so
+// the result is a lower bound.
+func SimulateCSP(harFile *har.HAR, policy CSPPolicy) CSPResult {
+	entries := harFile.Log.Entries
+	documentIndex := pageDocument(entries)
+	if documentIndex < 0 {
+		return CSPResult{}
+	}
+	document := entries[documentIndex]
+	self, err := url.Parse(document.Request.URL)
+	if err != nil {
+		return CSPResult{}
+	}
+
+	result := CSPResult{Document: RedactURL(document.Request.URL)}
+	inline := parseInline(har.ResponseBody(document), self)
+
+	for i, entry := range entries {
+		if i <= documentIndex && (i == documentIndex || isNavigationRedirect(entry)) {
+			continue
+		}
+		resource, fallbacks := cspFetchDirective(entry)
+		result.Requests++
+
+		directive, sources, ok := policy.governing(fallbacks)
+		if !ok {
+			continue
+		}
+		target, err := url.Parse(entry.Request.URL)
+		if err != nil {
+			continue
+		}
+		allowed := allowsRequest(sources, target, self)
+		if resource == "script" {
+			allowed = allowsScript(sources, entry, inline, target, self)
+		}
+		if allowed {
+			continue
+		}
+		result.Violations = append(result.Violations, CSPViolation{
+			EntryIndex: i,
+			URL:        RedactURL(entry.Request.URL),
+			Resource:   resource,
+			Directive:  directive,
+			Count:      1,
+		})
+	}
+
+	// Blocked attributes are reported once per kind, with a count
+	attributes := map[string]int{}
+	for _, item := range inline.items {
+		result.Inline++
+		directive, sources, ok := policy.governing(item.fallbacks)
+		if !ok || allowsInline(sources, item) {
+			continue
+		}
+		if item.attribute {
+			if index, ok := attributes[item.resource]; ok {
+				result.Violations[index].Count++
+				continue
+			}
+			attributes[item.resource] = len(result.Violations)
+		}
+		result.Violations = append(result.Violations, CSPViolation{
+			EntryIndex: documentIndex,
+			URL:        RedactURL(document.Request.URL),
+			Resource:   item.resource,
+			Directive:  directive,
+			Excerpt:    excerpt(item.content),
+			Count:      1,
+		})
+	}
+
+	return result
+}
+
+// pageDocument returns the index of the first successful HTML document, the
+// page the policy protects, or -1.
+func pageDocument(entries []har.Entry) int {
+	for i, entry := range entries {
+		if strings.Contains(entry.Response.Content.MimeType, "html") && entry.Response.Status >= 200 && entry.Response.Status < 300 {
+			return i
+		}
+	}
+	return -1
+}
+
+func isNavigationRedirect(entry har.Entry) bool {
+	return entry.Response.Status >= 300 && entry.Response.Status < 400
+}
+
+// cspFetchDirective names the kind of request and the fetch directives that
+// govern it, most specific first. Chrome's resource type is used when the
+// capture has one, else the response MIME type.
+func cspFetchDirective(entry har.Entry) (string, []string) {
+	kind := entry.ChromeResourceType
+	if kind == "" || kind == "other" {
+		mimeType := entry.Response.Content.MimeType
+		switch har.ResourceType(mimeType) {
+		case "javascript":
+			kind = "script"
+		case "css":
+			kind = "stylesheet"
+		case "image", "font":
+			kind = har.ResourceType(mimeType)
+		case "html":
+			kind = "document"
+		default:
+			if strings.HasPrefix(mimeType, "video/") || strings.HasPrefix(mimeType, "audio/") {
+				kind = "media"
+			}
+		}
+	}
+
+	switch kind {
+	case "script":
+		return "script", []string{"script-src-elem", "script-src", "default-src"}
+	case "stylesheet":
+		return "stylesheet", []string{"style-src-elem", "style-src", "default-src"}
+	case "image":
+		return "image", []string{"img-src", "default-src"}
+	case "font":
+		return "font", []string{"font-src", "default-src"}
+	case "media":
+		return "media", []string{"media-src", "default-src"}
+	case "manifest":
+		return "manifest", []string{"manifest-src", "default-src"}
+	case "document":
+		return "frame", []string{"frame-src", "child-src", "default-src"}
+	}
+	return "connection", []string{"connect-src", "default-src"}
+}
+
+// allowsRequest reports whether a source list allows loading target from the
+// page at self.
+func allowsRequest(sources []string, target, self *url.URL) bool {
+	for _, source := range sources {
+		if matchesSource(source, target, self) {
+			return true
+		}
+	}
+	return false
+}
+
+// allowsScript applies the script-only rules: the document's <script> tag for
+// it carries an allowed nonce, or under 'strict-dynamic' (which ignores host
+// and scheme sources) another script added it.
+func allowsScript(sources []string, entry har.Entry, inline inlineContent, target, self *url.URL) bool {
+	if hasNonce(sources, inline.scriptNonces[entry.Request.URL]) {
+		return true
+	}
+	if containsSource(sources, "'strict-dynamic'") {
+		return entry.Initiator != nil && entry.Initiator.Type == "script"
+	}
+	return allowsRequest(sources, target, self)
+}
+
+func matchesSource(source string, target, self *url.URL) bool {
+	source = strings.ToLower(source)
+	switch {
+	case source == "*":
+		return isNetworkScheme(target.Scheme) || target.Scheme == self.Scheme
+	case source == "'self'":
+		if !strings.EqualFold(target.Hostname(), self.Hostname()) {
+			return false
+		}
+		if target.Scheme == self.Scheme {
+			return port(target) == port(self)
+		}
+		return schemeMatches(self.Scheme, target.Scheme) || schemeUpgraded(self.Scheme, target.Scheme)
+	case strings.HasPrefix(source, "'"):
+		return false
+	case strings.HasSuffix(source, ":") && !strings.Contains(source, "/"):
+		return schemeMatches(strings.TrimSuffix(source, ":"), target.Scheme)
+	}
+
+	scheme, rest, hasScheme := strings.Cut(source, "://")
+	if !hasScheme {
+		rest, scheme = source, ""
+	}
+	hostPort, path, _ := strings.Cut(rest, "/")
+	host, sourcePort, hasPort := strings.Cut(hostPort, ":")
+
+	if scheme != "" {
+		if !schemeMatches(scheme, target.Scheme) {
+			return false
+		}
+	} else if !schemeMatches(self.Scheme, target.Scheme) && !schemeUpgraded(self.Scheme, target.Scheme) {
+		return false
+	}
+
+	targetHost := strings.ToLower(target.Hostname())
+	switch {
+	case host == "*":
+	case strings.HasPrefix(host, "*."):
+		if !strings.HasSuffix(targetHost, host[1:]) {
+			return false
+		}
+	case host != targetHost:
+		return false
+	}
+
+	switch {
+	case hasPort && sourcePort == "*":
+	case hasPort:
+		if sourcePort != port(target) {
+			return false
+		}
+	case target.Port() != "" && target.Port() != defaultPort(target.Scheme):
+		return false
+	}
+
+	if path == "" {
+		return true
+	}
+	path = "/" + path
+	if strings.HasSuffix(path, "/") {
+		return strings.HasPrefix(target.Path, path)
+	}
+	return target.Path == path
+}
+
+// schemeMatches allows the secure upgrade of a source scheme.
+func schemeMatches(source, target string) bool {
+	return source == target || source == "http" && target == "https" || source == "ws" && target == "wss"
+}
+
+// schemeUpgraded allows WebSocket connections to an HTTP(S) page's host.
+func schemeUpgraded(self, target string) bool {
+	return self == "http" && (target == "ws" || target == "wss") || self == "https" && target == "wss"
+}
+
+func isNetworkScheme(scheme string) bool {
+	return scheme == "http" || scheme == "https" || scheme == "ws" || scheme == "wss"
+}
+
+func port(u *url.URL) string {
+	if u.Port() != "" {
+		return u.Port()
+	}
+	return defaultPort(u.Scheme)
+}
+
+func defaultPort(scheme string) string {
+	switch scheme {
+	case "http", "ws":
+		return "80"
+	case "https", "wss":
+		return "443"
+	}
+	return ""
+}
+
+func containsSource(sources []string, want string) bool {
+	for _, source := range sources {
+		if strings.EqualFold(source, want) {
+			return true
+		}
+	}
+	return false
+}
+
+// inlineItem is an inline script, style, event handler or style attribute of
+// the page document.
+type inlineItem struct {
+	resource  string
+	fallbacks []string
+	content   string
+	nonce     string
+	attribute bool
+}
+
+type inlineContent struct {
+	items []inlineItem
+	// scriptNonces maps the absolute URL of each <script src> to its nonce
+	scriptNonces map[string]string
+}
+
+var (
+	inlineScriptFallbacks   = []string{"script-src-elem", "script-src", "default-src"}
+	inlineStyleFallbacks    = []string{"style-src-elem", "style-src", "default-src"}
+	eventHandlerFallbacks   = []string{"script-src-attr", "script-src", "default-src"}
+	styleAttributeFallbacks = []string{"style-src-attr", "style-src", "default-src"}
+)
+
+// parseInline collects the inline code of an HTML document.
+func parseInline(body string, base *url.URL) inlineContent {
+	content := inlineContent{scriptNonces: map[string]string{}}
+	tokenizer := html.NewTokenizer(strings.NewReader(body))
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return content
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := tokenizer.Token()
+			attributes := map[string]string{}
+			for _, attribute := range token.Attr {
+				name := strings.ToLower(attribute.Key)
+				attributes[name] = attribute.Val
+				switch {
+				case name == "style":
+					content.items = append(content.items, inlineItem{
+						resource: "inline style attribute", fallbacks: styleAttributeFallbacks,
+						content: attribute.Val, attribute: true,
+					})
+				case strings.HasPrefix(name, "on"):
+					content.items = append(content.items, inlineItem{
+						resource: "inline event handler", fallbacks: eventHandlerFallbacks,
+						content: attribute.Val, attribute: true,
+					})
+				}
+			}
+
+			switch token.Data {
+			case "script":
+				if src, ok := attributes["src"]; ok {
+					if resolved, err := base.Parse(src); err == nil {
+						content.scriptNonces[resolved.String()] = attributes["nonce"]
+					}
+					continue
+				}
+				if !isJavaScriptType(attributes["type"]) {
+					continue
+				}
+				content.items = append(content.items, inlineItem{
+					resource: "inline script", fallbacks: inlineScriptFallbacks,
+					content: elementText(tokenizer), nonce: attributes["nonce"],
+				})
+			case "style":
+				content.items = append(content.items, inlineItem{
+					resource: "inline style", fallbacks: inlineStyleFallbacks,
+					content: elementText(tokenizer), nonce: attributes["nonce"],
+				})
+			}
+		}
+	}
+}
+
+// elementText returns the raw text of the <script> or <style> just opened.
+func elementText(tokenizer *html.Tokenizer) string {
+	if tokenizer.Next() != html.TextToken {
+		return ""
+	}
+	return string(tokenizer.Text())
+}
+
+// isJavaScriptType reports whether a <script type> is executed, unlike data
+// blocks such as application/ld+json.
+func isJavaScriptType(scriptType string) bool {
+	switch strings.ToLower(strings.TrimSpace(scriptType)) {
+	case "", "module", "text/javascript", "application/javascript", "text/ecmascript", "application/ecmascript":
+		return true
+	}
+	return false
+}
+
+// allowsInline applies CSP's inline rules: a matching nonce or hash allows an
+// element, and 'unsafe-inline' allows everything unless a nonce, hash or
+// 'strict-dynamic' is present. Attributes only match hashes with
+// 'unsafe-hashes'.
+func allowsInline(sources []string, item inlineItem) bool {
+	overridden := false
+	for _, source := range sources {
+		lower := strings.ToLower(source)
+		switch {
+		case lower == "'strict-dynamic'":
+			overridden = true
+		case strings.HasPrefix(lower, "'nonce-"):
+			overridden = true
+			if !item.attribute && hasNonce(sources, item.nonce) {
+				return true
+			}
+		case strings.HasPrefix(lower, "'sha256-"), strings.HasPrefix(lower, "'sha384-"), strings.HasPrefix(lower, "'sha512-"):
+			overridden = true
+			if (!item.attribute || containsSource(sources, "'unsafe-hashes'")) && matchesHash(source, item.content) {
+				return true
+			}
+		}
+	}
+	return !overridden && containsSource(sources, "'unsafe-inline'")
+}
+
+// hasNonce reports whether the sources allow a nonce, which is case-sensitive.
+func hasNonce(sources []string, nonce string) bool {
+	if nonce == "" {
+		return false
+	}
+	for _, source := range sources {
+		if source == "'nonce-"+nonce+"'" {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesHash checks a 'sha256-…' source against inline content.
+func matchesHash(source, content string) bool {
+	algorithm, digest, ok := strings.Cut(strings.Trim(source, "'"), "-")
+	if !ok {
+		return false
+	}
+	var h hash.Hash
+	switch strings.ToLower(algorithm) {
+	case "sha256":
+		h = sha256.New()
+	case "sha384":
+		h = sha512.New384()
+	case "sha512":
+		h = sha512.New()
+	default:
+		return false
+	}
+	h.Write([]byte(content))
+	sum := h.Sum(nil)
+	return digest == base64.StdEncoding.EncodeToString(sum) || digest == base64.RawURLEncoding.EncodeToString(sum)
+}
+
+func excerpt(content string) string {
+	runes := []rune(strings.Join(strings.Fields(content), " "))
+	if len(runes) > 40 {
+		return string(runes[:37]) + "..."
+	}
+	return string(runes)
+}
@@ -0,0 +1,220 @@
+package audit
+
+import (
+	"fmt"
+	"github.com/jlgore/hartea/internal/har"
+	"net/url"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// SecretPattern recognizes one kind of credential by its value.
+type SecretPattern struct {
+	Name    string
+	Pattern *regexp.Regexp
+}
+
+// builtinSecretPatterns are the patterns every audit starts from.
+var builtinSecretPatterns = []SecretPattern{
+	{"AWS access key", regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{"JWT", regexp.MustCompile(`eyJ[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]+`)},
+	{"bearer token", regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9._~+/-]{16,}=*`)},
+	{"GitHub token", regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{36,}\b`)},
+	{"Slack token", regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}\b`)},
+	{"Stripe secret key", regexp.MustCompile(`\b[rs]k_live_[A-Za-z0-9]{16,}\b`)},
+	{"Google API key", regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`)},
+	{"private key", regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----`)},
+	{"credential field", regexp.MustCompile(`(?i)"(access_?token|refresh_?token|id_?token|client_?secret|api_?key|password)"\s*:\s*"[^"]{4,}"`)},
+}
+
+var secretPatterns = slices.Clone(builtinSecretPatterns)
+
+// ResetSecretPatterns removes the patterns added with RegisterSecretPattern.
+func ResetSecretPatterns() {
+	secretPatterns = slices.Clone(builtinSecretPatterns)
+}
+
+// RegisterSecretPattern adds a custom secret regular expression to every
+// subsequent audit. Registering an existing name replaces that pattern.
+func RegisterSecretPattern(name, expression string) error {
+	pattern, err := regexp.Compile(expression)
+	if err != nil {
+		return fmt.Errorf("invalid secret pattern %q: %w", name, err)
+	}
+
+	for i, existing := range secretPatterns {
+		if existing.Name == name {
+			secretPatterns[i].Pattern = pattern
+			return nil
+		}
+	}
+	secretPatterns = append(secretPatterns, SecretPattern{Name: name, Pattern: pattern})
+	return nil
+}
+
+// SecretPatterns returns the built-in and registered secret patterns.
+func SecretPatterns() []SecretPattern {
+	patterns := make([]SecretPattern, len(secretPatterns))
+	copy(patterns, secretPatterns)
+	return patterns
+}
+
+// IsSecretRule reports whether a rule is about leaked credentials.
+func IsSecretRule(id string) bool {
+	return id == RuleSecretInURL || id == RuleSecretInHeader || id == RuleSecretInBody
+}
+
+var (
+	secretParamNames  = regexp.MustCompile(`(?i)^(access_?token|id_?token|refresh_?token|api_?key|apikey|client_?secret|secret|password|passwd|pwd|auth|token|sig|signature)$`)
+	secretHeaderNames = regexp.MustCompile(`(?i)^(authorization|proxy-authorization|x-api-key|api-key|x-auth-token|x-access-token)$`)
+)
+
+func checkURLSecrets(entry har.Entry) []Finding {
+	parsed, err := url.Parse(entry.Request.URL)
+	if err != nil {
+		return nil
+	}
+
+	query := parsed.Query()
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var findings []Finding
+	for _, name := range names {
+		if secretParamNames.MatchString(name) && query.Get(name) != "" {
+			findings = append(findings, Finding{
+				RuleID:  RuleSecretInURL,
+				Message: fmt.Sprintf("Query parameter %q looks like a credential (%s)", name, redact(query.Get(name))),
+			})
+		}
+	}
+	if secrets := matchSecrets(entry.Request.URL); len(secrets) > 0 {
+		findings = append(findings, Finding{
+			RuleID:  RuleSecretInURL,
+			Message: "URL contains " + describeSecrets(secrets),
+		})
+	}
+	return findings
+}
+
+// checkHeaderSecrets scans request and response headers. Cookie headers are
+// left to the cookie checks, since nearly every capture carries session cookies.
+func checkHeaderSecrets(entry har.Entry) []Finding {
+	var findings []Finding
+	scan := func(direction string, headers []har.Header) {
+		for _, header := range headers {
+			if strings.EqualFold(header.Name, "Cookie") || strings.EqualFold(header.Name, "Set-Cookie") {
+				continue
+			}
+
+			if secrets := matchSecrets(header.Value); len(secrets) > 0 {
+				findings = append(findings, Finding{
+					RuleID:  RuleSecretInHeader,
+					Message: fmt.Sprintf("%s header %q contains %s", direction, header.Name, describeSecrets(secrets)),
+				})
+			} else if secretHeaderNames.MatchString(header.Name) && header.Value != "" {
+				findings = append(findings, Finding{
+					RuleID:  RuleSecretInHeader,
+					Message: fmt.Sprintf("%s header %q carries a credential (%s)", direction, header.Name, redact(header.Value)),
+				})
+			}
+		}
+	}
+	scan("Request", entry.Request.Headers)
+	scan("Response", entry.Response.Headers)
+	return findings
+}
+
+func checkBodySecrets(entry har.Entry) []Finding {
+	var findings []Finding
+	scan := func(direction, body string) {
+		if secrets := matchSecrets(body); len(secrets) > 0 {
+			findings = append(findings, Finding{
+				RuleID:  RuleSecretInBody,
+				Message: fmt.Sprintf("%s body contains %s", direction, describeSecrets(secrets)),
+			})
+		}
+	}
+
+	if entry.Request.PostData != nil {
+		scan("Request", entry.Request.PostData.Text)
+	}
+
+	scan("Response", har.ResponseBody(entry))
+	return findings
+}
+
+type secretMatch struct {
+	name  string
+	value string
+}
+
+// matchSecrets returns the first match of each pattern found in text.
+func matchSecrets(text string) []secretMatch {
+	if text == "" {
+		return nil
+	}
+	var matches []secretMatch
+	for _, secret := range secretPatterns {
+		if value := secret.Pattern.FindString(text); value != "" {
+			matches = append(matches, secretMatch{name: secret.Name, value: value})
+		}
+	}
+	return matches
+}
+
+// describeSecrets names every kind of secret found in one place, e.g.
+// "a possible JWT, bearer token (eyJh…jk)".
+func describeSecrets(secrets []secretMatch) string {
+	names := make([]string, len(secrets))
+	for i, secret := range secrets {
+		names[i] = secret.name
+	}
+	return fmt.Sprintf("a possible %s (%s)", strings.Join(names, ", "), redact(secrets[0].value))
+}
+
+// RedactURL masks the credentials in a URL, as findings and their exports
+// show it: the values of credential-like query parameters, a userinfo
+// password and anything a secret pattern matches.
+func RedactURL(rawURL string) string {
+	redacted := rawURL
+	if parsed, err := url.Parse(rawURL); err == nil {
+		if _, ok := parsed.User.Password(); ok {
+			parsed.User = url.UserPassword(parsed.User.Username(), "redacted")
+		}
+		params := strings.Split(parsed.RawQuery, "&")
+		for i, param := range params {
+			name, value, ok := strings.Cut(param, "=")
+			if !ok || value == "" {
+				continue
+			}
+			if unescaped, err := url.QueryUnescape(name); err == nil && secretParamNames.MatchString(unescaped) {
+				if decoded, err := url.QueryUnescape(value); err == nil {
+					value = decoded
+				}
+				params[i] = name + "=" + redact(value)
+			}
+		}
+		parsed.RawQuery = strings.Join(params, "&")
+		redacted = parsed.String()
+	}
+	for _, secret := range matchSecrets(redacted) {
+		redacted = strings.ReplaceAll(redacted, secret.value, redact(secret.value))
+	}
+	return redacted
+}
+
+// redact keeps just enough of a secret to recognize it, so reports do not
+// leak it a second time.
+func redact(value string) string {
+	runes := []rune(value)
+	if len(runes) <= 8 {
+		return strings.Repeat("*", len(runes))
+	}
+	return string(runes[:4]) + "…" + string(runes[len(runes)-2:])
+}
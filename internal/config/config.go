@@ -0,0 +1,57 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Config holds user preferences that apply to every session, unlike
+// workspace settings which belong to one project.
+type Config struct {
+	Path string `json:"-"`
+	// Keys rebinds TUI actions, e.g. {"export": ["x"], "up": ["up", "i"]}.
+	Keys map[string][]string `json:"keys,omitempty"`
+	// Theme is the TUI color theme, e.g. "light" or "colorblind".
+	Theme string `json:"theme,omitempty"`
+	// Plain uses ASCII markers instead of emoji, like --plain.
+	Plain bool `json:"plain,omitempty"`
+	// TimeZone is "local" (the default) or "utc", for the TUI's clock times.
+	TimeZone string `json:"timeZone,omitempty"`
+	// SizeUnits is "binary" (KiB, MiB; the default) or "decimal" (kB, MB),
+	// like --size-units.
+	SizeUnits string `json:"sizeUnits,omitempty"`
+}
+
+// Path returns where the config file lives.
+func Path() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate config directory: %w", err)
+	}
+	return filepath.Join(configDir, "hartea", "config.json"), nil
+}
+
+// Load reads the config file, returning an empty config if it does not exist.
+func Load() (*Config, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &Config{Path: path}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config %s: %w", path, err)
+	}
+
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+	return cfg, nil
+}
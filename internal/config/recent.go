@@ -0,0 +1,81 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// MaxRecent is how many recently opened files are remembered.
+const MaxRecent = 10
+
+// recentPath is where the recent files are kept, next to the config file.
+func recentPath() (string, error) {
+	path, err := Path()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(path), "recent.json"), nil
+}
+
+// LoadRecent returns the recently opened files, most recent first.
+func LoadRecent() ([]string, error) {
+	path, err := recentPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read recent files %s: %w", path, err)
+	}
+
+	var recent []string
+	if err := json.Unmarshal(data, &recent); err != nil {
+		return nil, fmt.Errorf("failed to parse recent files %s: %w", path, err)
+	}
+	return recent, nil
+}
+
+// AddRecent moves the files to the front of the recent files, as absolute
+// paths, keeping at most MaxRecent.
+func AddRecent(paths ...string) error {
+	recent, err := LoadRecent()
+	if err != nil {
+		return err
+	}
+
+	for i := len(paths) - 1; i >= 0; i-- {
+		path, err := filepath.Abs(paths[i])
+		if err != nil {
+			return fmt.Errorf("failed to resolve %s: %w", paths[i], err)
+		}
+		recent = slices.DeleteFunc(recent, func(existing string) bool { return existing == path })
+		recent = append([]string{path}, recent...)
+	}
+	if len(recent) > MaxRecent {
+		recent = recent[:MaxRecent]
+	}
+
+	path, err := recentPath()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(recent, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode recent files: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write recent files %s: %w", path, err)
+	}
+	return nil
+}
@@ -1,12 +1,15 @@
 package har
 
 import (
+	"math"
+	"net/url"
 	"sort"
 	"strings"
 	"time"
 )
 
 type Metrics struct {
+	Environment            Environment
 	TotalRequests          int
 	TotalTime              float64
 	TotalSize              int64
@@ -17,17 +20,53 @@ type Metrics struct {
 	SSLTime                float64
 	FirstContentfulPaint   float64
 	LargestContentfulPaint float64
-	CacheHitRatio          float64
+	CumulativeLayoutShift  float64
+	TotalBlockingTime      float64
+	HasLabMetrics          bool // a layout shift score was measured, e.g. by Lighthouse
+	SpeedIndex             float64
+	VisualComplete         float64
+	CacheHitRatio          float64 // responses reusable without a request on a repeat visit
+	CDNResponses           int     // responses classified as an edge hit or miss
+	CDNHitRatio            float64 // edge hits among CDNResponses, see EdgeCache
+	RepeatViewLoadTime     float64 // simulated warm-cache visit, see SimulateRepeatView
+	RepeatViewSize         int64
 	ThirdPartyRequests     int
 	ErrorRequests          int
+	InsecureRequests       int
+	PreloadWastedBytes     int64   // preloaded or prefetched but never used
+	RedundantAPIRequests   int     // identical API responses fetched again, see APIRefetches
+	RedirectLatency        float64 // time spent on redirects before the final requests
+	NewConnections         int
+	ConnectionReuse        float64 // requests sent over an existing connection
+	ConnectionSetupWaste   float64 // setup time of connections beyond what the protocol needs
+	ProtocolRequests       int     // requests with a recorded HTTP version
+	MultiplexedCoverage    float64 // requests over HTTP/2 or HTTP/3
+	TextResponses          int     // responses whose content-encoding is tracked
+	BrotliCoverage         float64
+	ZstdCoverage           float64
+	CompressionCoverage    float64 // text responses with any content-encoding
+}
+
+// HasData reports whether the metrics were computed from at least one entry.
+// Averages and ratios of a capture without entries are meaningless and
+// should be displayed as N/A.
+func (m *Metrics) HasData() bool {
+	return m != nil && m.TotalRequests > 0
 }
 
 type Analyzer struct {
-	har *HAR
+	har        *HAR
+	settings   Settings
+	firstParty *string // cached by FirstPartyDomain
+}
+
+func NewAnalyzer(har *HAR, settings Settings) *Analyzer {
+	return &Analyzer{har: har, settings: settings}
 }
 
-func NewAnalyzer(har *HAR) *Analyzer {
-	return &Analyzer{har: har}
+// Settings returns the settings the analyzer was created with.
+func (a *Analyzer) Settings() Settings {
+	return a.settings
 }
 
 func (a *Analyzer) CalculateMetrics() *Metrics {
@@ -38,23 +77,34 @@ func (a *Analyzer) CalculateMetrics() *Metrics {
 
 	metrics := &Metrics{
 		TotalRequests: len(entries),
+		Environment:   a.Environment(),
 	}
 
 	var totalSize int64
 	var totalTime float64
 	var dnsTime, connectTime, sslTime float64
-	var cacheHits int
 	var errorRequests int
 	var thirdPartyRequests int
+	var insecureRequests int
 	var firstByte float64 = -1
 
 	// Get page load time from page timings if available
-	if len(a.har.Log.Pages) > 0 {
-		page := a.har.Log.Pages[0]
-		if page.PageTimings.OnLoad > 0 {
-			metrics.PageLoadTime = float64(page.PageTimings.OnLoad)
+	page := a.Page()
+	if page.PageTimings.OnLoad > 0 {
+		metrics.PageLoadTime = float64(page.PageTimings.OnLoad)
+	}
+	metrics.FirstContentfulPaint = firstMeasured(page.FirstContentfulPaint, page.PageTimings.FirstContentfulPaint)
+	metrics.LargestContentfulPaint = firstMeasured(page.LargestContentfulPaint, page.PageTimings.LargestContentfulPaint)
+	metrics.TotalBlockingTime = firstMeasured(page.TotalBlockingTime, page.PageTimings.TotalBlockingTime)
+	if cls := page.CumulativeLayoutShift; cls != nil || page.PageTimings.CumulativeLayoutShift != nil {
+		if cls == nil {
+			cls = page.PageTimings.CumulativeLayoutShift
 		}
+		metrics.CumulativeLayoutShift = *cls
+		metrics.HasLabMetrics = true
 	}
+	metrics.SpeedIndex = page.SpeedIndex
+	metrics.VisualComplete = page.VisualComplete
 
 	for _, entry := range entries {
 		// Total time and size
@@ -82,126 +132,266 @@ func (a *Analyzer) CalculateMetrics() *Metrics {
 			firstByte = float64(entry.Timings.Wait)
 		}
 
-		// Cache analysis
-		if entry.Cache.BeforeRequest != nil {
-			cacheHits++
-		}
-
 		// Third-party analysis
 		if a.isThirdParty(entry.Request.URL) {
 			thirdPartyRequests++
 		}
+
+		if len(a.InsecureReasons(entry)) > 0 {
+			insecureRequests++
+		}
 	}
 
 	metrics.TotalTime = totalTime
 	metrics.TotalSize = totalSize
-	metrics.TTFB = firstByte
+	metrics.TTFB = math.Max(firstByte, 0)
 	metrics.DNSTime = dnsTime / float64(len(entries))
 	metrics.ConnectTime = connectTime / float64(len(entries))
 	metrics.SSLTime = sslTime / float64(len(entries))
-	metrics.CacheHitRatio = float64(cacheHits) / float64(len(entries)) * 100
 	metrics.ThirdPartyRequests = thirdPartyRequests
 	metrics.ErrorRequests = errorRequests
+	metrics.InsecureRequests = insecureRequests
 
 	// If no page load time from page timings, estimate from entries
 	if metrics.PageLoadTime == 0 {
 		metrics.PageLoadTime = a.calculateEstimatedPageLoadTime()
 	}
 
+	encodings, _ := a.EncodingDistribution()
+	metrics.TextResponses = encodings.Total
+	metrics.BrotliCoverage = encodings.Share("br") + encodings.Share("dcb")
+	metrics.ZstdCoverage = encodings.Share("zstd") + encodings.Share("dcz")
+	if encodings.Total > 0 {
+		metrics.CompressionCoverage = 100 - encodings.Share("identity")
+	}
+
+	for _, hint := range a.PreloadHints() {
+		if hint.Wasted() {
+			metrics.PreloadWastedBytes += int64(hint.Size)
+		}
+	}
+
+	for _, origin := range a.ConnectionAnalysis() {
+		metrics.NewConnections += origin.NewConnections
+		metrics.ConnectionReuse += float64(origin.Reused)
+		metrics.ConnectionSetupWaste += origin.WastedTime
+	}
+	metrics.ConnectionReuse = metrics.ConnectionReuse / float64(len(entries)) * 100
+
+	protocols := a.ProtocolDistribution()
+	metrics.ProtocolRequests = protocols.Known()
+	if known := protocols.Known(); known > 0 {
+		metrics.MultiplexedCoverage = float64(protocols.ByProtocol["HTTP/2"]+protocols.ByProtocol["HTTP/3"]) / float64(known) * 100
+	}
+
+	for _, chain := range a.RedirectChains() {
+		metrics.RedirectLatency += chain.Latency()
+	}
+
+	for _, group := range a.APIRefetches(DefaultRefetchWindow) {
+		metrics.RedundantAPIRequests += group.Redundant
+	}
+
+	repeatView := a.SimulateRepeatView(0, metrics)
+	metrics.RepeatViewLoadTime = repeatView.LoadTime
+	metrics.RepeatViewSize = repeatView.Size
+	metrics.CacheHitRatio = float64(repeatView.Cached) / float64(len(entries)) * 100
+
+	var cdn CDNDomainStats
+	for _, domain := range a.CDNCacheStats() {
+		cdn.Hits += domain.Hits
+		cdn.Misses += domain.Misses
+	}
+	metrics.CDNResponses = cdn.Hits + cdn.Misses
+	metrics.CDNHitRatio = cdn.HitRatio()
+
 	return metrics
 }
 
+// firstMeasured returns the first non-zero value, e.g. a page's own metric
+// before the same metric from its pageTimings extensions.
+func firstMeasured(values ...float64) float64 {
+	for _, value := range values {
+		if value > 0 {
+			return value
+		}
+	}
+	return 0
+}
+
 func (a *Analyzer) GetSlowestRequests(limit int) []Entry {
-	entries := make([]Entry, len(a.har.Log.Entries))
-	copy(entries, a.har.Log.Entries)
+	return a.entriesAt(a.SlowestRequestIndices(limit))
+}
 
-	sort.Slice(entries, func(i, j int) bool {
+// SlowestRequestIndices returns the indices of the limit slowest entries,
+// slowest first.
+func (a *Analyzer) SlowestRequestIndices(limit int) []int {
+	entries := a.har.Log.Entries
+	return rankedIndices(len(entries), limit, func(i, j int) bool {
 		return entries[i].Time > entries[j].Time
 	})
-
-	if limit > len(entries) {
-		limit = len(entries)
-	}
-
-	return entries[:limit]
 }
 
 func (a *Analyzer) GetLargestRequests(limit int) []Entry {
-	entries := make([]Entry, len(a.har.Log.Entries))
-	copy(entries, a.har.Log.Entries)
+	return a.entriesAt(a.LargestRequestIndices(limit))
+}
 
-	sort.Slice(entries, func(i, j int) bool {
+// LargestRequestIndices returns the indices of the limit largest responses,
+// largest first.
+func (a *Analyzer) LargestRequestIndices(limit int) []int {
+	entries := a.har.Log.Entries
+	return rankedIndices(len(entries), limit, func(i, j int) bool {
 		return entries[i].Response.Content.Size > entries[j].Response.Content.Size
 	})
-
-	if limit > len(entries) {
-		limit = len(entries)
-	}
-
-	return entries[:limit]
 }
 
 func (a *Analyzer) GetErrorRequests() []Entry {
-	var errors []Entry
-	for _, entry := range a.har.Log.Entries {
+	return a.entriesAt(a.ErrorRequestIndices())
+}
+
+// ErrorRequestIndices returns the indices of the entries with a 4xx or 5xx
+// status, in capture order.
+func (a *Analyzer) ErrorRequestIndices() []int {
+	var indices []int
+	for i, entry := range a.har.Log.Entries {
 		if entry.Response.Status >= 400 {
-			errors = append(errors, entry)
+			indices = append(indices, i)
 		}
 	}
-	return errors
+	return indices
+}
+
+// rankedIndices returns the first limit of n indices ordered by less, keeping
+// capture order among equals.
+func rankedIndices(n, limit int, less func(i, j int) bool) []int {
+	indices := make([]int, n)
+	for i := range indices {
+		indices[i] = i
+	}
+	sort.SliceStable(indices, func(i, j int) bool {
+		return less(indices[i], indices[j])
+	})
+
+	if limit > n {
+		limit = n
+	}
+
+	return indices[:limit]
+}
+
+func (a *Analyzer) entriesAt(indices []int) []Entry {
+	var entries []Entry
+	for _, index := range indices {
+		entries = append(entries, a.har.Log.Entries[index])
+	}
+	return entries
 }
 
 func (a *Analyzer) GetResourcesByType() map[string][]Entry {
 	resources := make(map[string][]Entry)
 
 	for _, entry := range a.har.Log.Entries {
-		contentType := entry.Response.Content.MimeType
-		if contentType == "" {
-			contentType = "unknown"
-		}
-
-		// Simplify content types
-		if strings.Contains(contentType, "javascript") {
-			contentType = "javascript"
-		} else if strings.Contains(contentType, "css") {
-			contentType = "css"
-		} else if strings.Contains(contentType, "image") {
-			contentType = "image"
-		} else if strings.Contains(contentType, "html") {
-			contentType = "html"
-		} else if strings.Contains(contentType, "json") {
-			contentType = "json"
-		} else if strings.Contains(contentType, "font") {
-			contentType = "font"
-		}
-
+		contentType := ResourceType(entry.Response.Content.MimeType)
 		resources[contentType] = append(resources[contentType], entry)
 	}
 
 	return resources
 }
 
-func (a *Analyzer) isThirdParty(url string) bool {
-	// Simple third-party detection based on common patterns
-	thirdPartyDomains := []string{
-		"googleapis.com",
-		"googletagmanager.com",
-		"facebook.com",
-		"twitter.com",
-		"analytics.google.com",
-		"doubleclick.net",
-		"amazon.com",
-		"cdn.",
-		"cdnjs.",
-	}
-
-	for _, domain := range thirdPartyDomains {
-		if strings.Contains(url, domain) {
-			return true
+// ResourceTypes are the types of the resource breakdown, in display order.
+// "other" holds every MIME type ResourceType does not simplify.
+var ResourceTypes = []string{"javascript", "css", "image", "font", "json", "html", "other"}
+
+// ResourceTypeStats totals the requests of one resource type.
+type ResourceTypeStats struct {
+	Type     string  `json:"type"`
+	Requests int     `json:"requests"`
+	Size     int64   `json:"size"`    // content bytes
+	Time     float64 `json:"time_ms"` // summed over the requests
+}
+
+// AverageSize returns the mean content size of the type's requests.
+func (s ResourceTypeStats) AverageSize() float64 {
+	if s.Requests == 0 {
+		return 0
+	}
+	return float64(s.Size) / float64(s.Requests)
+}
+
+// ResourceBreakdown totals requests, size and time per resource type, in
+// ResourceTypes order, leaving out types without requests.
+func (a *Analyzer) ResourceBreakdown() []ResourceTypeStats {
+	totals := make(map[string]*ResourceTypeStats)
+	for _, resourceType := range ResourceTypes {
+		totals[resourceType] = &ResourceTypeStats{Type: resourceType}
+	}
+	for _, entry := range a.har.Log.Entries {
+		stats := totals[EntryResourceType(entry)]
+		stats.Requests++
+		stats.Size += int64(max(entry.Response.Content.Size, 0))
+		stats.Time += entry.Time
+	}
+
+	var breakdown []ResourceTypeStats
+	for _, resourceType := range ResourceTypes {
+		if totals[resourceType].Requests > 0 {
+			breakdown = append(breakdown, *totals[resourceType])
 		}
 	}
+	return breakdown
+}
 
-	return false
+// EntryResourceType returns which of ResourceTypes the entry's response is.
+func EntryResourceType(entry Entry) string {
+	resourceType := ResourceType(entry.Response.Content.MimeType)
+	for _, known := range ResourceTypes {
+		if resourceType == known {
+			return resourceType
+		}
+	}
+	return "other"
+}
+
+// ResourceType simplifies a MIME type to javascript, css, image, html, json
+// or font, returning other types unchanged and "unknown" for none.
+func ResourceType(mimeType string) string {
+	switch {
+	case mimeType == "":
+		return "unknown"
+	case strings.Contains(mimeType, "javascript"):
+		return "javascript"
+	case strings.Contains(mimeType, "css"):
+		return "css"
+	case strings.Contains(mimeType, "image"):
+		return "image"
+	case strings.Contains(mimeType, "html"):
+		return "html"
+	case strings.Contains(mimeType, "json"):
+		return "json"
+	case strings.Contains(mimeType, "font"):
+		return "font"
+	}
+	return mimeType
+}
+
+// isThirdParty reports whether the URL's host is outside the page's
+// registrable domain. Without a first-party domain it falls back to the blocklist.
+func (a *Analyzer) isThirdParty(rawURL string) bool {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Hostname() == "" {
+		return false
+	}
+	if firstParty := a.FirstPartyDomain(); firstParty != "" {
+		host := strings.ToLower(parsed.Hostname())
+		return host != firstParty && !strings.HasSuffix(host, "."+firstParty)
+	}
+	_, ok := DomainCategory(parsed.Hostname())
+	return ok
+}
+
+// IsThirdParty reports whether an entry was served from another site than the page.
+func (a *Analyzer) IsThirdParty(entry Entry) bool {
+	return a.isThirdParty(entry.Request.URL)
 }
 
 func (a *Analyzer) calculateEstimatedPageLoadTime() float64 {
@@ -227,6 +417,59 @@ func (a *Analyzer) calculateEstimatedPageLoadTime() float64 {
 	return maxEndTime.Sub(minStartTime).Seconds() * 1000 // Convert to milliseconds
 }
 
+// GetHostMedianTimings returns the median of each timing phase across all
+// entries served by the given host.
+func (a *Analyzer) GetHostMedianTimings(host string) Timings {
+	var blocked, dns, connect, ssl, send, wait, receive []int
+
+	for _, entry := range a.har.Log.Entries {
+		if EntryHost(entry) != host {
+			continue
+		}
+		blocked = append(blocked, max(entry.Timings.Blocked, 0))
+		dns = append(dns, max(entry.Timings.DNS, 0))
+		connect = append(connect, max(entry.Timings.Connect, 0))
+		ssl = append(ssl, max(entry.Timings.SSL, 0))
+		send = append(send, max(entry.Timings.Send, 0))
+		wait = append(wait, max(entry.Timings.Wait, 0))
+		receive = append(receive, max(entry.Timings.Receive, 0))
+	}
+
+	return Timings{
+		Blocked: medianInt(blocked),
+		DNS:     medianInt(dns),
+		Connect: medianInt(connect),
+		SSL:     medianInt(ssl),
+		Send:    medianInt(send),
+		Wait:    medianInt(wait),
+		Receive: medianInt(receive),
+	}
+}
+
+// EntryHost returns the host name of the entry's request URL.
+func EntryHost(entry Entry) string {
+	parsed, err := url.Parse(entry.Request.URL)
+	if err != nil {
+		return ""
+	}
+	return parsed.Hostname()
+}
+
+func medianInt(values []int) int {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := make([]int, len(values))
+	copy(sorted, values)
+	sort.Ints(sorted)
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
 func (a *Analyzer) GenerateTimeline() []TimelineEvent {
 	var events []TimelineEvent
 
@@ -251,6 +494,18 @@ func (a *Analyzer) GenerateTimeline() []TimelineEvent {
 	return events
 }
 
+// PageStart returns when the page started loading: the primary page's start
+// time, or the first request's when that is earlier.
+func (a *Analyzer) PageStart() time.Time {
+	start := a.Page().StartedDateTime
+	for _, entry := range a.har.Log.Entries {
+		if start.IsZero() || entry.StartedDateTime.Before(start) {
+			start = entry.StartedDateTime
+		}
+	}
+	return start
+}
+
 type TimelineEvent struct {
 	Index       int
 	URL         string
@@ -0,0 +1,162 @@
+package har
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Domain categories of the bundled dataset. Blocklists loaded from files may
+// use any category name.
+const (
+	CategoryAnalytics  = "analytics"
+	CategoryAds        = "ads"
+	CategorySocial     = "social"
+	CategoryCDN        = "cdn"
+	CategoryTagManager = "tag-manager"
+	CategoryFonts      = "fonts"
+)
+
+//go:embed blocklist.txt
+var bundledBlocklist string
+
+// blocklist maps registered domains to their category. Lookups also match
+// subdomains, so the most specific entry wins.
+var blocklist = parseBundledBlocklist(bundledBlocklist)
+
+func parseBundledBlocklist(data string) map[string]string {
+	domains := make(map[string]string)
+	for _, line := range strings.Split(data, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && !strings.HasPrefix(fields[0], "#") {
+			domains[strings.ToLower(fields[0])] = fields[1]
+		}
+	}
+	return domains
+}
+
+// DomainCategory returns the category of a host, matching parent domains, e.g.
+// "ssl.google-analytics.com" is analytics. Hosts on a "cdn" subdomain not in
+// any list are categorized as CDN.
+func DomainCategory(host string) (string, bool) {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	for domain := host; domain != ""; {
+		if category, ok := blocklist[domain]; ok {
+			return category, true
+		}
+		_, parent, found := strings.Cut(domain, ".")
+		if !found {
+			break
+		}
+		domain = parent
+	}
+
+	if strings.HasPrefix(host, "cdn.") || strings.HasPrefix(host, "cdnjs.") {
+		return CategoryCDN, true
+	}
+	return "", false
+}
+
+// ResetBlocklist drops the domains added with LoadBlocklist, leaving the
+// bundled list.
+func ResetBlocklist() {
+	blocklist = parseBundledBlocklist(bundledBlocklist)
+}
+
+// LoadBlocklist adds every domain of a blocklist file under the given
+// category. It understands Adblock-style lists such as EasyList and
+// EasyPrivacy ("||domain^" rules; element hiding, exception and path rules
+// are skipped), hosts files ("0.0.0.0 domain") and plain domain-per-line lists.
+func LoadBlocklist(path, category string) (int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open blocklist: %w", err)
+	}
+	defer file.Close()
+
+	added := 0
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if domain := blocklistDomain(scanner.Text()); domain != "" {
+			blocklist[domain] = category
+			added++
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return added, fmt.Errorf("failed to read blocklist: %w", err)
+	}
+
+	return added, nil
+}
+
+func blocklistDomain(line string) string {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "!") || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "[") ||
+		strings.HasPrefix(line, "@@") || strings.Contains(line, "##") || strings.Contains(line, "#@#") {
+		return ""
+	}
+
+	if rule, ok := strings.CutPrefix(line, "||"); ok {
+		rule, _, _ = strings.Cut(rule, "$")
+		domain, ok := strings.CutSuffix(rule, "^")
+		if !ok || strings.ContainsAny(domain, "/*") {
+			return ""
+		}
+		return strings.ToLower(domain)
+	}
+
+	fields := strings.Fields(line)
+	switch {
+	case len(fields) >= 2 && (fields[0] == "0.0.0.0" || fields[0] == "127.0.0.1" || fields[0] == "::"):
+		if fields[1] == "localhost" || fields[1] == "0.0.0.0" {
+			return ""
+		}
+		return strings.ToLower(fields[1])
+	case len(fields) == 1 && strings.Contains(line, ".") && !strings.ContainsAny(line, "/*^|$"):
+		return strings.ToLower(line)
+	}
+	return ""
+}
+
+// CategoryStats totals the requests to one category of third-party domains.
+type CategoryStats struct {
+	Category string
+	Requests int
+	Size     int64
+	Time     float64
+}
+
+// GetCategoryStats groups categorized third-party requests, largest category first.
+func (a *Analyzer) GetCategoryStats() []CategoryStats {
+	byCategory := make(map[string]*CategoryStats)
+	for _, entry := range a.har.Log.Entries {
+		category, ok := DomainCategory(EntryHost(entry))
+		if !ok || !a.isThirdParty(entry.Request.URL) {
+			continue
+		}
+		stats, ok := byCategory[category]
+		if !ok {
+			stats = &CategoryStats{Category: category}
+			byCategory[category] = stats
+		}
+		stats.Requests++
+		stats.Size += int64(entry.Response.Content.Size)
+		stats.Time += entry.Time
+	}
+
+	result := make([]CategoryStats, 0, len(byCategory))
+	for _, stats := range byCategory {
+		result = append(result, *stats)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Size != result[j].Size {
+			return result[i].Size > result[j].Size
+		}
+		return result[i].Category < result[j].Category
+	})
+	return result
+}
@@ -0,0 +1,71 @@
+package har
+
+import (
+	"mime"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// bodyExtensions are the extensions for common response types, where
+// mime.ExtensionsByType would offer several (.jpe, .jpeg, .jpg).
+var bodyExtensions = map[string]string{
+	"application/javascript":    ".js",
+	"application/json":          ".json",
+	"application/manifest+json": ".webmanifest",
+	"application/octet-stream":  ".bin",
+	"application/pdf":           ".pdf",
+	"application/wasm":          ".wasm",
+	"application/xml":           ".xml",
+	"application/x-javascript":  ".js",
+	"font/otf":                  ".otf",
+	"font/ttf":                  ".ttf",
+	"font/woff":                 ".woff",
+	"font/woff2":                ".woff2",
+	"image/avif":                ".avif",
+	"image/gif":                 ".gif",
+	"image/jpeg":                ".jpg",
+	"image/png":                 ".png",
+	"image/svg+xml":             ".svg",
+	"image/webp":                ".webp",
+	"image/x-icon":              ".ico",
+	"image/vnd.microsoft.icon":  ".ico",
+	"text/css":                  ".css",
+	"text/csv":                  ".csv",
+	"text/html":                 ".html",
+	"text/javascript":           ".js",
+	"text/plain":                ".txt",
+	"text/xml":                  ".xml",
+	"video/mp4":                 ".mp4",
+	"video/webm":                ".webm",
+}
+
+// BodyExtension picks a file extension for a response body: from its MIME
+// type, then the URL path, falling back to .bin.
+func BodyExtension(mimeType, rawURL string) string {
+	mediaType, _, err := mime.ParseMediaType(mimeType)
+	if err == nil {
+		if ext, ok := bodyExtensions[mediaType]; ok {
+			return ext
+		}
+		if strings.HasSuffix(mediaType, "+json") {
+			return ".json"
+		}
+		if strings.HasSuffix(mediaType, "+xml") {
+			return ".xml"
+		}
+	}
+
+	if parsed, err := url.Parse(rawURL); err == nil {
+		if ext := path.Ext(parsed.Path); ext != "" && len(ext) <= 6 {
+			return strings.ToLower(ext)
+		}
+	}
+
+	if err == nil {
+		if exts, _ := mime.ExtensionsByType(mediaType); len(exts) > 0 {
+			return exts[0]
+		}
+	}
+	return ".bin"
+}
@@ -0,0 +1,90 @@
+package har
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// bodySnippetContext is how many characters a snippet keeps on each side of
+// the match.
+const bodySnippetContext = 40
+
+var whitespace = regexp.MustCompile(`\s+`)
+
+// BodyMatch is a request or response body that matched a search.
+type BodyMatch struct {
+	EntryIndex int
+	Response   bool // the response body matched, not the request body
+	Count      int  // matches in the body
+	// Before, Match and After are the first match with its context, on one
+	// line
+	Before string
+	Match  string
+	After  string
+}
+
+// ParseBodySearch compiles a body search: text between slashes is a regular
+// expression, e.g. /timeout|refused/, and anything else is matched
+// literally, ignoring case.
+func ParseBodySearch(query string) (*regexp.Regexp, error) {
+	if len(query) > 2 && strings.HasPrefix(query, "/") && strings.HasSuffix(query, "/") {
+		pattern, err := regexp.Compile(query[1 : len(query)-1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression %s: %w", query, err)
+		}
+		return pattern, nil
+	}
+	return regexp.MustCompile("(?i)" + regexp.QuoteMeta(query)), nil
+}
+
+// SearchBodies finds the entries whose request or response body matches the
+// pattern, with base64 responses decoded. Binary bodies are skipped.
+func SearchBodies(entries []Entry, pattern *regexp.Regexp) []BodyMatch {
+	var matches []BodyMatch
+	for i, entry := range entries {
+		if entry.Request.PostData != nil {
+			if match, ok := searchBody(entry.Request.PostData.Text, pattern); ok {
+				match.EntryIndex = i
+				matches = append(matches, match)
+			}
+		}
+		if match, ok := searchBody(ResponseBody(entry), pattern); ok {
+			match.EntryIndex, match.Response = i, true
+			matches = append(matches, match)
+		}
+	}
+	return matches
+}
+
+func searchBody(body string, pattern *regexp.Regexp) (BodyMatch, bool) {
+	if body == "" || !utf8.ValidString(body) {
+		return BodyMatch{}, false
+	}
+	locations := pattern.FindAllStringIndex(body, -1)
+	if len(locations) == 0 || locations[0][0] == locations[0][1] {
+		return BodyMatch{}, false
+	}
+
+	start, end := locations[0][0], locations[0][1]
+	before := []rune(body[:start])
+	after := []rune(body[end:])
+	match := BodyMatch{Count: len(locations), Match: oneLine(body[start:end])}
+	if len(before) > bodySnippetContext {
+		match.Before = "…" + oneLine(string(before[len(before)-bodySnippetContext:]))
+	} else {
+		match.Before = oneLine(string(before))
+	}
+	if len(after) > bodySnippetContext {
+		match.After = oneLine(string(after[:bodySnippetContext])) + "…"
+	} else {
+		match.After = oneLine(string(after))
+	}
+	return match, true
+}
+
+// oneLine collapses runs of whitespace, line breaks included, to a space.
+func oneLine(text string) string {
+	return whitespace.ReplaceAllString(text, " ")
+}
@@ -0,0 +1,286 @@
+package har
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CachePolicy is what a browser cache would make of a response's headers.
+type CachePolicy struct {
+	NoStore      bool
+	NoCache      bool
+	Immutable    bool
+	Cacheable    bool          // method and status allow the response to be stored
+	Lifetime     time.Duration // freshness lifetime minus the response's age
+	Heuristic    bool          // lifetime derived from Last-Modified
+	HasValidator bool          // ETag or Last-Modified allows a conditional request
+}
+
+// CacheOutcome is how a response is served on a repeat visit.
+type CacheOutcome int
+
+const (
+	CacheHit        CacheOutcome = iota // served from cache without a request
+	CacheRevalidate                     // conditional request answered with 304
+	CacheRefetch                        // downloaded again in full
+)
+
+func (o CacheOutcome) String() string {
+	switch o {
+	case CacheHit:
+		return "cached"
+	case CacheRevalidate:
+		return "revalidated"
+	default:
+		return "refetched"
+	}
+}
+
+// Statuses a cache may store without explicit freshness (RFC 9110 section 15.1).
+var heuristicallyCacheable = map[int]bool{
+	200: true, 203: true, 204: true, 206: true, 300: true, 301: true, 308: true,
+	404: true, 405: true, 410: true, 414: true, 501: true,
+}
+
+// ParseCachePolicy reads Cache-Control, Expires, Date, Age, ETag and
+// Last-Modified from the entry's response.
+func ParseCachePolicy(entry Entry) CachePolicy {
+	headers := entry.Response.Headers
+	policy := CachePolicy{
+		Cacheable: entry.Request.Method == "GET" && heuristicallyCacheable[entry.Response.Status],
+	}
+
+	_, hasETag := HeaderValue(headers, "ETag")
+	lastModified, hasLastModified := HeaderValue(headers, "Last-Modified")
+	policy.HasValidator = hasETag || hasLastModified
+
+	maxAge := -1
+	if cacheControl, ok := HeaderValue(headers, "Cache-Control"); ok {
+		for _, directive := range strings.Split(cacheControl, ",") {
+			name, value, _ := strings.Cut(strings.TrimSpace(directive), "=")
+			switch strings.ToLower(name) {
+			case "no-store":
+				policy.NoStore = true
+			case "no-cache":
+				policy.NoCache = true
+			case "immutable":
+				policy.Immutable = true
+			case "max-age":
+				if seconds, err := strconv.Atoi(strings.Trim(value, `"`)); err == nil {
+					maxAge = seconds
+				}
+			}
+		}
+	}
+	if policy.NoStore {
+		policy.Cacheable = false
+		return policy
+	}
+
+	date := entry.StartedDateTime
+	if value, ok := HeaderValue(headers, "Date"); ok {
+		if parsed, err := http.ParseTime(value); err == nil {
+			date = parsed
+		}
+	}
+
+	switch {
+	case maxAge >= 0:
+		policy.Lifetime = time.Duration(maxAge) * time.Second
+	case hasHeader(headers, "Expires"):
+		expiresValue, _ := HeaderValue(headers, "Expires")
+		// An invalid Expires, such as "0", means already expired
+		if expires, err := http.ParseTime(expiresValue); err == nil {
+			policy.Lifetime = expires.Sub(date)
+		}
+	case hasLastModified:
+		if modified, err := http.ParseTime(lastModified); err == nil && date.After(modified) {
+			policy.Lifetime = date.Sub(modified) / 10
+			policy.Heuristic = true
+		}
+	}
+
+	if value, ok := HeaderValue(headers, "Age"); ok {
+		if age, err := strconv.Atoi(value); err == nil {
+			policy.Lifetime -= time.Duration(age) * time.Second
+		}
+	}
+	if policy.Lifetime < 0 {
+		policy.Lifetime = 0
+	}
+
+	return policy
+}
+
+// RepeatOutcome predicts how the response is served when the page is visited
+// again after the given delay. immutable only spares revalidation while the
+// response is fresh, so a stale immutable response is revalidated too.
+func (p CachePolicy) RepeatOutcome(after time.Duration) CacheOutcome {
+	if !p.Cacheable {
+		return CacheRefetch
+	}
+	if !p.NoCache && p.Lifetime > after {
+		return CacheHit
+	}
+	if p.HasValidator {
+		return CacheRevalidate
+	}
+	return CacheRefetch
+}
+
+func hasHeader(headers []Header, name string) bool {
+	_, ok := HeaderValue(headers, name)
+	return ok
+}
+
+// RepeatView estimates a repeat visit from the first view's cache headers.
+type RepeatView struct {
+	After       time.Duration
+	Cached      int
+	Revalidated int
+	Refetched   int
+	LoadTime    float64 // estimated repeat-view load time in ms
+	Size        int64   // bytes downloaded on the repeat visit
+}
+
+// SimulateRepeatView replays the capture against a browser cache primed by
+// it, visiting again after the given delay. Cached responses cost nothing,
+// revalidations cost a round trip and their headers, and everything else is
+// downloaded again. Each request keeps its start on the timeline, and the
+// load time is the first view's less the network time before onLoad that
+// the repeat visit no longer spends, so time a cached response overlapped
+// with a download that still happens is not saved.
+func (a *Analyzer) SimulateRepeatView(after time.Duration, firstView *Metrics) RepeatView {
+	view := RepeatView{After: after}
+	deadline := a.onLoadTime()
+
+	var first, repeat []interval
+	for _, entry := range a.har.Log.Entries {
+		span := interval{entry.StartedDateTime, entryEnd(entry)}
+		if clipped, ok := span.clip(deadline); ok {
+			first = append(first, clipped)
+		}
+
+		switch ParseCachePolicy(entry).RepeatOutcome(after) {
+		case CacheHit:
+			view.Cached++
+			continue
+		case CacheRevalidate:
+			view.Revalidated++
+			roundTrip := float64(max(entry.Timings.Send, 0) + max(entry.Timings.Wait, 0))
+			span.end = span.start.Add(time.Duration(roundTrip * float64(time.Millisecond)))
+			view.Size += int64(max(entry.Response.HeadersSize, 0))
+		default:
+			view.Refetched++
+			view.Size += int64(entry.Response.Content.Size)
+		}
+		if clipped, ok := span.clip(deadline); ok {
+			repeat = append(repeat, clipped)
+		}
+	}
+
+	if firstView != nil && firstView.PageLoadTime > 0 {
+		saved := float64(coveredTime(first)-coveredTime(repeat)) / float64(time.Millisecond)
+		view.LoadTime = max(firstView.PageLoadTime-saved, 0)
+	}
+
+	return view
+}
+
+// ShortCacheTTL flags static assets that expire within an hour, so most
+// repeat visits revalidate or download them again.
+const ShortCacheTTL = time.Hour
+
+// CacheIssue is a response whose cache headers cost repeat visits.
+type CacheIssue struct {
+	EntryIndex int
+	URL        string
+	Size       int
+	Issue      string
+}
+
+// CacheReport summarizes how cacheable a capture's responses are.
+type CacheReport struct {
+	Requests      int
+	Cacheable     int // reusable without a download on an immediate repeat visit
+	Uncacheable   int // no-store, or no freshness and no validator
+	ShortTTL      int // static assets fresh for less than ShortCacheTTL
+	Heuristic     int // freshness guessed from Last-Modified
+	Revalidations int // conditional requests answered with 304 in the capture
+
+	RevalidationTime float64 // ms spent on 304 round trips
+	SavedBytes       int64   // not downloaded again on an immediate repeat visit
+	SavedTime        float64 // estimated repeat-view load time saved, in ms
+	Issues           []CacheIssue
+}
+
+// CacheAnalysis evaluates the Cache-Control, Expires, ETag and Last-Modified
+// headers of every response instead of relying on the cache fields of the
+// HAR, which browsers rarely fill in.
+func (a *Analyzer) CacheAnalysis(firstView *Metrics) CacheReport {
+	report := CacheReport{Requests: len(a.har.Log.Entries)}
+
+	for i, entry := range a.har.Log.Entries {
+		if entry.Response.Status == 304 {
+			report.Revalidations++
+			report.RevalidationTime += entry.Time
+		}
+
+		policy := ParseCachePolicy(entry)
+		issue := ""
+		switch {
+		case policy.RepeatOutcome(0) == CacheHit:
+			report.Cacheable++
+			if policy.Heuristic {
+				report.Heuristic++
+				issue = "no explicit lifetime, freshness guessed from Last-Modified"
+			} else if isStaticAsset(entry) && !policy.Immutable && policy.Lifetime < ShortCacheTTL {
+				report.ShortTTL++
+				issue = fmt.Sprintf("short cache lifetime (%s)", policy.Lifetime)
+			}
+		case policy.NoStore:
+			report.Uncacheable++
+			issue = "no-store"
+		case !policy.HasValidator && entry.Request.Method == "GET" && entry.Response.Status == 200:
+			report.Uncacheable++
+			issue = "no freshness lifetime or validator"
+		}
+
+		if issue != "" && isStaticAsset(entry) {
+			report.Issues = append(report.Issues, CacheIssue{
+				EntryIndex: i,
+				URL:        entry.Request.URL,
+				Size:       entry.Response.Content.Size,
+				Issue:      issue,
+			})
+		}
+	}
+
+	if firstView != nil {
+		repeatView := a.SimulateRepeatView(0, firstView)
+		report.SavedBytes = max(firstView.TotalSize-repeatView.Size, 0)
+		report.SavedTime = max(firstView.PageLoadTime-repeatView.LoadTime, 0)
+	}
+
+	sort.SliceStable(report.Issues, func(i, j int) bool {
+		return report.Issues[i].Size > report.Issues[j].Size
+	})
+
+	return report
+}
+
+// isStaticAsset reports whether a response is a script, stylesheet, image or
+// font, which should normally be cached for a long time.
+func isStaticAsset(entry Entry) bool {
+	mimeType := entry.Response.Content.MimeType
+	for _, kind := range []string{"javascript", "css", "image", "font"} {
+		if strings.Contains(mimeType, kind) {
+			return true
+		}
+	}
+	return false
+}
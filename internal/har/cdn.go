@@ -0,0 +1,183 @@
+package har
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// EdgeCacheStatus is how a CDN edge answered a request.
+type EdgeCacheStatus string
+
+const (
+	EdgeHit     EdgeCacheStatus = "HIT"
+	EdgeMiss    EdgeCacheStatus = "MISS"
+	EdgeBypass  EdgeCacheStatus = "BYPASS"
+	EdgeUnknown EdgeCacheStatus = ""
+)
+
+// edgeStatuses maps the cache status keywords CDNs use to edge statuses.
+// Stale and revalidated responses were served from the edge, expired ones
+// went to the origin.
+var edgeStatuses = map[string]EdgeCacheStatus{
+	"hit": EdgeHit, "stale": EdgeHit, "updating": EdgeHit, "revalidated": EdgeHit, "refreshhit": EdgeHit,
+	"tcp_hit": EdgeHit, "tcp_mem_hit": EdgeHit, "tcp_refresh_hit": EdgeHit, "tcp_ims_hit": EdgeHit, "prerender": EdgeHit,
+	"miss": EdgeMiss, "expired": EdgeMiss, "tcp_miss": EdgeMiss, "tcp_refresh_miss": EdgeMiss, "pass": EdgeBypass,
+	"bypass": EdgeBypass, "dynamic": EdgeBypass, "none": EdgeBypass, "uncacheable": EdgeBypass,
+}
+
+// EdgeCache classifies a response as an edge HIT, MISS or BYPASS from CDN
+// headers (cf-cache-status, x-vercel-cache, x-cache, x-cache-status and the
+// standard Cache-Status), and names the CDN when its headers give it away. A
+// response with a positive Age and no status header is counted as a hit,
+// since only a shared cache adds Age.
+func EdgeCache(entry Entry) (provider string, status EdgeCacheStatus) {
+	headers := entry.Response.Headers
+	provider = cdnProvider(headers)
+
+	for _, name := range []string{"cf-cache-status", "x-vercel-cache", "x-nextjs-cache", "x-cache", "x-cache-status", "x-proxy-cache"} {
+		if value, ok := HeaderValue(headers, name); ok {
+			if status := parseEdgeStatus(value); status != EdgeUnknown {
+				return provider, status
+			}
+		}
+	}
+	if value, ok := HeaderValue(headers, "Cache-Status"); ok {
+		if status := parseCacheStatusHeader(value); status != EdgeUnknown {
+			return provider, status
+		}
+	}
+	if value, ok := HeaderValue(headers, "Age"); ok {
+		if age, err := strconv.Atoi(strings.TrimSpace(value)); err == nil && age > 0 {
+			return provider, EdgeHit
+		}
+	}
+	return provider, EdgeUnknown
+}
+
+// parseEdgeStatus reads values such as "HIT", "Hit from cloudfront",
+// "TCP_MEM_HIT from a23-1-2-3" or Fastly's shielded "MISS, HIT", where the
+// last value is the edge closest to the client.
+func parseEdgeStatus(value string) EdgeCacheStatus {
+	values := strings.Split(value, ",")
+	fields := strings.Fields(values[len(values)-1])
+	if len(fields) == 0 {
+		return EdgeUnknown
+	}
+	return edgeStatuses[strings.ToLower(fields[0])]
+}
+
+// parseCacheStatusHeader reads an RFC 9211 Cache-Status header, whose last
+// member is the cache closest to the client.
+func parseCacheStatusHeader(value string) EdgeCacheStatus {
+	members := strings.Split(value, ",")
+	params := strings.Split(members[len(members)-1], ";")
+	for _, param := range params[1:] {
+		name, argument, _ := strings.Cut(strings.TrimSpace(param), "=")
+		switch strings.ToLower(name) {
+		case "hit":
+			return EdgeHit
+		case "fwd":
+			if argument == "bypass" {
+				return EdgeBypass
+			}
+			return EdgeMiss
+		}
+	}
+	return EdgeUnknown
+}
+
+// cdnProvider names the CDN that served a response, or "" when unknown.
+func cdnProvider(headers []Header) string {
+	has := func(name string) bool {
+		_, ok := HeaderValue(headers, name)
+		return ok
+	}
+	server, _ := HeaderValue(headers, "Server")
+	via, _ := HeaderValue(headers, "Via")
+	servedBy, _ := HeaderValue(headers, "X-Served-By")
+	server, via = strings.ToLower(server), strings.ToLower(via)
+
+	switch {
+	case has("cf-cache-status") || has("cf-ray") || server == "cloudflare":
+		return "Cloudflare"
+	case has("x-amz-cf-id") || has("x-amz-cf-pop") || strings.Contains(via, "cloudfront"):
+		return "CloudFront"
+	case strings.HasPrefix(servedBy, "cache-") || has("fastly-debug-digest") || has("x-fastly-request-id"):
+		return "Fastly"
+	case strings.Contains(server, "akamai") || has("x-akamai-transformed"):
+		return "Akamai"
+	case has("x-vercel-cache") || has("x-vercel-id"):
+		return "Vercel"
+	case has("x-nf-request-id"):
+		return "Netlify"
+	case strings.Contains(via, "google") || server == "google frontend":
+		return "Google Cloud CDN"
+	case has("x-azure-ref"):
+		return "Azure Front Door"
+	case strings.Contains(via, "varnish") || has("x-varnish"):
+		return "Varnish"
+	}
+	return ""
+}
+
+// CDNDomainStats counts the edge cache outcomes of one domain's responses.
+type CDNDomainStats struct {
+	Domain   string `json:"domain"`
+	Provider string `json:"provider,omitempty"`
+	Requests int    `json:"requests"`
+	Hits     int    `json:"hits"`
+	Misses   int    `json:"misses"`
+	Bypasses int    `json:"bypasses"`
+}
+
+// HitRatio is the share of cacheable edge responses (hits and misses) that
+// were hits, in percent.
+func (s CDNDomainStats) HitRatio() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Hits+s.Misses) * 100
+}
+
+// CDNCacheStats returns edge cache outcomes per domain, for domains with at
+// least one classified response, most requests first.
+func (a *Analyzer) CDNCacheStats() []CDNDomainStats {
+	byDomain := map[string]*CDNDomainStats{}
+	for _, entry := range a.har.Log.Entries {
+		provider, status := EdgeCache(entry)
+		if status == EdgeUnknown {
+			continue
+		}
+		domain := EntryHost(entry)
+		stats, ok := byDomain[domain]
+		if !ok {
+			stats = &CDNDomainStats{Domain: domain}
+			byDomain[domain] = stats
+		}
+		if stats.Provider == "" {
+			stats.Provider = provider
+		}
+		stats.Requests++
+		switch status {
+		case EdgeHit:
+			stats.Hits++
+		case EdgeMiss:
+			stats.Misses++
+		case EdgeBypass:
+			stats.Bypasses++
+		}
+	}
+
+	result := make([]CDNDomainStats, 0, len(byDomain))
+	for _, stats := range byDomain {
+		result = append(result, *stats)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Requests != result[j].Requests {
+			return result[i].Requests > result[j].Requests
+		}
+		return result[i].Domain < result[j].Domain
+	})
+	return result
+}
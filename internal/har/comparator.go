@@ -10,13 +10,42 @@ type Comparison struct {
 	Metrics     []*Metrics
 	Differences []MetricDifference
 	Summary     ComparisonSummary
+	Warnings    []string
+	Insights    []Insight
 }
 
+// NotAvailable is shown in place of values and changes that cannot be computed,
+// e.g. for captures without entries.
+const NotAvailable = "N/A"
+
 type MetricDifference struct {
+	ID           string
 	Name         string
 	Values       []interface{}
 	Changes      []string
 	Improvements []bool
+	// Direction is the metric's: changes of Neutral metrics are shown but
+	// are neither better nor worse
+	Direction Direction
+	// Noise marks changes smaller than the metric's NoiseThreshold, which
+	// count as unchanged
+	Noise []bool
+}
+
+// Flagged reports whether the file at index changed by more than noise
+// against the baseline.
+func (d MetricDifference) Flagged(index int) bool {
+	if index <= 0 || index >= len(d.Changes) {
+		return false
+	}
+	change := d.Changes[index]
+	return change != "No change" && change != NotAvailable && !d.Noise[index]
+}
+
+// Regressed reports whether the file at index got worse than the baseline by
+// more than noise. Changes of Neutral metrics never regress.
+func (d MetricDifference) Regressed(index int) bool {
+	return d.Flagged(index) && d.Direction != Neutral && !d.Improvements[index]
 }
 
 type ComparisonSummary struct {
@@ -27,14 +56,16 @@ type ComparisonSummary struct {
 }
 
 type Comparator struct {
-	files   []string
-	metrics []*Metrics
+	files    []string
+	metrics  []*Metrics
+	settings Settings
 }
 
-func NewComparator(files []string, metrics []*Metrics) *Comparator {
+func NewComparator(files []string, metrics []*Metrics, settings Settings) *Comparator {
 	return &Comparator{
-		files:   files,
-		metrics: metrics,
+		files:    files,
+		metrics:  metrics,
+		settings: settings,
 	}
 }
 
@@ -47,163 +78,141 @@ func (c *Comparator) Compare() *Comparison {
 	}
 
 	comparison := &Comparison{
-		Files:   c.files,
-		Metrics: c.metrics,
+		Files:    c.files,
+		Metrics:  c.metrics,
+		Warnings: c.dataWarnings(),
 	}
 
-	// Compare key metrics
-	comparison.Differences = []MetricDifference{
-		c.compareFloat("Total Load Time", "ms", extractPageLoadTime),
-		c.compareFloat("Time to First Byte", "ms", extractTTFB),
-		c.compareFloat("Average DNS Time", "ms", extractDNSTime),
-		c.compareFloat("Average Connect Time", "ms", extractConnectTime),
-		c.compareFloat("Average SSL Time", "ms", extractSSLTime),
-		c.compareInt("Total Requests", "", extractTotalRequests),
-		c.compareInt("Error Requests", "", extractErrorRequests),
-		c.compareInt("Third-party Requests", "", extractThirdPartyRequests),
-		c.compareFloat("Cache Hit Ratio", "%", extractCacheHitRatio),
-		c.compareSize("Total Transfer Size", extractTotalSize),
+	// Compare every registered metric
+	for _, descriptor := range metricRegistry {
+		if !c.anyMeasured(descriptor) {
+			continue
+		}
+		comparison.Differences = append(comparison.Differences, c.compareMetric(descriptor))
 	}
 
 	// Calculate summary
 	comparison.Summary = c.calculateSummary(comparison.Differences)
+	comparison.Insights = c.insights(comparison.Differences)
 
 	return comparison
 }
 
-func (c *Comparator) compareFloat(name, unit string, extractor func(*Metrics) float64) MetricDifference {
-	values := make([]interface{}, len(c.metrics))
-	changes := make([]string, len(c.metrics))
-	improvements := make([]bool, len(c.metrics))
-
-	for i, metric := range c.metrics {
-		value := extractor(metric)
-		values[i] = fmt.Sprintf("%.1f%s", value, unit)
-
-		if i > 0 {
-			baseValue := extractor(c.metrics[0])
-			change := value - baseValue
-			changePercent := 0.0
-			if baseValue != 0 {
-				changePercent = (change / baseValue) * 100
-			}
-
-			if math.Abs(changePercent) < 0.1 {
-				changes[i] = "No change"
-				improvements[i] = false
-			} else if changePercent > 0 {
-				changes[i] = fmt.Sprintf("+%.1f%%", changePercent)
-				improvements[i] = isImprovementFloat(name, change)
-			} else {
-				changes[i] = fmt.Sprintf("%.1f%%", changePercent)
-				improvements[i] = isImprovementFloat(name, change)
-			}
-		} else {
-			changes[i] = "Baseline"
-			improvements[i] = false
-		}
+// anyMeasured reports whether any file measured the metric. Built-in HAR
+// metrics always are, except when no file has entries.
+func (c *Comparator) anyMeasured(descriptor MetricDescriptor) bool {
+	if descriptor.Available == nil {
+		return true
 	}
-
-	return MetricDifference{
-		Name:         name,
-		Values:       values,
-		Changes:      changes,
-		Improvements: improvements,
+	for _, metric := range c.metrics {
+		if descriptor.Measured(metric) {
+			return true
+		}
 	}
+	return false
 }
 
-func (c *Comparator) compareInt(name, unit string, extractor func(*Metrics) int) MetricDifference {
+func (c *Comparator) compareMetric(descriptor MetricDescriptor) MetricDifference {
 	values := make([]interface{}, len(c.metrics))
 	changes := make([]string, len(c.metrics))
 	improvements := make([]bool, len(c.metrics))
+	noise := make([]bool, len(c.metrics))
+
+	baseValue := descriptor.Value(c.metrics[0])
+	baseHasData := descriptor.Measured(c.metrics[0])
 
 	for i, metric := range c.metrics {
-		value := extractor(metric)
-		if unit != "" {
-			values[i] = fmt.Sprintf("%d %s", value, unit)
-		} else {
-			values[i] = fmt.Sprintf("%d", value)
+		if !descriptor.Measured(metric) {
+			values[i] = NotAvailable
+			changes[i] = NotAvailable
+			continue
 		}
 
-		if i > 0 {
-			baseValue := extractor(c.metrics[0])
-			change := value - baseValue
-			changePercent := 0.0
-			if baseValue != 0 {
-				changePercent = (float64(change) / float64(baseValue)) * 100
-			}
+		value := descriptor.Value(metric)
+		values[i] = descriptor.Format(value, c.settings.SizeUnits)
 
-			if change == 0 {
-				changes[i] = "No change"
-				improvements[i] = false
-			} else if change > 0 {
-				changes[i] = fmt.Sprintf("+%d (+%.1f%%)", change, changePercent)
-				improvements[i] = isImprovementInt(name, change)
-			} else {
-				changes[i] = fmt.Sprintf("%d (%.1f%%)", change, changePercent)
-				improvements[i] = isImprovementInt(name, change)
-			}
-		} else {
+		if i == 0 {
 			changes[i] = "Baseline"
-			improvements[i] = false
+			continue
+		}
+		if !baseHasData {
+			changes[i] = NotAvailable
+			continue
 		}
-	}
-
-	return MetricDifference{
-		Name:         name,
-		Values:       values,
-		Changes:      changes,
-		Improvements: improvements,
-	}
-}
-
-func (c *Comparator) compareSize(name string, extractor func(*Metrics) int64) MetricDifference {
-	values := make([]interface{}, len(c.metrics))
-	changes := make([]string, len(c.metrics))
-	improvements := make([]bool, len(c.metrics))
 
-	for i, metric := range c.metrics {
-		value := extractor(metric)
-		values[i] = formatSize(int(value))
+		change := value - baseValue
+		changePercent := 0.0
+		if baseValue != 0 {
+			changePercent = (change / baseValue) * 100
+		}
 
-		if i > 0 {
-			baseValue := extractor(c.metrics[0])
-			change := value - baseValue
-			changePercent := 0.0
-			if baseValue != 0 {
-				changePercent = (float64(change) / float64(baseValue)) * 100
+		switch descriptor.Kind {
+		case CountMetric:
+			if change == 0 {
+				changes[i] = "No change"
+				continue
 			}
-
+			changes[i] = fmt.Sprintf("%+d (%s)", int(change), formatPercentChange(changePercent, baseValue))
+		case SizeMetric:
 			if change == 0 {
 				changes[i] = "No change"
-				improvements[i] = false
-			} else if change > 0 {
-				changes[i] = fmt.Sprintf("+%s (+%.1f%%)", formatSize(int(change)), changePercent)
-				improvements[i] = change < 0 // Smaller size is better
+				continue
+			}
+			sign := "+"
+			if change < 0 {
+				sign = "-"
+			}
+			changes[i] = fmt.Sprintf("%s%s (%s)", sign, c.settings.SizeUnits.FormatSize(int(math.Abs(change))), formatPercentChange(changePercent, baseValue))
+		default:
+			if baseValue == 0 {
+				// A percentage of zero is undefined, so report the absolute change
+				if change == 0 {
+					changes[i] = "No change"
+					continue
+				}
+				changes[i] = fmt.Sprintf("%+.1f%s", change, descriptor.Unit)
 			} else {
-				changes[i] = fmt.Sprintf("-%s (%.1f%%)", formatSize(int(-change)), changePercent)
-				improvements[i] = change < 0 // Smaller size is better
+				if math.Abs(changePercent) < 0.1 {
+					changes[i] = "No change"
+					continue
+				}
+				changes[i] = fmt.Sprintf("%+.1f%%", changePercent)
 			}
-		} else {
-			changes[i] = "Baseline"
-			improvements[i] = false
 		}
+		if !c.settings.NoiseThreshold(descriptor).Exceeded(change, baseValue) {
+			noise[i] = true
+			changes[i] += " (noise)"
+			continue
+		}
+		improvements[i] = descriptor.IsImprovement(change)
 	}
 
 	return MetricDifference{
-		Name:         name,
+		ID:           descriptor.ID,
+		Name:         descriptor.Name,
 		Values:       values,
 		Changes:      changes,
 		Improvements: improvements,
+		Direction:    descriptor.Direction,
+		Noise:        noise,
 	}
 }
 
+// calculateSummary counts the better, worse and unchanged metrics of every
+// file against the baseline. Neutral metrics such as the request count are
+// neither better nor worse when they change, so they are left out.
 func (c *Comparator) calculateSummary(differences []MetricDifference) ComparisonSummary {
 	var better, worse, unchanged int
 
 	for _, diff := range differences {
+		if diff.Direction == Neutral {
+			continue
+		}
 		for i := 1; i < len(diff.Improvements); i++ {
-			if diff.Changes[i] == "No change" {
+			if diff.Changes[i] == NotAvailable {
+				continue
+			}
+			if !diff.Flagged(i) {
 				unchanged++
 			} else if diff.Improvements[i] {
 				better++
@@ -221,56 +230,30 @@ func (c *Comparator) calculateSummary(differences []MetricDifference) Comparison
 	}
 }
 
-// Extractor functions
-func extractPageLoadTime(m *Metrics) float64   { return m.PageLoadTime }
-func extractTTFB(m *Metrics) float64           { return m.TTFB }
-func extractDNSTime(m *Metrics) float64        { return m.DNSTime }
-func extractConnectTime(m *Metrics) float64    { return m.ConnectTime }
-func extractSSLTime(m *Metrics) float64        { return m.SSLTime }
-func extractTotalRequests(m *Metrics) int      { return m.TotalRequests }
-func extractErrorRequests(m *Metrics) int      { return m.ErrorRequests }
-func extractThirdPartyRequests(m *Metrics) int { return m.ThirdPartyRequests }
-func extractCacheHitRatio(m *Metrics) float64  { return m.CacheHitRatio }
-func extractTotalSize(m *Metrics) int64        { return m.TotalSize }
-
-// Improvement detection
-func isImprovementFloat(metricName string, change float64) bool {
-	// For timing metrics, smaller is better
-	timingMetrics := []string{
-		"Total Load Time", "Time to First Byte", "Average DNS Time",
-		"Average Connect Time", "Average SSL Time",
+func formatPercentChange(changePercent, baseValue float64) string {
+	if baseValue == 0 {
+		return "new"
 	}
-
-	for _, timing := range timingMetrics {
-		if metricName == timing {
-			return change < 0
-		}
-	}
-
-	// For cache hit ratio, higher is better
-	if metricName == "Cache Hit Ratio" {
-		return change > 0
-	}
-
-	return false
+	return fmt.Sprintf("%+.1f%%", changePercent)
 }
 
-func isImprovementInt(metricName string, change int) bool {
-	// For error requests and third-party requests, fewer is better
-	if metricName == "Error Requests" || metricName == "Third-party Requests" {
-		return change < 0
+func (c *Comparator) fileName(index int) string {
+	if index < len(c.files) {
+		return c.files[index]
 	}
-
-	// For total requests, depends on context - we'll consider it neutral
-	return false
+	return fmt.Sprintf("File %d", index+1)
 }
 
-func formatSize(size int) string {
-	if size < 1024 {
-		return fmt.Sprintf("%dB", size)
-	} else if size < 1024*1024 {
-		return fmt.Sprintf("%.1fKB", float64(size)/1024)
-	} else {
-		return fmt.Sprintf("%.1fMB", float64(size)/(1024*1024))
+func (c *Comparator) dataWarnings() []string {
+	var warnings []string
+	for i, metrics := range c.metrics {
+		name := c.fileName(i)
+		switch {
+		case !metrics.HasData():
+			warnings = append(warnings, fmt.Sprintf("%s has no entries; its metrics are shown as N/A and excluded from the summary", name))
+		case metrics.TotalRequests == 1:
+			warnings = append(warnings, fmt.Sprintf("%s has a single entry; averages reflect one request only", name))
+		}
 	}
+	return warnings
 }
@@ -0,0 +1,37 @@
+package har
+
+import "testing"
+
+func TestCompareLeavesNeutralMetricsOutOfSummary(t *testing.T) {
+	base := &Metrics{TotalRequests: 10, PageLoadTime: 1000}
+	candidate := &Metrics{TotalRequests: 40, PageLoadTime: 800}
+
+	summary := NewComparator([]string{"before", "after"}, []*Metrics{base, candidate}, Settings{}).Compare().Summary
+	if summary.WorseCount != 0 {
+		t.Errorf("WorseCount = %d, want 0 when only the neutral request count rose", summary.WorseCount)
+	}
+	if summary.BetterCount != 1 {
+		t.Errorf("BetterCount = %d, want 1 for the faster load", summary.BetterCount)
+	}
+	if summary.TotalMetrics != summary.BetterCount+summary.UnchangedCount {
+		t.Errorf("TotalMetrics = %d, want better+unchanged = %d", summary.TotalMetrics, summary.BetterCount+summary.UnchangedCount)
+	}
+}
+
+func TestNeutralChangesNeverRegress(t *testing.T) {
+	base := &Metrics{TotalRequests: 10, PageLoadTime: 1000}
+	candidate := &Metrics{TotalRequests: 4, PageLoadTime: 1500}
+
+	for _, diff := range NewComparator([]string{"before", "after"}, []*Metrics{base, candidate}, Settings{}).Compare().Differences {
+		switch diff.ID {
+		case MetricTotalRequests:
+			if !diff.Flagged(1) || diff.Regressed(1) {
+				t.Errorf("request count change: flagged %v, regressed %v; want flagged, not regressed", diff.Flagged(1), diff.Regressed(1))
+			}
+		case MetricPageLoadTime:
+			if !diff.Regressed(1) {
+				t.Error("slower load is not a regression")
+			}
+		}
+	}
+}
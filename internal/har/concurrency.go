@@ -0,0 +1,125 @@
+package har
+
+import (
+	"sort"
+	"time"
+)
+
+// QueuedThreshold is how long a request must wait in the browser before it
+// counts as queued, e.g. for a free connection.
+const QueuedThreshold = 10 // ms
+
+// ConcurrencyProfile is the number of requests in flight over a capture,
+// split into equal time slices.
+type ConcurrencyProfile struct {
+	Start    time.Time
+	Slice    time.Duration
+	InFlight []int // most requests in flight at once during each slice
+
+	Peak        int           // most requests in flight at once
+	PeakAt      time.Duration // offset of the first moment of peak concurrency
+	Average     float64       // requests in flight on average over the capture
+	Queued      int           // requests that waited at least QueuedThreshold before being sent
+	QueuedTime  float64       // ms, total time requests spent waiting
+	QueuedSlice []int         // queued requests starting in each slice
+}
+
+// Concurrency builds the in-flight profile of the capture with the given
+// number of slices. Queued requests are those whose blocked time, the time
+// spent waiting for a connection or in the browser's queue, is at least
+// QueuedThreshold.
+func (a *Analyzer) Concurrency(slices int) ConcurrencyProfile {
+	entries := a.har.Log.Entries
+	if len(entries) == 0 || slices <= 0 {
+		return ConcurrencyProfile{}
+	}
+
+	start, end := entries[0].StartedDateTime, entryEnd(entries[0])
+	spans := make([]interval, len(entries))
+	var busy time.Duration
+	for i, entry := range entries {
+		spans[i] = interval{entry.StartedDateTime, entryEnd(entry)}
+		if spans[i].start.Before(start) {
+			start = spans[i].start
+		}
+		if spans[i].end.After(end) {
+			end = spans[i].end
+		}
+		busy += spans[i].end.Sub(spans[i].start)
+	}
+
+	total := end.Sub(start)
+	profile := ConcurrencyProfile{
+		Start:       start,
+		Slice:       max(total/time.Duration(slices), time.Millisecond),
+		InFlight:    make([]int, slices),
+		QueuedSlice: make([]int, slices),
+	}
+	if total > 0 {
+		profile.Average = float64(busy) / float64(total)
+	}
+
+	// The most requests in flight at any moment of each slice
+	edges := sortedEdges(spans)
+	current, next := 0, 0
+	for i := range profile.InFlight {
+		sliceEnd := start.Add(profile.Slice * time.Duration(i+1))
+		peak := current
+		for next < len(edges) && (edges[next].at.Before(sliceEnd) || i == slices-1) {
+			current += edges[next].delta
+			peak = max(peak, current)
+			next++
+		}
+		profile.InFlight[i] = peak
+	}
+
+	for _, entry := range entries {
+		if blocked := entry.Timings.Blocked; blocked >= QueuedThreshold {
+			profile.Queued++
+			profile.QueuedTime += float64(blocked)
+			profile.QueuedSlice[min(int(entry.StartedDateTime.Sub(start)/profile.Slice), slices-1)]++
+		}
+	}
+
+	peak, peakAt := peakParallel(spans)
+	profile.Peak, profile.PeakAt = peak, peakAt.Sub(start)
+	return profile
+}
+
+type interval struct{ start, end time.Time }
+
+type edge struct {
+	at    time.Time
+	delta int // +1 when an interval starts, -1 when it ends
+}
+
+// sortedEdges returns the starts and ends of the intervals in time order,
+// ends first when an interval ends as another starts.
+func sortedEdges(intervals []interval) []edge {
+	edges := make([]edge, 0, len(intervals)*2)
+	for _, span := range intervals {
+		edges = append(edges, edge{span.start, 1}, edge{span.end, -1})
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		if !edges[i].at.Equal(edges[j].at) {
+			return edges[i].at.Before(edges[j].at)
+		}
+		return edges[i].delta < edges[j].delta
+	})
+	return edges
+}
+
+// peakParallel returns the largest number of overlapping intervals and when
+// that many first overlapped. An interval ending when another starts does not
+// overlap it.
+func peakParallel(intervals []interval) (int, time.Time) {
+	current, peak := 0, 0
+	var peakAt time.Time
+	for _, e := range sortedEdges(intervals) {
+		current += e.delta
+		if current > peak {
+			peak, peakAt = current, e.at
+		}
+	}
+	return peak, peakAt
+}
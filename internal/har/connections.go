@@ -0,0 +1,106 @@
+package har
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Browsers open up to six parallel HTTP/1.x connections per origin, while
+// HTTP/2 and HTTP/3 multiplex every request over one.
+const (
+	HTTP1ConnectionsPerOrigin     = 6
+	MultiplexedConnectionsPerHost = 1
+)
+
+// OriginConnections is the connection usage of one origin.
+type OriginConnections struct {
+	Origin         string
+	Requests       int
+	NewConnections int
+	Reused         int
+	SetupTime      float64 // ms spent in TCP and TLS setup
+	WastedTime     float64 // setup time of connections beyond what the protocol needs
+	Multiplexed    bool    // served over HTTP/2 or HTTP/3
+}
+
+// ExtraConnections returns how many more connections were opened than the
+// protocol needs.
+func (o OriginConnections) ExtraConnections() int {
+	expected := HTTP1ConnectionsPerOrigin
+	if o.Multiplexed {
+		expected = MultiplexedConnectionsPerHost
+	}
+	return max(o.NewConnections-expected, 0)
+}
+
+// ConnectionAnalysis counts new and reused connections per origin, most
+// wasted setup time first. A request opened a new connection when its
+// Connection ID was not seen before or, without IDs, when it recorded connect
+// time. Setup time beyond the first connection (HTTP/2 and HTTP/3) or the
+// first six (HTTP/1.x) counts as lost to missing keep-alive or reuse.
+func (a *Analyzer) ConnectionAnalysis() []OriginConnections {
+	origins := make(map[string]*OriginConnections)
+	var order []string
+	seenConnections := make(map[string]bool)
+
+	for _, entry := range a.har.Log.Entries {
+		origin := entryOrigin(entry)
+		if origin == "" {
+			continue
+		}
+		stats, ok := origins[origin]
+		if !ok {
+			stats = &OriginConnections{Origin: origin}
+			origins[origin] = stats
+			order = append(order, origin)
+		}
+		stats.Requests++
+		if isMultiplexed(entry) {
+			stats.Multiplexed = true
+		}
+
+		isNew := entry.Timings.Connect > 0
+		if entry.Connection != "" {
+			key := origin + " " + entry.Connection
+			isNew = !seenConnections[key]
+			seenConnections[key] = true
+		}
+		if !isNew {
+			stats.Reused++
+			continue
+		}
+
+		stats.NewConnections++
+		setup := float64(max(entry.Timings.Connect, entry.Timings.SSL, 0))
+		stats.SetupTime += setup
+		expected := HTTP1ConnectionsPerOrigin
+		if stats.Multiplexed {
+			expected = MultiplexedConnectionsPerHost
+		}
+		if stats.NewConnections > expected {
+			stats.WastedTime += setup
+		}
+	}
+
+	result := make([]OriginConnections, 0, len(order))
+	for _, origin := range order {
+		result = append(result, *origins[origin])
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].WastedTime > result[j].WastedTime
+	})
+	return result
+}
+
+func entryOrigin(entry Entry) string {
+	parsed, err := url.Parse(entry.Request.URL)
+	if err != nil || parsed.Host == "" {
+		return ""
+	}
+	return strings.ToLower(parsed.Scheme + "://" + parsed.Host)
+}
+
+func isMultiplexed(entry Entry) bool {
+	return IsMultiplexedProtocol(EntryProtocol(entry))
+}
@@ -0,0 +1,166 @@
+package har
+
+import (
+	"sort"
+	"strings"
+)
+
+// OversizedCookieBytes is the name=value size above which a cookie is flagged.
+// Browsers cap cookies at 4KB, but anything over 1KB is sent on every request.
+const OversizedCookieBytes = 1024
+
+// CookieInfo describes one cookie sent with a request or set by a response.
+type CookieInfo struct {
+	Name       string
+	Domain     string // request host the cookie travelled with
+	Sent       bool   // true for request cookies, false for Set-Cookie
+	Size       int
+	ThirdParty bool
+	Issues     []string
+}
+
+// CookieDomainStats totals cookie overhead for one host.
+type CookieDomainStats struct {
+	Domain    string
+	SentBytes int
+	SetBytes  int
+	Cookies   int
+}
+
+func (s CookieDomainStats) TotalBytes() int {
+	return s.SentBytes + s.SetBytes
+}
+
+type CookieSummary struct {
+	TotalBytes int
+	Insecure   int // set without Secure, HttpOnly or SameSite
+	Oversized  int
+	ThirdParty int // cookies sent to third-party hosts
+	Domains    []CookieDomainStats
+}
+
+// EntryCookies analyzes the cookies of a single entry.
+func (a *Analyzer) EntryCookies(entry Entry) []CookieInfo {
+	host := EntryHost(entry)
+	thirdParty := a.isThirdParty(entry.Request.URL)
+
+	var cookies []CookieInfo
+	for _, cookie := range entry.Request.Cookies {
+		info := CookieInfo{Name: cookie.Name, Domain: host, Sent: true, Size: cookieSize(cookie), ThirdParty: thirdParty}
+		if info.Size > OversizedCookieBytes {
+			info.Issues = append(info.Issues, "oversized")
+		}
+		if thirdParty {
+			info.Issues = append(info.Issues, "sent to third party")
+		}
+		cookies = append(cookies, info)
+	}
+
+	for _, cookie := range entry.Response.Cookies {
+		info := CookieInfo{Name: cookie.Name, Domain: host, Size: cookieSize(cookie), ThirdParty: thirdParty}
+		if !cookie.Secure {
+			info.Issues = append(info.Issues, "missing Secure")
+		}
+		if !cookie.HTTPOnly {
+			info.Issues = append(info.Issues, "missing HttpOnly")
+		}
+		if cookieSameSite(entry, cookie) == "" {
+			info.Issues = append(info.Issues, "missing SameSite")
+		}
+		if info.Size > OversizedCookieBytes {
+			info.Issues = append(info.Issues, "oversized")
+		}
+		cookies = append(cookies, info)
+	}
+
+	return cookies
+}
+
+// CookieSummary totals cookie issues and overhead across the capture, with
+// the domains carrying the most cookie bytes first.
+func (a *Analyzer) CookieSummary() CookieSummary {
+	var summary CookieSummary
+	domains := make(map[string]*CookieDomainStats)
+
+	for _, entry := range a.har.Log.Entries {
+		for _, cookie := range a.EntryCookies(entry) {
+			stats, ok := domains[cookie.Domain]
+			if !ok {
+				stats = &CookieDomainStats{Domain: cookie.Domain}
+				domains[cookie.Domain] = stats
+			}
+			stats.Cookies++
+			summary.TotalBytes += cookie.Size
+
+			if cookie.Sent {
+				stats.SentBytes += cookie.Size
+				if cookie.ThirdParty {
+					summary.ThirdParty++
+				}
+			} else {
+				stats.SetBytes += cookie.Size
+				if containsInsecureIssue(cookie.Issues) {
+					summary.Insecure++
+				}
+			}
+			if containsString(cookie.Issues, "oversized") {
+				summary.Oversized++
+			}
+		}
+	}
+
+	for _, stats := range domains {
+		summary.Domains = append(summary.Domains, *stats)
+	}
+	sort.Slice(summary.Domains, func(i, j int) bool {
+		if summary.Domains[i].TotalBytes() != summary.Domains[j].TotalBytes() {
+			return summary.Domains[i].TotalBytes() > summary.Domains[j].TotalBytes()
+		}
+		return summary.Domains[i].Domain < summary.Domains[j].Domain
+	})
+
+	return summary
+}
+
+func containsInsecureIssue(issues []string) bool {
+	for _, issue := range issues {
+		if strings.HasPrefix(issue, "missing ") {
+			return true
+		}
+	}
+	return false
+}
+
+// cookieSize is the bytes the cookie adds on the wire as name=value.
+func cookieSize(cookie Cookie) int {
+	return len(cookie.Name) + 1 + len(cookie.Value)
+}
+
+// cookieSameSite returns the cookie's SameSite attribute. Chrome records it on
+// the cookie; otherwise it is read from the matching Set-Cookie header.
+func cookieSameSite(entry Entry, cookie Cookie) string {
+	if cookie.SameSite != "" {
+		return cookie.SameSite
+	}
+
+	for _, header := range entry.Response.Headers {
+		if !strings.EqualFold(header.Name, "Set-Cookie") {
+			continue
+		}
+		// Some exporters fold several Set-Cookie headers into one, newline separated
+		for _, line := range strings.Split(header.Value, "\n") {
+			attributes := strings.Split(line, ";")
+			name, _, _ := strings.Cut(attributes[0], "=")
+			if strings.TrimSpace(name) != cookie.Name {
+				continue
+			}
+			for _, attribute := range attributes[1:] {
+				key, value, _ := strings.Cut(strings.TrimSpace(attribute), "=")
+				if strings.EqualFold(key, "SameSite") {
+					return strings.TrimSpace(value)
+				}
+			}
+		}
+	}
+	return ""
+}
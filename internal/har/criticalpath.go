@@ -0,0 +1,133 @@
+package har
+
+import "time"
+
+// CriticalPath is the chain of dependent requests that gated page load.
+type CriticalPath struct {
+	Entries  []int   // entry indices, first request first
+	Duration float64 // ms from the start of the first request to the end of the last
+	Inferred bool    // some links were guessed from timing for lack of initiator data
+}
+
+// Contains reports whether the entry is on the path.
+func (p CriticalPath) Contains(index int) bool {
+	for _, entry := range p.Entries {
+		if entry == index {
+			return true
+		}
+	}
+	return false
+}
+
+// InitiatorURL returns the document or script that started the request: the
+// parser URL, else the innermost script of the call stack. It is empty when
+// the capture has no initiator data or the browser started the request.
+func (e Entry) InitiatorURL() string {
+	if e.Initiator == nil {
+		return ""
+	}
+	if e.Initiator.URL != "" {
+		return e.Initiator.URL
+	}
+	for stack := e.Initiator.Stack; stack != nil; stack = stack.Parent {
+		for _, frame := range stack.CallFrames {
+			if frame.URL != "" {
+				return frame.URL
+			}
+		}
+	}
+	return ""
+}
+
+// CriticalPath finds the request that finished last before onLoad (or last
+// overall without page timings) and follows its initiators back to the
+// first request. A request without initiator data is linked to the request
+// that finished most recently before it started, and the path is marked
+// Inferred.
+func (a *Analyzer) CriticalPath() CriticalPath {
+	entries := a.har.Log.Entries
+	deadline := a.onLoadTime()
+
+	last := -1
+	for i, entry := range entries {
+		end := entryEnd(entry)
+		if !deadline.IsZero() && end.After(deadline) {
+			continue
+		}
+		if last < 0 || end.After(entryEnd(entries[last])) ||
+			(end.Equal(entryEnd(entries[last])) && entry.StartedDateTime.Before(entries[last].StartedDateTime)) {
+			last = i
+		}
+	}
+	if last < 0 {
+		return CriticalPath{}
+	}
+
+	byURL := make(map[string][]int)
+	for i, entry := range entries {
+		key := stripFragment(entry.Request.URL)
+		byURL[key] = append(byURL[key], i)
+	}
+
+	var path CriticalPath
+	visited := make(map[int]bool)
+	for current := last; current >= 0 && !visited[current]; {
+		visited[current] = true
+		path.Entries = append([]int{current}, path.Entries...)
+
+		parent, inferred := a.initiatorOf(current, byURL)
+		path.Inferred = path.Inferred || (inferred && parent >= 0)
+		current = parent
+	}
+
+	first := entries[path.Entries[0]]
+	path.Duration = float64(entryEnd(entries[last]).Sub(first.StartedDateTime)) / float64(time.Millisecond)
+	return path
+}
+
+// initiatorOf returns the entry that started entry i, or -1 when it was the
+// browser. inferred is true when the link comes from timing alone.
+func (a *Analyzer) initiatorOf(i int, byURL map[string][]int) (int, bool) {
+	entries := a.har.Log.Entries
+	entry := entries[i]
+
+	if entry.Initiator != nil {
+		// The latest request for the initiator's URL that started before this one
+		parent := -1
+		for _, j := range byURL[stripFragment(entry.InitiatorURL())] {
+			if j == i || entries[j].StartedDateTime.After(entry.StartedDateTime) {
+				continue
+			}
+			if parent < 0 || entries[j].StartedDateTime.After(entries[parent].StartedDateTime) {
+				parent = j
+			}
+		}
+		return parent, false
+	}
+
+	parent := -1
+	for j, other := range entries {
+		if j == i || entryEnd(other).After(entry.StartedDateTime) {
+			continue
+		}
+		if parent < 0 || entryEnd(other).After(entryEnd(entries[parent])) {
+			parent = j
+		}
+	}
+	return parent, true
+}
+
+// onLoadTime returns when the first page fired onLoad, or the zero time when
+// the capture has no page timings.
+func (a *Analyzer) onLoadTime() time.Time {
+	page := a.Page()
+	if page.PageTimings.OnLoad <= 0 {
+		return time.Time{}
+	}
+	// onLoad is rounded to whole milliseconds
+	return page.StartedDateTime.Add(time.Duration(page.PageTimings.OnLoad+1) * time.Millisecond)
+}
+
+func entryEnd(entry Entry) time.Time {
+	return entry.StartedDateTime.Add(time.Duration(entry.Time * float64(time.Millisecond)))
+}
@@ -0,0 +1,94 @@
+package har
+
+import (
+	"sort"
+	"strings"
+)
+
+// Content encodings reported by the distribution, in display order. dcb and
+// dcz are Brotli and Zstandard with a shared compression dictionary.
+var ContentEncodings = []string{"br", "dcb", "zstd", "dcz", "gzip", "deflate", "identity"}
+
+// EncodingStats counts the content-encodings of text responses for one domain
+// (or the whole capture when Domain is empty).
+type EncodingStats struct {
+	Domain     string
+	Total      int
+	ByEncoding map[string]int
+}
+
+// Share returns the percentage of text responses served with the encoding.
+func (s EncodingStats) Share(encoding string) float64 {
+	if s.Total == 0 {
+		return 0
+	}
+	return float64(s.ByEncoding[encoding]) / float64(s.Total) * 100
+}
+
+// IsTextResponse reports whether the MIME type is worth compressing.
+func IsTextResponse(mimeType string) bool {
+	mimeType = strings.ToLower(mimeType)
+	if strings.HasPrefix(mimeType, "text/") {
+		return true
+	}
+	for _, kind := range []string{"javascript", "json", "xml", "svg", "wasm", "font/ttf", "font/otf"} {
+		if strings.Contains(mimeType, kind) {
+			return true
+		}
+	}
+	return false
+}
+
+// ResponseEncoding returns the outermost content-encoding of the response,
+// or "identity" when it is not encoded.
+func ResponseEncoding(entry Entry) string {
+	value, ok := HeaderValue(entry.Response.Headers, "Content-Encoding")
+	if !ok {
+		return "identity"
+	}
+	codings := strings.Split(value, ",")
+	encoding := strings.ToLower(strings.TrimSpace(codings[len(codings)-1]))
+	if encoding == "" {
+		return "identity"
+	}
+	return encoding
+}
+
+// EncodingDistribution summarizes the content-encodings of text responses
+// overall and per domain, domains with the most text responses first.
+// Responses without a body, such as 304s, are skipped.
+func (a *Analyzer) EncodingDistribution() (EncodingStats, []EncodingStats) {
+	overall := EncodingStats{ByEncoding: make(map[string]int)}
+	domains := make(map[string]*EncodingStats)
+
+	for _, entry := range a.har.Log.Entries {
+		if !IsTextResponse(entry.Response.Content.MimeType) || entry.Response.Content.Size <= 0 {
+			continue
+		}
+		encoding := ResponseEncoding(entry)
+		host := EntryHost(entry)
+
+		stats, ok := domains[host]
+		if !ok {
+			stats = &EncodingStats{Domain: host, ByEncoding: make(map[string]int)}
+			domains[host] = stats
+		}
+		stats.Total++
+		stats.ByEncoding[encoding]++
+		overall.Total++
+		overall.ByEncoding[encoding]++
+	}
+
+	perDomain := make([]EncodingStats, 0, len(domains))
+	for _, stats := range domains {
+		perDomain = append(perDomain, *stats)
+	}
+	sort.Slice(perDomain, func(i, j int) bool {
+		if perDomain[i].Total != perDomain[j].Total {
+			return perDomain[i].Total > perDomain[j].Total
+		}
+		return perDomain[i].Domain < perDomain[j].Domain
+	})
+
+	return overall, perDomain
+}
@@ -0,0 +1,91 @@
+package har
+
+import (
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// PathParameter replaces the path segments TemplatePath collapses.
+const PathParameter = "{id}"
+
+var (
+	numericSegment = regexp.MustCompile(`^\d+$`)
+	uuidSegment    = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	// hexSegment matches hashes and object IDs, e.g. 24-digit MongoDB IDs
+	hexSegment = regexp.MustCompile(`^[0-9a-fA-F]{16,}$`)
+)
+
+// TemplatePath collapses the path segments that identify a resource rather
+// than an endpoint (numbers, UUIDs and long hex IDs) into PathParameter, so
+// "/users/42/orders/7" becomes "/users/{id}/orders/{id}".
+func TemplatePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if numericSegment.MatchString(segment) || uuidSegment.MatchString(segment) ||
+			(hexSegment.MatchString(segment) && strings.ContainsAny(segment, "0123456789")) {
+			segments[i] = PathParameter
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// EndpointTemplate groups requests by method, host and templated path,
+// ignoring the query, e.g. "GET api.example.com/users/{id}".
+func EndpointTemplate(entry Entry) string {
+	parsed, err := url.Parse(entry.Request.URL)
+	if err != nil {
+		return entry.Request.Method + " " + entry.Request.URL
+	}
+	return entry.Request.Method + " " + parsed.Host + TemplatePath(parsed.Path)
+}
+
+// EndpointStats summarizes the requests to one templated endpoint.
+type EndpointStats struct {
+	Endpoint string  `json:"endpoint"`
+	Requests int     `json:"requests"`
+	Errors   int     `json:"errors"` // responses with status 400 or above
+	P50      float64 `json:"p50"`
+	P95      float64 `json:"p95"`
+}
+
+// ErrorRate is the share of the endpoint's requests that failed, in percent.
+func (s EndpointStats) ErrorRate() float64 {
+	return float64(s.Errors) / float64(s.Requests) * 100
+}
+
+// EndpointStats groups the capture's requests by EndpointTemplate, most
+// requested endpoint first.
+func (a *Analyzer) EndpointStats() []EndpointStats {
+	times := map[string][]float64{}
+	errors := map[string]int{}
+	var endpoints []string
+	for _, entry := range a.har.Log.Entries {
+		key := EndpointTemplate(entry)
+		if _, ok := times[key]; !ok {
+			endpoints = append(endpoints, key)
+		}
+		times[key] = append(times[key], entry.Time)
+		if entry.Response.Status >= 400 {
+			errors[key]++
+		}
+	}
+
+	stats := make([]EndpointStats, 0, len(endpoints))
+	for _, key := range endpoints {
+		durations := times[key]
+		sort.Float64s(durations)
+		stats = append(stats, EndpointStats{
+			Endpoint: key,
+			Requests: len(durations),
+			Errors:   errors[key],
+			P50:      Percentile(durations, 0.5),
+			P95:      Percentile(durations, 0.95),
+		})
+	}
+	sort.SliceStable(stats, func(i, j int) bool {
+		return stats[i].Requests > stats[j].Requests
+	})
+	return stats
+}
@@ -0,0 +1,234 @@
+package har
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// FieldDiff is one field of two entries side by side. A side that lacks the
+// field is empty.
+type FieldDiff struct {
+	Name   string
+	Before string
+	After  string
+}
+
+// Changed reports whether the two sides differ.
+func (d FieldDiff) Changed() bool {
+	return d.Before != d.After
+}
+
+// LineDiff is one line of a body diff: '-' only before, '+' only after, ' '
+// in both.
+type LineDiff struct {
+	Op   byte
+	Text string
+}
+
+// EntryDiff compares two requests field by field.
+type EntryDiff struct {
+	Summary         []FieldDiff
+	RequestHeaders  []FieldDiff
+	ResponseHeaders []FieldDiff
+	Query           []FieldDiff
+	Timings         []FieldDiff
+	RequestBody     []LineDiff
+	ResponseBody    []LineDiff
+}
+
+// Changes counts the differing fields and body lines.
+func (d EntryDiff) Changes() int {
+	changes := 0
+	for _, fields := range [][]FieldDiff{d.Summary, d.RequestHeaders, d.ResponseHeaders, d.Query, d.Timings} {
+		for _, field := range fields {
+			if field.Changed() {
+				changes++
+			}
+		}
+	}
+	for _, lines := range [][]LineDiff{d.RequestBody, d.ResponseBody} {
+		for _, line := range lines {
+			if line.Op != ' ' {
+				changes++
+			}
+		}
+	}
+	return changes
+}
+
+// MaxDiffLines caps the body lines compared, since the line diff is
+// quadratic; longer bodies are compared up to the cap.
+const MaxDiffLines = 2000
+
+// DiffEntries compares two entries: the request and response summary,
+// headers and query parameters matched by name, timing phases, and the
+// request and response bodies line by line, with JSON indented first. Sizes
+// are written in units.
+func DiffEntries(before, after Entry, units SizeUnits) EntryDiff {
+	return EntryDiff{
+		Summary: []FieldDiff{
+			{"Method", before.Request.Method, after.Request.Method},
+			{"URL", before.Request.URL, after.Request.URL},
+			{"HTTP Version", before.Request.HTTPVersion, after.Request.HTTPVersion},
+			{"Status", fmt.Sprintf("%d %s", before.Response.Status, before.Response.StatusText), fmt.Sprintf("%d %s", after.Response.Status, after.Response.StatusText)},
+			{"Content Type", before.Response.Content.MimeType, after.Response.Content.MimeType},
+			{"Content Size", units.FormatSize(before.Response.Content.Size), units.FormatSize(after.Response.Content.Size)},
+			{"Total Time", fmt.Sprintf("%.1fms", before.Time), fmt.Sprintf("%.1fms", after.Time)},
+		},
+		RequestHeaders:  diffNamed(headerPairs(before.Request.Headers), headerPairs(after.Request.Headers)),
+		ResponseHeaders: diffNamed(headerPairs(before.Response.Headers), headerPairs(after.Response.Headers)),
+		Query:           diffNamed(queryPairs(before), queryPairs(after)),
+		Timings: []FieldDiff{
+			timingDiff("Blocked", before.Timings.Blocked, after.Timings.Blocked),
+			timingDiff("DNS", before.Timings.DNS, after.Timings.DNS),
+			timingDiff("Connect", before.Timings.Connect, after.Timings.Connect),
+			timingDiff("SSL", before.Timings.SSL, after.Timings.SSL),
+			timingDiff("Send", before.Timings.Send, after.Timings.Send),
+			timingDiff("Wait", before.Timings.Wait, after.Timings.Wait),
+			timingDiff("Receive", before.Timings.Receive, after.Timings.Receive),
+		},
+		RequestBody:  DiffLines(requestBodyText(before), requestBodyText(after)),
+		ResponseBody: DiffLines(indentJSON(ResponseBody(before)), indentJSON(ResponseBody(after))),
+	}
+}
+
+func timingDiff(name string, before, after int) FieldDiff {
+	format := func(value int) string {
+		if value < 0 {
+			return "-"
+		}
+		return fmt.Sprintf("%dms", value)
+	}
+	return FieldDiff{name, format(before), format(after)}
+}
+
+// headerPairs lowercases header names, since they are case-insensitive.
+func headerPairs(headers []Header) [][2]string {
+	pairs := make([][2]string, len(headers))
+	for i, header := range headers {
+		pairs[i] = [2]string{strings.ToLower(header.Name), header.Value}
+	}
+	return pairs
+}
+
+func queryPairs(entry Entry) [][2]string {
+	params := QueryParams(entry)
+	pairs := make([][2]string, len(params))
+	for i, param := range params {
+		pairs[i] = [2]string{param.Name, param.Value}
+	}
+	return pairs
+}
+
+// diffNamed matches fields by name, sorted by name. Repeated fields are
+// joined with newlines in capture order.
+func diffNamed(before, after [][2]string) []FieldDiff {
+	values := map[string]*FieldDiff{}
+	var names []string
+	add := func(pairs [][2]string, side func(*FieldDiff) *string) {
+		for _, pair := range pairs {
+			field, ok := values[pair[0]]
+			if !ok {
+				field = &FieldDiff{Name: pair[0]}
+				values[pair[0]] = field
+				names = append(names, pair[0])
+			}
+			if value := side(field); *value == "" {
+				*value = pair[1]
+			} else {
+				*value += "\n" + pair[1]
+			}
+		}
+	}
+	add(before, func(field *FieldDiff) *string { return &field.Before })
+	add(after, func(field *FieldDiff) *string { return &field.After })
+
+	sort.Strings(names)
+	fields := make([]FieldDiff, len(names))
+	for i, name := range names {
+		fields[i] = *values[name]
+	}
+	return fields
+}
+
+func requestBodyText(entry Entry) string {
+	body, ok := ParseRequestBody(entry)
+	if !ok {
+		return ""
+	}
+	if body.Kind == JSONPostData {
+		return body.JSON
+	}
+	return body.Raw
+}
+
+// indentJSON indents JSON text so bodies diff line by line, and returns
+// anything else unchanged.
+func indentJSON(text string) string {
+	var indented bytes.Buffer
+	if json.Indent(&indented, []byte(text), "", "  ") != nil {
+		return text
+	}
+	return indented.String()
+}
+
+// DiffLines compares two texts line by line with a longest common
+// subsequence, up to MaxDiffLines lines each. Two empty texts have no lines.
+func DiffLines(before, after string) []LineDiff {
+	if before == "" && after == "" {
+		return nil
+	}
+	a, b := splitDiffLines(before), splitDiffLines(after)
+
+	// common[i][j] is the LCS length of a[i:] and b[j:]
+	common := make([][]int, len(a)+1)
+	for i := range common {
+		common[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				common[i][j] = common[i+1][j+1] + 1
+			} else {
+				common[i][j] = max(common[i+1][j], common[i][j+1])
+			}
+		}
+	}
+
+	var lines []LineDiff
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, LineDiff{' ', a[i]})
+			i, j = i+1, j+1
+		case common[i+1][j] >= common[i][j+1]:
+			lines = append(lines, LineDiff{'-', a[i]})
+			i++
+		default:
+			lines = append(lines, LineDiff{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		lines = append(lines, LineDiff{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		lines = append(lines, LineDiff{'+', b[j]})
+	}
+	return lines
+}
+
+func splitDiffLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	if len(lines) > MaxDiffLines {
+		lines = lines[:MaxDiffLines]
+	}
+	return lines
+}
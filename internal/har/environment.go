@@ -0,0 +1,103 @@
+package har
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// Environment is the kind of deployment a capture was taken against.
+type Environment string
+
+const (
+	EnvProduction Environment = "production"
+	EnvStaging    Environment = "staging"
+	EnvLocal      Environment = "local"
+)
+
+// ParseEnvironment parses production, staging or local. An empty name
+// leaves the environment to be detected from the page's host.
+func ParseEnvironment(name string) (Environment, error) {
+	switch Environment(name) {
+	case "", EnvProduction, EnvStaging, EnvLocal:
+		return Environment(name), nil
+	}
+	return "", fmt.Errorf("unknown environment %q (want production, staging or local)", name)
+}
+
+var stagingLabels = []string{"staging", "stage", "stg", "dev", "develop", "qa", "uat", "test", "preview", "sandbox"}
+
+// HostEnvironment classifies a host: loopback, private addresses and
+// .localhost/.local/.test names are local, hosts with a subdomain label such
+// as "staging", "dev" or "qa" (e.g. staging.example.com, app-dev.example.com)
+// are staging, and everything else is production. The registrable domain is
+// not checked, so go.dev, dev.to and www.test.com stay production.
+func HostEnvironment(host string) Environment {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") || strings.HasSuffix(host, ".local") ||
+		strings.HasSuffix(host, ".test") || strings.HasSuffix(host, ".internal") {
+		return EnvLocal
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() {
+			return EnvLocal
+		}
+		return EnvProduction
+	}
+
+	subdomain := strings.TrimSuffix(strings.TrimSuffix(host, RegistrableDomain(host)), ".")
+	if subdomain == "" {
+		return EnvProduction
+	}
+	for _, label := range strings.Split(subdomain, ".") {
+		for _, part := range strings.FieldsFunc(label, func(r rune) bool { return r == '-' || r == '_' }) {
+			if containsString(stagingLabels, part) {
+				return EnvStaging
+			}
+		}
+	}
+	return EnvProduction
+}
+
+// Environment returns the environment of the analyzer's settings, or that
+// of the page's host.
+func (a *Analyzer) Environment() Environment {
+	if a.settings.Environment != "" {
+		return a.settings.Environment
+	}
+	return HostEnvironment(a.pageHost())
+}
+
+// pageHost returns the host of the captured page: the page URL when the
+// browser recorded it as the page title, else the first HTML document
+// requested, else the first request.
+func (a *Analyzer) pageHost() string {
+	for _, page := range a.har.Log.Pages {
+		if parsed, err := url.Parse(page.Title); err == nil && parsed.Host != "" {
+			return parsed.Hostname()
+		}
+	}
+	for _, entry := range a.har.Log.Entries {
+		if strings.Contains(entry.Response.Content.MimeType, "html") {
+			return EntryHost(entry)
+		}
+	}
+	if len(a.har.Log.Entries) > 0 {
+		return EntryHost(a.har.Log.Entries[0])
+	}
+	return ""
+}
+
+// ChecksDelivery reports whether CDN, caching, compression and connection
+// recommendations apply. Staging and local servers are rarely set up like
+// production, so those findings would be noise.
+func (e Environment) ChecksDelivery() bool {
+	return e == EnvProduction
+}
+
+// ChecksTransport reports whether TLS and mixed-content findings apply.
+// Local development servers commonly run over plain HTTP.
+func (e Environment) ChecksTransport() bool {
+	return e != EnvLocal
+}
@@ -0,0 +1,40 @@
+package har
+
+import "testing"
+
+func TestHostEnvironment(t *testing.T) {
+	tests := []struct {
+		host string
+		want Environment
+	}{
+		{"localhost", EnvLocal},
+		{"app.localhost", EnvLocal},
+		{"127.0.0.1", EnvLocal},
+		{"192.168.1.20", EnvLocal},
+		{"myapp.test", EnvLocal},
+		{"staging.myapp.test", EnvLocal},
+		{"printer.local", EnvLocal},
+
+		{"staging.example.com", EnvStaging},
+		{"app-dev.example.com", EnvStaging},
+		{"qa.shop.example.co.uk", EnvStaging},
+		{"preview.example.dev", EnvStaging},
+		{"dev.example.com.", EnvStaging},
+
+		{"go.dev", EnvProduction},
+		{"web.dev", EnvProduction},
+		{"pkg.go.dev", EnvProduction},
+		{"dev.to", EnvProduction},
+		{"www.test.com", EnvProduction},
+		{"test.com", EnvProduction},
+		{"staging.com", EnvProduction},
+		{"www.example.com", EnvProduction},
+		{"www.bbc.co.uk", EnvProduction},
+		{"93.184.216.34", EnvProduction},
+	}
+	for _, tt := range tests {
+		if got := HostEnvironment(tt.host); got != tt.want {
+			t.Errorf("HostEnvironment(%q) = %s, want %s", tt.host, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package har
+
+import (
+	"net/url"
+	"sort"
+	"time"
+)
+
+// ErrorCluster groups the failed requests that share a status, host and
+// templated path, so hundreds of failures read as a handful of causes.
+type ErrorCluster struct {
+	Status   int       `json:"status"` // 0 when no response was received
+	Domain   string    `json:"domain"`
+	Path     string    `json:"path"` // see TemplatePath
+	Requests int       `json:"requests"`
+	First    time.Time `json:"first"`
+	Last     time.Time `json:"last"`
+	// Indices are the clustered entries of Log.Entries, in capture order
+	Indices []int `json:"-"`
+}
+
+// IsFailure reports whether a request failed: a 4xx or 5xx response, or no
+// response at all (status 0, e.g. blocked or aborted).
+func IsFailure(entry Entry) bool {
+	return entry.Response.Status == 0 || entry.Response.Status >= 400
+}
+
+// ErrorClusters groups the failed requests by status, host and templated
+// path, largest cluster first, then by first occurrence.
+func (a *Analyzer) ErrorClusters() []ErrorCluster {
+	type clusterKey struct {
+		status       int
+		domain, path string
+	}
+	positions := map[clusterKey]int{}
+	var clusters []ErrorCluster
+	for i, entry := range a.har.Log.Entries {
+		if !IsFailure(entry) {
+			continue
+		}
+		key := clusterKey{status: entry.Response.Status, domain: EntryHost(entry), path: entry.Request.URL}
+		if parsed, err := url.Parse(entry.Request.URL); err == nil {
+			key.path = TemplatePath(parsed.Path)
+		}
+
+		position, ok := positions[key]
+		if !ok {
+			position = len(clusters)
+			positions[key] = position
+			clusters = append(clusters, ErrorCluster{Status: key.status, Domain: key.domain, Path: key.path,
+				First: entry.StartedDateTime, Last: entry.StartedDateTime})
+		}
+		cluster := &clusters[position]
+		cluster.Requests++
+		cluster.Indices = append(cluster.Indices, i)
+		if entry.StartedDateTime.Before(cluster.First) {
+			cluster.First = entry.StartedDateTime
+		}
+		if entry.StartedDateTime.After(cluster.Last) {
+			cluster.Last = entry.StartedDateTime
+		}
+	}
+
+	sort.SliceStable(clusters, func(i, j int) bool {
+		if clusters[i].Requests != clusters[j].Requests {
+			return clusters[i].Requests > clusters[j].Requests
+		}
+		return clusters[i].First.Before(clusters[j].First)
+	})
+	return clusters
+}
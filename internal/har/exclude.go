@@ -0,0 +1,113 @@
+package har
+
+import (
+	"sort"
+	"strings"
+)
+
+// MatchesExclusion reports whether an exclusion pattern covers the entry. A
+// pattern ending in "://" excludes a scheme (e.g. "chrome-extension://"),
+// any other pattern with "://" one exact URL, ExcludeExtensions all browser
+// extension traffic, and anything else a host and its subdomains (e.g.
+// "localhost" or "doubleclick.net").
+func MatchesExclusion(entry Entry, pattern string) bool {
+	pattern = strings.TrimSpace(pattern)
+	switch {
+	case pattern == "":
+		return false
+	case pattern == ExcludeExtensions:
+		return IsExtensionEntry(entry)
+	case strings.HasSuffix(pattern, "://"):
+		return strings.HasPrefix(strings.ToLower(entry.Request.URL), strings.ToLower(pattern))
+	case strings.Contains(pattern, "://"):
+		return stripFragment(entry.Request.URL) == stripFragment(pattern)
+	}
+
+	return hostWithin(EntryHost(entry), pattern)
+}
+
+// hostWithin reports whether host is domain or one of its subdomains.
+func hostWithin(host, domain string) bool {
+	host = strings.ToLower(host)
+	domain = strings.ToLower(strings.TrimPrefix(domain, "."))
+	return host == domain || strings.HasSuffix(host, "."+domain)
+}
+
+// ExcludeEntries moves the entries matching any pattern from Log.Entries to
+// Log.Excluded, so every metric, comparison and report is computed without
+// them. It returns how many entries were excluded.
+func ExcludeEntries(har *HAR, patterns []string) int {
+	if len(patterns) == 0 {
+		return 0
+	}
+
+	kept := make([]Entry, 0, len(har.Log.Entries))
+	excluded := 0
+	for _, entry := range har.Log.Entries {
+		matched := false
+		for _, pattern := range patterns {
+			if MatchesExclusion(entry, pattern) {
+				matched = true
+				break
+			}
+		}
+		if matched {
+			har.Log.Excluded = append(har.Log.Excluded, entry)
+			excluded++
+		} else {
+			kept = append(kept, entry)
+		}
+	}
+	har.Log.Entries = kept
+	return excluded
+}
+
+// HideEntries moves the entries at the given indices of Log.Entries to
+// Log.Hidden, leaving them out of analysis like ExcludeEntries but only for
+// the session. It returns how many entries were hidden.
+func HideEntries(har *HAR, indices []int) int {
+	hide := make(map[int]bool, len(indices))
+	for _, index := range indices {
+		hide[index] = true
+	}
+
+	kept := make([]Entry, 0, len(har.Log.Entries))
+	hidden := 0
+	for i, entry := range har.Log.Entries {
+		if hide[i] {
+			har.Log.Hidden = append(har.Log.Hidden, entry)
+			hidden++
+		} else {
+			kept = append(kept, entry)
+		}
+	}
+	har.Log.Entries = kept
+	return hidden
+}
+
+// HideMatching hides the entries an exclusion pattern covers, see
+// MatchesExclusion and HideEntries. It returns how many entries were hidden.
+func HideMatching(har *HAR, pattern string) int {
+	var indices []int
+	for i, entry := range har.Log.Entries {
+		if MatchesExclusion(entry, pattern) {
+			indices = append(indices, i)
+		}
+	}
+	return HideEntries(har, indices)
+}
+
+// UnhideEntries moves every hidden entry back to Log.Entries, in the order
+// the requests started. It returns how many entries were restored.
+func UnhideEntries(har *HAR) int {
+	restored := len(har.Log.Hidden)
+	if restored == 0 {
+		return 0
+	}
+	har.Log.Entries = append(har.Log.Entries, har.Log.Hidden...)
+	har.Log.Hidden = nil
+	sort.SliceStable(har.Log.Entries, func(i, j int) bool {
+		return har.Log.Entries[i].StartedDateTime.Before(har.Log.Entries[j].StartedDateTime)
+	})
+	return restored
+}
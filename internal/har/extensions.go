@@ -0,0 +1,85 @@
+package har
+
+import (
+	"net/url"
+	"strings"
+)
+
+const (
+	// TagExtension is the tag added to requests made by browser extensions.
+	TagExtension = "extension"
+	// ExcludeExtensions is the exclusion pattern matching every request
+	// IsExtensionEntry reports.
+	ExcludeExtensions = "@extensions"
+)
+
+var extensionSchemes = []string{"chrome-extension://", "moz-extension://", "safari-web-extension://", "ms-browser-extension://", "extension://"}
+
+// extensionBeacon is a host and path that only browser extensions request.
+type extensionBeacon struct {
+	host string
+	path string
+}
+
+// Telemetry endpoints of popular extensions and the browsers' extension
+// update services. Vendors' own sites and APIs (lastpass.com, metamask.io,
+// addons.mozilla.org) are left out, since pages legitimately call them.
+var extensionBeacons = []extensionBeacon{
+	{"gnar.grammarly.com", "/"},
+	{"f-log-extension.grammarly.io", "/"},
+	{"clients2.google.com", "/service/update2/crx"},
+	{"clients2.googleusercontent.com", "/crx/"},
+	{"edge.microsoft.com", "/extensionwebstorebase/"},
+	{"versioncheck-bg.addons.mozilla.org", "/update/"},
+	{"easylist-downloads.adblockplus.org", "/"},
+	{"ublockorigin.github.io", "/uAssets/"},
+}
+
+// IsExtensionEntry reports whether a request was made by a browser extension:
+// an extension URL, a request whose Origin or Referer is an extension page,
+// or a request to a known extension beacon.
+func IsExtensionEntry(entry Entry) bool {
+	if isExtensionURL(entry.Request.URL) {
+		return true
+	}
+	for _, name := range []string{"Origin", "Referer"} {
+		if value, ok := HeaderValue(entry.Request.Headers, name); ok && isExtensionURL(value) {
+			return true
+		}
+	}
+
+	parsed, err := url.Parse(entry.Request.URL)
+	if err != nil {
+		return false
+	}
+	host := strings.ToLower(parsed.Hostname())
+	for _, beacon := range extensionBeacons {
+		if host == beacon.host && strings.HasPrefix(parsed.Path, beacon.path) {
+			return true
+		}
+	}
+	return false
+}
+
+func isExtensionURL(rawURL string) bool {
+	rawURL = strings.ToLower(rawURL)
+	for _, scheme := range extensionSchemes {
+		if strings.HasPrefix(rawURL, scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+// TagExtensionEntries tags every extension request with TagExtension and
+// returns how many there were.
+func TagExtensionEntries(har *HAR) int {
+	count := 0
+	for i := range har.Log.Entries {
+		if IsExtensionEntry(har.Log.Entries[i]) {
+			har.Log.Entries[i].AddTag(TagExtension)
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,36 @@
+package har
+
+import "testing"
+
+func TestIsExtensionEntry(t *testing.T) {
+	tests := []struct {
+		url    string
+		origin string
+		want   bool
+	}{
+		{"chrome-extension://abcdef/background.js", "", true},
+		{"moz-extension://1234/content.js", "", true},
+		{"https://api.example.com/data", "chrome-extension://abcdef", true},
+		{"https://gnar.grammarly.com/events", "", true},
+		{"https://clients2.google.com/service/update2/crx?x=id%3Dabc", "", true},
+		{"https://edge.microsoft.com/extensionwebstorebase/v1/crx", "", true},
+
+		{"https://lastpass.com/", "", false},
+		{"https://www.dashlane.com/pricing", "", false},
+		{"https://metamask.io/download/", "", false},
+		{"https://www.joinhoney.com/", "", false},
+		{"https://addons.mozilla.org/en-US/firefox/", "", false},
+		{"https://clients2.google.com/cr/report", "", false},
+		{"https://edge.microsoft.com/translate/auth", "", false},
+		{"https://www.grammarly.com/", "", false},
+	}
+	for _, tt := range tests {
+		entry := Entry{Request: Request{URL: tt.url}}
+		if tt.origin != "" {
+			entry.Request.Headers = []Header{{Name: "Origin", Value: tt.origin}}
+		}
+		if got := IsExtensionEntry(entry); got != tt.want {
+			t.Errorf("IsExtensionEntry(%q, origin %q) = %v, want %v", tt.url, tt.origin, got, tt.want)
+		}
+	}
+}
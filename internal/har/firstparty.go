@@ -0,0 +1,40 @@
+package har
+
+import (
+	"net"
+	"strings"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+// RegistrableDomain returns the eTLD+1 of a host, e.g. "bbc.co.uk" for
+// "www.bbc.co.uk". IP addresses, localhost and bare public suffixes are
+// returned unchanged.
+func RegistrableDomain(host string) string {
+	host = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(host)), ".")
+	if host == "" || net.ParseIP(host) != nil {
+		return host
+	}
+	domain, err := publicsuffix.EffectiveTLDPlusOne(host)
+	if err != nil {
+		return host
+	}
+	return domain
+}
+
+// FirstPartyDomain returns the registrable domain of the captured page: the
+// one in the analyzer's settings, else the page URL when the browser recorded
+// it as the page title, else the first HTML document requested, else the
+// first request.
+func (a *Analyzer) FirstPartyDomain() string {
+	if a.settings.FirstParty != "" {
+		return RegistrableDomain(a.settings.FirstParty)
+	}
+	if a.firstParty != nil {
+		return *a.firstParty
+	}
+
+	domain := RegistrableDomain(a.pageHost())
+	a.firstParty = &domain
+	return domain
+}
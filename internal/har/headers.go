@@ -0,0 +1,60 @@
+package har
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// HeaderValue returns the first header named name, compared case-insensitively.
+func HeaderValue(headers []Header, name string) (string, bool) {
+	for _, header := range headers {
+		if strings.EqualFold(header.Name, name) {
+			return header.Value, true
+		}
+	}
+	return "", false
+}
+
+// HeaderLine is one request or response header as listed in the detail view.
+type HeaderLine struct {
+	Name     string
+	Value    string
+	Response bool
+	// Derived marks pseudo-headers rebuilt from the request line, for HTTP/2
+	// and HTTP/3 captures that left them out
+	Derived bool
+}
+
+// HeaderLines lists every request header, then every response header, in
+// capture order. Set-Cookie headers that a browser folded into one value are
+// split one cookie per line.
+func HeaderLines(entry Entry) []HeaderLine {
+	var lines []HeaderLine
+	multiplexed := IsMultiplexedProtocol(EntryProtocol(entry))
+
+	if _, ok := HeaderValue(entry.Request.Headers, ":method"); multiplexed && !ok {
+		if parsed, err := url.Parse(entry.Request.URL); err == nil {
+			for _, pseudo := range [][2]string{{":method", entry.Request.Method}, {":scheme", parsed.Scheme}, {":authority", parsed.Host}, {":path", parsed.RequestURI()}} {
+				lines = append(lines, HeaderLine{Name: pseudo[0], Value: pseudo[1], Derived: true})
+			}
+		}
+	}
+	for _, header := range entry.Request.Headers {
+		lines = append(lines, HeaderLine{Name: header.Name, Value: header.Value})
+	}
+
+	if _, ok := HeaderValue(entry.Response.Headers, ":status"); multiplexed && !ok {
+		lines = append(lines, HeaderLine{Name: ":status", Value: strconv.Itoa(entry.Response.Status), Response: true, Derived: true})
+	}
+	for _, header := range entry.Response.Headers {
+		values := []string{header.Value}
+		if strings.EqualFold(header.Name, "Set-Cookie") {
+			values = strings.Split(header.Value, "\n")
+		}
+		for _, value := range values {
+			lines = append(lines, HeaderLine{Name: header.Name, Value: value, Response: true})
+		}
+	}
+	return lines
+}
@@ -0,0 +1,155 @@
+package har
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	// LargeHeaderBytes flags header blocks far beyond the usual ~1KB.
+	LargeHeaderBytes = 4096
+	// LargeCookieHeaderBytes flags Cookie headers that bloat every request.
+	LargeCookieHeaderBytes = 2048
+	// InitialCongestionWindow is ten TCP segments (RFC 6928); larger header
+	// blocks need an extra round trip on a fresh connection.
+	InitialCongestionWindow = 10 * 1460
+)
+
+// Headers that may legitimately repeat. HTTP/2 splits Cookie into crumbs.
+var repeatableHeaders = []string{"set-cookie", "cookie", "link", "www-authenticate", "proxy-authenticate", "via", "warning"}
+
+// EntryHeaderStats is the header overhead of one entry.
+type EntryHeaderStats struct {
+	EntryIndex    int
+	URL           string
+	Host          string
+	RequestBytes  int
+	ResponseBytes int
+	CookieBytes   int
+	Duplicates    []string // header names repeated in the request or response
+	Issues        []string
+}
+
+// HeaderDomainStats aggregates header overhead for one host.
+type HeaderDomainStats struct {
+	Domain          string
+	Requests        int
+	RequestBytes    int
+	ResponseBytes   int
+	MaxRequestBytes int
+	Flagged         int
+}
+
+type HeaderReport struct {
+	Flagged []EntryHeaderStats
+	Domains []HeaderDomainStats
+
+	// ExceedsCongestionWindow is set when any header block is larger than
+	// the initial congestion window.
+	ExceedsCongestionWindow bool
+}
+
+// HeaderStats measures the header blocks of an entry and lists its issues,
+// with sizes in units.
+func HeaderStats(entry Entry, units SizeUnits) EntryHeaderStats {
+	stats := EntryHeaderStats{
+		URL:           entry.Request.URL,
+		Host:          EntryHost(entry),
+		RequestBytes:  headerBlockSize(entry.Request.HeadersSize, entry.Request.Headers),
+		ResponseBytes: headerBlockSize(entry.Response.HeadersSize, entry.Response.Headers),
+	}
+
+	for _, header := range entry.Request.Headers {
+		if strings.EqualFold(header.Name, "Cookie") {
+			stats.CookieBytes += len(header.Value)
+		}
+	}
+	stats.Duplicates = append(duplicateHeaders(entry.Request.Headers), duplicateHeaders(entry.Response.Headers)...)
+
+	if stats.RequestBytes > LargeHeaderBytes {
+		stats.Issues = append(stats.Issues, fmt.Sprintf("large request headers (%s)", units.FormatSize(stats.RequestBytes)))
+	}
+	if stats.ResponseBytes > LargeHeaderBytes {
+		stats.Issues = append(stats.Issues, fmt.Sprintf("large response headers (%s)", units.FormatSize(stats.ResponseBytes)))
+	}
+	if stats.CookieBytes > LargeCookieHeaderBytes {
+		stats.Issues = append(stats.Issues, fmt.Sprintf("cookie bloat (%s of cookies)", units.FormatSize(stats.CookieBytes)))
+	}
+	if len(stats.Duplicates) > 0 {
+		stats.Issues = append(stats.Issues, "duplicated "+strings.Join(stats.Duplicates, ", "))
+	}
+
+	return stats
+}
+
+// HeaderAnalysis reports entries with oversized or duplicated headers and
+// aggregates header bytes per domain, heaviest request headers first.
+func (a *Analyzer) HeaderAnalysis() HeaderReport {
+	var report HeaderReport
+	domains := make(map[string]*HeaderDomainStats)
+
+	for i, entry := range a.har.Log.Entries {
+		stats := HeaderStats(entry, a.settings.SizeUnits)
+		stats.EntryIndex = i
+
+		domain, ok := domains[stats.Host]
+		if !ok {
+			domain = &HeaderDomainStats{Domain: stats.Host}
+			domains[stats.Host] = domain
+		}
+		domain.Requests++
+		domain.RequestBytes += stats.RequestBytes
+		domain.ResponseBytes += stats.ResponseBytes
+		domain.MaxRequestBytes = max(domain.MaxRequestBytes, stats.RequestBytes)
+
+		if len(stats.Issues) > 0 {
+			domain.Flagged++
+			report.Flagged = append(report.Flagged, stats)
+		}
+		if stats.RequestBytes > InitialCongestionWindow || stats.ResponseBytes > InitialCongestionWindow {
+			report.ExceedsCongestionWindow = true
+		}
+	}
+
+	for _, domain := range domains {
+		report.Domains = append(report.Domains, *domain)
+	}
+	sort.Slice(report.Domains, func(i, j int) bool {
+		if report.Domains[i].RequestBytes != report.Domains[j].RequestBytes {
+			return report.Domains[i].RequestBytes > report.Domains[j].RequestBytes
+		}
+		return report.Domains[i].Domain < report.Domains[j].Domain
+	})
+
+	return report
+}
+
+// headerBlockSize uses the recorded size, or estimates "Name: value\r\n" lines
+// when the capture reports -1 (e.g. HTTP/2 in some browsers).
+func headerBlockSize(recorded int, headers []Header) int {
+	if recorded > 0 {
+		return recorded
+	}
+	size := 0
+	for _, header := range headers {
+		size += len(header.Name) + len(header.Value) + 4
+	}
+	return size
+}
+
+func duplicateHeaders(headers []Header) []string {
+	counts := make(map[string]int)
+	var names []string
+	for _, header := range headers {
+		name := strings.ToLower(header.Name)
+		if strings.HasPrefix(name, ":") || containsString(repeatableHeaders, name) {
+			continue
+		}
+		counts[name]++
+		if counts[name] == 2 {
+			names = append(names, header.Name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,166 @@
+package har
+
+import (
+	"net/url"
+	"sort"
+	"time"
+)
+
+// HeatmapMinDuration is the capture length from which a latency heatmap
+// says more than the overall percentiles.
+const HeatmapMinDuration = 30 * time.Second
+
+// DegradedRatio is how much slower an endpoint must get from the first to the
+// last third of the capture to count as degrading, see Degradation.
+const DegradedRatio = 1.5
+
+// LatencyHeatmap is the p95 response time of the busiest endpoints in equal
+// time buckets over the capture.
+type LatencyHeatmap struct {
+	Start       time.Time     `json:"start"`
+	BucketWidth time.Duration `json:"bucket_width"`
+	Endpoints   []string      `json:"endpoints"`
+	// P95 has one row per endpoint and one column per bucket; buckets without
+	// requests to the endpoint are -1
+	P95    [][]float64 `json:"p95"`
+	Counts [][]int     `json:"counts"`
+}
+
+// Duration is the time the heatmap spans.
+func (h LatencyHeatmap) Duration() time.Duration {
+	if len(h.P95) == 0 {
+		return 0
+	}
+	return h.BucketWidth * time.Duration(len(h.P95[0]))
+}
+
+// Degradation returns how many times slower the endpoint's worst bucket p95
+// was in the last third of the buckets than in the first, or 0 when either
+// third has no requests.
+func (h LatencyHeatmap) Degradation(endpoint int) float64 {
+	row := h.P95[endpoint]
+	third := len(row) / 3
+	if third == 0 {
+		return 0
+	}
+	first, last := maxValue(row[:third]), maxValue(row[len(row)-third:])
+	if first <= 0 || last < 0 {
+		return 0
+	}
+	return last / first
+}
+
+// Degrading reports whether the endpoint slowed down by DegradedRatio.
+func (h LatencyHeatmap) Degrading(endpoint int) bool {
+	return h.Degradation(endpoint) >= DegradedRatio
+}
+
+func maxValue(values []float64) float64 {
+	result := -1.0
+	for _, value := range values {
+		if value > result {
+			result = value
+		}
+	}
+	return result
+}
+
+// EndpointKey groups requests by method, host and path, ignoring the query.
+func EndpointKey(entry Entry) string {
+	parsed, err := url.Parse(entry.Request.URL)
+	if err != nil {
+		return entry.Request.Method + " " + entry.Request.URL
+	}
+	return entry.Request.Method + " " + parsed.Host + parsed.Path
+}
+
+// LatencyHeatmap buckets the capture into the given number of time slices
+// and computes each slice's p95 response time for the maxEndpoints endpoints
+// requested most often. Endpoints requested only once are left out, since
+// they cannot change over time.
+func (a *Analyzer) LatencyHeatmap(buckets, maxEndpoints int) LatencyHeatmap {
+	entries := a.har.Log.Entries
+	if len(entries) == 0 || buckets <= 0 {
+		return LatencyHeatmap{}
+	}
+
+	start, end := entries[0].StartedDateTime, entries[0].StartedDateTime
+	byEndpoint := map[string][]Entry{}
+	for _, entry := range entries {
+		start = minTime(start, entry.StartedDateTime)
+		end = maxTime(end, entry.StartedDateTime)
+		key := EndpointKey(entry)
+		byEndpoint[key] = append(byEndpoint[key], entry)
+	}
+
+	var endpoints []string
+	for key, requests := range byEndpoint {
+		if len(requests) > 1 {
+			endpoints = append(endpoints, key)
+		}
+	}
+	sort.Slice(endpoints, func(i, j int) bool {
+		if len(byEndpoint[endpoints[i]]) != len(byEndpoint[endpoints[j]]) {
+			return len(byEndpoint[endpoints[i]]) > len(byEndpoint[endpoints[j]])
+		}
+		return endpoints[i] < endpoints[j]
+	})
+	if maxEndpoints > 0 && len(endpoints) > maxEndpoints {
+		endpoints = endpoints[:maxEndpoints]
+	}
+
+	// Buckets are assigned by request start, so the last start falls in the
+	// last bucket
+	width := end.Sub(start)/time.Duration(buckets) + 1
+	heatmap := LatencyHeatmap{Start: start, BucketWidth: width, Endpoints: endpoints}
+	for _, key := range endpoints {
+		times := make([][]float64, buckets)
+		for _, entry := range byEndpoint[key] {
+			bucket := int(entry.StartedDateTime.Sub(start) / width)
+			times[bucket] = append(times[bucket], entry.Time)
+		}
+
+		p95 := make([]float64, buckets)
+		counts := make([]int, buckets)
+		for i, bucket := range times {
+			counts[i] = len(bucket)
+			if len(bucket) == 0 {
+				p95[i] = -1
+				continue
+			}
+			sort.Float64s(bucket)
+			p95[i] = Percentile(bucket, 0.95)
+		}
+		heatmap.P95 = append(heatmap.P95, p95)
+		heatmap.Counts = append(heatmap.Counts, counts)
+	}
+	return heatmap
+}
+
+// CaptureDuration is the time from the first to the last request start.
+func (a *Analyzer) CaptureDuration() time.Duration {
+	entries := a.har.Log.Entries
+	if len(entries) == 0 {
+		return 0
+	}
+	start, end := entries[0].StartedDateTime, entries[0].StartedDateTime
+	for _, entry := range entries {
+		start = minTime(start, entry.StartedDateTime)
+		end = maxTime(end, entry.StartedDateTime)
+	}
+	return end.Sub(start)
+}
+
+// HeatmapThresholds are the p95 response times, in milliseconds, at which a
+// heatmap cell moves to the next color level.
+var HeatmapThresholds = []float64{100, 300, 1000, 3000}
+
+// HeatmapLevel returns the color level of a p95 value, from 0 (fast) to
+// len(HeatmapThresholds) (slowest).
+func HeatmapLevel(p95 float64) int {
+	level := 0
+	for level < len(HeatmapThresholds) && p95 >= HeatmapThresholds[level] {
+		level++
+	}
+	return level
+}
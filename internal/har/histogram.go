@@ -0,0 +1,70 @@
+package har
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// LatencyBounds are the upper bounds in ms of the latency histogram's
+// buckets, on a 1-2-5 log scale. A final bucket holds anything slower.
+var LatencyBounds = []float64{10, 20, 50, 100, 200, 500, 1000, 2000, 5000, 10000}
+
+// HistogramQuantiles are the percentiles marked on a latency histogram.
+var HistogramQuantiles = []float64{0.5, 0.75, 0.9, 0.95, 0.99}
+
+// LatencyHistogram counts requests by total time.
+type LatencyHistogram struct {
+	Counts      []int     // requests per bucket, one more than LatencyBounds
+	Total       int       // requests counted
+	Percentiles []float64 // ms, one per HistogramQuantiles
+}
+
+// NewLatencyHistogram buckets the entries by total time. A bucket includes
+// its upper bound.
+func NewLatencyHistogram(entries []Entry) LatencyHistogram {
+	histogram := LatencyHistogram{Counts: make([]int, len(LatencyBounds)+1), Total: len(entries)}
+	times := make([]float64, len(entries))
+	for i, entry := range entries {
+		times[i] = entry.Time
+		histogram.Counts[LatencyBucket(entry.Time)]++
+	}
+
+	sort.Float64s(times)
+	for _, quantile := range HistogramQuantiles {
+		histogram.Percentiles = append(histogram.Percentiles, Percentile(times, quantile))
+	}
+	return histogram
+}
+
+// LatencyBucket returns the index of the bucket a time in ms falls in.
+func LatencyBucket(ms float64) int {
+	return sort.SearchFloat64s(LatencyBounds, ms)
+}
+
+// BucketLabel describes a bucket's range, e.g. "20–50ms".
+func BucketLabel(bucket int) string {
+	switch {
+	case bucket == 0:
+		return "≤" + formatLatency(LatencyBounds[0])
+	case bucket >= len(LatencyBounds):
+		return ">" + formatLatency(LatencyBounds[len(LatencyBounds)-1])
+	}
+	return fmt.Sprintf("%s–%s", formatLatency(LatencyBounds[bucket-1]), formatLatency(LatencyBounds[bucket]))
+}
+
+func formatLatency(ms float64) string {
+	if ms >= 1000 {
+		return fmt.Sprintf("%gs", ms/1000)
+	}
+	return fmt.Sprintf("%gms", ms)
+}
+
+// Percentile returns the nearest-rank percentile of sorted values.
+func Percentile(sorted []float64, quantile float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(quantile*float64(len(sorted)))) - 1
+	return sorted[max(rank, 0)]
+}
@@ -0,0 +1,155 @@
+package har
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// HSTSPreloadMinAge is the max-age, one year, the HSTS preload list requires.
+const HSTSPreloadMinAge = 31536000
+
+// HSTSPolicy is a parsed Strict-Transport-Security header.
+type HSTSPolicy struct {
+	MaxAge            int64
+	IncludeSubDomains bool
+	Preload           bool
+}
+
+// ParseHSTS parses a Strict-Transport-Security header value. A missing or
+// invalid max-age is zero, which browsers treat as no policy.
+func ParseHSTS(value string) HSTSPolicy {
+	var policy HSTSPolicy
+	for _, directive := range strings.Split(value, ";") {
+		name, argument, _ := strings.Cut(strings.TrimSpace(directive), "=")
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "max-age":
+			policy.MaxAge, _ = strconv.ParseInt(strings.Trim(strings.TrimSpace(argument), `"`), 10, 64)
+		case "includesubdomains":
+			policy.IncludeSubDomains = true
+		case "preload":
+			policy.Preload = true
+		}
+	}
+	return policy
+}
+
+// PreloadProblems lists what keeps the policy off the preload list.
+func (p HSTSPolicy) PreloadProblems() []string {
+	var problems []string
+	if p.MaxAge < HSTSPreloadMinAge {
+		problems = append(problems, fmt.Sprintf("max-age %d is under one year", p.MaxAge))
+	}
+	if !p.IncludeSubDomains {
+		problems = append(problems, "no includeSubDomains")
+	}
+	if !p.Preload {
+		problems = append(problems, "no preload directive")
+	}
+	return problems
+}
+
+// HostHSTS is the HSTS posture of one captured host.
+type HostHSTS struct {
+	Host       string
+	FirstParty bool
+	// EntryIndex is the host's first request
+	EntryIndex int
+	// Header is the first Strict-Transport-Security header the host sent
+	// over HTTPS; empty when it sent none
+	Header string
+	Policy HSTSPolicy
+	// Problems keep the host's registrable domain off the preload list
+	Problems []string
+	// HTTPRequests counts plain-HTTP requests to the host, and
+	// HTTPNotRedirected those answered without a redirect to HTTPS
+	HTTPRequests      int
+	HTTPNotRedirected int
+	// RedirectVulnerable marks a first-party host whose first visit can be
+	// made over HTTP, because no preload-ready policy covers it
+	RedirectVulnerable bool
+}
+
+// PreloadReady reports whether the host meets the preload list requirements
+// seen in the capture.
+func (h HostHSTS) PreloadReady() bool {
+	return len(h.Problems) == 0
+}
+
+// HSTSReadiness checks every captured host's Strict-Transport-Security
+// headers against the HSTS preload requirements, first-party hosts first.
+// Only registrable domains are preloaded, with their subdomains, so a host is
+// covered on first visit when the capture shows its registrable domain
+// preload-ready; a domain that was not captured counts as not covered.
+func (a *Analyzer) HSTSReadiness() []HostHSTS {
+	byHost := map[string]*HostHSTS{}
+	var hosts []string
+	for i, entry := range a.har.Log.Entries {
+		parsed, err := url.Parse(entry.Request.URL)
+		if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") {
+			continue
+		}
+		// Browsers ignore HSTS for IP addresses and localhost
+		host := strings.ToLower(parsed.Hostname())
+		if net.ParseIP(host) != nil || host == "localhost" || strings.HasSuffix(host, ".localhost") {
+			continue
+		}
+		hsts, ok := byHost[host]
+		if !ok {
+			hsts = &HostHSTS{Host: host, FirstParty: !a.isThirdParty(entry.Request.URL), EntryIndex: i}
+			byHost[host] = hsts
+			hosts = append(hosts, host)
+		}
+
+		if parsed.Scheme == "http" {
+			hsts.HTTPRequests++
+			if !strings.HasPrefix(strings.ToLower(redirectLocation(entry)), "https://") {
+				hsts.HTTPNotRedirected++
+			}
+			continue
+		}
+		if value, ok := HeaderValue(entry.Response.Headers, "Strict-Transport-Security"); ok && hsts.Header == "" {
+			hsts.Header = value
+			hsts.Policy = ParseHSTS(value)
+		}
+	}
+
+	readiness := make([]HostHSTS, 0, len(hosts))
+	for _, host := range hosts {
+		hsts := byHost[host]
+		if hsts.Header == "" {
+			hsts.Problems = append(hsts.Problems, "no Strict-Transport-Security header")
+		} else {
+			hsts.Problems = append(hsts.Problems, hsts.Policy.PreloadProblems()...)
+		}
+		if hsts.HTTPNotRedirected > 0 {
+			hsts.Problems = append(hsts.Problems, "serves HTTP without redirecting to HTTPS")
+		}
+		readiness = append(readiness, *hsts)
+	}
+
+	for i := range readiness {
+		if !readiness[i].FirstParty {
+			continue
+		}
+		domain := RegistrableDomain(readiness[i].Host)
+		covered := false
+		for _, other := range readiness {
+			if other.Host == domain && other.PreloadReady() {
+				covered = true
+			}
+		}
+		readiness[i].RedirectVulnerable = !covered
+	}
+
+	sort.SliceStable(readiness, func(i, j int) bool {
+		if readiness[i].FirstParty != readiness[j].FirstParty {
+			return readiness[i].FirstParty
+		}
+		return readiness[i].Host < readiness[j].Host
+	})
+	return readiness
+}
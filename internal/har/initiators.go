@@ -0,0 +1,114 @@
+package har
+
+import "sort"
+
+// InitiatorNode is one request of the dependency graph.
+type InitiatorNode struct {
+	Entry    int    // entry index
+	Parent   int    // entry that started the request, -1 for the browser or unknown
+	Cause    string // "redirect", or Chrome's initiator type, e.g. "parser" or "script"; empty without initiator data
+	Children []int  // entries the request started, earliest first
+	Depth    int    // requests between it and its root
+}
+
+// InitiatorGraph is what started every request of a capture, built from
+// Chrome's initiator data and redirects.
+type InitiatorGraph struct {
+	Nodes []InitiatorNode // one per entry, in entry order
+	Roots []int           // entries without a parent, earliest first
+}
+
+// Order returns the entries depth first, each after the request that
+// started it, the way a tree of the graph reads.
+func (g InitiatorGraph) Order() []int {
+	order := make([]int, 0, len(g.Nodes))
+	var visit func(index int)
+	visit = func(index int) {
+		order = append(order, index)
+		for _, child := range g.Nodes[index].Children {
+			visit(child)
+		}
+	}
+	for _, root := range g.Roots {
+		visit(root)
+	}
+	return order
+}
+
+// HasInitiators reports whether any entry carries Chrome's initiator data.
+func (a *Analyzer) HasInitiators() bool {
+	for _, entry := range a.har.Log.Entries {
+		if entry.Initiator != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// InitiatorGraph links every request to the one that started it: the
+// redirect it followed, else the document or script its initiator names.
+// Unlike CriticalPath it never guesses from timing, so requests without
+// initiator data are roots.
+func (a *Analyzer) InitiatorGraph() InitiatorGraph {
+	entries := a.har.Log.Entries
+	graph := InitiatorGraph{Nodes: make([]InitiatorNode, len(entries))}
+
+	byURL := make(map[string][]int)
+	for i, entry := range entries {
+		key := stripFragment(entry.Request.URL)
+		byURL[key] = append(byURL[key], i)
+	}
+
+	for i, entry := range entries {
+		node := InitiatorNode{Entry: i, Parent: -1}
+		if entry.Initiator != nil {
+			node.Cause = entry.Initiator.Type
+			node.Parent, _ = a.initiatorOf(i, byURL)
+		}
+		graph.Nodes[i] = node
+	}
+	for _, chain := range a.RedirectChains() {
+		for h := 1; h < len(chain.Hops); h++ {
+			node := &graph.Nodes[chain.Hops[h].EntryIndex]
+			node.Parent, node.Cause = chain.Hops[h-1].EntryIndex, "redirect"
+		}
+	}
+
+	// Requests that started at the same moment can name each other; cut any
+	// link that would close a loop
+	for i := range graph.Nodes {
+		seen := map[int]bool{i: true}
+		for parent := graph.Nodes[i].Parent; parent >= 0; parent = graph.Nodes[parent].Parent {
+			if seen[parent] {
+				if parent == i {
+					graph.Nodes[i].Parent = -1
+				}
+				break
+			}
+			seen[parent] = true
+		}
+	}
+
+	for i, node := range graph.Nodes {
+		if node.Parent < 0 {
+			graph.Roots = append(graph.Roots, i)
+			continue
+		}
+		graph.Nodes[node.Parent].Children = append(graph.Nodes[node.Parent].Children, i)
+	}
+	earliest := func(indices []int) {
+		sort.SliceStable(indices, func(i, j int) bool {
+			return entries[indices[i]].StartedDateTime.Before(entries[indices[j]].StartedDateTime)
+		})
+	}
+	earliest(graph.Roots)
+	for i := range graph.Nodes {
+		earliest(graph.Nodes[i].Children)
+	}
+	for _, index := range graph.Order() {
+		if parent := graph.Nodes[index].Parent; parent >= 0 {
+			graph.Nodes[index].Depth = graph.Nodes[parent].Depth + 1
+		}
+	}
+	return graph
+}
@@ -0,0 +1,55 @@
+package har
+
+import "strings"
+
+// TagInsecure is the tag added to entries with an InsecureReasons finding.
+const TagInsecure = "insecure"
+
+// InsecureReasons explains why an entry weakens transport security: plain
+// HTTP loaded by an HTTPS page (mixed content), a redirect from HTTPS to HTTP,
+// or a new connection to an HTTPS origin without a TLS handshake recorded.
+// The requesting page is taken from the Referer header, falling back to the
+// capture's first request.
+func (a *Analyzer) InsecureReasons(entry Entry) []string {
+	var reasons []string
+
+	url := strings.ToLower(entry.Request.URL)
+	if strings.HasPrefix(url, "http://") && strings.HasPrefix(strings.ToLower(a.requestingPage(entry)), "https://") {
+		reasons = append(reasons, "mixed content: HTTP request from an HTTPS page")
+	}
+
+	location := entry.Response.RedirectURL
+	if location == "" {
+		location, _ = HeaderValue(entry.Response.Headers, "Location")
+	}
+	if strings.HasPrefix(url, "https://") && strings.HasPrefix(strings.ToLower(location), "http://") {
+		reasons = append(reasons, "downgrade redirect to "+location)
+	}
+
+	if strings.HasPrefix(url, "https://") && entry.Timings.Connect > 0 && entry.Timings.SSL <= 0 {
+		reasons = append(reasons, "secure origin connected without SSL timing")
+	}
+
+	return reasons
+}
+
+func (a *Analyzer) requestingPage(entry Entry) string {
+	if referer, ok := HeaderValue(entry.Request.Headers, "Referer"); ok && referer != "" {
+		return referer
+	}
+	if len(a.har.Log.Entries) > 0 {
+		return a.har.Log.Entries[0].Request.URL
+	}
+	return ""
+}
+
+// TagInsecureEntries tags every insecure entry of the HAR file so it can be
+// filtered with "tag:insecure".
+func TagInsecureEntries(har *HAR) {
+	analyzer := NewAnalyzer(har, Settings{})
+	for i := range har.Log.Entries {
+		if len(analyzer.InsecureReasons(har.Log.Entries[i])) > 0 {
+			har.Log.Entries[i].AddTag(TagInsecure)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package har
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// InsightSeverity ranks how much an insight matters.
+type InsightSeverity string
+
+const (
+	InsightInfo     InsightSeverity = "info"
+	InsightWarning  InsightSeverity = "warning"
+	InsightCritical InsightSeverity = "critical"
+)
+
+var severityRanks = map[InsightSeverity]int{InsightInfo: 0, InsightWarning: 1, InsightCritical: 2}
+
+// ParseInsightSeverity parses "info", "warning" or "critical".
+func ParseInsightSeverity(value string) (InsightSeverity, error) {
+	severity := InsightSeverity(strings.ToLower(value))
+	if _, ok := severityRanks[severity]; !ok {
+		return "", fmt.Errorf("unknown insight severity %q (expected info, warning or critical)", value)
+	}
+	return severity, nil
+}
+
+// AtLeast reports whether s is as severe as level or more.
+func (s InsightSeverity) AtLeast(level InsightSeverity) bool {
+	return severityRanks[s] >= severityRanks[level]
+}
+
+// Insight is one finding from comparing a capture against the baseline.
+type Insight struct {
+	ID       string          `json:"id"` // e.g. "page_load_time_regressed"
+	Severity InsightSeverity `json:"severity"`
+	Message  string          `json:"message"`
+	Metric   string          `json:"metric,omitempty"`
+	File     string          `json:"file,omitempty"`     // the capture compared against the baseline
+	Evidence string          `json:"evidence,omitempty"` // baseline and compared values, e.g. "1.2s → 1.8s"
+	Delta    float64         `json:"delta"`              // compared minus baseline value, in the metric's unit
+}
+
+// String renders the insight as a sentence with its evidence.
+func (i Insight) String() string {
+	if i.Evidence == "" {
+		return i.Message
+	}
+	return fmt.Sprintf("%s (%s: %s)", i.Message, i.File, i.Evidence)
+}
+
+// insightRule turns the change of one metric into an insight.
+type insightRule struct {
+	metric   string
+	improved string
+	worse    string
+	severity InsightSeverity // of a regression
+	stable   string          // reported when the metric did not change, if set
+}
+
+var insightRules = []insightRule{
+	{metric: MetricPageLoadTime, improved: "Page load time improved", worse: "Page load time regressed - investigate performance", severity: InsightWarning},
+	{metric: MetricErrorRequests, improved: "Error rate improved", worse: "Error rate increased - check for new issues", severity: InsightCritical, stable: "Error rate remained stable"},
+	{metric: MetricCacheHitRatio, improved: "Cache efficiency improved", worse: "Cache efficiency decreased", severity: InsightWarning},
+	{metric: MetricCDNHitRatio, improved: "CDN hit ratio improved", worse: "CDN hit ratio decreased - more requests reach the origin", severity: InsightWarning},
+	{metric: MetricTotalSize, improved: "Transfer size optimized", worse: "Transfer size increased - check for new assets", severity: InsightWarning},
+}
+
+// CoverageInsightPoints is the change in percentage points of a coverage
+// metric (e.g. Brotli coverage) that is called out as an insight.
+const CoverageInsightPoints = 10.0
+
+// insights compares each capture against the baseline: the headline metrics
+// in insightRules, then large swings in coverage metrics (IDs ending in
+// "_coverage"), then Core Web Vitals that moved to another rating.
+func (c *Comparator) insights(differences []MetricDifference) []Insight {
+	if !c.metrics[0].HasData() {
+		return []Insight{{ID: "baseline_empty", Severity: InsightWarning, Message: "The baseline capture has no entries, so changes cannot be computed"}}
+	}
+
+	changes := make(map[string]MetricDifference)
+	for _, diff := range differences {
+		changes[diff.ID] = diff
+	}
+
+	var insights []Insight
+	for i := 1; i < len(c.metrics); i++ {
+		for _, rule := range insightRules {
+			descriptor, ok := LookupMetric(rule.metric)
+			diff := changes[rule.metric]
+			if !ok || i >= len(diff.Changes) || diff.Changes[i] == NotAvailable {
+				continue
+			}
+			insight := c.metricInsight(descriptor, i)
+			switch {
+			case !diff.Flagged(i):
+				if rule.stable == "" {
+					continue
+				}
+				insight.ID, insight.Severity, insight.Message = rule.metric+"_stable", InsightInfo, rule.stable
+			case descriptor.IsImprovement(insight.Delta):
+				insight.ID, insight.Severity, insight.Message = rule.metric+"_improved", InsightInfo, rule.improved
+			default:
+				insight.ID, insight.Severity, insight.Message = rule.metric+"_regressed", rule.severity, rule.worse
+			}
+			insights = append(insights, insight)
+		}
+
+		for _, descriptor := range metricRegistry {
+			if !strings.HasSuffix(descriptor.ID, "_coverage") || !descriptor.Measured(c.metrics[0]) || !descriptor.Measured(c.metrics[i]) {
+				continue
+			}
+			insight := c.metricInsight(descriptor, i)
+			if math.Abs(insight.Delta) < CoverageInsightPoints {
+				continue
+			}
+			if insight.Delta > 0 {
+				insight.ID, insight.Severity, insight.Message = descriptor.ID+"_improved", InsightInfo, descriptor.Name+" rose"
+			} else {
+				insight.ID, insight.Severity, insight.Message = descriptor.ID+"_regressed", InsightWarning, descriptor.Name+" dropped"
+			}
+			insights = append(insights, insight)
+		}
+
+		for _, id := range WebVitalIDs {
+			descriptor, ok := LookupMetric(id)
+			if !ok || !descriptor.Measured(c.metrics[0]) || !descriptor.Measured(c.metrics[i]) {
+				continue
+			}
+			before, _ := RateVital(id, descriptor.Value(c.metrics[0]))
+			after, _ := RateVital(id, descriptor.Value(c.metrics[i]))
+			if before == after {
+				continue
+			}
+			insight := c.metricInsight(descriptor, i)
+			insight.Message = fmt.Sprintf("%s went from %s to %s", descriptor.Name, before, after)
+			switch {
+			case after.Rank() < before.Rank():
+				insight.ID, insight.Severity = id+"_rating_improved", InsightInfo
+			case after == VitalPoor:
+				insight.ID, insight.Severity = id+"_rating_regressed", InsightCritical
+			default:
+				insight.ID, insight.Severity = id+"_rating_regressed", InsightWarning
+			}
+			insights = append(insights, insight)
+		}
+	}
+
+	if len(insights) == 0 {
+		insights = append(insights, Insight{ID: "stable", Severity: InsightInfo, Message: "Performance appears stable across files"})
+	}
+	return insights
+}
+
+// metricInsight fills in the evidence of an insight about a metric of the
+// capture at index against the baseline.
+func (c *Comparator) metricInsight(descriptor MetricDescriptor, index int) Insight {
+	base, value := descriptor.Value(c.metrics[0]), descriptor.Value(c.metrics[index])
+	return Insight{
+		Metric:   descriptor.ID,
+		File:     c.fileName(index),
+		Evidence: descriptor.Format(base, c.settings.SizeUnits) + " → " + descriptor.Format(value, c.settings.SizeUnits),
+		Delta:    value - base,
+	}
+}
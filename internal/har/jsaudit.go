@@ -0,0 +1,225 @@
+package har
+
+import (
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// LargeBundleBytes is the decoded size above which a script is flagged as a
+// very large bundle: it costs parse and compile time on every page view,
+// however well it compresses.
+const LargeBundleBytes = 500 * 1024
+
+// JSBundle is one script file on one host, e.g. app.min.js on
+// static.example.com, however many times it was requested.
+type JSBundle struct {
+	Domain   string `json:"domain"`
+	Name     string `json:"name"` // file name, without query string
+	Requests int    `json:"requests"`
+	Bytes    int64  `json:"bytes"`    // decoded size of the script, the largest download when it changed
+	Transfer int64  `json:"transfer"` // bytes on the wire over every request
+	Large    bool   `json:"large"`    // over LargeBundleBytes
+}
+
+// JSDomain totals the scripts served by one host.
+type JSDomain struct {
+	Domain   string `json:"domain"`
+	Bundles  int    `json:"bundles"`
+	Requests int    `json:"requests"`
+	Bytes    int64  `json:"bytes"`
+	Transfer int64  `json:"transfer"`
+}
+
+// DuplicateLibrary is a well-known library the page loaded more than once,
+// from several hosts (e.g. two CDNs) or in several versions.
+type DuplicateLibrary struct {
+	Library  string   `json:"library"`
+	Hosts    []string `json:"hosts"`
+	Versions []string `json:"versions"` // empty when no URL names a version
+	Requests int      `json:"requests"`
+	Bytes    int64    `json:"bytes"`
+}
+
+// SourceMapLeak is a source map a production page downloaded successfully,
+// exposing the original source of its scripts.
+type SourceMapLeak struct {
+	URL    string `json:"url"`
+	Status int    `json:"status"`
+	Bytes  int64  `json:"bytes"`
+}
+
+// JSAudit is the JavaScript payload of a capture.
+type JSAudit struct {
+	Requests           int                `json:"requests"`
+	Bytes              int64              `json:"bytes"`
+	Transfer           int64              `json:"transfer"`
+	Domains            []JSDomain         `json:"domains"` // most bytes first
+	Bundles            []JSBundle         `json:"bundles"` // most bytes first
+	DuplicateLibraries []DuplicateLibrary `json:"duplicate_libraries"`
+	SourceMaps         []SourceMapLeak    `json:"source_maps"`
+}
+
+// HasData reports whether the capture loaded any JavaScript.
+func (a JSAudit) HasData() bool {
+	return a.Requests > 0
+}
+
+// LargeBundles counts the bundles over LargeBundleBytes.
+func (a JSAudit) LargeBundles() int {
+	count := 0
+	for _, bundle := range a.Bundles {
+		if bundle.Large {
+			count++
+		}
+	}
+	return count
+}
+
+// jsLibraries are the libraries recognized in script URLs, longer names
+// first so react-dom is not mistaken for react.
+var jsLibraries = []string{
+	"jquery-ui", "jquery", "react-dom", "react", "vue", "angular", "lodash", "underscore",
+	"moment", "bootstrap", "popper", "d3", "axios", "backbone", "three", "gsap", "swiper",
+}
+
+var (
+	jsLibraryPatterns = func() []*regexp.Regexp {
+		patterns := make([]*regexp.Regexp, len(jsLibraries))
+		for i, library := range jsLibraries {
+			patterns[i] = regexp.MustCompile(`(?:^|[/@._-])` + regexp.QuoteMeta(library) + `(?:[/@._-]|$)`)
+		}
+		return patterns
+	}()
+	jsVersionPattern = regexp.MustCompile(`[/@-]v?(\d+\.\d+(?:\.\d+)?)`)
+)
+
+// jsLibrary names the library and version a script URL path points at, e.g.
+// ("jquery", "3.6.0") for /ajax/libs/jquery/3.6.0/jquery.min.js.
+func jsLibrary(urlPath string) (string, string) {
+	lower := strings.ToLower(urlPath)
+	for i, pattern := range jsLibraryPatterns {
+		location := pattern.FindStringIndex(lower)
+		if location == nil {
+			continue
+		}
+		version := ""
+		if match := jsVersionPattern.FindStringSubmatch(lower[location[0]:]); match != nil {
+			version = match[1]
+		}
+		return jsLibraries[i], version
+	}
+	return "", ""
+}
+
+// isScript reports whether the entry downloaded JavaScript, by MIME type or
+// file extension.
+func isScript(entry Entry, urlPath string) bool {
+	if ResourceType(entry.Response.Content.MimeType) == "javascript" {
+		return true
+	}
+	extension := strings.ToLower(path.Ext(urlPath))
+	return extension == ".js" || extension == ".mjs"
+}
+
+// JSAudit groups the capture's scripts by host and file, flags bundles over
+// LargeBundleBytes and well-known libraries loaded from several hosts or in
+// several versions, and, in production captures, lists source maps that were
+// downloaded successfully.
+func (a *Analyzer) JSAudit() JSAudit {
+	var audit JSAudit
+	domains := make(map[string]*JSDomain)
+	bundles := make(map[string]*JSBundle)
+	libraries := make(map[string]*DuplicateLibrary)
+	var libraryOrder []string
+	production := a.Environment() == EnvProduction
+
+	for _, entry := range a.har.Log.Entries {
+		parsed, err := url.Parse(entry.Request.URL)
+		if err != nil || parsed.Host == "" {
+			continue
+		}
+		host := strings.ToLower(parsed.Hostname())
+		size := int64(max(entry.Response.Content.Size, 0))
+		transfer := int64(max(TransferSize(entry), 0))
+
+		if strings.HasSuffix(strings.ToLower(parsed.Path), ".map") {
+			if production && entry.Response.Status >= 200 && entry.Response.Status < 400 {
+				audit.SourceMaps = append(audit.SourceMaps, SourceMapLeak{URL: entry.Request.URL, Status: entry.Response.Status, Bytes: size})
+			}
+			continue
+		}
+		if !isScript(entry, parsed.Path) {
+			continue
+		}
+
+		audit.Requests++
+		audit.Bytes += size
+		audit.Transfer += transfer
+
+		domain, ok := domains[host]
+		if !ok {
+			domain = &JSDomain{Domain: host}
+			domains[host] = domain
+		}
+		domain.Requests++
+		domain.Bytes += size
+		domain.Transfer += transfer
+
+		name := path.Base(parsed.Path)
+		bundle, ok := bundles[host+"/"+name]
+		if !ok {
+			bundle = &JSBundle{Domain: host, Name: name}
+			bundles[host+"/"+name] = bundle
+			domain.Bundles++
+		}
+		bundle.Requests++
+		bundle.Bytes = max(bundle.Bytes, size)
+		bundle.Transfer += transfer
+		bundle.Large = bundle.Bytes > LargeBundleBytes
+
+		if library, version := jsLibrary(parsed.Path); library != "" {
+			stats, ok := libraries[library]
+			if !ok {
+				stats = &DuplicateLibrary{Library: library}
+				libraries[library] = stats
+				libraryOrder = append(libraryOrder, library)
+			}
+			stats.Requests++
+			stats.Bytes += size
+			if !containsString(stats.Hosts, host) {
+				stats.Hosts = append(stats.Hosts, host)
+			}
+			if version != "" && !containsString(stats.Versions, version) {
+				stats.Versions = append(stats.Versions, version)
+			}
+		}
+	}
+
+	for _, domain := range domains {
+		audit.Domains = append(audit.Domains, *domain)
+	}
+	sort.Slice(audit.Domains, func(i, j int) bool {
+		if audit.Domains[i].Bytes != audit.Domains[j].Bytes {
+			return audit.Domains[i].Bytes > audit.Domains[j].Bytes
+		}
+		return audit.Domains[i].Domain < audit.Domains[j].Domain
+	})
+	for _, bundle := range bundles {
+		audit.Bundles = append(audit.Bundles, *bundle)
+	}
+	sort.Slice(audit.Bundles, func(i, j int) bool {
+		if audit.Bundles[i].Bytes != audit.Bundles[j].Bytes {
+			return audit.Bundles[i].Bytes > audit.Bundles[j].Bytes
+		}
+		return audit.Bundles[i].Domain+audit.Bundles[i].Name < audit.Bundles[j].Domain+audit.Bundles[j].Name
+	})
+	for _, library := range libraryOrder {
+		if stats := libraries[library]; len(stats.Hosts) > 1 || len(stats.Versions) > 1 {
+			audit.DuplicateLibraries = append(audit.DuplicateLibraries, *stats)
+		}
+	}
+	return audit
+}
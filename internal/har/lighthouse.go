@@ -0,0 +1,113 @@
+package har
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// LabMetrics holds the lab metrics a Lighthouse run measured for a page.
+type LabMetrics struct {
+	FirstContentfulPaint   float64
+	LargestContentfulPaint float64
+	CumulativeLayoutShift  *float64 // nil when the report has no layout shift audit
+	TotalBlockingTime      float64
+	SpeedIndex             float64
+	FetchTime              time.Time
+	URL                    string
+}
+
+type lighthouseReport struct {
+	LighthouseVersion string    `json:"lighthouseVersion"`
+	FetchTime         time.Time `json:"fetchTime"`
+	FinalURL          string    `json:"finalUrl"`
+	FinalDisplayedURL string    `json:"finalDisplayedUrl"`
+	Audits            map[string]struct {
+		NumericValue *float64 `json:"numericValue"`
+	} `json:"audits"`
+}
+
+// audit returns an audit's numeric value, and whether the report has it.
+func (r *lighthouseReport) audit(id string) (float64, bool) {
+	if audit, ok := r.Audits[id]; ok && audit.NumericValue != nil {
+		return *audit.NumericValue, true
+	}
+	return 0, false
+}
+
+// measured returns an audit's numeric value, or 0 when the report lacks it.
+func (r *lighthouseReport) measured(id string) float64 {
+	value, _ := r.audit(id)
+	return value
+}
+
+func ParseLighthouseFile(path string) (*LabMetrics, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open Lighthouse report: %w", err)
+	}
+	defer file.Close()
+
+	return ParseLighthouse(file)
+}
+
+// ParseLighthouse extracts lab metrics from a Lighthouse JSON report.
+func ParseLighthouse(reader io.Reader) (*LabMetrics, error) {
+	var report lighthouseReport
+	if err := json.NewDecoder(reader).Decode(&report); err != nil {
+		return nil, fmt.Errorf("failed to decode Lighthouse JSON: %w", err)
+	}
+	if report.LighthouseVersion == "" || report.Audits == nil {
+		return nil, fmt.Errorf("not a Lighthouse report: missing lighthouseVersion or audits")
+	}
+
+	url := report.FinalDisplayedURL
+	if url == "" {
+		url = report.FinalURL
+	}
+
+	lab := &LabMetrics{
+		FirstContentfulPaint:   report.measured("first-contentful-paint"),
+		LargestContentfulPaint: report.measured("largest-contentful-paint"),
+		TotalBlockingTime:      report.measured("total-blocking-time"),
+		SpeedIndex:             report.measured("speed-index"),
+		FetchTime:              report.FetchTime,
+		URL:                    url,
+	}
+	if cls, ok := report.audit("cumulative-layout-shift"); ok {
+		lab.CumulativeLayoutShift = &cls
+	}
+	return lab, nil
+}
+
+// ApplyLabMetrics merges lab metrics into the first page of a HAR from the
+// same run, creating the page if the capture has none. Values the HAR already
+// carries (e.g. from WebPageTest) are only replaced by non-zero lab values.
+func ApplyLabMetrics(har *HAR, lab *LabMetrics) {
+	if len(har.Log.Pages) == 0 {
+		page := Page{ID: "page_1", Title: lab.URL, StartedDateTime: lab.FetchTime}
+		if len(har.Log.Entries) > 0 {
+			page.StartedDateTime = har.Log.Entries[0].StartedDateTime
+		}
+		har.Log.Pages = append(har.Log.Pages, page)
+	}
+
+	page := &har.Log.Pages[0]
+	setIfMeasured(&page.FirstContentfulPaint, lab.FirstContentfulPaint)
+	setIfMeasured(&page.LargestContentfulPaint, lab.LargestContentfulPaint)
+	// A layout shift score of zero is a perfect result, not a missing one
+	if lab.CumulativeLayoutShift != nil {
+		cls := *lab.CumulativeLayoutShift
+		page.CumulativeLayoutShift = &cls
+	}
+	setIfMeasured(&page.TotalBlockingTime, lab.TotalBlockingTime)
+	setIfMeasured(&page.SpeedIndex, lab.SpeedIndex)
+}
+
+func setIfMeasured(field *float64, value float64) {
+	if value > 0 {
+		*field = value
+	}
+}
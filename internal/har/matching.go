@@ -0,0 +1,102 @@
+package har
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// MatchRule decides which requests of two captures are the same request.
+type MatchRule string
+
+const (
+	MatchExact       MatchRule = "exact"        // method and full URL
+	MatchIgnoreQuery MatchRule = "ignore-query" // method, host and path; query strings often carry cache busters
+	MatchPath        MatchRule = "path"         // method and path on any host, e.g. staging vs production
+)
+
+// MatchRules lists the rules in the order they are offered.
+var MatchRules = []MatchRule{MatchExact, MatchIgnoreQuery, MatchPath}
+
+// ParseMatchRule validates a match rule name.
+func ParseMatchRule(name string) (MatchRule, error) {
+	for _, rule := range MatchRules {
+		if string(rule) == name {
+			return rule, nil
+		}
+	}
+	return "", fmt.Errorf("unknown match rule %q (want exact, ignore-query or path)", name)
+}
+
+// Key returns the identity of an entry under the rule.
+func (r MatchRule) Key(entry Entry) string {
+	parsed, err := url.Parse(entry.Request.URL)
+	if err != nil || r == MatchExact {
+		return entry.Request.Method + " " + stripFragment(entry.Request.URL)
+	}
+	if r == MatchPath {
+		return entry.Request.Method + " " + parsed.EscapedPath()
+	}
+	return entry.Request.Method + " " + strings.ToLower(parsed.Host) + parsed.EscapedPath()
+}
+
+// EntryMatch pairs a request of the baseline with the same request of the
+// candidate. Index -1 means the request is missing from that capture.
+type EntryMatch struct {
+	Key       string
+	Before    int
+	After     int
+	TimeDelta float64 // ms, candidate minus baseline
+	SizeDelta int
+}
+
+// Added reports whether the request only exists in the candidate.
+func (m EntryMatch) Added() bool { return m.Before < 0 }
+
+// Removed reports whether the request only exists in the baseline.
+func (m EntryMatch) Removed() bool { return m.After < 0 }
+
+// MatchEntries pairs the requests of two captures. Repeated requests with the
+// same key are paired in order. Matches are sorted by the absolute change in
+// time, added and removed requests last.
+func MatchEntries(before, after *HAR, rule MatchRule) []EntryMatch {
+	pending := make(map[string][]int)
+	for i, entry := range after.Log.Entries {
+		key := rule.Key(entry)
+		pending[key] = append(pending[key], i)
+	}
+
+	var matches []EntryMatch
+	for i, entry := range before.Log.Entries {
+		key := rule.Key(entry)
+		match := EntryMatch{Key: key, Before: i, After: -1}
+		if candidates := pending[key]; len(candidates) > 0 {
+			match.After = candidates[0]
+			pending[key] = candidates[1:]
+			candidate := after.Log.Entries[match.After]
+			match.TimeDelta = candidate.Time - entry.Time
+			match.SizeDelta = candidate.Response.Content.Size - entry.Response.Content.Size
+		}
+		matches = append(matches, match)
+	}
+	for i, entry := range after.Log.Entries {
+		key := rule.Key(entry)
+		for _, index := range pending[key] {
+			if index == i {
+				matches = append(matches, EntryMatch{Key: key, Before: -1, After: i})
+			}
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		pairedI := !matches[i].Added() && !matches[i].Removed()
+		pairedJ := !matches[j].Added() && !matches[j].Removed()
+		if pairedI != pairedJ {
+			return pairedI
+		}
+		return math.Abs(matches[i].TimeDelta) > math.Abs(matches[j].TimeDelta)
+	})
+	return matches
+}
@@ -0,0 +1,229 @@
+package har
+
+import (
+	"fmt"
+	"slices"
+)
+
+type Direction int
+
+const (
+	Neutral Direction = iota
+	LowerIsBetter
+	HigherIsBetter
+)
+
+type MetricKind int
+
+const (
+	DurationMetric MetricKind = iota
+	CountMetric
+	SizeMetric
+	PercentMetric
+	ScoreMetric
+)
+
+// MetricDescriptor describes how a metric is extracted, displayed and judged.
+// The comparator, reports and TUI all key off the ID rather than the display name.
+type MetricDescriptor struct {
+	ID        string
+	Name      string
+	Unit      string
+	Kind      MetricKind
+	Direction Direction
+	Value     func(*Metrics) float64
+
+	// Available reports whether a file measured this metric at all, e.g. visual
+	// metrics that plain HAR captures lack. Nil means always available; metrics
+	// no file measured are left out of comparisons.
+	Available func(*Metrics) bool
+
+	// Lab marks browser lab and visual metrics, which come from WebPageTest
+	// or Lighthouse rather than the requests themselves.
+	Lab bool
+}
+
+// Measured reports whether m has a value for this metric.
+func (d MetricDescriptor) Measured(m *Metrics) bool {
+	if !m.HasData() {
+		return false
+	}
+	return d.Available == nil || d.Available(m)
+}
+
+// Format renders a value of this metric for display, with sizes in units.
+func (d MetricDescriptor) Format(value float64, units SizeUnits) string {
+	switch d.Kind {
+	case CountMetric:
+		if d.Unit != "" {
+			return fmt.Sprintf("%d %s", int(value), d.Unit)
+		}
+		return fmt.Sprintf("%d", int(value))
+	case SizeMetric:
+		return units.FormatSize(int(value))
+	case ScoreMetric:
+		return fmt.Sprintf("%.3f", value)
+	default:
+		return fmt.Sprintf("%.1f%s", value, d.Unit)
+	}
+}
+
+// IsImprovement reports whether a change of the given sign is better for this metric.
+func (d MetricDescriptor) IsImprovement(change float64) bool {
+	switch d.Direction {
+	case LowerIsBetter:
+		return change < 0
+	case HigherIsBetter:
+		return change > 0
+	}
+	return false
+}
+
+// WithinBudget reports whether value satisfies a budget limit for this metric.
+// Limits are maximums unless higher values are better, in which case they are minimums.
+func (d MetricDescriptor) WithinBudget(value, limit float64) bool {
+	if d.Direction == HigherIsBetter {
+		return value >= limit
+	}
+	return value <= limit
+}
+
+const (
+	MetricPageLoadTime       = "page_load_time"
+	MetricTTFB               = "ttfb"
+	MetricDNSTime            = "dns_time"
+	MetricConnectTime        = "connect_time"
+	MetricSSLTime            = "ssl_time"
+	MetricTotalRequests      = "total_requests"
+	MetricErrorRequests      = "error_requests"
+	MetricThirdPartyRequests = "third_party_requests"
+	MetricInsecureRequests   = "insecure_requests"
+	MetricCacheHitRatio      = "cache_hit_ratio"
+	MetricCDNHitRatio        = "cdn_hit_ratio"
+	MetricTotalSize          = "total_size"
+	MetricRepeatViewLoadTime = "repeat_view_load_time"
+	MetricRepeatViewSize     = "repeat_view_size"
+	MetricPreloadWaste       = "preload_wasted_bytes"
+	MetricRedundantAPI       = "redundant_api_requests"
+	MetricRedirectLatency    = "redirect_latency"
+	MetricConnectionReuse    = "connection_reuse"
+	MetricSetupWaste         = "connection_setup_waste"
+	MetricMultiplexed        = "multiplexed_coverage"
+	MetricCompression        = "compression_coverage"
+	MetricBrotli             = "brotli_coverage"
+	MetricZstd               = "zstd_coverage"
+	MetricSpeedIndex         = "speed_index"
+	MetricVisualComplete     = "visual_complete"
+	MetricFCP                = "first_contentful_paint"
+	MetricLCP                = "largest_contentful_paint"
+	MetricCLS                = "cumulative_layout_shift"
+	MetricTBT                = "total_blocking_time"
+)
+
+// metricRegistry lists the metrics that take part in comparisons, in display order.
+var metricRegistry = []MetricDescriptor{
+	{ID: MetricPageLoadTime, Name: "Total Load Time", Unit: "ms", Kind: DurationMetric, Direction: LowerIsBetter,
+		Value: func(m *Metrics) float64 { return m.PageLoadTime }},
+	{ID: MetricTTFB, Name: "Time to First Byte", Unit: "ms", Kind: DurationMetric, Direction: LowerIsBetter,
+		Value: func(m *Metrics) float64 { return m.TTFB }},
+	{ID: MetricDNSTime, Name: "Average DNS Time", Unit: "ms", Kind: DurationMetric, Direction: LowerIsBetter,
+		Value: func(m *Metrics) float64 { return m.DNSTime }},
+	{ID: MetricConnectTime, Name: "Average Connect Time", Unit: "ms", Kind: DurationMetric, Direction: LowerIsBetter,
+		Value: func(m *Metrics) float64 { return m.ConnectTime }},
+	{ID: MetricSSLTime, Name: "Average SSL Time", Unit: "ms", Kind: DurationMetric, Direction: LowerIsBetter,
+		Value: func(m *Metrics) float64 { return m.SSLTime }},
+	{ID: MetricTotalRequests, Name: "Total Requests", Kind: CountMetric, Direction: Neutral,
+		Value: func(m *Metrics) float64 { return float64(m.TotalRequests) }},
+	{ID: MetricErrorRequests, Name: "Error Requests", Kind: CountMetric, Direction: LowerIsBetter,
+		Value: func(m *Metrics) float64 { return float64(m.ErrorRequests) }},
+	{ID: MetricThirdPartyRequests, Name: "Third-party Requests", Kind: CountMetric, Direction: LowerIsBetter,
+		Value: func(m *Metrics) float64 { return float64(m.ThirdPartyRequests) }},
+	{ID: MetricInsecureRequests, Name: "Insecure Requests", Kind: CountMetric, Direction: LowerIsBetter,
+		Value: func(m *Metrics) float64 { return float64(m.InsecureRequests) }},
+	{ID: MetricCacheHitRatio, Name: "Cache Hit Ratio", Unit: "%", Kind: PercentMetric, Direction: HigherIsBetter,
+		Value: func(m *Metrics) float64 { return m.CacheHitRatio }},
+	{ID: MetricCDNHitRatio, Name: "CDN Hit Ratio", Unit: "%", Kind: PercentMetric, Direction: HigherIsBetter,
+		Value:     func(m *Metrics) float64 { return m.CDNHitRatio },
+		Available: func(m *Metrics) bool { return m.CDNResponses > 0 }},
+	{ID: MetricTotalSize, Name: "Total Transfer Size", Kind: SizeMetric, Direction: LowerIsBetter,
+		Value: func(m *Metrics) float64 { return float64(m.TotalSize) }},
+	{ID: MetricRepeatViewLoadTime, Name: "Repeat View Load Time", Unit: "ms", Kind: DurationMetric, Direction: LowerIsBetter,
+		Value: func(m *Metrics) float64 { return m.RepeatViewLoadTime }},
+	{ID: MetricRepeatViewSize, Name: "Repeat View Size", Kind: SizeMetric, Direction: LowerIsBetter,
+		Value: func(m *Metrics) float64 { return float64(m.RepeatViewSize) }},
+	{ID: MetricPreloadWaste, Name: "Unused Preload Bytes", Kind: SizeMetric, Direction: LowerIsBetter,
+		Value: func(m *Metrics) float64 { return float64(m.PreloadWastedBytes) }},
+	{ID: MetricRedundantAPI, Name: "Redundant API Requests", Kind: CountMetric, Direction: LowerIsBetter,
+		Value: func(m *Metrics) float64 { return float64(m.RedundantAPIRequests) }},
+	{ID: MetricRedirectLatency, Name: "Redirect Latency", Unit: "ms", Kind: DurationMetric, Direction: LowerIsBetter,
+		Value: func(m *Metrics) float64 { return m.RedirectLatency }},
+	{ID: MetricConnectionReuse, Name: "Connection Reuse", Unit: "%", Kind: PercentMetric, Direction: HigherIsBetter,
+		Value: func(m *Metrics) float64 { return m.ConnectionReuse }},
+	{ID: MetricSetupWaste, Name: "Repeated Setup Time", Unit: "ms", Kind: DurationMetric, Direction: LowerIsBetter,
+		Value: func(m *Metrics) float64 { return m.ConnectionSetupWaste }},
+	{ID: MetricMultiplexed, Name: "HTTP/2+ Coverage", Unit: "%", Kind: PercentMetric, Direction: HigherIsBetter,
+		Value:     func(m *Metrics) float64 { return m.MultiplexedCoverage },
+		Available: func(m *Metrics) bool { return m.ProtocolRequests > 0 }},
+	{ID: MetricCompression, Name: "Compression Coverage", Unit: "%", Kind: PercentMetric, Direction: HigherIsBetter,
+		Value:     func(m *Metrics) float64 { return m.CompressionCoverage },
+		Available: func(m *Metrics) bool { return m.TextResponses > 0 }},
+	{ID: MetricBrotli, Name: "Brotli Coverage", Unit: "%", Kind: PercentMetric, Direction: HigherIsBetter,
+		Value:     func(m *Metrics) float64 { return m.BrotliCoverage },
+		Available: func(m *Metrics) bool { return m.TextResponses > 0 }},
+	{ID: MetricZstd, Name: "Zstandard Coverage", Unit: "%", Kind: PercentMetric, Direction: HigherIsBetter,
+		Value:     func(m *Metrics) float64 { return m.ZstdCoverage },
+		Available: func(m *Metrics) bool { return m.TextResponses > 0 }},
+	{ID: MetricFCP, Name: "First Contentful Paint", Unit: "ms", Kind: DurationMetric, Direction: LowerIsBetter, Lab: true,
+		Value:     func(m *Metrics) float64 { return m.FirstContentfulPaint },
+		Available: func(m *Metrics) bool { return m.FirstContentfulPaint > 0 }},
+	{ID: MetricLCP, Name: "Largest Contentful Paint", Unit: "ms", Kind: DurationMetric, Direction: LowerIsBetter, Lab: true,
+		Value:     func(m *Metrics) float64 { return m.LargestContentfulPaint },
+		Available: func(m *Metrics) bool { return m.LargestContentfulPaint > 0 }},
+	{ID: MetricCLS, Name: "Cumulative Layout Shift", Kind: ScoreMetric, Direction: LowerIsBetter, Lab: true,
+		Value:     func(m *Metrics) float64 { return m.CumulativeLayoutShift },
+		Available: func(m *Metrics) bool { return m.HasLabMetrics }},
+	{ID: MetricTBT, Name: "Total Blocking Time", Unit: "ms", Kind: DurationMetric, Direction: LowerIsBetter, Lab: true,
+		Value:     func(m *Metrics) float64 { return m.TotalBlockingTime },
+		Available: func(m *Metrics) bool { return m.HasLabMetrics || m.TotalBlockingTime > 0 }},
+	{ID: MetricSpeedIndex, Name: "Speed Index", Kind: CountMetric, Direction: LowerIsBetter, Lab: true,
+		Value:     func(m *Metrics) float64 { return m.SpeedIndex },
+		Available: func(m *Metrics) bool { return m.SpeedIndex > 0 }},
+	{ID: MetricVisualComplete, Name: "Visually Complete", Unit: "ms", Kind: DurationMetric, Direction: LowerIsBetter, Lab: true,
+		Value:     func(m *Metrics) float64 { return m.VisualComplete },
+		Available: func(m *Metrics) bool { return m.VisualComplete > 0 }},
+}
+
+// RegisterMetric adds a custom or derived metric to every subsequent
+// comparison, report and budget check. Registering an existing ID replaces
+// that descriptor. The returned function restores the metrics registered
+// before, e.g. once a test is done with the metric.
+func RegisterMetric(descriptor MetricDescriptor) (restore func()) {
+	previous := slices.Clone(metricRegistry)
+	restore = func() { metricRegistry = previous }
+
+	for i, existing := range metricRegistry {
+		if existing.ID == descriptor.ID {
+			metricRegistry[i] = descriptor
+			return restore
+		}
+	}
+	metricRegistry = append(metricRegistry, descriptor)
+	return restore
+}
+
+// MetricDescriptors returns all registered metrics in display order.
+func MetricDescriptors() []MetricDescriptor {
+	descriptors := make([]MetricDescriptor, len(metricRegistry))
+	copy(descriptors, metricRegistry)
+	return descriptors
+}
+
+// LookupMetric returns the descriptor registered under id.
+func LookupMetric(id string) (MetricDescriptor, bool) {
+	for _, descriptor := range metricRegistry {
+		if descriptor.ID == id {
+			return descriptor, true
+		}
+	}
+	return MetricDescriptor{}, false
+}
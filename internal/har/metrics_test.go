@@ -0,0 +1,20 @@
+package har
+
+import "testing"
+
+func TestRegisterMetricRestore(t *testing.T) {
+	restore := RegisterMetric(MetricDescriptor{ID: MetricTTFB, Name: "Server Wait"})
+	if descriptor, _ := LookupMetric(MetricTTFB); descriptor.Name != "Server Wait" {
+		t.Errorf("registering an existing ID kept %q", descriptor.Name)
+	}
+	restore()
+	if descriptor, _ := LookupMetric(MetricTTFB); descriptor.Name != "Time to First Byte" {
+		t.Errorf("restore left %q registered", descriptor.Name)
+	}
+
+	count := len(MetricDescriptors())
+	RegisterMetric(MetricDescriptor{ID: "custom"})()
+	if _, ok := LookupMetric("custom"); ok || len(MetricDescriptors()) != count {
+		t.Error("restore left a new metric registered")
+	}
+}
@@ -0,0 +1,80 @@
+package har
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// NoiseThreshold is the smallest change of a metric that comparisons flag as
+// better or worse. A change must reach both the absolute amount, in the
+// metric's unit, and the relative amount, in percent of the baseline.
+type NoiseThreshold struct {
+	Absolute float64 `json:"absolute"`
+	Relative float64 `json:"relative"`
+}
+
+// Exceeded reports whether a change from base is larger than noise. Any
+// change from a zero baseline is relative enough.
+func (t NoiseThreshold) Exceeded(change, base float64) bool {
+	if math.Abs(change) < t.Absolute {
+		return false
+	}
+	return base == 0 || math.Abs(change/base)*100 >= t.Relative
+}
+
+// defaultNoise keeps run-to-run jitter out of comparisons: a few
+// milliseconds or kilobytes, or a point of a ratio. Counts are exact.
+var defaultNoise = map[MetricKind]NoiseThreshold{
+	DurationMetric: {Absolute: 5, Relative: 2},
+	SizeMetric:     {Absolute: 1024, Relative: 1},
+	PercentMetric:  {Absolute: 1},
+	ScoreMetric:    {Absolute: 0.01},
+}
+
+// NoiseThreshold returns the threshold below which changes of a metric are
+// noise: the one set for its ID, else the default for its kind.
+func (s Settings) NoiseThreshold(d MetricDescriptor) NoiseThreshold {
+	if threshold, ok := s.Noise[d.ID]; ok {
+		return threshold
+	}
+	return defaultNoise[d.Kind]
+}
+
+// ParseNoiseThreshold parses a threshold such as "ttfb=20ms,5%" or
+// "total_size=10KB": an absolute amount in the metric's unit (durations also
+// take ms or s, sizes KB or MB) and/or a relative amount in percent. A part
+// left out is zero.
+func ParseNoiseThreshold(spec string) (string, NoiseThreshold, error) {
+	id, limits, ok := strings.Cut(spec, "=")
+	descriptor, known := LookupMetric(strings.TrimSpace(id))
+	if !ok || !known {
+		return "", NoiseThreshold{}, fmt.Errorf("invalid noise threshold %q: expected \"metric=absolute[,relative%%]\" with a known metric ID", spec)
+	}
+
+	var threshold NoiseThreshold
+	for _, limit := range strings.Split(limits, ",") {
+		limit = strings.TrimSpace(limit)
+		var err error
+		switch {
+		case strings.HasSuffix(limit, "%"):
+			threshold.Relative, err = strconv.ParseFloat(strings.TrimSuffix(limit, "%"), 64)
+		case descriptor.Kind == SizeMetric:
+			var size int64
+			size, err = ParseSize(limit)
+			threshold.Absolute = float64(size)
+		case descriptor.Kind == DurationMetric && strings.HasSuffix(limit, "ms"):
+			threshold.Absolute, err = strconv.ParseFloat(strings.TrimSuffix(limit, "ms"), 64)
+		case descriptor.Kind == DurationMetric && strings.HasSuffix(limit, "s"):
+			threshold.Absolute, err = strconv.ParseFloat(strings.TrimSuffix(limit, "s"), 64)
+			threshold.Absolute *= 1000
+		default:
+			threshold.Absolute, err = strconv.ParseFloat(limit, 64)
+		}
+		if err != nil || threshold.Absolute < 0 || threshold.Relative < 0 {
+			return "", NoiseThreshold{}, fmt.Errorf("invalid limit %q in noise threshold %q", limit, spec)
+		}
+	}
+	return descriptor.ID, threshold, nil
+}
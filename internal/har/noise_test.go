@@ -0,0 +1,92 @@
+package har
+
+import "testing"
+
+func TestNoiseThresholdExceeded(t *testing.T) {
+	threshold := NoiseThreshold{Absolute: 5, Relative: 2}
+	tests := []struct {
+		name         string
+		change, base float64
+		want         bool
+	}{
+		{"both reached", 50, 1000, true},
+		{"both reached, getting smaller", -50, 1000, true},
+		{"exactly at both limits", 5, 250, true},
+		{"absolute only", 10, 1000, false},
+		{"relative only", 4, 100, false},
+		{"neither", 1, 1000, false},
+		{"no change", 0, 1000, false},
+		{"from a zero baseline", 5, 0, true},
+		{"below the absolute amount from a zero baseline", 4, 0, false},
+	}
+	for _, tt := range tests {
+		if got := threshold.Exceeded(tt.change, tt.base); got != tt.want {
+			t.Errorf("%s: Exceeded(%v, %v) = %v, want %v", tt.name, tt.change, tt.base, got, tt.want)
+		}
+	}
+
+	if !(NoiseThreshold{}).Exceeded(1, 1e9) {
+		t.Error("a zero threshold treats a change as noise")
+	}
+}
+
+func TestDefaultNoiseByKind(t *testing.T) {
+	tests := []struct {
+		metric string
+		want   NoiseThreshold
+	}{
+		{MetricTTFB, NoiseThreshold{Absolute: 5, Relative: 2}},
+		{MetricTotalSize, NoiseThreshold{Absolute: 1024, Relative: 1}},
+		{MetricCacheHitRatio, NoiseThreshold{Absolute: 1}},
+		{MetricCLS, NoiseThreshold{Absolute: 0.01}},
+		{MetricErrorRequests, NoiseThreshold{}},
+	}
+	for _, tt := range tests {
+		descriptor, _ := LookupMetric(tt.metric)
+		if got := (Settings{}).NoiseThreshold(descriptor); got != tt.want {
+			t.Errorf("%s noise = %+v, want %+v", tt.metric, got, tt.want)
+		}
+	}
+}
+
+func TestParseNoiseThreshold(t *testing.T) {
+	tests := []struct {
+		spec string
+		id   string
+		want NoiseThreshold
+	}{
+		{"ttfb=20ms,5%", MetricTTFB, NoiseThreshold{Absolute: 20, Relative: 5}},
+		{"ttfb=0.5s", MetricTTFB, NoiseThreshold{Absolute: 500}},
+		{" page_load_time = 3% ", MetricPageLoadTime, NoiseThreshold{Relative: 3}},
+		{"total_size=10KB", MetricTotalSize, NoiseThreshold{Absolute: 10 * 1024}},
+		{"cache_hit_ratio=2", MetricCacheHitRatio, NoiseThreshold{Absolute: 2}},
+		{"cumulative_layout_shift=0.05,10%", MetricCLS, NoiseThreshold{Absolute: 0.05, Relative: 10}},
+	}
+	for _, tt := range tests {
+		id, got, err := ParseNoiseThreshold(tt.spec)
+		if err != nil || id != tt.id || got != tt.want {
+			t.Errorf("ParseNoiseThreshold(%q) = %q, %+v, %v; want %q, %+v", tt.spec, id, got, err, tt.id, tt.want)
+		}
+	}
+}
+
+func TestParseNoiseThresholdRejectsMalformedSpecs(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"ttfb",
+		"20ms",
+		"unknown_metric=5",
+		"ttfb=",
+		"ttfb=fast",
+		"ttfb=-5ms",
+		"ttfb=5ms,-2%",
+		"ttfb=5ms,%",
+		"ttfb=5ms,",
+		"total_size=10XB",
+		"cache_hit_ratio=1ms",
+	} {
+		if id, threshold, err := ParseNoiseThreshold(spec); err == nil {
+			t.Errorf("ParseNoiseThreshold(%q) = %q, %+v, want an error", spec, id, threshold)
+		}
+	}
+}
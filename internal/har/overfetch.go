@@ -0,0 +1,110 @@
+package har
+
+import (
+	"crypto/sha256"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultRefetchWindow is how soon an identical API response must be fetched
+// again to count as over-fetching.
+const DefaultRefetchWindow = 30 * time.Second
+
+// RefetchGroup is an API request that was repeated and answered with a
+// response identical to the previous one within the window.
+type RefetchGroup struct {
+	Method      string
+	URL         string
+	Fetches     int   // every fetch of the request in the capture
+	Redundant   int   // fetches returning the same body as the previous one within the window
+	Entries     []int // entry indices of the redundant fetches
+	WastedBytes int64
+	WastedTime  float64 // ms
+	Shortest    time.Duration
+}
+
+// IsAPIResponse reports whether the entry looks like a data request rather
+// than a document or static asset.
+func IsAPIResponse(entry Entry) bool {
+	mimeType := strings.ToLower(entry.Response.Content.MimeType)
+	if strings.Contains(mimeType, "html") || strings.Contains(mimeType, "svg") {
+		return false
+	}
+	for _, kind := range []string{"json", "xml", "graphql", "protobuf", "grpc"} {
+		if strings.Contains(mimeType, kind) {
+			return true
+		}
+	}
+	return false
+}
+
+// APIRefetches finds API requests (same method, URL and request body) whose
+// successful response was identical to the previous fetch within window,
+// which server caching or stale-while-revalidate on the client would have
+// avoided. Bodies are compared by hash, so captures saved without response
+// content report nothing. Groups are ordered by wasted bytes.
+func (a *Analyzer) APIRefetches(window time.Duration) []RefetchGroup {
+	type lastFetch struct {
+		started time.Time
+		hash    [32]byte
+	}
+
+	entries := a.har.Log.Entries
+	order := make([]int, len(entries))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return entries[order[i]].StartedDateTime.Before(entries[order[j]].StartedDateTime)
+	})
+
+	groups := make(map[string]*RefetchGroup)
+	last := make(map[string]lastFetch)
+	var keys []string
+
+	for _, i := range order {
+		entry := entries[i]
+		body := ResponseBody(entry)
+		if !IsAPIResponse(entry) || entry.Response.Status != 200 || body == "" {
+			continue
+		}
+
+		key := entry.Request.Method + " " + entry.Request.URL
+		if entry.Request.PostData != nil {
+			key += " " + entry.Request.PostData.Text
+		}
+		group, ok := groups[key]
+		if !ok {
+			group = &RefetchGroup{Method: entry.Request.Method, URL: entry.Request.URL}
+			groups[key] = group
+			keys = append(keys, key)
+		}
+		group.Fetches++
+
+		fetch := lastFetch{started: entry.StartedDateTime, hash: sha256.Sum256([]byte(body))}
+		if previous, ok := last[key]; ok && previous.hash == fetch.hash {
+			if gap := fetch.started.Sub(previous.started); gap <= window {
+				group.Redundant++
+				group.Entries = append(group.Entries, i)
+				group.WastedBytes += int64(entry.Response.Content.Size)
+				group.WastedTime += entry.Time
+				if group.Shortest == 0 || gap < group.Shortest {
+					group.Shortest = gap
+				}
+			}
+		}
+		last[key] = fetch
+	}
+
+	var result []RefetchGroup
+	for _, key := range keys {
+		if groups[key].Redundant > 0 {
+			result = append(result, *groups[key])
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].WastedBytes > result[j].WastedBytes
+	})
+	return result
+}
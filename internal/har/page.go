@@ -0,0 +1,46 @@
+package har
+
+import "strings"
+
+// SyntheticPageID is the ID of the page PrimaryPage derives for captures that
+// recorded none.
+const SyntheticPageID = "synthetic"
+
+// PrimaryPage returns the page that page-level metrics are read from: the
+// first recorded page, or for captures without pages (typical of API-only
+// captures) a synthetic one. A synthetic page starts at the first HTML
+// document, else at the earliest request, and has no timings, so callers fall
+// back to estimates rather than reading zero values as measurements.
+func (l *Log) PrimaryPage() Page {
+	if len(l.Pages) > 0 {
+		page := l.Pages[0]
+		if page.StartedDateTime.IsZero() {
+			page.StartedDateTime = l.syntheticPage().StartedDateTime
+		}
+		return page
+	}
+	return l.syntheticPage()
+}
+
+func (l *Log) syntheticPage() Page {
+	page := Page{ID: SyntheticPageID, Synthetic: true}
+	for _, entry := range l.Entries {
+		if strings.Contains(entry.Response.Content.MimeType, "html") {
+			page.StartedDateTime = entry.StartedDateTime
+			page.Title = entry.Request.URL
+			return page
+		}
+	}
+	for _, entry := range l.Entries {
+		if page.StartedDateTime.IsZero() || entry.StartedDateTime.Before(page.StartedDateTime) {
+			page.StartedDateTime = entry.StartedDateTime
+			page.Title = entry.Request.URL
+		}
+	}
+	return page
+}
+
+// Page returns the capture's primary page, see Log.PrimaryPage.
+func (a *Analyzer) Page() Page {
+	return a.har.Log.PrimaryPage()
+}
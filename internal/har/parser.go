@@ -2,6 +2,9 @@ package har
 
 import (
 	"bufio"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -28,12 +31,55 @@ func (p *Parser) ParseFile(filepath string) (*HAR, error) {
 	return p.ParseReader(file)
 }
 
+// ParseFileWithDigest parses a HAR file and returns the SHA-256 digest of its
+// raw content, which identifies the same capture loaded more than once.
+func (p *Parser) ParseFileWithDigest(filepath string) (*HAR, string, error) {
+	file, err := os.Open(filepath)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to open HAR file: %w", err)
+	}
+	defer file.Close()
+
+	hasher := sha256.New()
+	har, err := p.ParseReader(io.TeeReader(file, hasher))
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Drain anything the decoder did not consume so the digest covers the whole file
+	if _, err := io.Copy(hasher, file); err != nil {
+		return nil, "", fmt.Errorf("failed to read HAR file: %w", err)
+	}
+
+	return har, hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// ParseReader parses a HAR document, gunzipping it first when it is
+// compressed, e.g. a .har.gz file.
 func (p *Parser) ParseReader(reader io.Reader) (*HAR, error) {
 	bufferedReader := bufio.NewReaderSize(reader, p.bufferSize)
-	decoder := json.NewDecoder(bufferedReader)
+	var source io.Reader = bufferedReader
+	if magic, err := bufferedReader.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gzipReader, err := gzip.NewReader(bufferedReader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress HAR file: %w", err)
+		}
+		defer gzipReader.Close()
+		source = gzipReader
+	}
+	decoder := json.NewDecoder(source)
+
+	var document json.RawMessage
+	if err := decoder.Decode(&document); err != nil {
+		return nil, fmt.Errorf("failed to decode HAR JSON: %w", err)
+	}
+
+	if isWebPageTest(document) {
+		return ParseWebPageTest(document)
+	}
 
 	var har HAR
-	if err := decoder.Decode(&har); err != nil {
+	if err := json.Unmarshal(document, &har); err != nil {
 		return nil, fmt.Errorf("failed to decode HAR JSON: %w", err)
 	}
 
@@ -59,9 +105,20 @@ func (p *Parser) ValidateHAR(har *HAR) error {
 		return fmt.Errorf("missing HAR version")
 	}
 
-	if len(har.Log.Entries) == 0 {
-		return fmt.Errorf("no entries found in HAR file")
+	return nil
+}
+
+// Warnings lists conditions that do not make a HAR invalid but limit how
+// meaningful its metrics are.
+func (p *Parser) Warnings(har *HAR) []string {
+	var warnings []string
+
+	switch len(har.Log.Entries) {
+	case 0:
+		warnings = append(warnings, "no entries found; metrics will be shown as N/A")
+	case 1:
+		warnings = append(warnings, "only one entry found; averages and comparisons are based on a single request")
 	}
 
-	return nil
+	return warnings
 }
@@ -0,0 +1,120 @@
+package har
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"strings"
+)
+
+// PostDataKind is how a request body is encoded.
+type PostDataKind string
+
+const (
+	FormPostData      PostDataKind = "form"
+	JSONPostData      PostDataKind = "json"
+	MultipartPostData PostDataKind = "multipart"
+	TextPostData      PostDataKind = "text"
+)
+
+// BodyPart is one field of a form or part of a multipart body. Size is the
+// length of the part's content, which is all a capture may keep of uploaded
+// files.
+type BodyPart struct {
+	Name        string
+	Value       string
+	FileName    string
+	ContentType string
+	Size        int
+}
+
+// RequestBody is a request body decoded for display.
+type RequestBody struct {
+	Kind     PostDataKind
+	MimeType string
+	Raw      string
+	JSON     string     // indented, for JSON bodies
+	Parts    []BodyPart // form fields or multipart parts, in order
+}
+
+// ParseRequestBody decodes the body of a request by its MIME type: form
+// fields, indented JSON or multipart parts. Bodies that fail to decode are
+// shown as text. It returns false when the request has no body.
+func ParseRequestBody(entry Entry) (RequestBody, bool) {
+	data := entry.Request.PostData
+	if data == nil || (data.Text == "" && len(data.Params) == 0) {
+		return RequestBody{}, false
+	}
+	body := RequestBody{Kind: TextPostData, MimeType: data.MimeType, Raw: data.Text}
+	mediaType, params, _ := mime.ParseMediaType(data.MimeType)
+
+	switch {
+	case mediaType == "application/x-www-form-urlencoded":
+		body.Kind = FormPostData
+		body.Parts = paramParts(data.Params)
+		if len(body.Parts) == 0 {
+			for _, field := range strings.Split(data.Text, "&") {
+				if field == "" {
+					continue
+				}
+				name, value, _ := strings.Cut(field, "=")
+				value = decodeQueryComponent(value)
+				body.Parts = append(body.Parts, BodyPart{Name: decodeQueryComponent(name), Value: value, Size: len(value)})
+			}
+		}
+	case mediaType == "multipart/form-data":
+		parts, err := multipartParts(data.Text, params["boundary"])
+		if err != nil && len(data.Params) > 0 {
+			parts, err = paramParts(data.Params), nil
+		}
+		if err == nil {
+			body.Kind, body.Parts = MultipartPostData, parts
+		}
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json") || json.Valid([]byte(data.Text)):
+		var indented bytes.Buffer
+		if json.Indent(&indented, []byte(data.Text), "", "  ") == nil {
+			body.Kind, body.JSON = JSONPostData, indented.String()
+		}
+	}
+	return body, true
+}
+
+// paramParts converts the params a capture recorded for a form body.
+func paramParts(params []Param) []BodyPart {
+	parts := make([]BodyPart, 0, len(params))
+	for _, param := range params {
+		parts = append(parts, BodyPart{Name: param.Name, Value: param.Value, FileName: param.FileName, ContentType: param.ContentType, Size: len(param.Value)})
+	}
+	return parts
+}
+
+// multipartParts reads the parts of a multipart body. Captures often drop
+// binary content, so a truncated final part is kept rather than failing.
+func multipartParts(text, boundary string) ([]BodyPart, error) {
+	if boundary == "" {
+		return nil, errors.New("multipart body without a boundary")
+	}
+	reader := multipart.NewReader(strings.NewReader(text), boundary)
+	var parts []BodyPart
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return parts, nil
+		}
+		if err != nil {
+			if len(parts) > 0 {
+				return parts, nil
+			}
+			return nil, err
+		}
+		content, _ := io.ReadAll(part)
+		bodyPart := BodyPart{Name: part.FormName(), FileName: part.FileName(), ContentType: part.Header.Get("Content-Type"), Size: len(content)}
+		if bodyPart.FileName == "" {
+			bodyPart.Value = string(content)
+		}
+		parts = append(parts, bodyPart)
+	}
+}
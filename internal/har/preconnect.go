@@ -0,0 +1,190 @@
+package har
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Browsers warn when a page preconnects to more origins than they will keep
+// warm, so only the origins that save the most get rel=preconnect and the
+// rest fall back to dns-prefetch.
+const (
+	preconnectLimit       = 4
+	resourceHintMinSaving = 10.0 // ms
+)
+
+// ResourceHint recommends resolving or connecting to an origin as soon as
+// the document arrives, before its first request needs the connection.
+type ResourceHint struct {
+	Origin      string  `json:"origin"`
+	Rel         string  `json:"rel"` // "preconnect" or "dns-prefetch"
+	Requests    int     `json:"requests"`
+	DNS         float64 `json:"dns"`         // ms the first request spent resolving the host
+	Connect     float64 `json:"connect"`     // ms it spent in TCP and TLS setup
+	Saved       float64 `json:"saved"`       // estimated ms the hint takes off the first request
+	CrossOrigin bool    `json:"crossorigin"` // the origin serves fonts or CORS requests, which need their own connection
+}
+
+// Tag returns the <link> element that adds the hint to the document's head.
+func (h ResourceHint) Tag() string {
+	if h.CrossOrigin && h.Rel == "preconnect" {
+		return fmt.Sprintf(`<link rel="%s" href="%s" crossorigin>`, h.Rel, h.Origin)
+	}
+	return fmt.Sprintf(`<link rel="%s" href="%s">`, h.Rel, h.Origin)
+}
+
+// Avoids names what the hint saves: "DNS lookup" or "connection setup".
+func (h ResourceHint) Avoids() string {
+	if h.Rel == "dns-prefetch" {
+		return "DNS lookup"
+	}
+	return "connection setup"
+}
+
+// ResourceHints recommends preconnect and dns-prefetch hints for the origins
+// the page connects to after its document, most time saved first. A hint can
+// only start once the document's response starts, so the saving is the
+// first request's DNS (dns-prefetch) or DNS, TCP and TLS time (preconnect),
+// capped by how long after that the request started. Origins the document
+// already hints at, or that save less than resourceHintMinSaving, are left
+// out. The first preconnectLimit origins needed before DOMContentLoaded get
+// preconnect; the others get dns-prefetch. Captures of environments that skip
+// delivery checks get no hints.
+func (a *Analyzer) ResourceHints() []ResourceHint {
+	if !a.Environment().ChecksDelivery() {
+		return nil
+	}
+
+	entries := a.har.Log.Entries
+	document := -1
+	for i, entry := range entries {
+		if ResourceType(entry.Response.Content.MimeType) == "html" {
+			document = i
+			break
+		}
+	}
+	if document < 0 {
+		return nil
+	}
+
+	doc := entries[document]
+	documentOrigin := entryOrigin(doc)
+	timings := doc.Timings
+	responseStart := doc.StartedDateTime.Add(time.Duration(max(timings.Blocked, 0)+max(timings.DNS, 0)+
+		max(timings.Connect, 0)+max(timings.Send, 0)+max(timings.Wait, 0)) * time.Millisecond)
+
+	var contentLoaded time.Time
+	if page := a.Page(); page.PageTimings.OnContentLoad > 0 {
+		contentLoaded = page.StartedDateTime.Add(time.Duration(page.PageTimings.OnContentLoad) * time.Millisecond)
+	}
+
+	hinted := existingResourceHints(doc)
+	byOrigin := make(map[string]*ResourceHint)
+	var early, late []*ResourceHint
+	for _, entry := range entries {
+		origin := entryOrigin(entry)
+		if origin == "" || origin == documentOrigin || hinted[origin] {
+			continue
+		}
+		if hint, ok := byOrigin[origin]; ok {
+			hint.Requests++
+			continue
+		}
+
+		hint := &ResourceHint{
+			Origin:   origin,
+			Requests: 1,
+			DNS:      float64(max(entry.Timings.DNS, 0)),
+			Connect:  float64(max(entry.Timings.Connect, entry.Timings.SSL, 0)),
+		}
+		_, cors := HeaderValue(entry.Request.Headers, "Origin")
+		hint.CrossOrigin = cors || ResourceType(entry.Response.Content.MimeType) == "font"
+		byOrigin[origin] = hint
+
+		lead := float64(entry.StartedDateTime.Sub(responseStart)) / float64(time.Millisecond)
+		if lead <= 0 {
+			continue
+		}
+		if contentLoaded.IsZero() || entry.StartedDateTime.Before(contentLoaded) {
+			hint.Saved = min(hint.DNS+hint.Connect, lead)
+			early = append(early, hint)
+		} else {
+			hint.Saved = min(hint.DNS, lead)
+			late = append(late, hint)
+		}
+	}
+
+	bySaving := func(hints []*ResourceHint) {
+		sort.SliceStable(hints, func(i, j int) bool { return hints[i].Saved > hints[j].Saved })
+	}
+	bySaving(early)
+	for i, hint := range early {
+		if i < preconnectLimit {
+			hint.Rel = "preconnect"
+			continue
+		}
+		hint.Saved = min(hint.Saved, hint.DNS)
+		late = append(late, hint)
+	}
+	bySaving(late)
+	for _, hint := range late {
+		hint.Rel = "dns-prefetch"
+	}
+
+	var hints []ResourceHint
+	for _, hint := range append(early[:min(len(early), preconnectLimit)], late...) {
+		if hint.Saved >= resourceHintMinSaving {
+			hints = append(hints, *hint)
+		}
+	}
+	sort.SliceStable(hints, func(i, j int) bool { return hints[i].Saved > hints[j].Saved })
+	return hints
+}
+
+// existingResourceHints returns the origins the document already preconnects
+// to or prefetches DNS for, through Link headers or <link> tags.
+func existingResourceHints(document Entry) map[string]bool {
+	base, _ := url.Parse(document.Request.URL)
+	hinted := make(map[string]bool)
+	add := func(href, rel string) {
+		rels := strings.Fields(strings.ToLower(rel))
+		if !containsString(rels, "preconnect") && !containsString(rels, "dns-prefetch") {
+			return
+		}
+		target, err := url.Parse(strings.TrimSpace(href))
+		if err != nil {
+			return
+		}
+		if base != nil {
+			target = base.ResolveReference(target)
+		}
+		if target.Host != "" {
+			hinted[strings.ToLower(target.Scheme+"://"+target.Host)] = true
+		}
+	}
+
+	for _, header := range document.Response.Headers {
+		if !strings.EqualFold(header.Name, "Link") {
+			continue
+		}
+		for _, part := range strings.Split(header.Value, ",") {
+			fields := strings.Split(part, ";")
+			for _, param := range fields[1:] {
+				if name, rel, _ := strings.Cut(strings.TrimSpace(param), "="); strings.EqualFold(name, "rel") {
+					add(strings.Trim(strings.TrimSpace(fields[0]), "<>"), strings.Trim(rel, `"`))
+				}
+			}
+		}
+	}
+	for _, tag := range linkTagPattern.FindAllString(ResponseBody(document), -1) {
+		rel := linkRelPattern.FindStringSubmatch(tag)
+		href := linkHrefPattern.FindStringSubmatch(tag)
+		if rel != nil && href != nil {
+			add(href[1], rel[1]+" "+rel[2]+" "+rel[3])
+		}
+	}
+	return hinted
+}
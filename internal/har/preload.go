@@ -0,0 +1,180 @@
+package har
+
+import (
+	"encoding/base64"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// PreloadHint is a resource the page asked the browser to fetch ahead of
+// time with rel=preload, modulepreload or prefetch.
+type PreloadHint struct {
+	URL        string
+	Rel        string
+	Source     string // "Link header", "<link> tag" or "Sec-Purpose header"
+	EntryIndex int    // entry that downloaded it, -1 if it never was
+	Size       int
+	Used       bool
+}
+
+// Wasted reports whether the hint cost a download nothing else referenced.
+func (h PreloadHint) Wasted() bool {
+	return h.EntryIndex >= 0 && !h.Used
+}
+
+var (
+	linkTagPattern  = regexp.MustCompile(`(?is)<link\b[^>]*>`)
+	linkRelPattern  = regexp.MustCompile(`(?i)\brel\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s>]+))`)
+	linkHrefPattern = regexp.MustCompile(`(?i)\bhref\s*=\s*["']([^"']+)["']`)
+)
+
+var preloadRels = []string{"preload", "modulepreload", "prefetch"}
+
+// PreloadHints finds preload and prefetch hints in Link headers, HTML <link>
+// tags and speculative requests, and decides whether each resource was used.
+// A resource counts as used when another captured response body mentions it
+// or a request names it as Referer, so captures saved without response
+// content can over-report waste.
+func (a *Analyzer) PreloadHints() []PreloadHint {
+	entries := a.har.Log.Entries
+	var hints []PreloadHint
+	declarations := make(map[int][]string) // entry index -> <link> tags to ignore when looking for uses
+	seen := make(map[string]bool)
+
+	add := func(rawURL, rel, source string, base *url.URL) {
+		resolved := rawURL
+		if base != nil {
+			if reference, err := url.Parse(strings.TrimSpace(rawURL)); err == nil {
+				resolved = base.ResolveReference(reference).String()
+			}
+		}
+		resolved = stripFragment(resolved)
+		if seen[resolved] {
+			return
+		}
+		seen[resolved] = true
+		hints = append(hints, PreloadHint{URL: resolved, Rel: rel, Source: source, EntryIndex: -1})
+	}
+
+	for i, entry := range entries {
+		base, _ := url.Parse(entry.Request.URL)
+
+		for _, header := range entry.Response.Headers {
+			if strings.EqualFold(header.Name, "Link") {
+				for _, link := range parseLinkHeader(header.Value) {
+					add(link[0], link[1], "Link header", base)
+				}
+			}
+		}
+
+		if strings.Contains(entry.Response.Content.MimeType, "html") {
+			for _, tag := range linkTagPattern.FindAllString(ResponseBody(entry), -1) {
+				rel := linkRelPattern.FindStringSubmatch(tag)
+				href := linkHrefPattern.FindStringSubmatch(tag)
+				if rel == nil || href == nil {
+					continue
+				}
+				if preloadRel := matchPreloadRel(rel[1] + " " + rel[2] + " " + rel[3]); preloadRel != "" {
+					add(href[1], preloadRel, "<link> tag", base)
+					declarations[i] = append(declarations[i], tag)
+				}
+			}
+		}
+
+		purpose, ok := HeaderValue(entry.Request.Headers, "Sec-Purpose")
+		if !ok {
+			purpose, ok = HeaderValue(entry.Request.Headers, "Purpose")
+		}
+		if ok && strings.Contains(strings.ToLower(purpose), "prefetch") {
+			add(entry.Request.URL, "prefetch", "Sec-Purpose header", nil)
+		}
+	}
+
+	for h := range hints {
+		hint := &hints[h]
+		for i, entry := range entries {
+			if stripFragment(entry.Request.URL) == hint.URL {
+				hint.EntryIndex = i
+				hint.Size = entry.Response.Content.Size
+				break
+			}
+		}
+		if hint.EntryIndex >= 0 {
+			hint.Used = a.preloadUsed(*hint, declarations)
+		}
+	}
+
+	return hints
+}
+
+func (a *Analyzer) preloadUsed(hint PreloadHint, declarations map[int][]string) bool {
+	path := hint.URL
+	if parsed, err := url.Parse(hint.URL); err == nil && len(parsed.Path) > 1 {
+		path = parsed.Path
+	}
+
+	for i, entry := range a.har.Log.Entries {
+		if i == hint.EntryIndex {
+			continue
+		}
+		if referer, ok := HeaderValue(entry.Request.Headers, "Referer"); ok && stripFragment(referer) == hint.URL {
+			return true
+		}
+
+		body := ResponseBody(entry)
+		for _, tag := range declarations[i] {
+			body = strings.ReplaceAll(body, tag, "")
+		}
+		if strings.Contains(body, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseLinkHeader returns the [url, rel] pairs of preload hints in a Link header.
+func parseLinkHeader(value string) [][2]string {
+	var links [][2]string
+	for _, part := range strings.Split(value, ",") {
+		fields := strings.Split(part, ";")
+		target := strings.TrimSpace(fields[0])
+		if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+			continue
+		}
+		for _, param := range fields[1:] {
+			name, rel, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if strings.EqualFold(name, "rel") {
+				if preloadRel := matchPreloadRel(strings.Trim(rel, `"`)); preloadRel != "" {
+					links = append(links, [2]string{strings.Trim(target, "<>"), preloadRel})
+				}
+			}
+		}
+	}
+	return links
+}
+
+// matchPreloadRel returns the preload relation in a space-separated rel value.
+func matchPreloadRel(rel string) string {
+	for _, value := range strings.Fields(strings.ToLower(rel)) {
+		if containsString(preloadRels, value) {
+			return value
+		}
+	}
+	return ""
+}
+
+// ResponseBody returns the response text, decoding base64 content.
+func ResponseBody(entry Entry) string {
+	if entry.Response.Content.Encoding == "base64" {
+		if decoded, err := base64.StdEncoding.DecodeString(entry.Response.Content.Text); err == nil {
+			return string(decoded)
+		}
+	}
+	return entry.Response.Content.Text
+}
+
+func stripFragment(rawURL string) string {
+	rawURL, _, _ = strings.Cut(rawURL, "#")
+	return rawURL
+}
@@ -0,0 +1,121 @@
+package har
+
+import (
+	"sort"
+	"strings"
+)
+
+// HTTP protocol versions reported by the distribution, in display order.
+var HTTPProtocols = []string{"HTTP/3", "HTTP/2", "HTTP/1.1", "HTTP/1.0", "unknown"}
+
+// ProtocolStats counts requests by HTTP protocol version.
+type ProtocolStats struct {
+	Total      int
+	ByProtocol map[string]int
+}
+
+// Share returns the percentage of requests made over the protocol.
+func (s ProtocolStats) Share(protocol string) float64 {
+	if s.Total == 0 {
+		return 0
+	}
+	return float64(s.ByProtocol[protocol]) / float64(s.Total) * 100
+}
+
+// Known returns the number of requests with a recorded protocol version.
+func (s ProtocolStats) Known() int {
+	return s.Total - s.ByProtocol["unknown"]
+}
+
+// NormalizeHTTPVersion maps the spellings browsers and proxies record, such as
+// "h2", "HTTP/2.0", "h3-29" or "http/1.1", to one of HTTPProtocols.
+func NormalizeHTTPVersion(version string) string {
+	version = strings.ToLower(strings.TrimSpace(version))
+	switch {
+	case version == "":
+		return "unknown"
+	case strings.HasPrefix(version, "h3"), strings.HasPrefix(version, "http/3"), strings.Contains(version, "quic"):
+		return "HTTP/3"
+	case strings.HasPrefix(version, "h2"), strings.HasPrefix(version, "http/2"), strings.HasPrefix(version, "spdy"):
+		return "HTTP/2"
+	case version == "http/1.0":
+		return "HTTP/1.0"
+	case strings.HasPrefix(version, "http/1"):
+		return "HTTP/1.1"
+	}
+	return "unknown"
+}
+
+// EntryProtocol returns the protocol the response was served over, falling
+// back to the request's version when the response did not record one.
+func EntryProtocol(entry Entry) string {
+	if protocol := NormalizeHTTPVersion(entry.Response.HTTPVersion); protocol != "unknown" {
+		return protocol
+	}
+	return NormalizeHTTPVersion(entry.Request.HTTPVersion)
+}
+
+// IsMultiplexedProtocol reports whether requests share one connection.
+func IsMultiplexedProtocol(protocol string) bool {
+	return protocol == "HTTP/2" || protocol == "HTTP/3"
+}
+
+// ProtocolDistribution counts the requests of the capture by protocol.
+func (a *Analyzer) ProtocolDistribution() ProtocolStats {
+	stats := ProtocolStats{ByProtocol: make(map[string]int)}
+	for _, entry := range a.har.Log.Entries {
+		stats.Total++
+		stats.ByProtocol[EntryProtocol(entry)]++
+	}
+	return stats
+}
+
+// HTTP1Origin is an origin served over HTTP/1.x.
+type HTTP1Origin struct {
+	Origin       string
+	Protocol     string
+	Requests     int
+	PeakParallel int // most requests in flight at once
+}
+
+// Queued reports whether more requests were in flight than the browser opens
+// connections for, so some of them waited for a free connection.
+func (o HTTP1Origin) Queued() bool {
+	return o.PeakParallel > HTTP1ConnectionsPerOrigin
+}
+
+// HTTP1Origins lists the origins served over HTTP/1.x, busiest first. Origins
+// that had more than six requests in flight at once are the ones that would
+// gain most from HTTP/2 or HTTP/3.
+func (a *Analyzer) HTTP1Origins() []HTTP1Origin {
+	origins := make(map[string]*HTTP1Origin)
+	spans := make(map[string][]interval)
+
+	for _, entry := range a.har.Log.Entries {
+		protocol := EntryProtocol(entry)
+		origin := entryOrigin(entry)
+		if origin == "" || (protocol != "HTTP/1.1" && protocol != "HTTP/1.0") {
+			continue
+		}
+		stats, ok := origins[origin]
+		if !ok {
+			stats = &HTTP1Origin{Origin: origin, Protocol: protocol}
+			origins[origin] = stats
+		}
+		stats.Requests++
+		spans[origin] = append(spans[origin], interval{entry.StartedDateTime, entryEnd(entry)})
+	}
+
+	result := make([]HTTP1Origin, 0, len(origins))
+	for origin, stats := range origins {
+		stats.PeakParallel, _ = peakParallel(spans[origin])
+		result = append(result, *stats)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].PeakParallel != result[j].PeakParallel {
+			return result[i].PeakParallel > result[j].PeakParallel
+		}
+		return result[i].Origin < result[j].Origin
+	})
+	return result
+}
@@ -0,0 +1,62 @@
+package har
+
+import (
+	"net/url"
+	"strings"
+)
+
+// LongQueryValue is the decoded length from which a query parameter value is
+// suspiciously long, e.g. an encoded state blob or leaked token.
+const LongQueryValue = 200
+
+// QueryParam is one query-string parameter of a request.
+type QueryParam struct {
+	Name  string
+	Value string // decoded
+	// Duplicate marks a name that appears more than once, which servers
+	// resolve differently (first, last or all values)
+	Duplicate bool
+	Empty     bool
+	Long      bool
+}
+
+// QueryParams lists the query-string parameters of a request in order, with
+// decoded values. It falls back to parsing the URL when the capture recorded
+// no query string.
+func QueryParams(entry Entry) []QueryParam {
+	pairs := entry.Request.QueryString
+	if len(pairs) == 0 {
+		if parsed, err := url.Parse(entry.Request.URL); err == nil && parsed.RawQuery != "" {
+			for _, part := range strings.Split(parsed.RawQuery, "&") {
+				if part == "" {
+					continue
+				}
+				name, value, _ := strings.Cut(part, "=")
+				pairs = append(pairs, QueryItem{Name: name, Value: value})
+			}
+		}
+	}
+
+	counts := map[string]int{}
+	params := make([]QueryParam, len(pairs))
+	for i, pair := range pairs {
+		params[i] = QueryParam{Name: decodeQueryComponent(pair.Name), Value: decodeQueryComponent(pair.Value)}
+		counts[params[i].Name]++
+	}
+	for i := range params {
+		params[i].Duplicate = counts[params[i].Name] > 1
+		params[i].Empty = params[i].Value == ""
+		params[i].Long = len(params[i].Value) >= LongQueryValue
+	}
+	return params
+}
+
+// decodeQueryComponent decodes a query name or value, which browsers record
+// either raw or already decoded; values that do not decode are kept as is.
+func decodeQueryComponent(value string) string {
+	decoded, err := url.QueryUnescape(value)
+	if err != nil {
+		return value
+	}
+	return decoded
+}
@@ -0,0 +1,214 @@
+package har
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// rateLimitHeaderPrefixes are the prefixes of the quota headers APIs send,
+// e.g. X-RateLimit-Remaining (GitHub, most APIs), RateLimit-Remaining (IETF
+// draft) and X-Rate-Limit-Remaining (Twitter).
+var rateLimitHeaderPrefixes = []string{"X-RateLimit-", "RateLimit-", "X-Rate-Limit-"}
+
+// RateLimitHeaders are the rate limiting headers of one response.
+type RateLimitHeaders struct {
+	Limit     int // -1 when not sent
+	Remaining int // -1 when not sent
+	// RetryAfter is how long the server asked the client to wait, from the
+	// Retry-After header; 0 when not sent
+	RetryAfter time.Duration
+}
+
+// HasQuota reports whether the response told how much of its quota is left.
+func (h RateLimitHeaders) HasQuota() bool {
+	return h.Remaining >= 0
+}
+
+// ParseRateLimit reads the Retry-After and X-RateLimit-* style headers of a
+// response.
+func ParseRateLimit(entry Entry) RateLimitHeaders {
+	headers := entry.Response.Headers
+	result := RateLimitHeaders{
+		Limit:     rateLimitValue(headers, "Limit"),
+		Remaining: rateLimitValue(headers, "Remaining"),
+	}
+
+	if value, ok := HeaderValue(headers, "Retry-After"); ok {
+		value = strings.TrimSpace(value)
+		if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+			result.RetryAfter = time.Duration(seconds) * time.Second
+		} else if retry, err := http.ParseTime(value); err == nil {
+			date := entry.StartedDateTime
+			if value, ok := HeaderValue(headers, "Date"); ok {
+				if parsed, err := http.ParseTime(value); err == nil {
+					date = parsed
+				}
+			}
+			result.RetryAfter = max(retry.Sub(date), 0)
+		}
+	}
+	return result
+}
+
+// rateLimitValue returns the leading number of the first quota header with
+// the given suffix, e.g. 100 from "100, 100;w=60", or -1 when there is none.
+func rateLimitValue(headers []Header, suffix string) int {
+	for _, prefix := range rateLimitHeaderPrefixes {
+		value, ok := HeaderValue(headers, prefix+suffix)
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		end := strings.IndexFunc(value, func(r rune) bool { return r < '0' || r > '9' })
+		if end < 0 {
+			end = len(value)
+		}
+		if number, err := strconv.Atoi(value[:end]); err == nil {
+			return number
+		}
+	}
+	return -1
+}
+
+// ThrottledEndpoint counts the 429 responses of one endpoint, see EndpointKey.
+type ThrottledEndpoint struct {
+	Endpoint  string `json:"endpoint"`
+	Requests  int    `json:"requests"`
+	Throttled int    `json:"throttled"`
+	// RetryAfter is the longest wait the server asked for
+	RetryAfter time.Duration `json:"retry_after"`
+	// Backoff is the time the throttled requests took plus the wait before
+	// the next request to the endpoint, or the Retry-After wait when the
+	// capture has no later request
+	Backoff time.Duration `json:"backoff"`
+}
+
+// QuotaSample is the remaining quota reported by one response.
+type QuotaSample struct {
+	EntryIndex int       `json:"entry_index"`
+	Time       time.Time `json:"time"`
+	Limit      int       `json:"limit"` // -1 when not sent
+	Remaining  int       `json:"remaining"`
+}
+
+// HostQuota is the remaining quota one host reported over the capture, in
+// request order.
+type HostQuota struct {
+	Host    string        `json:"host"`
+	Samples []QuotaSample `json:"samples"`
+}
+
+// Limit is the last quota limit the host sent, or -1 when it sent none.
+func (q HostQuota) Limit() int {
+	for i := len(q.Samples) - 1; i >= 0; i-- {
+		if q.Samples[i].Limit >= 0 {
+			return q.Samples[i].Limit
+		}
+	}
+	return -1
+}
+
+// MinRemaining is the lowest remaining quota the host reported.
+func (q HostQuota) MinRemaining() int {
+	lowest := q.Samples[0].Remaining
+	for _, sample := range q.Samples {
+		lowest = min(lowest, sample.Remaining)
+	}
+	return lowest
+}
+
+// RateLimitAnalysis is how much rate limiting a capture ran into.
+type RateLimitAnalysis struct {
+	Throttled int                 `json:"throttled"` // 429 responses
+	Backoff   time.Duration       `json:"backoff"`   // summed over Endpoints
+	Endpoints []ThrottledEndpoint `json:"endpoints,omitempty"`
+	Quotas    []HostQuota         `json:"quotas,omitempty"`
+}
+
+// HasData reports whether the capture shows any rate limiting.
+func (r RateLimitAnalysis) HasData() bool {
+	return r.Throttled > 0 || len(r.Quotas) > 0
+}
+
+// RateLimits finds the endpoints that answered 429 Too Many Requests and the
+// time likely lost backing off, and follows the remaining quota each host
+// reported. Throttled endpoints are listed most throttled first, quotas
+// lowest remaining quota first.
+func (a *Analyzer) RateLimits() RateLimitAnalysis {
+	entries := a.har.Log.Entries
+	order := make([]int, len(entries))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return entries[order[i]].StartedDateTime.Before(entries[order[j]].StartedDateTime)
+	})
+
+	var analysis RateLimitAnalysis
+	byEndpoint := map[string][]int{}
+	var endpoints []string
+	quotas := map[string]*HostQuota{}
+	var hosts []string
+	for _, i := range order {
+		entry := entries[i]
+		key := EndpointKey(entry)
+		if _, ok := byEndpoint[key]; !ok {
+			endpoints = append(endpoints, key)
+		}
+		byEndpoint[key] = append(byEndpoint[key], i)
+
+		headers := ParseRateLimit(entry)
+		if !headers.HasQuota() {
+			continue
+		}
+		host := EntryHost(entry)
+		quota, ok := quotas[host]
+		if !ok {
+			quota = &HostQuota{Host: host}
+			quotas[host] = quota
+			hosts = append(hosts, host)
+		}
+		quota.Samples = append(quota.Samples, QuotaSample{EntryIndex: i, Time: entry.StartedDateTime, Limit: headers.Limit, Remaining: headers.Remaining})
+	}
+
+	for _, key := range endpoints {
+		requests := byEndpoint[key]
+		endpoint := ThrottledEndpoint{Endpoint: key, Requests: len(requests)}
+		for position, i := range requests {
+			entry := entries[i]
+			if entry.Response.Status != http.StatusTooManyRequests {
+				continue
+			}
+			endpoint.Throttled++
+			retryAfter := ParseRateLimit(entry).RetryAfter
+			endpoint.RetryAfter = max(endpoint.RetryAfter, retryAfter)
+
+			duration := time.Duration(entry.Time * float64(time.Millisecond))
+			wait := retryAfter
+			if position+1 < len(requests) {
+				end := entry.StartedDateTime.Add(duration)
+				wait = max(entries[requests[position+1]].StartedDateTime.Sub(end), 0)
+			}
+			endpoint.Backoff += duration + wait
+		}
+		if endpoint.Throttled > 0 {
+			analysis.Throttled += endpoint.Throttled
+			analysis.Backoff += endpoint.Backoff
+			analysis.Endpoints = append(analysis.Endpoints, endpoint)
+		}
+	}
+	sort.SliceStable(analysis.Endpoints, func(i, j int) bool {
+		return analysis.Endpoints[i].Throttled > analysis.Endpoints[j].Throttled
+	})
+
+	for _, host := range hosts {
+		analysis.Quotas = append(analysis.Quotas, *quotas[host])
+	}
+	sort.SliceStable(analysis.Quotas, func(i, j int) bool {
+		return analysis.Quotas[i].MinRemaining() < analysis.Quotas[j].MinRemaining()
+	})
+	return analysis
+}
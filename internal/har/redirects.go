@@ -0,0 +1,168 @@
+package har
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// LongRedirectChain is the number of redirects at which a chain is reported
+// as too long.
+const LongRedirectChain = 3
+
+// RedirectHop is one request of a redirect chain.
+type RedirectHop struct {
+	EntryIndex int
+	URL        string
+	Status     int
+	Time       float64
+	Kind       string // how the next hop differs, e.g. "http→https"; empty for the last hop
+}
+
+// RedirectChain is a run of redirects ending at the request that was finally
+// answered. A chain whose target was not captured ends at its last redirect.
+type RedirectChain struct {
+	Hops []RedirectHop
+}
+
+// Redirects returns the number of redirects in the chain.
+func (c RedirectChain) Redirects() int {
+	if len(c.Hops) > 0 && isRedirect(c.Hops[len(c.Hops)-1].Status) {
+		return len(c.Hops)
+	}
+	return len(c.Hops) - 1
+}
+
+// Latency is the time spent on redirect responses before the final request.
+func (c RedirectChain) Latency() float64 {
+	var total float64
+	for _, hop := range c.Hops[:c.Redirects()] {
+		total += hop.Time
+	}
+	return total
+}
+
+// Contains reports whether the entry is part of the chain.
+func (c RedirectChain) Contains(entryIndex int) bool {
+	for _, hop := range c.Hops {
+		if hop.EntryIndex == entryIndex {
+			return true
+		}
+	}
+	return false
+}
+
+// RedirectChains follows Response.RedirectURL (or the Location header) from
+// every 3xx response to the next request of the target URL, slowest chain first.
+func (a *Analyzer) RedirectChains() []RedirectChain {
+	entries := a.har.Log.Entries
+	next := make(map[int]int)
+	targeted := make(map[int]bool)
+
+	for i, entry := range entries {
+		location := redirectLocation(entry)
+		if !isRedirect(entry.Response.Status) || location == "" {
+			continue
+		}
+		for j, candidate := range entries {
+			if j == i || targeted[j] || candidate.StartedDateTime.Before(entry.StartedDateTime) {
+				continue
+			}
+			if stripFragment(candidate.Request.URL) == location {
+				next[i] = j
+				targeted[j] = true
+				break
+			}
+		}
+	}
+
+	var chains []RedirectChain
+	for i, entry := range entries {
+		if targeted[i] || !isRedirect(entry.Response.Status) || redirectLocation(entry) == "" {
+			continue
+		}
+
+		var chain RedirectChain
+		seen := make(map[int]bool)
+		for index, ok := i, true; ok && !seen[index]; index, ok = next[index] {
+			seen[index] = true
+			chain.Hops = append(chain.Hops, RedirectHop{
+				EntryIndex: index,
+				URL:        entries[index].Request.URL,
+				Status:     entries[index].Response.Status,
+				Time:       entries[index].Time,
+			})
+		}
+		for h := 0; h < len(chain.Hops)-1; h++ {
+			chain.Hops[h].Kind = redirectKind(chain.Hops[h].URL, chain.Hops[h+1].URL)
+		}
+		chains = append(chains, chain)
+	}
+
+	sort.SliceStable(chains, func(i, j int) bool {
+		return chains[i].Latency() > chains[j].Latency()
+	})
+	return chains
+}
+
+// RedirectChainFor returns the chain an entry belongs to.
+func (a *Analyzer) RedirectChainFor(entryIndex int) (RedirectChain, bool) {
+	for _, chain := range a.RedirectChains() {
+		if chain.Contains(entryIndex) {
+			return chain, true
+		}
+	}
+	return RedirectChain{}, false
+}
+
+func isRedirect(status int) bool {
+	return status >= 300 && status < 400 && status != 304
+}
+
+// redirectLocation returns the absolute redirect target of an entry.
+func redirectLocation(entry Entry) string {
+	location := entry.Response.RedirectURL
+	if location == "" {
+		location, _ = HeaderValue(entry.Response.Headers, "Location")
+	}
+	if location == "" {
+		return ""
+	}
+	if base, err := url.Parse(entry.Request.URL); err == nil {
+		if reference, err := url.Parse(location); err == nil {
+			location = base.ResolveReference(reference).String()
+		}
+	}
+	return stripFragment(location)
+}
+
+// redirectKind describes what a redirect changed: "http→https", "→www",
+// "www→" (to the bare domain), "cross-origin" or "same-origin".
+func redirectKind(from, to string) string {
+	source, err := url.Parse(from)
+	if err != nil {
+		return ""
+	}
+	target, err := url.Parse(to)
+	if err != nil {
+		return ""
+	}
+
+	var kinds []string
+	if source.Scheme == "http" && target.Scheme == "https" {
+		kinds = append(kinds, "http→https")
+	}
+	sourceHost, targetHost := source.Hostname(), target.Hostname()
+	switch {
+	case "www."+sourceHost == targetHost:
+		kinds = append(kinds, "→www")
+	case sourceHost == "www."+targetHost:
+		kinds = append(kinds, "www→")
+	case sourceHost != targetHost:
+		kinds = append(kinds, "cross-origin")
+	}
+	if len(kinds) == 0 {
+		return "same-origin"
+	}
+	return strings.Join(kinds, ", ")
+}
@@ -0,0 +1,201 @@
+package har
+
+import (
+	"math"
+	"sort"
+)
+
+// SignificanceLevel is the p-value below which a difference between two run
+// sets is considered real rather than run-to-run noise.
+const SignificanceLevel = 0.05
+
+// RunSet is a labeled group of captures of the same page, e.g. ten runs
+// before and ten after a change.
+type RunSet struct {
+	Label   string
+	Metrics []*Metrics
+}
+
+// RunStats summarizes one metric over the runs of a set.
+type RunStats struct {
+	Runs   int     `json:"runs"`
+	Mean   float64 `json:"mean"`
+	Median float64 `json:"median"`
+	StdDev float64 `json:"std_dev"` // sample standard deviation
+	Min    float64 `json:"min"`
+	Max    float64 `json:"max"`
+}
+
+// NewRunStats summarizes the values of a metric over several runs.
+func NewRunStats(values []float64) RunStats {
+	if len(values) == 0 {
+		return RunStats{}
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	stats := RunStats{Runs: len(sorted), Min: sorted[0], Max: sorted[len(sorted)-1]}
+	for _, value := range sorted {
+		stats.Mean += value
+	}
+	stats.Mean /= float64(len(sorted))
+	if middle := len(sorted) / 2; len(sorted)%2 == 0 {
+		stats.Median = (sorted[middle-1] + sorted[middle]) / 2
+	} else {
+		stats.Median = sorted[middle]
+	}
+	if len(sorted) > 1 {
+		for _, value := range sorted {
+			stats.StdDev += (value - stats.Mean) * (value - stats.Mean)
+		}
+		stats.StdDev = math.Sqrt(stats.StdDev / float64(len(sorted)-1))
+	}
+	return stats
+}
+
+// RunSetDifference compares one metric between two run sets. Change is the
+// change of the mean in percent; PValue comes from Welch's t-test.
+type RunSetDifference struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Baseline    RunStats `json:"baseline"`
+	Candidate   RunStats `json:"candidate"`
+	Change      float64  `json:"change_percent"`
+	PValue      float64  `json:"p_value"`
+	Significant bool     `json:"significant"`
+	Improvement bool     `json:"improvement"`
+}
+
+// Significance marks the p-value the way papers do: *** below 0.001, ** below
+// 0.01, * below SignificanceLevel and "ns" otherwise.
+func (d RunSetDifference) Significance() string {
+	switch {
+	case d.PValue < 0.001:
+		return "***"
+	case d.PValue < 0.01:
+		return "**"
+	case d.PValue < SignificanceLevel:
+		return "*"
+	}
+	return "ns"
+}
+
+// RunSetComparison compares every measured metric of a candidate run set
+// against a baseline run set.
+type RunSetComparison struct {
+	Baseline    string             `json:"baseline"`
+	Candidate   string             `json:"candidate"`
+	Differences []RunSetDifference `json:"differences"`
+}
+
+// CompareRunSets compares the metrics of two run sets by their distributions
+// rather than run by run. Metrics neither set measured are left out.
+func CompareRunSets(baseline, candidate RunSet) RunSetComparison {
+	comparison := RunSetComparison{Baseline: baseline.Label, Candidate: candidate.Label}
+	for _, descriptor := range metricRegistry {
+		before, after := runValues(descriptor, baseline), runValues(descriptor, candidate)
+		if len(before) == 0 || len(after) == 0 {
+			continue
+		}
+
+		difference := RunSetDifference{
+			ID:        descriptor.ID,
+			Name:      descriptor.Name,
+			Baseline:  NewRunStats(before),
+			Candidate: NewRunStats(after),
+		}
+		change := difference.Candidate.Mean - difference.Baseline.Mean
+		if difference.Baseline.Mean != 0 {
+			difference.Change = change / difference.Baseline.Mean * 100
+		}
+		difference.PValue = welchTTest(difference.Baseline, difference.Candidate)
+		difference.Significant = difference.PValue < SignificanceLevel
+		difference.Improvement = difference.Significant && descriptor.IsImprovement(change)
+		comparison.Differences = append(comparison.Differences, difference)
+	}
+	return comparison
+}
+
+func runValues(descriptor MetricDescriptor, set RunSet) []float64 {
+	var values []float64
+	for _, metrics := range set.Metrics {
+		if descriptor.Measured(metrics) {
+			values = append(values, descriptor.Value(metrics))
+		}
+	}
+	return values
+}
+
+// welchTTest returns the two-sided p-value of Welch's t-test, which does not
+// assume equal variances. Sets of a single run cannot show significance.
+func welchTTest(a, b RunStats) float64 {
+	if a.Runs < 2 || b.Runs < 2 {
+		return 1
+	}
+	varA, varB := a.StdDev*a.StdDev/float64(a.Runs), b.StdDev*b.StdDev/float64(b.Runs)
+	if varA+varB == 0 {
+		// Identical runs within each set: any difference is certain
+		if a.Mean == b.Mean {
+			return 1
+		}
+		return 0
+	}
+	t := (b.Mean - a.Mean) / math.Sqrt(varA+varB)
+	df := (varA + varB) * (varA + varB) / (varA*varA/float64(a.Runs-1) + varB*varB/float64(b.Runs-1))
+	return regularizedBeta(df/(df+t*t), df/2, 0.5)
+}
+
+// regularizedBeta is the regularized incomplete beta function I_x(a, b),
+// evaluated with a continued fraction (Numerical Recipes, betai).
+func regularizedBeta(x, a, b float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	if x >= 1 {
+		return 1
+	}
+	lgammaAB, _ := math.Lgamma(a + b)
+	lgammaA, _ := math.Lgamma(a)
+	lgammaB, _ := math.Lgamma(b)
+	front := math.Exp(lgammaAB - lgammaA - lgammaB + a*math.Log(x) + b*math.Log(1-x))
+	if x < (a+1)/(a+b+2) {
+		return front * betaFraction(x, a, b) / a
+	}
+	return 1 - front*betaFraction(1-x, b, a)/b
+}
+
+func betaFraction(x, a, b float64) float64 {
+	const (
+		iterations = 200
+		epsilon    = 1e-12
+		tiny       = 1e-300
+	)
+	c, d := 1.0, 1-(a+b)*x/(a+1)
+	if math.Abs(d) < tiny {
+		d = tiny
+	}
+	d = 1 / d
+	result := d
+	for m := 1; m <= iterations; m++ {
+		m2 := float64(2 * m)
+		for _, numerator := range []float64{
+			float64(m) * (b - float64(m)) * x / ((a + m2 - 1) * (a + m2)),
+			-(a + float64(m)) * (a + b + float64(m)) * x / ((a + m2) * (a + m2 + 1)),
+		} {
+			d = 1 + numerator*d
+			if math.Abs(d) < tiny {
+				d = tiny
+			}
+			c = 1 + numerator/c
+			if math.Abs(c) < tiny {
+				c = tiny
+			}
+			d = 1 / d
+			result *= d * c
+		}
+		if math.Abs(d*c-1) < epsilon {
+			break
+		}
+	}
+	return result
+}
@@ -0,0 +1,112 @@
+package har
+
+import "math"
+
+// ScoreCategory is the grade of one area of a capture, e.g. caching.
+type ScoreCategory struct {
+	ID    string  `json:"id"`
+	Name  string  `json:"name"`
+	Score float64 `json:"score"` // 0 to 100
+	Grade string  `json:"grade"` // A to F, or NotAvailable when nothing in the area applies
+}
+
+// PerformanceScore is a Lighthouse-style score of a capture: a weighted mean
+// of how its headline metrics rate on a log-normal curve, plus a grade per
+// category.
+type PerformanceScore struct {
+	Score      float64         `json:"score"` // 0 to 100
+	Grade      string          `json:"grade"`
+	Categories []ScoreCategory `json:"categories"`
+}
+
+// Score categories, in display order.
+var scoreCategories = []struct{ id, name string }{
+	{"network", "Network"},
+	{"caching", "Caching"},
+	{"payload", "Payload"},
+	{"third_party", "Third-party"},
+}
+
+// scoreInput rates one aspect of a capture from 0 to 1. Inputs with a weight
+// count towards the overall score; every input counts towards its category.
+type scoreInput struct {
+	category string
+	weight   float64
+	rate     func(m *Metrics) (float64, bool) // false when the input does not apply
+}
+
+var scoreInputs = []scoreInput{
+	{category: "network", weight: 0.20, rate: func(m *Metrics) (float64, bool) {
+		return logNormalScore(m.TTFB, 200, 600), true
+	}},
+	{category: "network", weight: 0.25, rate: func(m *Metrics) (float64, bool) {
+		return logNormalScore(m.PageLoadTime, 1500, 4000), m.PageLoadTime > 0
+	}},
+	{category: "network", weight: 0.15, rate: func(m *Metrics) (float64, bool) {
+		return logNormalScore(float64(m.ErrorRequests)/float64(m.TotalRequests)*100, 1, 5), true
+	}},
+	{category: "caching", weight: 0.15, rate: func(m *Metrics) (float64, bool) {
+		return m.CacheHitRatio / 100, m.Environment.ChecksDelivery()
+	}},
+	{category: "caching", rate: func(m *Metrics) (float64, bool) {
+		return m.CDNHitRatio / 100, m.Environment.ChecksDelivery() && m.CDNResponses > 0
+	}},
+	{category: "payload", weight: 0.15, rate: func(m *Metrics) (float64, bool) {
+		return logNormalScore(float64(m.TotalSize), 1.5*1024*1024, 5*1024*1024), m.Environment.ChecksDelivery()
+	}},
+	{category: "payload", weight: 0.10, rate: func(m *Metrics) (float64, bool) {
+		return logNormalScore(float64(m.TotalRequests), 50, 150), true
+	}},
+	{category: "payload", rate: func(m *Metrics) (float64, bool) {
+		return m.CompressionCoverage / 100, m.Environment.ChecksDelivery() && m.TextResponses > 0
+	}},
+	{category: "third_party", rate: func(m *Metrics) (float64, bool) {
+		return logNormalScore(float64(m.ThirdPartyRequests)/float64(m.TotalRequests)*100, 10, 50), true
+	}},
+}
+
+// logNormalScore rates a lower-is-better value from 1 to 0 the way
+// Lighthouse does: p10 scores 0.9 and median scores 0.5.
+func logNormalScore(value, p10, median float64) float64 {
+	if value <= 0 {
+		return 1
+	}
+	// 1.2816 is the standard normal quantile of 0.9
+	sigma := math.Log(median/p10) / 1.2816
+	return 0.5 * math.Erfc(math.Log(value/median)/(sigma*math.Sqrt2))
+}
+
+// ScorePerformance scores a capture. Inputs that do not apply, e.g. caching
+// in a development capture, are left out and the weights of the others
+// scaled up. A capture without entries is graded NotAvailable throughout.
+func ScorePerformance(m *Metrics) PerformanceScore {
+	var weighted, weights float64
+	sums := make(map[string]float64)
+	counts := make(map[string]int)
+	for _, input := range scoreInputs {
+		rating, ok := input.rate(m)
+		if !ok || !m.HasData() {
+			continue
+		}
+		rating = math.Max(0, math.Min(rating, 1))
+		weighted += rating * input.weight
+		weights += input.weight
+		sums[input.category] += rating
+		counts[input.category]++
+	}
+
+	score := PerformanceScore{Grade: NotAvailable}
+	if weights > 0 {
+		score.Score = math.Round(weighted / weights * 100)
+		score.Grade = Grade(score.Score)
+	}
+	for _, category := range scoreCategories {
+		graded := ScoreCategory{ID: category.id, Name: category.name, Grade: NotAvailable}
+		if counts[category.id] > 0 {
+			graded.Score = math.Round(sums[category.id] / float64(counts[category.id]) * 100)
+			graded.Grade = Grade(graded.Score)
+		}
+		score.Categories = append(score.Categories, graded)
+	}
+	return score
+}
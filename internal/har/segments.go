@@ -0,0 +1,63 @@
+package har
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// DefaultSegmentGap is the idle time after which a new segment starts.
+const DefaultSegmentGap = 2 * time.Second
+
+// Segment is a burst of requests separated from its neighbours by idle time,
+// approximating one user action in captures without page markers.
+type Segment struct {
+	Label   string    `json:"label"`
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+	Entries []Entry   `json:"-"`
+	Metrics *Metrics  `json:"metrics"`
+}
+
+// Duration is the time from the first request start to the last response end.
+func (s Segment) Duration() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
+// Segments splits the capture wherever no request is in flight for longer
+// than gap, and computes metrics for each segment. A gap of zero or less
+// uses DefaultSegmentGap.
+func (a *Analyzer) Segments(gap time.Duration) []Segment {
+	if gap <= 0 {
+		gap = DefaultSegmentGap
+	}
+
+	entries := make([]Entry, len(a.har.Log.Entries))
+	copy(entries, a.har.Log.Entries)
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].StartedDateTime.Before(entries[j].StartedDateTime)
+	})
+
+	var segments []Segment
+	for _, entry := range entries {
+		end := entry.StartedDateTime.Add(time.Duration(entry.Time * float64(time.Millisecond)))
+
+		if len(segments) == 0 || entry.StartedDateTime.Sub(segments[len(segments)-1].End) > gap {
+			segments = append(segments, Segment{Start: entry.StartedDateTime, End: end})
+		}
+
+		current := &segments[len(segments)-1]
+		current.Entries = append(current.Entries, entry)
+		if end.After(current.End) {
+			current.End = end
+		}
+	}
+
+	for i := range segments {
+		segments[i].Label = fmt.Sprintf("Segment %d", i+1)
+		segment := &HAR{Log: Log{Entries: segments[i].Entries}}
+		segments[i].Metrics = NewAnalyzer(segment, a.settings).CalculateMetrics()
+	}
+
+	return segments
+}
@@ -0,0 +1,16 @@
+package har
+
+// Settings are the choices of a session that change how its captures are
+// analyzed, compared and displayed. The zero value detects the first-party
+// domain and environment of each capture, uses the default noise thresholds
+// and writes sizes in binary units.
+type Settings struct {
+	// FirstParty is the domain whose hosts, and their subdomains, are first
+	// party instead of the one detected from the capture.
+	FirstParty string
+	// Environment replaces the one detected from the page's host.
+	Environment Environment
+	// Noise replaces the default threshold of metrics by ID.
+	Noise     map[string]NoiseThreshold
+	SizeUnits SizeUnits
+}
@@ -0,0 +1,67 @@
+package har
+
+import "testing"
+
+func TestSettingsApplyOnlyToTheirAnalysis(t *testing.T) {
+	capture := &HAR{Log: Log{Entries: []Entry{
+		{Request: Request{URL: "https://www.example.com/"}, Response: Response{Content: Content{MimeType: "text/html"}}},
+		{Request: Request{URL: "https://api.example.net/items"}},
+	}}}
+
+	configured := NewAnalyzer(capture, Settings{FirstParty: "www.example.net", Environment: EnvStaging})
+	detected := NewAnalyzer(capture, Settings{})
+
+	if got := configured.FirstPartyDomain(); got != "example.net" {
+		t.Errorf("configured first-party domain = %q, want example.net", got)
+	}
+	if got := detected.FirstPartyDomain(); got != "example.com" {
+		t.Errorf("detected first-party domain = %q, want example.com from the page", got)
+	}
+	if got := configured.Environment(); got != EnvStaging {
+		t.Errorf("configured environment = %s, want staging", got)
+	}
+	if got := detected.Environment(); got != EnvProduction {
+		t.Errorf("detected environment = %s, want production", got)
+	}
+
+	base := &Metrics{TotalRequests: 1, TTFB: 100}
+	candidate := &Metrics{TotalRequests: 1, TTFB: 120}
+	ttfb := func(settings Settings) MetricDifference {
+		for _, diff := range NewComparator([]string{"before", "after"}, []*Metrics{base, candidate}, settings).Compare().Differences {
+			if diff.ID == MetricTTFB {
+				return diff
+			}
+		}
+		t.Fatal("TTFB was not compared")
+		return MetricDifference{}
+	}
+	if diff := ttfb(Settings{Noise: map[string]NoiseThreshold{MetricTTFB: {Absolute: 50}}}); diff.Flagged(1) {
+		t.Errorf("TTFB change %q is flagged under a 50ms threshold", diff.Changes[1])
+	}
+	if diff := ttfb(Settings{}); !diff.Flagged(1) {
+		t.Errorf("TTFB change %q is not flagged under the default threshold", diff.Changes[1])
+	}
+}
+
+func TestSizeUnits(t *testing.T) {
+	tests := []struct {
+		units SizeUnits
+		size  int
+		want  string
+	}{
+		{"", 512, "512B"},
+		{"", 1536, "1.5KiB"},
+		{BinaryUnits, 3 * 1024 * 1024, "3.0MiB"},
+		{DecimalUnits, 1500, "1.5kB"},
+		{DecimalUnits, 2_000_000, "2.0MB"},
+	}
+	for _, tt := range tests {
+		if got := tt.units.FormatSize(tt.size); got != tt.want {
+			t.Errorf("%q.FormatSize(%d) = %q, want %q", tt.units, tt.size, got, tt.want)
+		}
+	}
+
+	if _, err := ParseSizeUnits("metric"); err == nil {
+		t.Error("ParseSizeUnits accepted an unknown name")
+	}
+}
@@ -0,0 +1,74 @@
+package har
+
+import "fmt"
+
+// SizeUnits is how byte sizes are written in the TUI and reports.
+type SizeUnits string
+
+const (
+	// BinaryUnits are multiples of 1024: KiB, MiB
+	BinaryUnits SizeUnits = "binary"
+	// DecimalUnits are multiples of 1000, as in SI: kB, MB
+	DecimalUnits SizeUnits = "decimal"
+)
+
+// ParseSizeUnits parses binary or decimal. An empty name is the binary
+// default.
+func ParseSizeUnits(name string) (SizeUnits, error) {
+	switch SizeUnits(name) {
+	case "", BinaryUnits:
+		return BinaryUnits, nil
+	case DecimalUnits:
+		return DecimalUnits, nil
+	}
+	return "", fmt.Errorf("unknown size units %q (want binary or decimal)", name)
+}
+
+// Base is the step between units: 1024 bytes to the KiB, or 1000 to the kB.
+// The zero value is binary.
+func (u SizeUnits) Base() float64 {
+	if u == DecimalUnits {
+		return 1000
+	}
+	return 1024
+}
+
+// KilobyteUnit names the kilobyte unit: KiB or kB.
+func (u SizeUnits) KilobyteUnit() string {
+	if u == DecimalUnits {
+		return "kB"
+	}
+	return "KiB"
+}
+
+// MegabyteUnit names the megabyte unit: MiB or MB.
+func (u SizeUnits) MegabyteUnit() string {
+	if u == DecimalUnits {
+		return "MB"
+	}
+	return "MiB"
+}
+
+// Kilobytes converts bytes to kilobytes.
+func (u SizeUnits) Kilobytes(bytes float64) float64 {
+	return bytes / u.Base()
+}
+
+// Megabytes converts bytes to megabytes.
+func (u SizeUnits) Megabytes(bytes float64) float64 {
+	return bytes / (u.Base() * u.Base())
+}
+
+// FormatSize writes a byte count in the largest unit below it, e.g. 512B,
+// 1.5KiB or 2.3MB.
+func (u SizeUnits) FormatSize(size int) string {
+	base := u.Base()
+	switch {
+	case float64(size) < base:
+		return fmt.Sprintf("%dB", size)
+	case float64(size) < base*base:
+		return fmt.Sprintf("%.1f%s", u.Kilobytes(float64(size)), u.KilobyteUnit())
+	default:
+		return fmt.Sprintf("%.1f%s", u.Megabytes(float64(size)), u.MegabyteUnit())
+	}
+}
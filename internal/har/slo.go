@@ -0,0 +1,229 @@
+package har
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ApdexDefaultThreshold is the Apdex target time T, in ms, of SLOs that set
+// no latency objective.
+const ApdexDefaultThreshold = 500.0
+
+// SLO is a service level objective for the requests in a scope, e.g. "95%
+// of API requests under 300ms and fewer than 1% errors".
+type SLO struct {
+	Name    string  `json:"name"`
+	Scope   string  `json:"scope"`   // "all", "api", "type:<resource type>", "tag:<tag>" or a host with its subdomains
+	Latency float64 `json:"latency"` // ms a request may take; 0 means no latency objective
+	Target  float64 `json:"target"`  // percent of requests that must be within Latency
+	Errors  float64 `json:"errors"`  // percent of requests that may fail; negative means no error objective
+	Apdex   float64 `json:"apdex"`   // minimum Apdex score; 0 means no Apdex objective
+}
+
+// ParseSLO parses an objective such as
+// "checkout=api latency=300ms target=95% errors=1% apdex=0.9". The scope is
+// all, api, type:<resource type> (see ResourceType), tag:<tag> or a host and
+// its subdomains; the name is optional and defaults to the scope. Without a
+// target, a latency objective covers every request.
+func ParseSLO(spec string) (SLO, error) {
+	fields := strings.Fields(spec)
+	if len(fields) < 2 {
+		return SLO{}, fmt.Errorf("invalid SLO %q: expected \"[name=]scope latency=DURATION [target=PERCENT] errors=PERCENT apdex=SCORE\"", spec)
+	}
+
+	slo := SLO{Scope: fields[0], Target: 100, Errors: -1}
+	if name, scope, ok := strings.Cut(slo.Scope, "="); ok {
+		slo.Name, slo.Scope = name, scope
+	}
+	if slo.Scope == "" {
+		return SLO{}, fmt.Errorf("invalid SLO %q: no scope", spec)
+	}
+	if slo.Name == "" {
+		slo.Name = slo.Scope
+	}
+
+	for _, objective := range fields[1:] {
+		name, value, _ := strings.Cut(objective, "=")
+		var err error
+		switch name {
+		case "latency":
+			var duration time.Duration
+			duration, err = time.ParseDuration(value)
+			slo.Latency = float64(duration) / float64(time.Millisecond)
+			if err == nil && slo.Latency <= 0 {
+				err = fmt.Errorf("latency must be positive")
+			}
+		case "target":
+			slo.Target, err = parsePercent(value)
+		case "errors":
+			slo.Errors, err = parsePercent(value)
+		case "apdex":
+			slo.Apdex, err = strconv.ParseFloat(value, 64)
+			if err == nil && (slo.Apdex <= 0 || slo.Apdex > 1) {
+				err = fmt.Errorf("apdex must be between 0 and 1")
+			}
+		default:
+			return SLO{}, fmt.Errorf("unknown objective %q in SLO %q (want latency=, target=, errors= or apdex=)", objective, spec)
+		}
+		if err != nil {
+			return SLO{}, fmt.Errorf("invalid objective %q in SLO %q: %w", objective, spec, err)
+		}
+	}
+	if slo.Latency == 0 && slo.Errors < 0 && slo.Apdex == 0 {
+		return SLO{}, fmt.Errorf("invalid SLO %q: set at least one of latency=, errors= or apdex=", spec)
+	}
+	return slo, nil
+}
+
+// parsePercent parses a percentage between 0 and 100, with or without "%".
+func parsePercent(value string) (float64, error) {
+	percent, err := strconv.ParseFloat(strings.TrimSuffix(value, "%"), 64)
+	if err != nil || percent < 0 || percent > 100 {
+		return 0, fmt.Errorf("expected a percentage between 0 and 100")
+	}
+	return percent, nil
+}
+
+// ApdexThreshold is the Apdex target time T of the SLO, in ms: its latency
+// objective, or ApdexDefaultThreshold.
+func (s SLO) ApdexThreshold() float64 {
+	if s.Latency > 0 {
+		return s.Latency
+	}
+	return ApdexDefaultThreshold
+}
+
+// Covers reports whether the entry is in the SLO's scope.
+func (s SLO) Covers(entry Entry) bool {
+	switch {
+	case s.Scope == "all":
+		return true
+	case s.Scope == "api":
+		return IsAPIResponse(entry)
+	case strings.HasPrefix(s.Scope, "type:"):
+		return ResourceType(entry.Response.Content.MimeType) == strings.TrimPrefix(s.Scope, "type:")
+	case strings.HasPrefix(s.Scope, "tag:"):
+		return entry.HasTag(strings.TrimPrefix(s.Scope, "tag:"))
+	}
+	return hostWithin(EntryHost(entry), s.Scope)
+}
+
+// SLOObjective is one objective of an SLO checked against a capture.
+type SLOObjective struct {
+	Name   string `json:"name"`   // "latency", "errors" or "apdex"
+	Actual string `json:"actual"` // e.g. "97.5% ≤ 300ms"
+	Target string `json:"target"` // e.g. "95.0%"
+	Pass   bool   `json:"pass"`
+}
+
+// SLOResult is how the requests of one capture did against an SLO.
+type SLOResult struct {
+	SLO           SLO            `json:"slo"`
+	Requests      int            `json:"requests"`
+	WithinLatency int            `json:"within_latency"` // requests no slower than the latency objective
+	Errors        int            `json:"errors"`
+	Satisfied     int            `json:"satisfied"`  // successful within the Apdex threshold T
+	Tolerating    int            `json:"tolerating"` // successful within 4T
+	Apdex         float64        `json:"apdex"`      // see apdexScore
+	Objectives    []SLOObjective `json:"objectives"` // in the order latency, errors, apdex
+}
+
+// Compliance is the percentage of requests within the latency objective.
+func (r SLOResult) Compliance() float64 {
+	if r.Requests == 0 {
+		return 100
+	}
+	return float64(r.WithinLatency) / float64(r.Requests) * 100
+}
+
+// ErrorRate is the percentage of requests that failed.
+func (r SLOResult) ErrorRate() float64 {
+	if r.Requests == 0 {
+		return 0
+	}
+	return float64(r.Errors) / float64(r.Requests) * 100
+}
+
+// apdexScore is (satisfied + tolerating/2) / requests; failed requests count
+// as frustrated. A scope without requests scores 1.
+func (r SLOResult) apdexScore() float64 {
+	if r.Requests == 0 {
+		return 1
+	}
+	return (float64(r.Satisfied) + float64(r.Tolerating)/2) / float64(r.Requests)
+}
+
+// objectives checks each objective the SLO sets.
+func (r SLOResult) objectives() []SLOObjective {
+	var objectives []SLOObjective
+	if r.SLO.Latency > 0 {
+		objectives = append(objectives, SLOObjective{
+			Name:   "latency",
+			Actual: fmt.Sprintf("%.1f%% ≤ %.0fms", r.Compliance(), r.SLO.Latency),
+			Target: fmt.Sprintf("%.1f%%", r.SLO.Target),
+			Pass:   r.Compliance() >= r.SLO.Target,
+		})
+	}
+	if r.SLO.Errors >= 0 {
+		objectives = append(objectives, SLOObjective{
+			Name:   "errors",
+			Actual: fmt.Sprintf("%.1f%%", r.ErrorRate()),
+			Target: fmt.Sprintf("%.1f%%", r.SLO.Errors),
+			Pass:   r.ErrorRate() <= r.SLO.Errors,
+		})
+	}
+	if r.SLO.Apdex > 0 {
+		objectives = append(objectives, SLOObjective{
+			Name:   "apdex",
+			Actual: fmt.Sprintf("%.2f", r.Apdex),
+			Target: fmt.Sprintf("%.2f", r.SLO.Apdex),
+			Pass:   r.Apdex >= r.SLO.Apdex,
+		})
+	}
+	return objectives
+}
+
+// Pass reports whether every objective was met.
+func (r SLOResult) Pass() bool {
+	for _, objective := range r.Objectives {
+		if !objective.Pass {
+			return false
+		}
+	}
+	return true
+}
+
+// EvaluateSLOs checks the capture's requests against each SLO, in SLO order.
+// Requests that failed (status 400 and above) count as errors and as
+// frustrated for Apdex.
+func (a *Analyzer) EvaluateSLOs(slos []SLO) []SLOResult {
+	results := make([]SLOResult, len(slos))
+	for i, slo := range slos {
+		results[i].SLO = slo
+		threshold := slo.ApdexThreshold()
+		for _, entry := range a.har.Log.Entries {
+			if !slo.Covers(entry) {
+				continue
+			}
+			results[i].Requests++
+			if slo.Latency == 0 || entry.Time <= slo.Latency {
+				results[i].WithinLatency++
+			}
+			if entry.Response.Status >= 400 {
+				results[i].Errors++
+				continue
+			}
+			switch {
+			case entry.Time <= threshold:
+				results[i].Satisfied++
+			case entry.Time <= 4*threshold:
+				results[i].Tolerating++
+			}
+		}
+		results[i].Apdex = results[i].apdexScore()
+		results[i].Objectives = results[i].objectives()
+	}
+	return results
+}
@@ -0,0 +1,86 @@
+package har
+
+import (
+	"fmt"
+	"sort"
+)
+
+// StatusClasses are the response status classes in display order. "other"
+// holds requests without a valid status, e.g. blocked or aborted ones.
+var StatusClasses = []string{"1xx", "2xx", "3xx", "4xx", "5xx", "other"}
+
+// StatusClass returns the class of a response status, e.g. "4xx".
+func StatusClass(status int) string {
+	if status < 100 || status > 599 {
+		return "other"
+	}
+	return fmt.Sprintf("%dxx", status/100)
+}
+
+// StatusCounts counts requests by status class and code.
+type StatusCounts struct {
+	Requests int
+	ByClass  map[string]int
+	ByCode   map[int]int
+}
+
+// Codes returns the codes seen in a class, in ascending order.
+func (c StatusCounts) Codes(class string) []int {
+	var codes []int
+	for code := range c.ByCode {
+		if StatusClass(code) == class {
+			codes = append(codes, code)
+		}
+	}
+	sort.Ints(codes)
+	return codes
+}
+
+func (c *StatusCounts) add(status int) {
+	if c.ByClass == nil {
+		c.ByClass, c.ByCode = make(map[string]int), make(map[int]int)
+	}
+	c.Requests++
+	c.ByClass[StatusClass(status)]++
+	c.ByCode[status]++
+}
+
+// DomainStatus is the status breakdown of one host.
+type DomainStatus struct {
+	Domain string
+	StatusCounts
+}
+
+// StatusDistribution is the status breakdown of a capture, overall and by
+// host.
+type StatusDistribution struct {
+	StatusCounts
+	Domains []DomainStatus // most requests first
+}
+
+// StatusDistribution counts the capture's responses by status class and code.
+func (a *Analyzer) StatusDistribution() StatusDistribution {
+	var distribution StatusDistribution
+	domains := make(map[string]*DomainStatus)
+	var order []string
+	for _, entry := range a.har.Log.Entries {
+		distribution.add(entry.Response.Status)
+
+		host := EntryHost(entry)
+		domain, ok := domains[host]
+		if !ok {
+			domain = &DomainStatus{Domain: host}
+			domains[host] = domain
+			order = append(order, host)
+		}
+		domain.add(entry.Response.Status)
+	}
+
+	for _, host := range order {
+		distribution.Domains = append(distribution.Domains, *domains[host])
+	}
+	sort.SliceStable(distribution.Domains, func(i, j int) bool {
+		return distribution.Domains[i].Requests > distribution.Domains[j].Requests
+	})
+	return distribution
+}
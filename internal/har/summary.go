@@ -0,0 +1,167 @@
+package har
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// ExecutiveSummary is a short, template-based account of one capture: its
+// grade, its worst issues and its biggest change against the baseline.
+type ExecutiveSummary struct {
+	Grade      string          `json:"grade"` // A to F, or NotAvailable for a capture without entries
+	Score      float64         `json:"score"` // 0 to 100, see ScorePerformance
+	Categories []ScoreCategory `json:"categories,omitempty"`
+	Issues     []string        `json:"issues,omitempty"`
+	Change     *Insight        `json:"change,omitempty"`
+	Text       string          `json:"text"`
+}
+
+// SummaryIssueLimit is how many issues a summary names.
+const SummaryIssueLimit = 3
+
+// summaryIssue is a problem found in the metrics and how much it matters.
+type summaryIssue struct {
+	message string
+	penalty float64
+}
+
+// summaryIssues checks the metrics against the thresholds the metrics view
+// and reports recommend on, worst first.
+func summaryIssues(m *Metrics, units SizeUnits) []summaryIssue {
+	var issues []summaryIssue
+	add := func(penalty float64, format string, args ...interface{}) {
+		issues = append(issues, summaryIssue{message: fmt.Sprintf(format, args...), penalty: penalty})
+	}
+
+	if m.ErrorRequests > 0 {
+		rate := float64(m.ErrorRequests) / float64(m.TotalRequests) * 100
+		add(10+math.Min(rate, 20), "%d of %d requests failed", m.ErrorRequests, m.TotalRequests)
+	}
+	switch {
+	case m.PageLoadTime > 3000:
+		add(20, "the page takes %.1fs to load", m.PageLoadTime/1000)
+	case m.PageLoadTime > 1500:
+		add(10, "the page takes %.1fs to load", m.PageLoadTime/1000)
+	}
+	switch {
+	case m.TTFB > 800:
+		add(15, "the server is slow to respond (%.0fms to first byte)", m.TTFB)
+	case m.TTFB > 200:
+		add(5, "time to first byte is %.0fms", m.TTFB)
+	}
+	if m.Environment.ChecksTransport() && m.InsecureRequests > 0 {
+		add(10, "%d requests are sent over plain HTTP", m.InsecureRequests)
+	}
+	if m.ThirdPartyRequests > m.TotalRequests/2 {
+		add(5, "%d of %d requests go to third parties", m.ThirdPartyRequests, m.TotalRequests)
+	}
+	if m.RedirectLatency > 0 {
+		add(3, "redirects add %.0fms", m.RedirectLatency)
+	}
+
+	if m.Environment.ChecksDelivery() {
+		switch {
+		case m.CacheHitRatio < 30:
+			add(10, "only %.0f%% of responses can be reused from cache", m.CacheHitRatio)
+		case m.CacheHitRatio < 50:
+			add(5, "only %.0f%% of responses can be reused from cache", m.CacheHitRatio)
+		}
+		if m.TotalSize > 1024*1024*5 {
+			add(10, "the page transfers %s", units.FormatSize(int(m.TotalSize)))
+		}
+		if m.CDNResponses > 0 && m.CDNHitRatio < 50 {
+			add(5, "the CDN serves only %.0f%% of cacheable responses from the edge", m.CDNHitRatio)
+		}
+		if m.ConnectionSetupWaste > 0 {
+			add(3, "repeated connection setup wastes %.0fms", m.ConnectionSetupWaste)
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool { return issues[i].penalty > issues[j].penalty })
+	return issues
+}
+
+// Grade maps a 0-100 score to a letter grade.
+func Grade(score float64) string {
+	switch {
+	case score >= 90:
+		return "A"
+	case score >= 80:
+		return "B"
+	case score >= 70:
+		return "C"
+	case score >= 60:
+		return "D"
+	}
+	return "F"
+}
+
+// Summarize writes the executive summary of the capture at index. When the
+// comparison is not nil and index is not the baseline, it names the metric
+// that changed most against the baseline, relative to the baseline value.
+// Sizes are written in units.
+func Summarize(metrics *Metrics, comparison *Comparison, index int, units SizeUnits) ExecutiveSummary {
+	if !metrics.HasData() {
+		return ExecutiveSummary{Grade: NotAvailable, Text: "The capture has no entries, so it cannot be graded."}
+	}
+
+	issues := summaryIssues(metrics, units)
+	score := ScorePerformance(metrics)
+	summary := ExecutiveSummary{Score: score.Score, Grade: score.Grade, Categories: score.Categories}
+	for i := 0; i < len(issues) && i < SummaryIssueLimit; i++ {
+		summary.Issues = append(summary.Issues, issues[i].message)
+	}
+	summary.Change = biggestChange(comparison, index)
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "Grade %s (%.0f/100): %d requests transferring %s", summary.Grade, summary.Score, metrics.TotalRequests, units.FormatSize(int(metrics.TotalSize)))
+	if metrics.PageLoadTime > 0 {
+		fmt.Fprintf(&text, ", loaded in %.1fs", metrics.PageLoadTime/1000)
+	}
+	text.WriteString(". ")
+	switch len(summary.Issues) {
+	case 0:
+		text.WriteString("No major issues were found.")
+	case 1:
+		fmt.Fprintf(&text, "The main issue is that %s.", summary.Issues[0])
+	default:
+		fmt.Fprintf(&text, "The top issues are that %s and %s.", strings.Join(summary.Issues[:len(summary.Issues)-1], ", "), summary.Issues[len(summary.Issues)-1])
+	}
+	if summary.Change != nil {
+		// Insight messages may add advice after a dash, e.g. "... regressed - investigate"
+		change, _, _ := strings.Cut(summary.Change.Message, " - ")
+		fmt.Fprintf(&text, " The biggest change against the baseline: %s (%s).", change, summary.Change.Evidence)
+	}
+	summary.Text = text.String()
+	return summary
+}
+
+// biggestChange returns the insight about the capture at index whose metric
+// moved most relative to its baseline value, or nil when there is none.
+func biggestChange(comparison *Comparison, index int) *Insight {
+	if comparison == nil || index <= 0 || index >= len(comparison.Files) {
+		return nil
+	}
+	var biggest *Insight
+	largest := 0.0
+	for i, insight := range comparison.Insights {
+		if insight.File != comparison.Files[index] || insight.Metric == "" || insight.Delta == 0 {
+			continue
+		}
+		descriptor, ok := LookupMetric(insight.Metric)
+		if !ok {
+			continue
+		}
+		// A metric that was zero in the baseline changed by its whole value
+		relative := 1.0
+		if base := descriptor.Value(comparison.Metrics[0]); base != 0 {
+			relative = math.Abs(insight.Delta / base)
+		}
+		if relative > largest {
+			biggest, largest = &comparison.Insights[i], relative
+		}
+	}
+	return biggest
+}
@@ -0,0 +1,149 @@
+package har
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// TagRule assigns a tag to every entry whose field matches, e.g.
+// "tag:auth when url contains /oauth/".
+type TagRule struct {
+	Tag      string
+	Field    string
+	Operator string
+	Value    string
+	pattern  *regexp.Regexp
+}
+
+var tagRuleFields = []string{"url", "host", "method", "status", "type"}
+
+var tagRuleOperators = []string{"contains", "is", "startswith", "endswith", "matches"}
+
+// ParseTagRule parses "[tag:]<name> when <field> <operator> <value>". Fields are
+// url, host, method, status and type (MIME type); operators are contains, is,
+// startswith, endswith and matches (regular expression).
+func ParseTagRule(rule string) (TagRule, error) {
+	head, condition, ok := strings.Cut(rule, " when ")
+	if !ok {
+		return TagRule{}, fmt.Errorf("invalid tag rule %q: expected \"<tag> when <field> <operator> <value>\"", rule)
+	}
+
+	tag := strings.TrimPrefix(strings.TrimSpace(head), "tag:")
+	if tag == "" || strings.ContainsAny(tag, " ,") {
+		return TagRule{}, fmt.Errorf("invalid tag rule %q: tag names cannot be empty or contain spaces or commas", rule)
+	}
+
+	parts := strings.SplitN(strings.TrimSpace(condition), " ", 3)
+	if len(parts) != 3 {
+		return TagRule{}, fmt.Errorf("invalid tag rule %q: expected \"<field> <operator> <value>\" after \"when\"", rule)
+	}
+
+	parsed := TagRule{
+		Tag:      tag,
+		Field:    strings.ToLower(parts[0]),
+		Operator: strings.ToLower(parts[1]),
+		Value:    strings.TrimSpace(parts[2]),
+	}
+	if !containsString(tagRuleFields, parsed.Field) {
+		return TagRule{}, fmt.Errorf("invalid tag rule %q: unknown field %q (use %s)", rule, parsed.Field, strings.Join(tagRuleFields, ", "))
+	}
+	if !containsString(tagRuleOperators, parsed.Operator) {
+		return TagRule{}, fmt.Errorf("invalid tag rule %q: unknown operator %q (use %s)", rule, parsed.Operator, strings.Join(tagRuleOperators, ", "))
+	}
+	if parsed.Operator == "matches" {
+		pattern, err := regexp.Compile(parsed.Value)
+		if err != nil {
+			return TagRule{}, fmt.Errorf("invalid tag rule %q: %w", rule, err)
+		}
+		parsed.pattern = pattern
+	}
+
+	return parsed, nil
+}
+
+func (r TagRule) Matches(entry Entry) bool {
+	var value string
+	switch r.Field {
+	case "url":
+		value = entry.Request.URL
+	case "host":
+		value = EntryHost(entry)
+	case "method":
+		value = entry.Request.Method
+	case "status":
+		value = strconv.Itoa(entry.Response.Status)
+	case "type":
+		value = entry.Response.Content.MimeType
+	}
+
+	switch r.Operator {
+	case "contains":
+		return strings.Contains(strings.ToLower(value), strings.ToLower(r.Value))
+	case "is":
+		return strings.EqualFold(value, r.Value)
+	case "startswith":
+		return strings.HasPrefix(strings.ToLower(value), strings.ToLower(r.Value))
+	case "endswith":
+		return strings.HasSuffix(strings.ToLower(value), strings.ToLower(r.Value))
+	case "matches":
+		return r.pattern != nil && r.pattern.MatchString(value)
+	}
+	return false
+}
+
+// ApplyTagRules tags every matching entry of the HAR file.
+func ApplyTagRules(har *HAR, rules []TagRule) {
+	for i := range har.Log.Entries {
+		for _, rule := range rules {
+			if rule.Matches(har.Log.Entries[i]) {
+				har.Log.Entries[i].AddTag(rule.Tag)
+			}
+		}
+	}
+}
+
+func (e *Entry) AddTag(tag string) {
+	if !e.HasTag(tag) {
+		// Copy so entries sharing the slice (e.g. filtered views) are unaffected
+		tags := append(append([]string{}, e.Tags...), tag)
+		sort.Strings(tags)
+		e.Tags = tags
+	}
+}
+
+func (e *Entry) RemoveTag(tag string) {
+	var kept []string
+	for _, existing := range e.Tags {
+		if existing != tag {
+			kept = append(kept, existing)
+		}
+	}
+	e.Tags = kept
+}
+
+func (e Entry) HasTag(tag string) bool {
+	return containsString(e.Tags, tag)
+}
+
+// GetEntriesByTag groups entries by tag; untagged entries are left out.
+func (a *Analyzer) GetEntriesByTag() map[string][]Entry {
+	tagged := make(map[string][]Entry)
+	for _, entry := range a.har.Log.Entries {
+		for _, tag := range entry.Tags {
+			tagged[tag] = append(tagged[tag], entry)
+		}
+	}
+	return tagged
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,123 @@
+package har
+
+import (
+	"sort"
+	"time"
+)
+
+// LongReceive is how long a response must take to download to count as a
+// long-tail download, such as a streamed or very large response.
+const LongReceive = time.Second
+
+// ThroughputProfile is the bytes received over a capture, split into equal
+// time slices.
+type ThroughputProfile struct {
+	Start time.Time
+	Slice time.Duration
+	Bytes []int64 // received during each slice
+	Busy  []bool  // whether any request was in flight during each slice
+
+	Total     int64
+	Peak      int64 // most bytes received in one slice
+	PeakAt    time.Duration
+	StallTime time.Duration // slices with requests in flight but nothing received
+	LongTail  []int         // entries downloading for LongReceive or more, longest first
+}
+
+// Rate returns the bytes per second received during a slice.
+func (p ThroughputProfile) Rate(slice int) float64 {
+	return float64(p.Bytes[slice]) / p.Slice.Seconds()
+}
+
+// Throughput spreads each response's transfer size evenly over its receive
+// phase and sums the bytes per slice. Responses without a receive time are
+// counted when they complete.
+func (a *Analyzer) Throughput(slices int) ThroughputProfile {
+	entries := a.har.Log.Entries
+	if len(entries) == 0 || slices <= 0 {
+		return ThroughputProfile{}
+	}
+
+	start, end := entries[0].StartedDateTime, entryEnd(entries[0])
+	for _, entry := range entries {
+		if entry.StartedDateTime.Before(start) {
+			start = entry.StartedDateTime
+		}
+		if entryEnd(entry).After(end) {
+			end = entryEnd(entry)
+		}
+	}
+
+	total := end.Sub(start)
+	slice := max((total+time.Duration(slices)-1)/time.Duration(slices), time.Millisecond)
+	profile := ThroughputProfile{Start: start, Slice: slice, Bytes: make([]int64, slices), Busy: make([]bool, slices)}
+	index := func(at time.Time) int {
+		return min(int(at.Sub(start)/slice), slices-1)
+	}
+
+	received := make([]float64, slices)
+	for i, entry := range entries {
+		finished := entryEnd(entry)
+		for s := index(entry.StartedDateTime); s <= index(finished); s++ {
+			profile.Busy[s] = true
+		}
+
+		size := float64(TransferSize(entry))
+		receive := time.Duration(entry.Timings.Receive) * time.Millisecond
+		if receive >= LongReceive {
+			profile.LongTail = append(profile.LongTail, i)
+		}
+		if receive <= 0 {
+			received[index(finished)] += size
+			continue
+		}
+
+		receiving := finished.Add(-receive)
+		for s := index(receiving); s <= index(finished); s++ {
+			sliceStart := start.Add(slice * time.Duration(s))
+			overlap := minTime(finished, sliceStart.Add(slice)).Sub(maxTime(receiving, sliceStart))
+			if overlap > 0 {
+				received[s] += size * float64(overlap) / float64(receive)
+			}
+		}
+	}
+
+	for s, bytes := range received {
+		profile.Bytes[s] = int64(bytes)
+		profile.Total += profile.Bytes[s]
+		if profile.Bytes[s] > profile.Peak {
+			profile.Peak, profile.PeakAt = profile.Bytes[s], slice*time.Duration(s)
+		}
+		if profile.Busy[s] && profile.Bytes[s] == 0 {
+			profile.StallTime += slice
+		}
+	}
+
+	sort.SliceStable(profile.LongTail, func(i, j int) bool {
+		return entries[profile.LongTail[i]].Timings.Receive > entries[profile.LongTail[j]].Timings.Receive
+	})
+	return profile
+}
+
+// TransferSize returns the bytes of the response body on the wire, falling
+// back to the decoded content size when the capture did not record it.
+func TransferSize(entry Entry) int {
+	if entry.Response.BodySize > 0 {
+		return entry.Response.BodySize
+	}
+	return max(entry.Response.Content.Size, 0)
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,74 @@
+package har
+
+import (
+	"sort"
+	"time"
+)
+
+// TrendPoint is a metric's value in one capture.
+type TrendPoint struct {
+	File  string    `json:"file"`
+	Time  time.Time `json:"time"`
+	Value float64   `json:"value"`
+}
+
+// Trend follows one metric across captures in chronological order.
+type Trend struct {
+	ID     string       `json:"id"`
+	Name   string       `json:"name"`
+	Unit   string       `json:"unit,omitempty"`
+	Points []TrendPoint `json:"points"`
+}
+
+// Values returns the metric's values, oldest first.
+func (t Trend) Values() []float64 {
+	values := make([]float64, len(t.Points))
+	for i, point := range t.Points {
+		values[i] = point.Value
+	}
+	return values
+}
+
+// ChronologicalOrder returns the indices of the captures sorted by when each
+// page started loading, keeping the given order for ties.
+func ChronologicalOrder(analyzers []*Analyzer) []int {
+	order := make([]int, len(analyzers))
+	starts := make([]time.Time, len(analyzers))
+	for i, analyzer := range analyzers {
+		order[i], starts[i] = i, analyzer.PageStart()
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return starts[order[i]].Before(starts[order[j]])
+	})
+	return order
+}
+
+// Trends follows every registered metric across the captures, e.g. nightly
+// runs, in chronological order. Captures that did not measure a metric are
+// skipped in its trend, and metrics no capture measured are left out.
+func Trends(files []string, analyzers []*Analyzer) []Trend {
+	order := ChronologicalOrder(analyzers)
+	metrics := make([]*Metrics, len(analyzers))
+	for i, analyzer := range analyzers {
+		metrics[i] = analyzer.CalculateMetrics()
+	}
+
+	var trends []Trend
+	for _, descriptor := range metricRegistry {
+		trend := Trend{ID: descriptor.ID, Name: descriptor.Name, Unit: descriptor.Unit}
+		for _, index := range order {
+			if !descriptor.Measured(metrics[index]) {
+				continue
+			}
+			trend.Points = append(trend.Points, TrendPoint{
+				File:  files[index],
+				Time:  analyzers[index].PageStart(),
+				Value: descriptor.Value(metrics[index]),
+			})
+		}
+		if len(trend.Points) > 0 {
+			trends = append(trends, trend)
+		}
+	}
+	return trends
+}
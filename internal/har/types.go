@@ -13,6 +13,12 @@ type Log struct {
 	Pages   []Page  `json:"pages,omitempty"`
 	Entries []Entry `json:"entries"`
 	Comment string  `json:"comment,omitempty"`
+
+	// Excluded holds entries left out of analysis, see ExcludeEntries.
+	Excluded []Entry `json:"-"`
+	// Hidden holds entries left out of analysis for the session, see
+	// HideEntries.
+	Hidden []Entry `json:"-"`
 }
 
 type Creator struct {
@@ -33,12 +39,31 @@ type Page struct {
 	Title           string      `json:"title"`
 	PageTimings     PageTimings `json:"pageTimings"`
 	Comment         string      `json:"comment,omitempty"`
+
+	// Synthetic marks a page derived from the entries of a capture without
+	// pages, see Log.PrimaryPage.
+	Synthetic bool `json:"-"`
+
+	// Visual and lab metrics as exported by WebPageTest or merged from Lighthouse
+	FirstContentfulPaint   float64  `json:"_firstContentfulPaint,omitempty"`
+	LargestContentfulPaint float64  `json:"_largestContentfulPaint,omitempty"`
+	CumulativeLayoutShift  *float64 `json:"_cumulativeLayoutShift,omitempty"`
+	TotalBlockingTime      float64  `json:"_totalBlockingTime,omitempty"`
+	SpeedIndex             float64  `json:"_SpeedIndex,omitempty"`
+	VisualComplete         float64  `json:"_visualComplete,omitempty"`
 }
 
 type PageTimings struct {
 	OnContentLoad int    `json:"onContentLoad,omitempty"`
 	OnLoad        int    `json:"onLoad,omitempty"`
 	Comment       string `json:"comment,omitempty"`
+
+	// Custom timings some Chrome extensions and test runners add, used when
+	// the page itself carries no visual metrics
+	FirstContentfulPaint   float64  `json:"_firstContentfulPaint,omitempty"`
+	LargestContentfulPaint float64  `json:"_largestContentfulPaint,omitempty"`
+	CumulativeLayoutShift  *float64 `json:"_cumulativeLayoutShift,omitempty"`
+	TotalBlockingTime      float64  `json:"_totalBlockingTime,omitempty"`
 }
 
 type Entry struct {
@@ -52,6 +77,35 @@ type Entry struct {
 	ServerIPAddress string    `json:"serverIPAddress,omitempty"`
 	Connection      string    `json:"connection,omitempty"`
 	Comment         string    `json:"comment,omitempty"`
+	Tags            []string  `json:"_tags,omitempty"`
+
+	// Initiator is Chrome's record of what started the request.
+	Initiator *Initiator `json:"_initiator,omitempty"`
+	// ChromeResourceType is Chrome's resource type, e.g. "script", "xhr" or
+	// "document".
+	ChromeResourceType string `json:"_resourceType,omitempty"`
+}
+
+// Initiator names the document or script that started a request: a parser
+// initiator has a URL, a script initiator has a stack whose frames name the
+// scripts.
+type Initiator struct {
+	Type       string          `json:"type"`
+	URL        string          `json:"url,omitempty"`
+	LineNumber int             `json:"lineNumber,omitempty"`
+	Stack      *InitiatorStack `json:"stack,omitempty"`
+}
+
+type InitiatorStack struct {
+	CallFrames []CallFrame     `json:"callFrames"`
+	Parent     *InitiatorStack `json:"parent,omitempty"`
+}
+
+type CallFrame struct {
+	FunctionName string `json:"functionName"`
+	URL          string `json:"url"`
+	LineNumber   int    `json:"lineNumber"`
+	ColumnNumber int    `json:"columnNumber"`
 }
 
 type Request struct {
@@ -88,6 +142,7 @@ type Cookie struct {
 	Expires  time.Time `json:"expires,omitempty"`
 	HTTPOnly bool      `json:"httpOnly,omitempty"`
 	Secure   bool      `json:"secure,omitempty"`
+	SameSite string    `json:"sameSite,omitempty"`
 	Comment  string    `json:"comment,omitempty"`
 }
 
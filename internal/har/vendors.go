@@ -0,0 +1,166 @@
+package har
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// VendorBudget limits the requests and bytes of one third-party vendor,
+// identified by the domains it serves from.
+type VendorBudget struct {
+	Vendor      string
+	Domains     []string // the vendor's domains, each including its subdomains
+	MaxRequests int      // 0 means no request limit
+	MaxBytes    int64    // 0 means no size limit
+}
+
+// ParseVendorBudget parses a budget such as
+// "analytics=google-analytics.com,googletagmanager.com requests=10 bytes=150KB".
+// The vendor name is optional; "doubleclick.net requests=5" names the vendor
+// after its domain.
+func ParseVendorBudget(spec string) (VendorBudget, error) {
+	fields := strings.Fields(spec)
+	if len(fields) < 2 {
+		return VendorBudget{}, fmt.Errorf("invalid vendor budget %q: expected \"[vendor=]domain[,domain...] requests=N bytes=SIZE\"", spec)
+	}
+
+	var budget VendorBudget
+	domains := fields[0]
+	if vendor, list, ok := strings.Cut(domains, "="); ok {
+		budget.Vendor, domains = vendor, list
+	}
+	for _, domain := range strings.Split(domains, ",") {
+		if domain = strings.TrimSpace(domain); domain != "" {
+			budget.Domains = append(budget.Domains, domain)
+		}
+	}
+	if len(budget.Domains) == 0 {
+		return VendorBudget{}, fmt.Errorf("invalid vendor budget %q: no domains", spec)
+	}
+	if budget.Vendor == "" {
+		budget.Vendor = budget.Domains[0]
+	}
+
+	for _, limit := range fields[1:] {
+		name, value, _ := strings.Cut(limit, "=")
+		switch name {
+		case "requests":
+			requests, err := strconv.Atoi(value)
+			if err != nil || requests <= 0 {
+				return VendorBudget{}, fmt.Errorf("invalid request limit %q in vendor budget %q", value, spec)
+			}
+			budget.MaxRequests = requests
+		case "bytes":
+			size, err := ParseSize(value)
+			if err != nil || size <= 0 {
+				return VendorBudget{}, fmt.Errorf("invalid size limit %q in vendor budget %q", value, spec)
+			}
+			budget.MaxBytes = size
+		default:
+			return VendorBudget{}, fmt.Errorf("unknown limit %q in vendor budget %q (want requests= or bytes=)", limit, spec)
+		}
+	}
+	return budget, nil
+}
+
+// ParseSize parses a size such as "150KB", "150KiB", "1.5MB" or "2048".
+// Units are binary whichever SizeUnits are displayed, so budgets keep their
+// meaning.
+func ParseSize(value string) (int64, error) {
+	upper := strings.ToUpper(strings.TrimSpace(value))
+	multiplier := 1.0
+	for _, unit := range []struct {
+		suffix string
+		factor float64
+	}{{"GIB", 1 << 30}, {"MIB", 1 << 20}, {"KIB", 1 << 10}, {"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"B", 1}} {
+		if strings.HasSuffix(upper, unit.suffix) {
+			upper, multiplier = strings.TrimSuffix(upper, unit.suffix), unit.factor
+			break
+		}
+	}
+	number, err := strconv.ParseFloat(strings.TrimSpace(upper), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size %q", value)
+	}
+	return int64(number * multiplier), nil
+}
+
+// Matches reports whether the entry was served from one of the vendor's domains.
+func (b VendorBudget) Matches(entry Entry) bool {
+	host := EntryHost(entry)
+	for _, domain := range b.Domains {
+		if hostWithin(host, domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// Limits describes the budget, e.g. "10 req, 150.0KiB", with sizes in units.
+func (b VendorBudget) Limits(units SizeUnits) string {
+	var parts []string
+	if b.MaxRequests > 0 {
+		parts = append(parts, fmt.Sprintf("%d req", b.MaxRequests))
+	}
+	if b.MaxBytes > 0 {
+		parts = append(parts, units.FormatSize(int(b.MaxBytes)))
+	}
+	if len(parts) == 0 {
+		return "no limit"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// VendorScorecard is a vendor's usage in one capture against its budget.
+type VendorScorecard struct {
+	Budget   VendorBudget
+	Requests int
+	Bytes    int64
+	Time     float64 // ms, summed over the vendor's requests
+}
+
+// OverRequests reports whether the vendor made more requests than allowed.
+func (s VendorScorecard) OverRequests() bool {
+	return s.Budget.MaxRequests > 0 && s.Requests > s.Budget.MaxRequests
+}
+
+// OverBytes reports whether the vendor transferred more than allowed.
+func (s VendorScorecard) OverBytes() bool {
+	return s.Budget.MaxBytes > 0 && s.Bytes > s.Budget.MaxBytes
+}
+
+// Usage describes what the vendor used, for the limits its budget sets, with
+// sizes in units.
+func (s VendorScorecard) Usage(units SizeUnits) string {
+	var parts []string
+	if s.Budget.MaxRequests > 0 || s.Budget.MaxBytes == 0 {
+		parts = append(parts, fmt.Sprintf("%d req", s.Requests))
+	}
+	if s.Budget.MaxBytes > 0 {
+		parts = append(parts, units.FormatSize(int(s.Bytes)))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Pass reports whether the vendor stayed within every limit.
+func (s VendorScorecard) Pass() bool {
+	return !s.OverRequests() && !s.OverBytes()
+}
+
+// VendorScorecards measures each budgeted vendor in the capture, in budget order.
+func (a *Analyzer) VendorScorecards(budgets []VendorBudget) []VendorScorecard {
+	scorecards := make([]VendorScorecard, len(budgets))
+	for i, budget := range budgets {
+		scorecards[i].Budget = budget
+		for _, entry := range a.har.Log.Entries {
+			if !budget.Matches(entry) {
+				continue
+			}
+			scorecards[i].Requests++
+			scorecards[i].Bytes += int64(entry.Response.Content.Size)
+			scorecards[i].Time += entry.Time
+		}
+	}
+	return scorecards
+}
@@ -0,0 +1,265 @@
+package har
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// wptNumber accepts the numbers WebPageTest emits either as JSON numbers or
+// as strings, treating empty strings as zero.
+type wptNumber float64
+
+func (n *wptNumber) UnmarshalJSON(data []byte) error {
+	text := strings.Trim(string(data), `"`)
+	if text == "" || text == "null" {
+		*n = 0
+		return nil
+	}
+	value, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return fmt.Errorf("invalid WebPageTest number %s: %w", data, err)
+	}
+	*n = wptNumber(value)
+	return nil
+}
+
+// ms converts a WebPageTest duration to HAR timing milliseconds, mapping the
+// -1 "not applicable" marker to zero.
+func (n wptNumber) ms() int {
+	if n < 0 {
+		return 0
+	}
+	return int(n)
+}
+
+type wptResult struct {
+	ID        string             `json:"id"`
+	TestURL   string             `json:"testUrl"`
+	Completed wptNumber          `json:"completed"`
+	Runs      map[string]wptRun  `json:"runs"`
+	Median    map[string]wptView `json:"median"`
+}
+
+type wptRun struct {
+	FirstView *wptView `json:"firstView"`
+}
+
+type wptView struct {
+	Run                        wptNumber    `json:"run"`
+	URL                        string       `json:"URL"`
+	Date                       wptNumber    `json:"date"`
+	BrowserName                string       `json:"browser_name"`
+	BrowserVersion             string       `json:"browser_version"`
+	LoadTime                   wptNumber    `json:"loadTime"`
+	DOMContentLoadedEventStart wptNumber    `json:"domContentLoadedEventStart"`
+	FirstContentfulPaint       wptNumber    `json:"firstContentfulPaint"`
+	LargestContentfulPaint     wptNumber    `json:"chromeUserTiming.LargestContentfulPaint"`
+	CumulativeLayoutShift      *wptNumber   `json:"chromeUserTiming.CumulativeLayoutShift"`
+	TotalBlockingTime          wptNumber    `json:"TotalBlockingTime"`
+	SpeedIndex                 wptNumber    `json:"SpeedIndex"`
+	VisualComplete             wptNumber    `json:"visualComplete"`
+	Requests                   []wptRequest `json:"requests"`
+}
+
+type wptRequest struct {
+	FullURL                string    `json:"full_url"`
+	Method                 string    `json:"method"`
+	Protocol               string    `json:"protocol"`
+	ResponseCode           wptNumber `json:"responseCode"`
+	ContentType            string    `json:"contentType"`
+	LoadStart              wptNumber `json:"load_start"`
+	AllMs                  wptNumber `json:"all_ms"`
+	DNSMs                  wptNumber `json:"dns_ms"`
+	ConnectMs              wptNumber `json:"connect_ms"`
+	SSLMs                  wptNumber `json:"ssl_ms"`
+	TTFBMs                 wptNumber `json:"ttfb_ms"`
+	DownloadMs             wptNumber `json:"download_ms"`
+	ObjectSize             wptNumber `json:"objectSize"`
+	ObjectSizeUncompressed wptNumber `json:"objectSizeUncompressed"`
+	Cached                 wptNumber `json:"cached"`
+	IP                     string    `json:"ip_addr"`
+	Headers                struct {
+		Request  []string `json:"request"`
+		Response []string `json:"response"`
+	} `json:"headers"`
+}
+
+// isWebPageTest reports whether a JSON document looks like a WebPageTest
+// result rather than a HAR file.
+func isWebPageTest(document json.RawMessage) bool {
+	var probe struct {
+		Log  json.RawMessage `json:"log"`
+		Data json.RawMessage `json:"data"`
+		Runs json.RawMessage `json:"runs"`
+	}
+	if err := json.Unmarshal(document, &probe); err != nil {
+		return false
+	}
+	return probe.Log == nil && (probe.Data != nil || probe.Runs != nil)
+}
+
+// ParseWebPageTest converts a WebPageTest JSON result into the HAR model.
+// The median first view is imported when it carries request data, otherwise
+// the median run (or the first run) is used. Visual metrics are kept on the page.
+func ParseWebPageTest(document []byte) (*HAR, error) {
+	// API responses wrap the result in {"statusCode": 200, "data": {...}}
+	var envelope struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(document, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to decode WebPageTest JSON: %w", err)
+	}
+	if envelope.Data != nil {
+		document = envelope.Data
+	}
+
+	var result wptResult
+	if err := json.Unmarshal(document, &result); err != nil {
+		return nil, fmt.Errorf("failed to decode WebPageTest JSON: %w", err)
+	}
+
+	view := result.selectView()
+	if view == nil {
+		return nil, fmt.Errorf("WebPageTest result %s contains no first view data", result.ID)
+	}
+
+	start := time.Unix(int64(view.Date), 0).UTC()
+	if view.Date == 0 {
+		start = time.Unix(int64(result.Completed), 0).UTC()
+	}
+
+	pageURL := view.URL
+	if pageURL == "" {
+		pageURL = result.TestURL
+	}
+
+	har := &HAR{
+		Log: Log{
+			Version: "1.2",
+			Creator: Creator{Name: "WebPageTest"},
+			Browser: Browser{Name: view.BrowserName, Version: view.BrowserVersion},
+			Pages: []Page{{
+				StartedDateTime: start,
+				ID:              "page_1",
+				Title:           pageURL,
+				PageTimings: PageTimings{
+					OnContentLoad: view.DOMContentLoadedEventStart.ms(),
+					OnLoad:        view.LoadTime.ms(),
+				},
+				FirstContentfulPaint:   float64(view.FirstContentfulPaint),
+				LargestContentfulPaint: float64(view.LargestContentfulPaint),
+				CumulativeLayoutShift:  (*float64)(view.CumulativeLayoutShift),
+				TotalBlockingTime:      float64(view.TotalBlockingTime),
+				SpeedIndex:             float64(view.SpeedIndex),
+				VisualComplete:         float64(view.VisualComplete),
+			}},
+			Entries: make([]Entry, 0, len(view.Requests)),
+		},
+	}
+	if result.ID != "" {
+		har.Log.Comment = "Imported from WebPageTest test " + result.ID
+	}
+
+	for _, request := range view.Requests {
+		har.Log.Entries = append(har.Log.Entries, request.toEntry(start))
+	}
+
+	return har, nil
+}
+
+func (r *wptResult) selectView() *wptView {
+	if median, ok := r.Median["firstView"]; ok {
+		if len(median.Requests) > 0 {
+			return &median
+		}
+		if run, ok := r.Runs[strconv.Itoa(int(median.Run))]; ok && run.FirstView != nil {
+			return run.FirstView
+		}
+	}
+
+	keys := make([]string, 0, len(r.Runs))
+	for key := range r.Runs {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, _ := strconv.Atoi(keys[i])
+		b, _ := strconv.Atoi(keys[j])
+		return a < b
+	})
+	for _, key := range keys {
+		if view := r.Runs[key].FirstView; view != nil {
+			return view
+		}
+	}
+
+	return nil
+}
+
+func (r wptRequest) toEntry(pageStart time.Time) Entry {
+	method := r.Method
+	if method == "" {
+		method = "GET"
+	}
+
+	contentSize := r.ObjectSizeUncompressed
+	if contentSize <= 0 {
+		contentSize = r.ObjectSize
+	}
+
+	entry := Entry{
+		PageRef:         "page_1",
+		StartedDateTime: pageStart.Add(time.Duration(float64(r.LoadStart) * float64(time.Millisecond))),
+		Time:            float64(r.AllMs.ms()),
+		Request: Request{
+			Method:      method,
+			URL:         r.FullURL,
+			HTTPVersion: r.Protocol,
+			Headers:     parseWPTHeaders(r.Headers.Request),
+			HeadersSize: -1,
+			BodySize:    -1,
+		},
+		Response: Response{
+			Status:      int(r.ResponseCode),
+			HTTPVersion: r.Protocol,
+			Headers:     parseWPTHeaders(r.Headers.Response),
+			Content: Content{
+				Size:     contentSize.ms(),
+				MimeType: r.ContentType,
+			},
+			HeadersSize: -1,
+			BodySize:    r.ObjectSize.ms(),
+		},
+		Timings: Timings{
+			DNS:     r.DNSMs.ms(),
+			Connect: r.ConnectMs.ms() + r.SSLMs.ms(),
+			SSL:     r.SSLMs.ms(),
+			Wait:    r.TTFBMs.ms(),
+			Receive: r.DownloadMs.ms(),
+		},
+		ServerIPAddress: r.IP,
+	}
+
+	if r.Cached > 0 {
+		entry.Cache.BeforeRequest = &CacheState{}
+	}
+
+	return entry
+}
+
+// parseWPTHeaders converts "Name: value" lines into HAR headers, skipping
+// request and status lines.
+func parseWPTHeaders(lines []string) []Header {
+	headers := make([]Header, 0, len(lines))
+	for _, line := range lines {
+		name, value, ok := strings.Cut(line, ":")
+		if !ok || name == "" || strings.Contains(name, " ") {
+			continue
+		}
+		headers = append(headers, Header{Name: name, Value: strings.TrimSpace(value)})
+	}
+	return headers
+}
@@ -0,0 +1,91 @@
+package har
+
+// VitalRating is how a Core Web Vital compares to Google's thresholds.
+type VitalRating string
+
+const (
+	VitalGood             VitalRating = "good"
+	VitalNeedsImprovement VitalRating = "needs-improvement"
+	VitalPoor             VitalRating = "poor"
+)
+
+// String renders the rating for people, e.g. "Needs Improvement".
+func (r VitalRating) String() string {
+	switch r {
+	case VitalGood:
+		return "Good"
+	case VitalNeedsImprovement:
+		return "Needs Improvement"
+	case VitalPoor:
+		return "Poor"
+	}
+	return string(r)
+}
+
+// Rank orders ratings from good (0) to poor (2).
+func (r VitalRating) Rank() int {
+	switch r {
+	case VitalNeedsImprovement:
+		return 1
+	case VitalPoor:
+		return 2
+	}
+	return 0
+}
+
+// vitalThreshold holds the largest good and needs-improvement values of a
+// vital; anything above is poor.
+type vitalThreshold struct {
+	good, needsImprovement float64
+}
+
+// vitalThresholds are the web.dev thresholds, in the metric's unit.
+var vitalThresholds = map[string]vitalThreshold{
+	MetricFCP: {good: 1800, needsImprovement: 3000},
+	MetricLCP: {good: 2500, needsImprovement: 4000},
+	MetricCLS: {good: 0.1, needsImprovement: 0.25},
+	MetricTBT: {good: 200, needsImprovement: 600},
+}
+
+// WebVitalIDs are the metric IDs of the Core Web Vitals that are rated, in
+// display order.
+var WebVitalIDs = []string{MetricFCP, MetricLCP, MetricCLS, MetricTBT}
+
+// RateVital rates the value of a Core Web Vital. It reports false for
+// metrics that are not rated.
+func RateVital(id string, value float64) (VitalRating, bool) {
+	threshold, ok := vitalThresholds[id]
+	switch {
+	case !ok:
+		return "", false
+	case value <= threshold.good:
+		return VitalGood, true
+	case value <= threshold.needsImprovement:
+		return VitalNeedsImprovement, true
+	}
+	return VitalPoor, true
+}
+
+// WebVital is a measured Core Web Vital with its rating.
+type WebVital struct {
+	ID     string      `json:"id"`
+	Name   string      `json:"name"`
+	Value  float64     `json:"value"`
+	Rating VitalRating `json:"rating"`
+}
+
+// WebVitals returns the Core Web Vitals the capture measured, e.g. from
+// Lighthouse, WebPageTest or pageTimings extensions.
+func (m *Metrics) WebVitals() []WebVital {
+	var vitals []WebVital
+	for _, id := range WebVitalIDs {
+		descriptor, ok := LookupMetric(id)
+		if !ok || !descriptor.Measured(m) {
+			continue
+		}
+		value := descriptor.Value(m)
+		rating, _ := RateVital(id, value)
+		vitals = append(vitals, WebVital{ID: id, Name: descriptor.Name, Value: value, Rating: rating})
+	}
+	return vitals
+}
@@ -0,0 +1,145 @@
+package har
+
+import (
+	"sort"
+	"time"
+)
+
+// DomainRemoval estimates what a page would save without every request to
+// some domains, e.g. a third-party tag manager.
+type DomainRemoval struct {
+	Domains    []string `json:"domains"`
+	Requests   int      `json:"requests"`   // requests to the domains and their subdomains
+	Dependents int      `json:"dependents"` // requests those started, per initiator data, removed with them
+	Bytes      int64    `json:"bytes"`
+
+	// LoadTimeSaved is the network time before onLoad during which only
+	// removed requests were in flight, in ms. Time they overlapped with
+	// requests that stay is not saved.
+	LoadTimeSaved float64 `json:"load_time_saved"`
+	// CriticalPathSaved is the time the critical path spent on removed
+	// requests, in ms.
+	CriticalPathSaved float64 `json:"critical_path_saved"`
+
+	Entries []int `json:"-"` // indices of the removed entries
+}
+
+// SimulateDomainRemoval estimates the requests, bytes and time saved by
+// eliminating every request to the domains (subdomains included), along
+// with the requests they started according to Chrome's initiator data.
+func (a *Analyzer) SimulateDomainRemoval(domains ...string) DomainRemoval {
+	entries := a.har.Log.Entries
+	removal := DomainRemoval{Domains: domains}
+
+	removed := make([]bool, len(entries))
+	for i, entry := range entries {
+		for _, domain := range domains {
+			if hostWithin(EntryHost(entry), domain) {
+				removed[i] = true
+				removal.Requests++
+				break
+			}
+		}
+	}
+	if removal.Requests == 0 {
+		return removal
+	}
+
+	// Requests started by removed scripts go with them, however deep the chain
+	byURL := make(map[string][]int)
+	for i, entry := range entries {
+		key := stripFragment(entry.Request.URL)
+		byURL[key] = append(byURL[key], i)
+	}
+	for changed := true; changed; {
+		changed = false
+		for i, entry := range entries {
+			if removed[i] || entry.Initiator == nil {
+				continue
+			}
+			if parent, _ := a.initiatorOf(i, byURL); parent >= 0 && removed[parent] {
+				removed[i], changed = true, true
+				removal.Dependents++
+			}
+		}
+	}
+
+	deadline := a.onLoadTime()
+	var all, kept []interval
+	for i, entry := range entries {
+		span, ok := interval{entry.StartedDateTime, entryEnd(entry)}.clip(deadline)
+		if !ok {
+			continue
+		}
+		all = append(all, span)
+		if removed[i] {
+			removal.Entries = append(removal.Entries, i)
+			removal.Bytes += int64(max(entry.Response.Content.Size, 0))
+		} else {
+			kept = append(kept, span)
+		}
+	}
+	removal.LoadTimeSaved = float64(coveredTime(all)-coveredTime(kept)) / float64(time.Millisecond)
+
+	for _, index := range a.CriticalPath().Entries {
+		if removed[index] {
+			removal.CriticalPathSaved += entries[index].Time
+		}
+	}
+	return removal
+}
+
+// DomainRemovals simulates removing each third-party site (registrable
+// domain) of the capture on its own, most time saved first.
+func (a *Analyzer) DomainRemovals() []DomainRemoval {
+	seen := make(map[string]bool)
+	var removals []DomainRemoval
+	for _, entry := range a.har.Log.Entries {
+		domain := RegistrableDomain(EntryHost(entry))
+		if domain == "" || seen[domain] || !a.isThirdParty(entry.Request.URL) {
+			continue
+		}
+		seen[domain] = true
+		removals = append(removals, a.SimulateDomainRemoval(domain))
+	}
+
+	sort.SliceStable(removals, func(i, j int) bool {
+		if removals[i].LoadTimeSaved != removals[j].LoadTimeSaved {
+			return removals[i].LoadTimeSaved > removals[j].LoadTimeSaved
+		}
+		return removals[i].Bytes > removals[j].Bytes
+	})
+	return removals
+}
+
+// clip cuts the interval off at deadline, reporting false when it starts
+// after it. A zero deadline leaves the interval whole.
+func (i interval) clip(deadline time.Time) (interval, bool) {
+	if deadline.IsZero() {
+		return i, true
+	}
+	if !i.start.Before(deadline) {
+		return interval{}, false
+	}
+	if i.end.After(deadline) {
+		i.end = deadline
+	}
+	return i, true
+}
+
+// coveredTime is how long at least one of the intervals was open.
+func coveredTime(intervals []interval) time.Duration {
+	var covered time.Duration
+	var openedAt time.Time
+	open := 0
+	for _, e := range sortedEdges(intervals) {
+		if open == 0 && e.delta > 0 {
+			openedAt = e.at
+		}
+		open += e.delta
+		if open == 0 {
+			covered += e.at.Sub(openedAt)
+		}
+	}
+	return covered
+}
@@ -0,0 +1,309 @@
+package remote
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Backend mirrors the shared state between a local staging directory and a
+// remote location. Fetch makes dir match the remote, Publish makes the remote match dir.
+type Backend interface {
+	Fetch(dir string) error
+	Publish(dir string) error
+	String() string
+}
+
+// Shared state lives in these subdirectories of the hartea config directory.
+const (
+	workspacesDir = "workspaces"
+	historyDir    = "history"
+	baselinesDir  = "baselines"
+)
+
+// Open returns the backend for a remote: "s3://bucket/prefix" uses the AWS
+// CLI, anything else is treated as a git repository URL or path.
+func Open(remote string) (Backend, error) {
+	if remote == "" {
+		return nil, fmt.Errorf("no sync remote configured; pass --remote or set HARTEA_SYNC_REMOTE")
+	}
+	if strings.HasPrefix(remote, "s3://") {
+		return &s3Backend{url: strings.TrimSuffix(remote, "/")}, nil
+	}
+	return &gitBackend{url: remote}, nil
+}
+
+// Pull brings the team's workspaces and baselines into root, the local hartea
+// config directory. Remote workspaces and baselines win; histories are merged.
+func Pull(backend Backend, root string) error {
+	staging, err := stagingDir(backend)
+	if err != nil {
+		return err
+	}
+	if err := backend.Fetch(staging); err != nil {
+		return err
+	}
+
+	if err := copyTree(filepath.Join(staging, workspacesDir), filepath.Join(root, workspacesDir)); err != nil {
+		return err
+	}
+	if err := copyTree(filepath.Join(staging, baselinesDir), filepath.Join(root, baselinesDir)); err != nil {
+		return err
+	}
+	return mergeHistories(filepath.Join(staging, historyDir), filepath.Join(root, historyDir))
+}
+
+// Push publishes local workspaces, their baseline captures and histories.
+// Local workspaces win; histories are merged so nobody's records are lost.
+func Push(backend Backend, root string) error {
+	staging, err := stagingDir(backend)
+	if err != nil {
+		return err
+	}
+	if err := backend.Fetch(staging); err != nil {
+		return err
+	}
+
+	if err := stageWorkspaces(filepath.Join(root, workspacesDir), staging); err != nil {
+		return err
+	}
+	if err := copyTree(filepath.Join(root, baselinesDir), filepath.Join(staging, baselinesDir)); err != nil {
+		return err
+	}
+	if err := mergeHistories(filepath.Join(root, historyDir), filepath.Join(staging, historyDir)); err != nil {
+		return err
+	}
+
+	return backend.Publish(staging)
+}
+
+// stageWorkspaces copies each workspace into the staging directory. Baselines
+// outside the config directory are machine-specific, so the capture itself is
+// shared under baselines/<workspace>/ and the staged workspace points there.
+func stageWorkspaces(localDir, staging string) error {
+	files, err := filepath.Glob(filepath.Join(localDir, "*.json"))
+	if err != nil {
+		return err
+	}
+
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read workspace: %w", err)
+		}
+
+		var ws map[string]interface{}
+		if err := json.Unmarshal(data, &ws); err != nil {
+			return fmt.Errorf("failed to parse workspace %s: %w", path, err)
+		}
+
+		name := strings.TrimSuffix(filepath.Base(path), ".json")
+		if baseline, ok := ws["baseline"].(string); ok && baseline != "" && !strings.HasPrefix(filepath.ToSlash(baseline), baselinesDir+"/") {
+			shared := filepath.Join(baselinesDir, name, filepath.Base(baseline))
+			if err := copyFile(baseline, filepath.Join(staging, shared)); err != nil {
+				return fmt.Errorf("failed to share baseline of workspace %s: %w", name, err)
+			}
+			ws["baseline"] = filepath.ToSlash(shared)
+		}
+
+		data, err = json.MarshalIndent(ws, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode workspace: %w", err)
+		}
+		target := filepath.Join(staging, workspacesDir, filepath.Base(path))
+		if err := writeFile(target, append(data, '\n')); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// mergeHistories unions the NDJSON histories in from into to, dropping
+// duplicate records. History is append-only, so a union never loses data.
+func mergeHistories(from, to string) error {
+	files, err := filepath.Glob(filepath.Join(from, "*.ndjson"))
+	if err != nil {
+		return err
+	}
+
+	for _, source := range files {
+		target := filepath.Join(to, filepath.Base(source))
+
+		lines, err := readLines(target)
+		if err != nil {
+			return err
+		}
+		incoming, err := readLines(source)
+		if err != nil {
+			return err
+		}
+
+		seen := make(map[string]bool, len(lines))
+		for _, line := range lines {
+			seen[line] = true
+		}
+		changed := false
+		for _, line := range incoming {
+			if !seen[line] {
+				seen[line] = true
+				lines = append(lines, line)
+				changed = true
+			}
+		}
+		if !changed {
+			continue
+		}
+
+		var buffer bytes.Buffer
+		for _, line := range lines {
+			buffer.WriteString(line)
+			buffer.WriteByte('\n')
+		}
+		if err := writeFile(target, buffer.Bytes()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func readLines(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return lines, nil
+}
+
+// copyTree copies every file under from into to, overwriting existing files.
+func copyTree(from, to string) error {
+	err := filepath.WalkDir(from, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		relative, err := filepath.Rel(from, path)
+		if err != nil {
+			return err
+		}
+		return copyFile(path, filepath.Join(to, relative))
+	})
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func copyFile(from, to string) error {
+	data, err := os.ReadFile(from)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", from, err)
+	}
+	return writeFile(to, data)
+}
+
+func writeFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// stagingDir returns a per-remote working directory in the user cache.
+func stagingDir(backend Backend) (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate cache directory: %w", err)
+	}
+	sum := sha256.Sum256([]byte(backend.String()))
+	return filepath.Join(cacheDir, "hartea", "sync", hex.EncodeToString(sum[:8])), nil
+}
+
+func run(dir, name string, args ...string) error {
+	cmd := exec.Command(name, args...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s %s failed: %w\n%s", name, strings.Join(args, " "), err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+type gitBackend struct {
+	url string
+}
+
+func (b *gitBackend) String() string { return b.url }
+
+func (b *gitBackend) Fetch(dir string) error {
+	if _, err := os.Stat(filepath.Join(dir, ".git")); errors.Is(err, os.ErrNotExist) {
+		if err := os.MkdirAll(filepath.Dir(dir), 0o755); err != nil {
+			return fmt.Errorf("failed to create sync directory: %w", err)
+		}
+		return run(filepath.Dir(dir), "git", "clone", "--quiet", b.url, dir)
+	}
+
+	// An empty repository has no upstream branch to pull yet
+	if err := run(dir, "git", "rev-parse", "--verify", "--quiet", "@{upstream}"); err != nil {
+		return nil
+	}
+	return run(dir, "git", "pull", "--quiet", "--ff-only")
+}
+
+func (b *gitBackend) Publish(dir string) error {
+	if err := run(dir, "git", "add", "-A"); err != nil {
+		return err
+	}
+	if err := run(dir, "git", "diff", "--cached", "--quiet"); err == nil {
+		return nil // nothing changed
+	}
+	if err := run(dir, "git", "commit", "--quiet", "-m", "Update hartea baselines"); err != nil {
+		return err
+	}
+	return run(dir, "git", "push", "--quiet", "origin", "HEAD")
+}
+
+type s3Backend struct {
+	url string
+}
+
+func (b *s3Backend) String() string { return b.url }
+
+func (b *s3Backend) Fetch(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create sync directory: %w", err)
+	}
+	return run(dir, "aws", "s3", "sync", "--only-show-errors", "--delete", b.url, dir)
+}
+
+func (b *s3Backend) Publish(dir string) error {
+	return run(dir, "aws", "s3", "sync", "--only-show-errors", dir, b.url)
+}
@@ -0,0 +1,204 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/jlgore/hartea/internal/har"
+	"io"
+	"strings"
+	"time"
+)
+
+// RequestChange is one request of a before/after comparison.
+type RequestChange struct {
+	Request      string  `json:"request"`
+	Change       string  `json:"change"` // "changed", "unchanged", "added" or "removed"
+	BeforeTime   float64 `json:"before_time,omitempty"`
+	AfterTime    float64 `json:"after_time,omitempty"`
+	BeforeSize   int     `json:"before_size,omitempty"`
+	AfterSize    int     `json:"after_size,omitempty"`
+	BeforeStatus int     `json:"before_status,omitempty"`
+	AfterStatus  int     `json:"after_status,omitempty"`
+}
+
+// BeforeAfter compares a candidate capture against a baseline, metric by
+// metric and request by request.
+type BeforeAfter struct {
+	GeneratedAt time.Time            `json:"generated_at"`
+	Baseline    string               `json:"baseline"`
+	Candidate   string               `json:"candidate"`
+	MatchRule   har.MatchRule        `json:"match_rule"`
+	Comparison  *har.Comparison      `json:"comparison"`
+	Summary     har.ExecutiveSummary `json:"summary"` // of the candidate
+	Requests    []RequestChange      `json:"requests"`
+
+	units har.SizeUnits
+}
+
+// NewBeforeAfter compares candidate against baseline with the settings,
+// pairing requests with rule.
+func NewBeforeAfter(baseline, candidate *har.HAR, names [2]string, rule har.MatchRule, settings har.Settings) *BeforeAfter {
+	metrics := []*har.Metrics{har.NewAnalyzer(baseline, settings).CalculateMetrics(), har.NewAnalyzer(candidate, settings).CalculateMetrics()}
+	bundle := &BeforeAfter{
+		GeneratedAt: time.Now(),
+		Baseline:    names[0],
+		Candidate:   names[1],
+		MatchRule:   rule,
+		Comparison:  har.NewComparator(names[:], metrics, settings).Compare(),
+		units:       settings.SizeUnits,
+	}
+	bundle.Summary = har.Summarize(metrics[1], bundle.Comparison, 1, settings.SizeUnits)
+
+	for _, match := range har.MatchEntries(baseline, candidate, rule) {
+		change := RequestChange{Request: match.Key}
+		if !match.Added() {
+			entry := baseline.Log.Entries[match.Before]
+			change.BeforeTime, change.BeforeSize, change.BeforeStatus = entry.Time, entry.Response.Content.Size, entry.Response.Status
+		}
+		if !match.Removed() {
+			entry := candidate.Log.Entries[match.After]
+			change.AfterTime, change.AfterSize, change.AfterStatus = entry.Time, entry.Response.Content.Size, entry.Response.Status
+		}
+
+		switch {
+		case match.Added():
+			change.Change = "added"
+		case match.Removed():
+			change.Change = "removed"
+		case change.BeforeStatus != change.AfterStatus || change.BeforeSize != change.AfterSize || change.BeforeTime != change.AfterTime:
+			change.Change = "changed"
+		default:
+			change.Change = "unchanged"
+		}
+		bundle.Requests = append(bundle.Requests, change)
+	}
+
+	return bundle
+}
+
+// ExportBeforeAfterBundle writes the HTML report, a Markdown summary and the
+// JSON comparison of a baseline and candidate capture next to each other,
+// returning the files written. plain writes ASCII markers instead of emoji.
+func ExportBeforeAfterBundle(basePath string, baseline, candidate *har.HAR, names [2]string, rule har.MatchRule, settings har.Settings, plain bool) ([]string, error) {
+	bundle := NewBeforeAfter(baseline, candidate, names, rule, settings)
+
+	harFiles := []*har.HAR{baseline, candidate}
+	analyzers := []*har.Analyzer{har.NewAnalyzer(baseline, settings), har.NewAnalyzer(candidate, settings)}
+	generator := NewGenerator(harFiles, analyzers, bundle.Comparison, settings)
+	generator.SetSources(names[:])
+	generator.SetFileNames(names[:])
+	generator.SetPlain(plain)
+
+	var files []string
+	if err := generator.ExportHTML(basePath + ".html"); err != nil {
+		return files, err
+	}
+	files = append(files, basePath+".html")
+
+	writeMarkdown := bundle.WriteMarkdown
+	if plain {
+		writeMarkdown = func(w io.Writer) error {
+			if _, err := io.WriteString(w, Plain(bundle.Markdown())); err != nil {
+				return fmt.Errorf("failed to write Markdown summary: %w", err)
+			}
+			return nil
+		}
+	}
+	if err := exportFile(basePath+".md", "Markdown", writeMarkdown); err != nil {
+		return files, err
+	}
+	files = append(files, basePath+".md")
+
+	if err := exportFile(basePath+".json", "JSON", bundle.WriteJSON); err != nil {
+		return files, err
+	}
+	files = append(files, basePath+".json")
+
+	return files, nil
+}
+
+// WriteMarkdown writes the Markdown summary.
+func (b *BeforeAfter) WriteMarkdown(w io.Writer) error {
+	if _, err := io.WriteString(w, b.Markdown()); err != nil {
+		return fmt.Errorf("failed to write Markdown summary: %w", err)
+	}
+	return nil
+}
+
+// WriteJSON writes the comparison as indented JSON.
+func (b *BeforeAfter) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(b); err != nil {
+		return fmt.Errorf("failed to encode comparison: %w", err)
+	}
+	return nil
+}
+
+// Markdown renders the comparison for pull requests and tickets.
+func (b *BeforeAfter) Markdown() string {
+	var md strings.Builder
+
+	fmt.Fprintf(&md, "# Before/after: %s → %s\n\n", b.Baseline, b.Candidate)
+	fmt.Fprintf(&md, "Generated %s, requests matched by `%s`.\n\n", b.GeneratedAt.Format("January 2, 2006 at 3:04 PM"), b.MatchRule)
+	fmt.Fprintf(&md, "**%s**\n\n%s\n\n", scoreLine(b.Summary), b.Summary.Text)
+
+	md.WriteString("## Metrics\n\n")
+	fmt.Fprintf(&md, "| Metric | %s | %s | Change |\n|---|---|---|---|\n", b.Baseline, b.Candidate)
+	for _, diff := range b.Comparison.Differences {
+		if len(diff.Values) < 2 {
+			continue
+		}
+		change := diff.Changes[1]
+		if diff.Flagged(1) && diff.Improvements[1] {
+			change += " ✅"
+		} else if diff.Regressed(1) {
+			change += " ❌"
+		}
+		fmt.Fprintf(&md, "| %s | %v | %v | %s |\n", diff.Name, diff.Values[0], diff.Values[1], change)
+	}
+	summary := b.Comparison.Summary
+	fmt.Fprintf(&md, "\n%d better, %d worse, %d unchanged.\n", summary.BetterCount, summary.WorseCount, summary.UnchangedCount)
+
+	if len(b.Comparison.Insights) > 0 {
+		md.WriteString("\n## Insights\n\n")
+		for _, insight := range b.Comparison.Insights {
+			fmt.Fprintf(&md, "- **%s** %s\n", insight.Severity, insight)
+		}
+	}
+
+	counts := make(map[string]int)
+	for _, request := range b.Requests {
+		counts[request.Change]++
+	}
+	md.WriteString("\n## Requests\n\n")
+	fmt.Fprintf(&md, "%d changed, %d unchanged, %d added, %d removed.\n\n", counts["changed"], counts["unchanged"], counts["added"], counts["removed"])
+	md.WriteString("| Request | Change | Time | Size | Status |\n|---|---|---|---|---|\n")
+	for _, request := range b.Requests {
+		if request.Change == "unchanged" {
+			continue
+		}
+		fmt.Fprintf(&md, "| `%s` | %s | %s | %s | %s |\n",
+			strings.ReplaceAll(request.Request, "|", "\\|"),
+			request.Change,
+			markdownDelta(request, fmt.Sprintf("%.1fms", request.BeforeTime), fmt.Sprintf("%.1fms", request.AfterTime)),
+			markdownDelta(request, b.units.FormatSize(request.BeforeSize), b.units.FormatSize(request.AfterSize)),
+			markdownDelta(request, fmt.Sprint(request.BeforeStatus), fmt.Sprint(request.AfterStatus)))
+	}
+
+	return md.String()
+}
+
+// markdownDelta shows "before → after", or just the side that exists.
+func markdownDelta(request RequestChange, before, after string) string {
+	switch request.Change {
+	case "added":
+		return after
+	case "removed":
+		return before
+	}
+	if before == after {
+		return before
+	}
+	return before + " → " + after
+}
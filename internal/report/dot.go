@@ -0,0 +1,82 @@
+package report
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// dotLabelLength caps the URL shown on a graph node.
+const dotLabelLength = 60
+
+// ExportDOT writes the request dependency graph of every file as Graphviz
+// DOT, e.g. for `dot -Tsvg`.
+func (g *Generator) ExportDOT(filename string) error {
+	return exportFile(filename, "DOT", g.WriteDOT)
+}
+
+// WriteDOT writes one cluster per file whose nodes are requests and whose
+// edges run from each request to those it started, labeled with the cause
+// (parser, script, redirect, ...). See har.Analyzer.InitiatorGraph.
+func (g *Generator) WriteDOT(w io.Writer) error {
+	writer := bufio.NewWriter(w)
+	fmt.Fprintln(writer, "digraph hartea {")
+	fmt.Fprintln(writer, "  rankdir=LR;")
+	fmt.Fprintln(writer, `  node [shape=box, fontname="Helvetica", fontsize=10];`)
+	fmt.Fprintln(writer, `  edge [fontname="Helvetica", fontsize=9];`)
+
+	for i, analyzer := range g.analyzers {
+		entries := g.harFiles[i].Log.Entries
+		graph := analyzer.InitiatorGraph()
+
+		fmt.Fprintf(writer, "  subgraph cluster_%d {\n", i)
+		fmt.Fprintf(writer, "    label=%s;\n", dotQuote(g.FileName(i)))
+		for _, node := range graph.Nodes {
+			entry := entries[node.Entry]
+			attributes := ""
+			if entry.Response.Status >= 400 || entry.Response.Status == 0 {
+				attributes = ", color=red, fontcolor=red"
+			}
+			label := fmt.Sprintf("%s %s\n%d · %.0fms", entry.Request.Method, dotURL(entry.Request.URL), entry.Response.Status, entry.Time)
+			fmt.Fprintf(writer, "    f%d_e%d [label=%s%s];\n", i, node.Entry, dotQuote(label), attributes)
+		}
+		for _, node := range graph.Nodes {
+			if node.Parent < 0 {
+				continue
+			}
+			cause := node.Cause
+			if cause == "" {
+				cause = "other"
+			}
+			fmt.Fprintf(writer, "    f%d_e%d -> f%d_e%d [label=%s];\n", i, node.Parent, i, node.Entry, dotQuote(cause))
+		}
+		fmt.Fprintln(writer, "  }")
+	}
+	fmt.Fprintln(writer, "}")
+
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("failed to write DOT graph: %w", err)
+	}
+	return nil
+}
+
+// dotURL shortens a URL to its host and path for a node label.
+func dotURL(rawURL string) string {
+	label := rawURL
+	if parsed, err := url.Parse(rawURL); err == nil && parsed.Host != "" {
+		label = parsed.Host + parsed.EscapedPath()
+	}
+	if runes := []rune(label); len(runes) > dotLabelLength {
+		label = string(runes[:dotLabelLength-3]) + "..."
+	}
+	return label
+}
+
+// dotQuote quotes a DOT string, keeping newlines as line breaks.
+func dotQuote(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `"`, `\"`)
+	return `"` + strings.ReplaceAll(value, "\n", `\n`) + `"`
+}
@@ -0,0 +1,60 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/jlgore/hartea/internal/audit"
+	"io"
+	"time"
+)
+
+// FindingRecord is one security audit finding with its rule and file resolved.
+type FindingRecord struct {
+	RuleID     string         `json:"rule_id"`
+	RuleName   string         `json:"rule_name"`
+	Severity   audit.Severity `json:"severity"`
+	Message    string         `json:"message"`
+	File       string         `json:"file"`
+	EntryIndex int            `json:"entry_index"`
+	URL        string         `json:"url"`
+}
+
+type FindingsReport struct {
+	GeneratedAt time.Time       `json:"generated_at"`
+	Findings    []FindingRecord `json:"findings"`
+}
+
+// FindingRecords runs the security audit, including the secret scan, over every file.
+func (g *Generator) FindingRecords() []FindingRecord {
+	records := []FindingRecord{}
+	for _, finding := range audit.Audit(g.harFiles, g.settings) {
+		rule, _ := audit.LookupRule(finding.RuleID)
+		records = append(records, FindingRecord{
+			RuleID:     finding.RuleID,
+			RuleName:   rule.Name,
+			Severity:   finding.Severity,
+			Message:    finding.Message,
+			File:       g.sourceName(finding.File),
+			EntryIndex: finding.EntryIndex,
+			URL:        finding.URL,
+		})
+	}
+	return records
+}
+
+// ExportFindingsJSON writes the security audit findings as plain JSON, for
+// tools that do not read SARIF.
+func (g *Generator) ExportFindingsJSON(filename string) error {
+	return exportFile(filename, "findings", g.WriteFindingsJSON)
+}
+
+// WriteFindingsJSON writes the security audit findings as plain JSON.
+func (g *Generator) WriteFindingsJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(FindingsReport{GeneratedAt: time.Now(), Findings: g.FindingRecords()}); err != nil {
+		return fmt.Errorf("failed to encode findings: %w", err)
+	}
+
+	return nil
+}
@@ -1,12 +1,14 @@
 package report
 
 import (
+	"bufio"
 	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"github.com/jlgore/hartea/internal/har"
+	"html/template"
+	"io"
 	"os"
-	"path/filepath"
 	"strings"
 	"time"
 )
@@ -15,6 +17,13 @@ type Generator struct {
 	harFiles   []*har.HAR
 	analyzers  []*har.Analyzer
 	comparison *har.Comparison
+	sources    []string
+	names      []string
+	segmentGap time.Duration
+	vendors    []har.VendorBudget
+	slos       []har.SLO
+	plain      bool
+	settings   har.Settings
 }
 
 type Report struct {
@@ -24,6 +33,45 @@ type Report struct {
 	Metrics     []*har.Metrics  `json:"metrics"`
 	Comparison  *har.Comparison `json:"comparison,omitempty"`
 	Entries     []har.Entry     `json:"entries,omitempty"`
+	Segments    [][]har.Segment `json:"segments,omitempty"`
+
+	// SizeUnits are the units sizes are written in, e.g. TotalTransferMB
+	SizeUnits har.SizeUnits `json:"size_units,omitempty"`
+
+	// ExecutiveSummary describes the last file, against the first as the
+	// baseline when there are several
+	ExecutiveSummary har.ExecutiveSummary `json:"executive_summary"`
+
+	// Scores has the performance score and category grades of every file
+	Scores []har.PerformanceScore `json:"scores"`
+
+	// ResourceTypes has the per-type breakdown of every file
+	ResourceTypes [][]har.ResourceTypeStats `json:"resource_types"`
+
+	// Endpoints has the templated API endpoints of every file, see
+	// har.EndpointTemplate
+	Endpoints [][]har.EndpointStats `json:"endpoints"`
+
+	// Heatmaps has the latency heatmap of every file that spans at least
+	// har.HeatmapMinDuration, and nil for shorter files
+	Heatmaps []*har.LatencyHeatmap `json:"heatmaps,omitempty"`
+
+	// RateLimits has the rate limiting of every file, when any file was
+	// throttled or reported a quota
+	RateLimits []har.RateLimitAnalysis `json:"rate_limits,omitempty"`
+
+	// VendorScorecards has one scorecard per vendor budget for every file
+	VendorScorecards [][]har.VendorScorecard `json:"vendor_scorecards,omitempty"`
+
+	// SLOs has the result of every service level objective for every file
+	SLOs [][]har.SLOResult `json:"slos,omitempty"`
+
+	// ResourceHints has the recommended preconnect and dns-prefetch hints of
+	// every file
+	ResourceHints [][]har.ResourceHint `json:"resource_hints"`
+
+	// JSAudits has the JavaScript payload audit of every file
+	JSAudits []har.JSAudit `json:"js_audits"`
 }
 
 type ReportSummary struct {
@@ -35,14 +83,86 @@ type ReportSummary struct {
 	TotalTransferMB float64 `json:"total_transfer_mb"`
 }
 
-func NewGenerator(harFiles []*har.HAR, analyzers []*har.Analyzer, comparison *har.Comparison) *Generator {
+func NewGenerator(harFiles []*har.HAR, analyzers []*har.Analyzer, comparison *har.Comparison, settings har.Settings) *Generator {
 	return &Generator{
 		harFiles:   harFiles,
 		analyzers:  analyzers,
 		comparison: comparison,
+		settings:   settings,
 	}
 }
 
+// SetSources records the paths the HAR files were loaded from, so exports that
+// point back at files (e.g. SARIF) can reference them.
+func (g *Generator) SetSources(paths []string) {
+	g.sources = paths
+}
+
+// SetSegmentGap sets the idle time used to split captures into segments.
+func (g *Generator) SetSegmentGap(gap time.Duration) {
+	g.segmentGap = gap
+}
+
+// SetVendorBudgets adds a scorecard per budgeted third-party vendor to reports.
+func (g *Generator) SetVendorBudgets(budgets []har.VendorBudget) {
+	g.vendors = budgets
+}
+
+// SetSLOs adds the compliance of every file with these service level
+// objectives to reports.
+func (g *Generator) SetSLOs(slos []har.SLO) {
+	g.slos = slos
+}
+
+// SetPlain makes the HTML report use ASCII markers instead of emoji, see
+// Plain.
+func (g *Generator) SetPlain(plain bool) {
+	g.plain = plain
+}
+
+// SetFileNames sets the labels the report shows for the files, e.g. their
+// file names or user-supplied labels.
+func (g *Generator) SetFileNames(names []string) {
+	g.names = names
+}
+
+// FileName returns the label of the file at index, or "File N" when it has
+// none.
+func (g *Generator) FileName(index int) string {
+	if index < len(g.names) && g.names[index] != "" {
+		return g.names[index]
+	}
+	return fmt.Sprintf("File %d", index+1)
+}
+
+func (g *Generator) sourceName(index int) string {
+	if index < len(g.sources) && g.sources[index] != "" {
+		return g.sources[index]
+	}
+	return g.FileName(index)
+}
+
+// NewGeneratorFromHAR analyzes the HAR files with the settings, labeled with
+// names (missing names become "File N"), and compares them when there is
+// more than one.
+func NewGeneratorFromHAR(harFiles []*har.HAR, names []string, settings har.Settings) *Generator {
+	generator := NewGenerator(harFiles, make([]*har.Analyzer, len(harFiles)), nil, settings)
+	generator.SetFileNames(names)
+
+	metrics := make([]*har.Metrics, len(harFiles))
+	fileNames := make([]string, len(harFiles))
+	for i, harFile := range harFiles {
+		generator.analyzers[i] = har.NewAnalyzer(harFile, settings)
+		metrics[i] = generator.analyzers[i].CalculateMetrics()
+		fileNames[i] = generator.FileName(i)
+	}
+
+	if len(harFiles) > 1 {
+		generator.comparison = har.NewComparator(fileNames, metrics, settings).Compare()
+	}
+	return generator
+}
+
 func (g *Generator) GenerateReport(includeEntries bool) *Report {
 	// Calculate summary metrics
 	summary := g.calculateSummary()
@@ -56,15 +176,73 @@ func (g *Generator) GenerateReport(includeEntries bool) *Report {
 	// File names
 	fileNames := make([]string, len(g.harFiles))
 	for i := range g.harFiles {
-		fileNames[i] = fmt.Sprintf("File %d", i+1)
+		fileNames[i] = g.FileName(i)
 	}
 
 	report := &Report{
-		GeneratedAt: time.Now(),
-		Files:       fileNames,
-		Summary:     summary,
-		Metrics:     metrics,
-		Comparison:  g.comparison,
+		GeneratedAt:   time.Now(),
+		Files:         fileNames,
+		Summary:       summary,
+		Metrics:       metrics,
+		Comparison:    g.comparison,
+		SizeUnits:     g.settings.SizeUnits,
+		ResourceTypes: make([][]har.ResourceTypeStats, len(g.analyzers)),
+		Endpoints:     make([][]har.EndpointStats, len(g.analyzers)),
+		Scores:        make([]har.PerformanceScore, len(g.analyzers)),
+		ResourceHints: make([][]har.ResourceHint, len(g.analyzers)),
+		JSAudits:      make([]har.JSAudit, len(g.analyzers)),
+	}
+	for i, analyzer := range g.analyzers {
+		report.ResourceTypes[i] = analyzer.ResourceBreakdown()
+		report.Endpoints[i] = analyzer.EndpointStats()
+		report.Scores[i] = har.ScorePerformance(metrics[i])
+		report.ResourceHints[i] = analyzer.ResourceHints()
+		report.JSAudits[i] = analyzer.JSAudit()
+	}
+	if last := len(metrics) - 1; last >= 0 {
+		report.ExecutiveSummary = har.Summarize(metrics[last], g.comparison, last, g.settings.SizeUnits)
+	}
+
+	// Segments are only worth reporting when a capture splits into several
+	segments := make([][]har.Segment, len(g.analyzers))
+	for i, analyzer := range g.analyzers {
+		segments[i] = analyzer.Segments(g.segmentGap)
+		if len(segments[i]) > 1 {
+			report.Segments = segments
+		}
+	}
+
+	for i, analyzer := range g.analyzers {
+		if analyzer.CaptureDuration() < har.HeatmapMinDuration {
+			continue
+		}
+		if report.Heatmaps == nil {
+			report.Heatmaps = make([]*har.LatencyHeatmap, len(g.analyzers))
+		}
+		heatmap := analyzer.LatencyHeatmap(reportHeatmapBuckets, reportHeatmapEndpoints)
+		report.Heatmaps[i] = &heatmap
+	}
+
+	rateLimits := make([]har.RateLimitAnalysis, len(g.analyzers))
+	for i, analyzer := range g.analyzers {
+		rateLimits[i] = analyzer.RateLimits()
+		if rateLimits[i].HasData() {
+			report.RateLimits = rateLimits
+		}
+	}
+
+	if len(g.vendors) > 0 {
+		report.VendorScorecards = make([][]har.VendorScorecard, len(g.analyzers))
+		for i, analyzer := range g.analyzers {
+			report.VendorScorecards[i] = analyzer.VendorScorecards(g.vendors)
+		}
+	}
+
+	if len(g.slos) > 0 {
+		report.SLOs = make([][]har.SLOResult, len(g.analyzers))
+		for i, analyzer := range g.analyzers {
+			report.SLOs[i] = analyzer.EvaluateSLOs(g.slos)
+		}
 	}
 
 	// Include entries if requested (for detailed analysis)
@@ -75,6 +253,19 @@ func (g *Generator) GenerateReport(includeEntries bool) *Report {
 	return report
 }
 
+// FileLabels returns the file names with the environment of every capture
+// not taken against production, e.g. "File 2 (staging)".
+func (r *Report) FileLabels() []string {
+	labels := make([]string, len(r.Files))
+	for i, name := range r.Files {
+		labels[i] = name
+		if i < len(r.Metrics) && r.Metrics[i].Environment != "" && r.Metrics[i].Environment != har.EnvProduction {
+			labels[i] = fmt.Sprintf("%s (%s)", name, r.Metrics[i].Environment)
+		}
+	}
+	return labels
+}
+
 func (g *Generator) calculateSummary() ReportSummary {
 	summary := ReportSummary{
 		TotalFiles: len(g.harFiles),
@@ -84,11 +275,15 @@ func (g *Generator) calculateSummary() ReportSummary {
 		return summary
 	}
 
-	var totalRequests, totalErrors int
+	var totalRequests, totalErrors, filesWithData int
 	var totalLoadTime, totalTTFB, totalTransferBytes float64
 
 	for _, analyzer := range g.analyzers {
 		metrics := analyzer.CalculateMetrics()
+		if !metrics.HasData() {
+			continue
+		}
+		filesWithData++
 		totalRequests += metrics.TotalRequests
 		totalErrors += metrics.ErrorRequests
 		totalLoadTime += metrics.PageLoadTime
@@ -96,28 +291,97 @@ func (g *Generator) calculateSummary() ReportSummary {
 		totalTransferBytes += float64(metrics.TotalSize)
 	}
 
-	fileCount := float64(len(g.analyzers))
 	summary.TotalRequests = totalRequests
 	summary.TotalErrors = totalErrors
-	summary.AverageLoadTime = totalLoadTime / fileCount
-	summary.AverageTTFB = totalTTFB / fileCount
-	summary.TotalTransferMB = totalTransferBytes / (1024 * 1024) // Convert to MB
+	if filesWithData > 0 {
+		// Captures without entries would drag the averages towards zero
+		fileCount := float64(filesWithData)
+		summary.AverageLoadTime = totalLoadTime / fileCount
+		summary.AverageTTFB = totalTTFB / fileCount
+	}
+	summary.TotalTransferMB = g.settings.SizeUnits.Megabytes(totalTransferBytes)
 
 	return summary
 }
 
-func (g *Generator) ExportJSON(filename string, includeEntries bool) error {
-	report := g.GenerateReport(includeEntries)
-
+// exportFile creates filename and writes one export to it, so every format
+// can also be written to buffers, HTTP responses or archives.
+func exportFile(filename, kind string, write func(io.Writer) error) error {
 	file, err := os.Create(filename)
 	if err != nil {
-		return fmt.Errorf("failed to create JSON file: %w", err)
+		return fmt.Errorf("failed to create %s file: %w", kind, err)
+	}
+	if err := write(file); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write %s file: %w", kind, err)
 	}
-	defer file.Close()
+	return nil
+}
+
+// Formats are the export formats Write accepts.
+var Formats = []string{"json", "csv", "entries-csv", "ndjson", "html", "pdf", "prometheus", "sarif", "findings", "trends-csv", "trends-json", "dot", "mermaid", "waterfall-svg", "waterfall-png", "trace"}
+
+// Write writes the report to w in one of the Formats. includeEntries only
+// applies to JSON.
+func (g *Generator) Write(w io.Writer, format string, includeEntries bool) error {
+	switch format {
+	case "json":
+		return g.WriteJSON(w, includeEntries)
+	case "csv":
+		return g.WriteCSV(w)
+	case "entries-csv":
+		return g.WriteEntriesCSV(w)
+	case "ndjson":
+		return g.WriteNDJSON(w)
+	case "html":
+		return g.WriteHTML(w)
+	case "pdf":
+		return g.WritePDF(w)
+	case "prometheus":
+		return g.WritePrometheus(w)
+	case "sarif":
+		return g.WriteSARIF(w)
+	case "findings":
+		return g.WriteFindingsJSON(w)
+	case "trends-csv":
+		return g.WriteTrendsCSV(w)
+	case "trends-json":
+		return g.WriteTrendsJSON(w)
+	case "dot":
+		return g.WriteDOT(w)
+	case "mermaid":
+		return g.WriteMermaid(w)
+	case "waterfall-svg":
+		return g.WriteWaterfallSVG(w)
+	case "waterfall-png":
+		return g.WriteWaterfallPNG(w)
+	case "trace":
+		return g.WriteTrace(w)
+	}
+	return fmt.Errorf("unknown export format %q", format)
+}
 
-	encoder := json.NewEncoder(file)
+// Export writes the report to filename in one of the Formats.
+func (g *Generator) Export(filename, format string, includeEntries bool) error {
+	return exportFile(filename, format, func(w io.Writer) error {
+		return g.Write(w, format, includeEntries)
+	})
+}
+
+func (g *Generator) ExportJSON(filename string, includeEntries bool) error {
+	return exportFile(filename, "JSON", func(w io.Writer) error {
+		return g.WriteJSON(w, includeEntries)
+	})
+}
+
+// WriteJSON writes the report as indented JSON.
+func (g *Generator) WriteJSON(w io.Writer, includeEntries bool) error {
+	encoder := json.NewEncoder(w)
 	encoder.SetIndent("", "  ")
-	if err := encoder.Encode(report); err != nil {
+	if err := encoder.Encode(g.GenerateReport(includeEntries)); err != nil {
 		return fmt.Errorf("failed to encode JSON: %w", err)
 	}
 
@@ -125,21 +389,21 @@ func (g *Generator) ExportJSON(filename string, includeEntries bool) error {
 }
 
 func (g *Generator) ExportCSV(filename string) error {
-	file, err := os.Create(filename)
-	if err != nil {
-		return fmt.Errorf("failed to create CSV file: %w", err)
-	}
-	defer file.Close()
+	return exportFile(filename, "CSV", g.WriteCSV)
+}
 
-	writer := csv.NewWriter(file)
-	defer writer.Flush()
+// WriteCSV writes one row of metrics per file.
+func (g *Generator) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
 
 	// Write headers
-	headers := []string{
-		"File", "Total Load Time (ms)", "TTFB (ms)", "DNS Time (ms)",
-		"Connect Time (ms)", "SSL Time (ms)", "Total Requests",
-		"Error Requests", "Third-party Requests", "Cache Hit Ratio (%)",
-		"Total Size (MB)",
+	descriptors := har.MetricDescriptors()
+	headers := []string{"File"}
+	for _, descriptor := range descriptors {
+		headers = append(headers, csvMetricHeader(descriptor, g.settings.SizeUnits))
+	}
+	for _, resourceType := range har.ResourceTypes {
+		headers = append(headers, resourceType+" Requests", resourceType+" Size ("+g.settings.SizeUnits.MegabyteUnit()+")", resourceType+" Time (ms)")
 	}
 	if err := writer.Write(headers); err != nil {
 		return fmt.Errorf("failed to write CSV headers: %w", err)
@@ -148,46 +412,247 @@ func (g *Generator) ExportCSV(filename string) error {
 	// Write metrics for each file
 	for i, analyzer := range g.analyzers {
 		metrics := analyzer.CalculateMetrics()
-		record := []string{
-			fmt.Sprintf("File %d", i+1),
-			fmt.Sprintf("%.1f", metrics.PageLoadTime),
-			fmt.Sprintf("%.1f", metrics.TTFB),
-			fmt.Sprintf("%.1f", metrics.DNSTime),
-			fmt.Sprintf("%.1f", metrics.ConnectTime),
-			fmt.Sprintf("%.1f", metrics.SSLTime),
-			fmt.Sprintf("%d", metrics.TotalRequests),
-			fmt.Sprintf("%d", metrics.ErrorRequests),
-			fmt.Sprintf("%d", metrics.ThirdPartyRequests),
-			fmt.Sprintf("%.1f", metrics.CacheHitRatio),
-			fmt.Sprintf("%.2f", float64(metrics.TotalSize)/(1024*1024)),
+		record := []string{g.FileName(i)}
+		for _, descriptor := range descriptors {
+			record = append(record, csvMetricValue(descriptor, descriptor.Value(metrics), g.settings.SizeUnits))
 		}
+		record = append(record, resourceTypeCells(analyzer.ResourceBreakdown(), g.settings.SizeUnits)...)
 		if err := writer.Write(record); err != nil {
 			return fmt.Errorf("failed to write CSV record: %w", err)
 		}
 	}
 
+	return flushCSV(writer)
+}
+
+// flushCSV flushes buffered records, reporting any write error.
+func flushCSV(writer *csv.Writer) error {
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
 	return nil
 }
 
-func (g *Generator) ExportHTML(filename string) error {
-	report := g.GenerateReport(false)
+// resourceTypeCells lays out a breakdown as requests, size and time for every
+// type in har.ResourceTypes, with zeros for types the file lacks. Sizes are
+// in megabytes of units.
+func resourceTypeCells(breakdown []har.ResourceTypeStats, units har.SizeUnits) []string {
+	byType := make(map[string]har.ResourceTypeStats)
+	for _, stats := range breakdown {
+		byType[stats.Type] = stats
+	}
+	var cells []string
+	for _, resourceType := range har.ResourceTypes {
+		stats := byType[resourceType]
+		cells = append(cells, fmt.Sprintf("%d", stats.Requests), fmt.Sprintf("%.2f", units.Megabytes(float64(stats.Size))), fmt.Sprintf("%.1f", stats.Time))
+	}
+	return cells
+}
 
-	html := g.generateHTMLContent(report)
+func csvMetricHeader(descriptor har.MetricDescriptor, units har.SizeUnits) string {
+	switch descriptor.Kind {
+	case har.SizeMetric:
+		return descriptor.Name + " (" + units.MegabyteUnit() + ")"
+	case har.CountMetric, har.ScoreMetric:
+		return descriptor.Name
+	}
+	return fmt.Sprintf("%s (%s)", descriptor.Name, descriptor.Unit)
+}
 
-	file, err := os.Create(filename)
+func csvMetricValue(descriptor har.MetricDescriptor, value float64, units har.SizeUnits) string {
+	switch descriptor.Kind {
+	case har.SizeMetric:
+		return fmt.Sprintf("%.2f", units.Megabytes(value))
+	case har.CountMetric:
+		return fmt.Sprintf("%d", int(value))
+	case har.ScoreMetric:
+		return fmt.Sprintf("%.3f", value)
+	}
+	return fmt.Sprintf("%.1f", value)
+}
+
+func (g *Generator) ExportEntriesCSV(filename string) error {
+	return exportFile(filename, "CSV", g.WriteEntriesCSV)
+}
+
+// WriteEntriesCSV writes one row per entry of every file.
+func (g *Generator) WriteEntriesCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+
+	headers := []string{
+		"File", "Method", "URL", "Status", "Domain", "MIME Type", "Start Time",
+		"Total Time (ms)", "Blocked (ms)", "DNS (ms)", "Connect (ms)", "SSL (ms)",
+		"Send (ms)", "Wait (ms)", "Receive (ms)", "Request Headers Size",
+		"Request Body Size", "Response Headers Size", "Response Body Size", "Content Size", "Tags", "Resource Type",
+	}
+	if err := writer.Write(headers); err != nil {
+		return fmt.Errorf("failed to write CSV headers: %w", err)
+	}
+
+	for i, harFile := range g.harFiles {
+		for _, entry := range harFile.Log.Entries {
+			record := []string{
+				g.FileName(i),
+				entry.Request.Method,
+				entry.Request.URL,
+				fmt.Sprintf("%d", entry.Response.Status),
+				har.EntryHost(entry),
+				entry.Response.Content.MimeType,
+				entry.StartedDateTime.Format(time.RFC3339Nano),
+				fmt.Sprintf("%.1f", entry.Time),
+				fmt.Sprintf("%d", entry.Timings.Blocked),
+				fmt.Sprintf("%d", entry.Timings.DNS),
+				fmt.Sprintf("%d", entry.Timings.Connect),
+				fmt.Sprintf("%d", entry.Timings.SSL),
+				fmt.Sprintf("%d", entry.Timings.Send),
+				fmt.Sprintf("%d", entry.Timings.Wait),
+				fmt.Sprintf("%d", entry.Timings.Receive),
+				fmt.Sprintf("%d", entry.Request.HeadersSize),
+				fmt.Sprintf("%d", entry.Request.BodySize),
+				fmt.Sprintf("%d", entry.Response.HeadersSize),
+				fmt.Sprintf("%d", entry.Response.BodySize),
+				fmt.Sprintf("%d", entry.Response.Content.Size),
+				strings.Join(entry.Tags, ";"),
+				har.EntryResourceType(entry),
+			}
+			if err := writer.Write(record); err != nil {
+				return fmt.Errorf("failed to write CSV record: %w", err)
+			}
+		}
+	}
+
+	return flushCSV(writer)
+}
+
+// EntryRecord is the flattened, per-entry shape used by the NDJSON export.
+type EntryRecord struct {
+	File            string   `json:"file"`
+	Index           int      `json:"index"`
+	StartedDateTime string   `json:"started_date_time"`
+	Method          string   `json:"method"`
+	URL             string   `json:"url"`
+	Domain          string   `json:"domain"`
+	Status          int      `json:"status"`
+	MimeType        string   `json:"mime_type"`
+	ResourceType    string   `json:"resource_type"`
+	HTTPVersion     string   `json:"http_version"`
+	TotalTime       float64  `json:"total_time_ms"`
+	Blocked         int      `json:"blocked_ms"`
+	DNS             int      `json:"dns_ms"`
+	Connect         int      `json:"connect_ms"`
+	SSL             int      `json:"ssl_ms"`
+	Send            int      `json:"send_ms"`
+	Wait            int      `json:"wait_ms"`
+	Receive         int      `json:"receive_ms"`
+	RequestSize     int      `json:"request_size"`
+	ContentSize     int      `json:"content_size"`
+	TransferSize    int      `json:"transfer_size"`
+	Tags            []string `json:"tags,omitempty"`
+}
+
+func newEntryRecord(file string, index int, entry har.Entry) EntryRecord {
+	return EntryRecord{
+		File:            file,
+		Index:           index,
+		StartedDateTime: entry.StartedDateTime.Format(time.RFC3339Nano),
+		Method:          entry.Request.Method,
+		URL:             entry.Request.URL,
+		Domain:          har.EntryHost(entry),
+		Status:          entry.Response.Status,
+		MimeType:        entry.Response.Content.MimeType,
+		ResourceType:    har.EntryResourceType(entry),
+		HTTPVersion:     entry.Response.HTTPVersion,
+		TotalTime:       entry.Time,
+		Blocked:         entry.Timings.Blocked,
+		DNS:             entry.Timings.DNS,
+		Connect:         entry.Timings.Connect,
+		SSL:             entry.Timings.SSL,
+		Send:            entry.Timings.Send,
+		Wait:            entry.Timings.Wait,
+		Receive:         entry.Timings.Receive,
+		RequestSize:     max(entry.Request.HeadersSize, 0) + max(entry.Request.BodySize, 0),
+		ContentSize:     entry.Response.Content.Size,
+		TransferSize:    transferSize(entry),
+		Tags:            entry.Tags,
+	}
+}
+
+// transferSize estimates bytes on the wire, falling back to the content size
+// when the capture does not record header and body sizes.
+func transferSize(entry har.Entry) int {
+	if entry.Response.HeadersSize < 0 && entry.Response.BodySize < 0 {
+		return entry.Response.Content.Size
+	}
+	return max(entry.Response.HeadersSize, 0) + max(entry.Response.BodySize, 0)
+}
+
+// EntryRecords flattens the entries of every file.
+func (g *Generator) EntryRecords() []EntryRecord {
+	records := []EntryRecord{}
+	for i, harFile := range g.harFiles {
+		name := g.FileName(i)
+		for j, entry := range harFile.Log.Entries {
+			records = append(records, newEntryRecord(name, j, entry))
+		}
+	}
+	return records
+}
+
+// ExportNDJSON streams every entry as one flattened JSON object per line.
+func (g *Generator) ExportNDJSON(filename string) error {
+	return exportFile(filename, "NDJSON", g.WriteNDJSON)
+}
+
+// WriteNDJSON streams every entry as one flattened JSON object per line.
+func (g *Generator) WriteNDJSON(w io.Writer) error {
+	writer := bufio.NewWriter(w)
+	encoder := json.NewEncoder(writer)
+
+	for i, harFile := range g.harFiles {
+		name := g.FileName(i)
+		for j, entry := range harFile.Log.Entries {
+			if err := encoder.Encode(newEntryRecord(name, j, entry)); err != nil {
+				return fmt.Errorf("failed to encode entry: %w", err)
+			}
+		}
+	}
+
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("failed to write NDJSON: %w", err)
+	}
+
+	return nil
+}
+
+func (g *Generator) ExportHTML(filename string) error {
+	return exportFile(filename, "HTML", g.WriteHTML)
+}
+
+// WriteHTML writes the standalone HTML report.
+func (g *Generator) WriteHTML(w io.Writer) error {
+	html, err := g.HTMLContent()
 	if err != nil {
-		return fmt.Errorf("failed to create HTML file: %w", err)
+		return err
 	}
-	defer file.Close()
 
-	if _, err := file.WriteString(html); err != nil {
+	if _, err := io.WriteString(w, html); err != nil {
 		return fmt.Errorf("failed to write HTML content: %w", err)
 	}
 
 	return nil
 }
 
-func (g *Generator) generateHTMLContent(report *Report) string {
+// HTMLContent renders the standalone HTML report, including the interactive explorer.
+func (g *Generator) HTMLContent() (string, error) {
+	html, err := g.generateHTMLContent(g.GenerateReport(false))
+	if err != nil || !g.plain {
+		return html, err
+	}
+	return Plain(html), nil
+}
+
+func (g *Generator) generateHTMLContent(report *Report) (string, error) {
 	var html strings.Builder
 
 	html.WriteString(`<!DOCTYPE html>
@@ -226,6 +691,12 @@ func (g *Generator) generateHTMLContent(report *Report) string {
             gap: 20px;
             margin: 20px 0;
         }
+        .executive-summary {
+            font-size: 16px;
+            padding: 15px 20px;
+            background: #f8f9fa;
+            border-left: 4px solid #007acc;
+        }
         .metric-card {
             background: #f8f9fa;
             padding: 20px;
@@ -256,6 +727,17 @@ func (g *Generator) generateHTMLContent(report *Report) string {
             font-weight: 600;
             color: #333;
         }
+        .heatmap th {
+            font-weight: normal;
+            font-family: monospace;
+            white-space: nowrap;
+        }
+        .heatmap td {
+            padding: 0;
+            width: 14px;
+            height: 18px;
+            border: 1px solid #fff;
+        }
         tr:hover {
             background-color: #f8f9fa;
         }
@@ -279,18 +761,23 @@ func (g *Generator) generateHTMLContent(report *Report) string {
         }
         .status-good { color: #28a745; }
         .status-warning { color: #ffc107; }
-        .status-danger { color: #dc3545; }
+        .status-danger { color: #dc3545; }` + interactiveStyles + `
     </style>
 </head>
 <body>
     <div class="container">
         <h1>⚓ Hartea Analysis Report - Ahoy Matey!</h1>
         <p><strong>Generated:</strong> ` + report.GeneratedAt.Format("January 2, 2006 at 3:04 PM") + `</p>
-        <p><strong>Files Analyzed:</strong> ` + strings.Join(report.Files, ", ") + `</p>`)
+        <p><strong>Files Analyzed:</strong> ` + template.HTMLEscapeString(strings.Join(report.FileLabels(), ", ")) + `</p>`)
 
 	// Summary section
 	html.WriteString(`
         <h2>📊 Executive Summary</h2>
+        <p class="executive-summary">` + template.HTMLEscapeString(report.ExecutiveSummary.Text) + `</p>`)
+
+	writeScoresHTML(&html, report)
+
+	html.WriteString(`
         <div class="summary">
             <div class="metric-card">
                 <div class="metric-value">` + fmt.Sprintf("%d", report.Summary.TotalFiles) + `</div>
@@ -309,7 +796,7 @@ func (g *Generator) generateHTMLContent(report *Report) string {
                 <div class="metric-label">Average TTFB</div>
             </div>
             <div class="metric-card">
-                <div class="metric-value">` + fmt.Sprintf("%.2fMB", report.Summary.TotalTransferMB) + `</div>
+                <div class="metric-value">` + fmt.Sprintf("%.2f%s", report.Summary.TotalTransferMB, report.SizeUnits.MegabyteUnit()) + `</div>
                 <div class="metric-label">Total Transfer Size</div>
             </div>
             <div class="metric-card">
@@ -330,12 +817,22 @@ func (g *Generator) generateHTMLContent(report *Report) string {
                     <th>Requests</th>
                     <th>Errors</th>
                     <th>Cache Hit %</th>
-                    <th>Size (MB)</th>
+                    <th>Size (` + report.SizeUnits.MegabyteUnit() + `)</th>
+                    <th>Repeat View</th>
                 </tr>
             </thead>
             <tbody>`)
 
 	for i, metrics := range report.Metrics {
+		if !metrics.HasData() {
+			html.WriteString(`
+                <tr>
+                    <td><strong>` + template.HTMLEscapeString(report.Files[i]) + `</strong></td>
+                    <td colspan="7" class="unchanged">N/A - no entries in this capture</td>
+                </tr>`)
+			continue
+		}
+
 		statusClass := getLoadTimeStatusClass(metrics.PageLoadTime)
 		ttfbClass := getTTFBStatusClass(metrics.TTFB)
 		errorClass := getErrorStatusClass(metrics.ErrorRequests)
@@ -349,20 +846,24 @@ func (g *Generator) generateHTMLContent(report *Report) string {
                     <td class="%s">%d</td>
                     <td>%.1f%%</td>
                     <td>%.2f</td>
+                    <td>%s</td>
                 </tr>`,
-			report.Files[i],
+			template.HTMLEscapeString(report.Files[i]),
 			statusClass, metrics.PageLoadTime,
 			ttfbClass, metrics.TTFB,
 			metrics.TotalRequests,
 			errorClass, metrics.ErrorRequests,
 			metrics.CacheHitRatio,
-			float64(metrics.TotalSize)/(1024*1024)))
+			report.SizeUnits.Megabytes(float64(metrics.TotalSize)),
+			repeatViewCell(metrics, report.SizeUnits)))
 	}
 
 	html.WriteString(`
             </tbody>
         </table>`)
 
+	writeWebVitalsHTML(&html, report)
+
 	// Comparison section (if available)
 	if report.Comparison != nil {
 		html.WriteString(`
@@ -370,7 +871,18 @@ func (g *Generator) generateHTMLContent(report *Report) string {
         <p><strong>Summary:</strong> ` + fmt.Sprintf("%d improvements, %d regressions, %d unchanged",
 			report.Comparison.Summary.BetterCount,
 			report.Comparison.Summary.WorseCount,
-			report.Comparison.Summary.UnchangedCount) + `</p>
+			report.Comparison.Summary.UnchangedCount) + `</p>`)
+
+		for _, warning := range report.Comparison.Warnings {
+			html.WriteString(`
+        <p class="status-warning">⚠️ ` + template.HTMLEscapeString(warning) + `</p>`)
+		}
+		for _, insight := range report.Comparison.Insights {
+			html.WriteString(`
+        <p class="` + insightClass(insight.Severity) + `">💡 ` + template.HTMLEscapeString(insight.String()) + `</p>`)
+		}
+
+		html.WriteString(`
         
         <table>
             <thead>
@@ -379,9 +891,9 @@ func (g *Generator) generateHTMLContent(report *Report) string {
 
 		for i, file := range report.Comparison.Files {
 			if i == 0 {
-				html.WriteString(`<th>` + file + ` (Base)</th>`)
+				html.WriteString(`<th>` + template.HTMLEscapeString(file) + ` (Base)</th>`)
 			} else {
-				html.WriteString(`<th>` + file + `</th>`)
+				html.WriteString(`<th>` + template.HTMLEscapeString(file) + `</th>`)
 			}
 		}
 
@@ -391,25 +903,22 @@ func (g *Generator) generateHTMLContent(report *Report) string {
             <tbody>`)
 
 		for _, diff := range report.Comparison.Differences {
-			html.WriteString(`<tr><td><strong>` + diff.Name + `</strong></td>`)
+			html.WriteString(`<tr><td><strong>` + template.HTMLEscapeString(diff.Name) + `</strong></td>`)
 
 			for i, value := range diff.Values {
 				if i == 0 {
-					html.WriteString(`<td>` + fmt.Sprintf("%v", value) + `</td>`)
+					html.WriteString(`<td>` + template.HTMLEscapeString(fmt.Sprint(value)) + `</td>`)
 				} else {
 					change := diff.Changes[i]
-					improvement := diff.Improvements[i]
 					class := "unchanged"
-					if change != "Baseline" && change != "No change" {
-						if improvement {
-							class = "improvement"
-							change += " ✅"
-						} else {
-							class = "regression"
-							change += " ⚠️"
-						}
+					if diff.Flagged(i) && diff.Improvements[i] {
+						class = "improvement"
+						change += " ✅"
+					} else if diff.Regressed(i) {
+						class = "regression"
+						change += " ⚠️"
 					}
-					html.WriteString(`<td>` + fmt.Sprintf("%v", value) + ` <span class="` + class + `">(` + change + `)</span></td>`)
+					html.WriteString(`<td>` + template.HTMLEscapeString(fmt.Sprint(value)) + ` <span class="` + class + `">(` + change + `)</span></td>`)
 				}
 			}
 
@@ -421,6 +930,29 @@ func (g *Generator) generateHTMLContent(report *Report) string {
         </table>`)
 	}
 
+	writeResourceTypes(&html, report)
+
+	if len(report.Heatmaps) > 0 {
+		writeHeatmaps(&html, report)
+	}
+
+	if len(report.VendorScorecards) > 0 {
+		writeVendorScorecards(&html, report)
+	}
+
+	if len(report.SLOs) > 0 {
+		writeSLOs(&html, report)
+	}
+
+	writeJSAudits(&html, report)
+
+	g.writeRecommendationsHTML(&html, report)
+
+	// Request explorer with sortable table and waterfall
+	if err := g.writeInteractiveSection(&html); err != nil {
+		return "", err
+	}
+
 	// Footer
 	html.WriteString(`
         <div class="footer">
@@ -431,7 +963,332 @@ func (g *Generator) generateHTMLContent(report *Report) string {
 </body>
 </html>`)
 
-	return html.String()
+	return html.String(), nil
+}
+
+// writeResourceTypes renders the requests, size and time of each resource
+// type per file.
+func writeResourceTypes(html *strings.Builder, report *Report) {
+	html.WriteString(`
+        <h2>🧩 Resource Types</h2>
+        <table>
+            <thead>
+                <tr>
+                    <th>File</th>
+                    <th>Type</th>
+                    <th>Requests</th>
+                    <th>Total Size</th>
+                    <th>Average Size</th>
+                    <th>Total Time</th>
+                </tr>
+            </thead>
+            <tbody>`)
+
+	labels := htmlEscapeAll(report.FileLabels())
+	for i, breakdown := range report.ResourceTypes {
+		for _, stats := range breakdown {
+			html.WriteString(fmt.Sprintf(`
+                <tr>
+                    <td>%s</td>
+                    <td><strong>%s</strong></td>
+                    <td>%d</td>
+                    <td>%s</td>
+                    <td>%s</td>
+                    <td>%.1fms</td>
+                </tr>`, labels[i], stats.Type, stats.Requests, report.SizeUnits.FormatSize(int(stats.Size)), report.SizeUnits.FormatSize(int(stats.AverageSize())), stats.Time))
+		}
+	}
+
+	html.WriteString(`
+            </tbody>
+        </table>`)
+}
+
+const (
+	reportHeatmapBuckets   = 30
+	reportHeatmapEndpoints = 20
+)
+
+// heatmapColors has one cell color per har.HeatmapLevel, green to red.
+var heatmapColors = []string{"#4caf50", "#cddc39", "#ffc107", "#ff9800", "#f44336"}
+
+// writeHeatmaps renders the p95 latency of each file's busiest endpoints
+// over time as a grid of colored cells.
+func writeHeatmaps(html *strings.Builder, report *Report) {
+	html.WriteString(`
+        <h2>🌡️ Response Time Heatmap</h2>
+        <p>p95 response time of the busiest endpoints over the capture. ▲ marks endpoints whose p95 grew ` +
+		fmt.Sprintf("%.1fx", har.DegradedRatio) + ` or more from the first to the last third.</p>`)
+
+	labels := htmlEscapeAll(report.FileLabels())
+	for i, heatmap := range report.Heatmaps {
+		if heatmap == nil || len(heatmap.Endpoints) == 0 {
+			continue
+		}
+		html.WriteString(fmt.Sprintf(`
+        <h3>%s <small>(%s per column)</small></h3>
+        <table class="heatmap">
+            <tbody>`, labels[i], heatmap.BucketWidth.Round(time.Millisecond)))
+		for j, endpoint := range heatmap.Endpoints {
+			marker := ""
+			if heatmap.Degrading(j) {
+				marker = fmt.Sprintf(` <span class="status-danger">▲ %.1fx</span>`, heatmap.Degradation(j))
+			}
+			html.WriteString(`
+                <tr><th>` + template.HTMLEscapeString(endpoint) + marker + `</th>`)
+			for k, p95 := range heatmap.P95[j] {
+				if p95 < 0 {
+					html.WriteString(`<td></td>`)
+					continue
+				}
+				html.WriteString(fmt.Sprintf(`<td style="background:%s" title="%s: p95 %.1fms, %d requests"></td>`,
+					heatmapColors[har.HeatmapLevel(p95)],
+					(heatmap.BucketWidth * time.Duration(k)).Round(time.Second), p95, heatmap.Counts[j][k]))
+			}
+			html.WriteString(`</tr>`)
+		}
+		html.WriteString(`
+            </tbody>
+        </table>`)
+	}
+
+	var legend []string
+	for level, color := range heatmapColors {
+		label := fmt.Sprintf("≥%.0fms", har.HeatmapThresholds[len(har.HeatmapThresholds)-1])
+		if level < len(har.HeatmapThresholds) {
+			label = fmt.Sprintf("&lt;%.0fms", har.HeatmapThresholds[level])
+		}
+		legend = append(legend, fmt.Sprintf(`<span style="color:%s">■</span> %s`, color, label))
+	}
+	html.WriteString(`
+        <p>` + strings.Join(legend, " &nbsp; ") + `</p>`)
+}
+
+// writeVendorScorecards renders a table of vendor usage against budgets per file.
+func writeVendorScorecards(html *strings.Builder, report *Report) {
+	html.WriteString(`
+        <h2>🏷️ Vendor Scorecards</h2>
+        <table>
+            <thead>
+                <tr>
+                    <th>File</th>
+                    <th>Vendor</th>
+                    <th>Requests</th>
+                    <th>Size</th>
+                    <th>Time</th>
+                    <th>Budget</th>
+                    <th>Status</th>
+                </tr>
+            </thead>
+            <tbody>`)
+
+	labels := htmlEscapeAll(report.FileLabels())
+	for i, scorecards := range report.VendorScorecards {
+		for _, scorecard := range scorecards {
+			requestClass, sizeClass, status := "", "", `<span class="status-good">✅ Within budget</span>`
+			if scorecard.OverRequests() {
+				requestClass = "status-danger"
+			}
+			if scorecard.OverBytes() {
+				sizeClass = "status-danger"
+			}
+			if !scorecard.Pass() {
+				status = `<span class="status-danger">❌ Over budget</span>`
+			}
+			html.WriteString(fmt.Sprintf(`
+                <tr>
+                    <td>%s</td>
+                    <td><strong>%s</strong><br><small>%s</small></td>
+                    <td class="%s">%d</td>
+                    <td class="%s">%s</td>
+                    <td>%.1fms</td>
+                    <td>%s</td>
+                    <td>%s</td>
+                </tr>`,
+				labels[i],
+				template.HTMLEscapeString(scorecard.Budget.Vendor), template.HTMLEscapeString(strings.Join(scorecard.Budget.Domains, ", ")),
+				requestClass, scorecard.Requests,
+				sizeClass, report.SizeUnits.FormatSize(int(scorecard.Bytes)),
+				scorecard.Time,
+				scorecard.Budget.Limits(report.SizeUnits),
+				status))
+		}
+	}
+
+	html.WriteString(`
+            </tbody>
+        </table>`)
+}
+
+// writeSLOs renders a table of every objective of every SLO per file.
+func writeSLOs(html *strings.Builder, report *Report) {
+	html.WriteString(`
+        <h2>🎯 Service Level Objectives</h2>
+        <table>
+            <thead>
+                <tr>
+                    <th>File</th>
+                    <th>SLO</th>
+                    <th>Requests</th>
+                    <th>Apdex</th>
+                    <th>Objective</th>
+                    <th>Actual</th>
+                    <th>Target</th>
+                    <th>Status</th>
+                </tr>
+            </thead>
+            <tbody>`)
+
+	labels := htmlEscapeAll(report.FileLabels())
+	for i, results := range report.SLOs {
+		for _, result := range results {
+			for _, objective := range result.Objectives {
+				status := `<span class="status-good">✅ Met</span>`
+				if !objective.Pass {
+					status = `<span class="status-danger">❌ Missed</span>`
+				}
+				html.WriteString(fmt.Sprintf(`
+                <tr>
+                    <td>%s</td>
+                    <td><strong>%s</strong><br><small>%s</small></td>
+                    <td>%d</td>
+                    <td>%.2f</td>
+                    <td>%s</td>
+                    <td>%s</td>
+                    <td>%s</td>
+                    <td>%s</td>
+                </tr>`,
+					labels[i],
+					template.HTMLEscapeString(result.SLO.Name), template.HTMLEscapeString(result.SLO.Scope),
+					result.Requests,
+					result.Apdex,
+					template.HTMLEscapeString(objective.Name), objective.Actual, objective.Target,
+					status))
+			}
+		}
+	}
+
+	html.WriteString(`
+            </tbody>
+        </table>`)
+}
+
+// jsAuditBundles caps the bundles listed per file in the JS audit.
+const jsAuditBundles = 15
+
+// writeJSAudits renders the JavaScript payload of each file by host and
+// bundle, with its duplicate libraries and leaked source maps.
+func writeJSAudits(html *strings.Builder, report *Report) {
+	labels := htmlEscapeAll(report.FileLabels())
+	written := false
+	for i, audit := range report.JSAudits {
+		if !audit.HasData() && len(audit.SourceMaps) == 0 {
+			continue
+		}
+		if !written {
+			html.WriteString(`
+        <h2>📜 JS Audit</h2>`)
+			written = true
+		}
+
+		html.WriteString(fmt.Sprintf(`
+        <h3>%s</h3>
+        <p>%d script requests, %s decoded, %s transferred</p>
+        <table>
+            <thead>
+                <tr>
+                    <th>Host</th>
+                    <th>Bundles</th>
+                    <th>Requests</th>
+                    <th>Size (%s)</th>
+                    <th>Transferred (%s)</th>
+                </tr>
+            </thead>
+            <tbody>`, labels[i], audit.Requests, report.SizeUnits.FormatSize(int(audit.Bytes)), report.SizeUnits.FormatSize(int(audit.Transfer)), report.SizeUnits.KilobyteUnit(), report.SizeUnits.KilobyteUnit()))
+		for _, domain := range audit.Domains {
+			html.WriteString(fmt.Sprintf(`
+                <tr><td>%s</td><td>%d</td><td>%d</td><td>%.1f</td><td>%.1f</td></tr>`,
+				template.HTMLEscapeString(domain.Domain), domain.Bundles, domain.Requests,
+				report.SizeUnits.Kilobytes(float64(domain.Bytes)), report.SizeUnits.Kilobytes(float64(domain.Transfer))))
+		}
+		html.WriteString(`
+            </tbody>
+        </table>
+        <table>
+            <thead>
+                <tr>
+                    <th>Bundle</th>
+                    <th>Host</th>
+                    <th>Requests</th>
+                    <th>Size (` + report.SizeUnits.KilobyteUnit() + `)</th>
+                    <th>Status</th>
+                </tr>
+            </thead>
+            <tbody>`)
+		for _, bundle := range audit.Bundles[:min(len(audit.Bundles), jsAuditBundles)] {
+			status := `<span class="status-good">OK</span>`
+			if bundle.Large {
+				status = fmt.Sprintf(`<span class="status-warning">⚠️ Over %s</span>`, report.SizeUnits.FormatSize(har.LargeBundleBytes))
+			}
+			html.WriteString(fmt.Sprintf(`
+                <tr><td>%s</td><td>%s</td><td>%d</td><td>%.1f</td><td>%s</td></tr>`,
+				template.HTMLEscapeString(bundle.Name), template.HTMLEscapeString(bundle.Domain),
+				bundle.Requests, report.SizeUnits.Kilobytes(float64(bundle.Bytes)), status))
+		}
+		html.WriteString(`
+            </tbody>
+        </table>`)
+		if len(audit.Bundles) > jsAuditBundles {
+			html.WriteString(fmt.Sprintf(`
+        <p>... and %d more bundles</p>`, len(audit.Bundles)-jsAuditBundles))
+		}
+
+		for _, library := range audit.DuplicateLibraries {
+			detail := strings.Join(library.Hosts, ", ")
+			if len(library.Versions) > 1 {
+				detail += "; versions " + strings.Join(library.Versions, ", ")
+			}
+			html.WriteString(fmt.Sprintf(`
+        <p class="status-warning">⚠️ %s loaded %d times (%s)</p>`,
+				library.Library, library.Requests, template.HTMLEscapeString(detail)))
+		}
+		for _, sourceMap := range audit.SourceMaps {
+			html.WriteString(`
+        <p class="status-danger">⚠️ Source map served in production: ` + template.HTMLEscapeString(sourceMap.URL) + `</p>`)
+		}
+	}
+}
+
+// writeRecommendationsHTML lists the recommendations of the PDF report,
+// followed by the resource hints to paste into each file's document.
+func (g *Generator) writeRecommendationsHTML(html *strings.Builder, report *Report) {
+	html.WriteString(`
+        <h2>💡 Recommendations</h2>
+        <ul>`)
+	recommendations := g.generateRecommendations(report)
+	if len(recommendations) == 0 {
+		recommendations = []string{"Performance metrics are within acceptable ranges"}
+	}
+	for _, recommendation := range recommendations {
+		html.WriteString(`
+            <li>` + template.HTMLEscapeString(recommendation) + `</li>`)
+	}
+	html.WriteString(`
+        </ul>`)
+
+	labels := htmlEscapeAll(report.FileLabels())
+	for i, hints := range report.ResourceHints {
+		if len(hints) == 0 {
+			continue
+		}
+		var tags []string
+		for _, hint := range hints {
+			tags = append(tags, hint.Tag())
+		}
+		html.WriteString(`
+        <p><strong>Resource hints for ` + labels[i] + `</strong></p>
+        <pre><code>` + template.HTMLEscapeString(strings.Join(tags, "\n")) + `</code></pre>`)
+	}
 }
 
 func getLoadTimeStatusClass(loadTime float64) string {
@@ -443,6 +1300,142 @@ func getLoadTimeStatusClass(loadTime float64) string {
 	return "status-danger"
 }
 
+// insightClass colours an insight by severity.
+func insightClass(severity har.InsightSeverity) string {
+	switch severity {
+	case har.InsightCritical:
+		return "status-danger"
+	case har.InsightWarning:
+		return "status-warning"
+	}
+	return "status-good"
+}
+
+// writeScoresHTML writes the score, grade and category grades of every file.
+func writeScoresHTML(html *strings.Builder, report *Report) {
+	if len(report.Scores) == 0 {
+		return
+	}
+
+	html.WriteString(`
+        <table>
+            <thead>
+                <tr>
+                    <th>File</th>
+                    <th>Score</th>`)
+	for _, category := range report.Scores[0].Categories {
+		html.WriteString(`<th>` + template.HTMLEscapeString(category.Name) + `</th>`)
+	}
+	html.WriteString(`
+                </tr>
+            </thead>
+            <tbody>`)
+
+	for i, score := range report.Scores {
+		html.WriteString(fmt.Sprintf(`
+                <tr>
+                    <td><strong>%s</strong></td>
+                    <td class="%s"><strong>%s</strong> (%.0f/100)</td>`, template.HTMLEscapeString(report.Files[i]), getGradeStatusClass(score.Grade), score.Grade, score.Score))
+		for _, category := range score.Categories {
+			html.WriteString(fmt.Sprintf(`<td class="%s">%s</td>`, getGradeStatusClass(category.Grade), category.Grade))
+		}
+		html.WriteString(`
+                </tr>`)
+	}
+
+	html.WriteString(`
+            </tbody>
+        </table>`)
+}
+
+// scoreLine renders the score and category grades of a summary, e.g.
+// "Score 87/100 (B) - Network A, Caching C, Payload B, Third-party A".
+func scoreLine(summary har.ExecutiveSummary) string {
+	grades := make([]string, len(summary.Categories))
+	for i, category := range summary.Categories {
+		grades[i] = category.Name + " " + category.Grade
+	}
+	return fmt.Sprintf("Score %.0f/100 (%s) - %s", summary.Score, summary.Grade, strings.Join(grades, ", "))
+}
+
+func getGradeStatusClass(grade string) string {
+	switch grade {
+	case "A", "B":
+		return "status-good"
+	case "C", "D":
+		return "status-warning"
+	case "F":
+		return "status-danger"
+	}
+	return "unchanged"
+}
+
+// writeWebVitalsHTML writes the Core Web Vitals of every file rated against
+// the web.dev thresholds, if any file measured them.
+func writeWebVitalsHTML(html *strings.Builder, report *Report) {
+	var descriptors []har.MetricDescriptor
+	for _, id := range har.WebVitalIDs {
+		descriptor, ok := har.LookupMetric(id)
+		if !ok {
+			continue
+		}
+		for _, metrics := range report.Metrics {
+			if descriptor.Measured(metrics) {
+				descriptors = append(descriptors, descriptor)
+				break
+			}
+		}
+	}
+	if len(descriptors) == 0 {
+		return
+	}
+
+	html.WriteString(`
+        <h2>🎯 Core Web Vitals</h2>
+        <table>
+            <thead>
+                <tr>
+                    <th>File</th>`)
+	for _, descriptor := range descriptors {
+		html.WriteString(`<th>` + template.HTMLEscapeString(descriptor.Name) + `</th>`)
+	}
+	html.WriteString(`
+                </tr>
+            </thead>
+            <tbody>`)
+
+	for i, metrics := range report.Metrics {
+		html.WriteString(`
+                <tr>
+                    <td><strong>` + template.HTMLEscapeString(report.Files[i]) + `</strong></td>`)
+		for _, descriptor := range descriptors {
+			if !descriptor.Measured(metrics) {
+				html.WriteString(`<td class="unchanged">N/A</td>`)
+				continue
+			}
+			value := descriptor.Value(metrics)
+			rating, _ := har.RateVital(descriptor.ID, value)
+			html.WriteString(fmt.Sprintf(`<td class="%s">%s (%s)</td>`, getVitalStatusClass(rating), descriptor.Format(value, report.SizeUnits), rating))
+		}
+		html.WriteString(`
+                </tr>`)
+	}
+
+	html.WriteString(`
+            </tbody>
+        </table>`)
+}
+
+func getVitalStatusClass(rating har.VitalRating) string {
+	switch rating {
+	case har.VitalGood:
+		return "status-good"
+	case har.VitalNeedsImprovement:
+		return "status-warning"
+	}
+	return "status-danger"
+}
+
 func getTTFBStatusClass(ttfb float64) string {
 	if ttfb <= 200 {
 		return "status-good"
@@ -462,19 +1455,33 @@ func getErrorStatusClass(errors int) string {
 }
 
 func (g *Generator) ExportPDF(filename string) error {
-	// First generate HTML
-	htmlFile := strings.TrimSuffix(filename, filepath.Ext(filename)) + ".html"
-	if err := g.ExportHTML(htmlFile); err != nil {
-		return fmt.Errorf("failed to generate HTML for PDF: %w", err)
+	return exportFile(filename, "PDF", g.WritePDF)
+}
+
+// WritePDF writes the report as a PDF document.
+func (g *Generator) WritePDF(w io.Writer) error {
+	if err := g.generateNativePDF(g.GenerateReport(false), w); err != nil {
+		return fmt.Errorf("failed to generate PDF: %w", err)
 	}
+	return nil
+}
 
-	// Convert HTML to PDF using gofpdf (native approach)
-	return g.convertHTMLToPDF(htmlFile, filename)
+// repeatViewCell shows the simulated warm-cache load time and size of a
+// capture, in the units of the cold-load columns beside it.
+func repeatViewCell(metrics *har.Metrics, units har.SizeUnits) string {
+	size := fmt.Sprintf("%.2f %s", units.Megabytes(float64(metrics.RepeatViewSize)), units.MegabyteUnit())
+	if metrics.PageLoadTime == 0 {
+		return size
+	}
+	return fmt.Sprintf("%.1fms / %s", metrics.RepeatViewLoadTime, size)
 }
 
-func (g *Generator) convertHTMLToPDF(htmlFile, pdfFile string) error {
-	// For this implementation, we'll create a native PDF report
-	// rather than converting HTML, which gives us better control
-	report := g.GenerateReport(false)
-	return g.generateNativePDF(report, pdfFile)
+// htmlEscapeAll escapes each value for HTML, e.g. file labels given with
+// --label.
+func htmlEscapeAll(values []string) []string {
+	escaped := make([]string, len(values))
+	for i, value := range values {
+		escaped[i] = template.HTMLEscapeString(value)
+	}
+	return escaped
 }
@@ -0,0 +1,94 @@
+package report
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jlgore/hartea/internal/har"
+)
+
+// metricRow returns the text from a metric's name up to the end of its line
+// or table row.
+func metricRow(t *testing.T, output, name, end string) string {
+	t.Helper()
+	start := strings.Index(output, name)
+	if start < 0 {
+		t.Fatalf("no %s row in:\n%s", name, output)
+	}
+	row := output[start:]
+	if stop := strings.Index(row, end); stop >= 0 {
+		row = row[:stop]
+	}
+	return row
+}
+
+func TestNeutralChangesAreNotShownAsRegressions(t *testing.T) {
+	parser := har.NewParser()
+	harFiles, err := parser.ParseMultipleFiles([]string{"../../example.har", "../../example2.har"})
+	if err != nil {
+		t.Fatalf("parsing example captures: %v", err)
+	}
+	if len(harFiles[0].Log.Entries) == len(harFiles[1].Log.Entries) {
+		t.Fatal("the example captures need different request counts")
+	}
+
+	html, err := NewGeneratorFromHAR(harFiles, []string{"before", "after"}, har.Settings{}).HTMLContent()
+	if err != nil {
+		t.Fatalf("generating HTML: %v", err)
+	}
+	if row := metricRow(t, html, "<strong>Total Requests</strong>", "</tr>"); strings.Contains(row, "regression") || strings.Contains(row, "⚠️") {
+		t.Errorf("HTML shows the request count change as a regression: %s", row)
+	}
+
+	markdown := NewBeforeAfter(harFiles[0], harFiles[1], [2]string{"before", "after"}, har.MatchExact, har.Settings{}).Markdown()
+	if row := metricRow(t, markdown, "| Total Requests |", "\n"); strings.Contains(row, "❌") {
+		t.Errorf("bundle shows the request count change as a regression: %s", row)
+	}
+}
+
+func TestRegisteredMetricReachesComparisonAndReports(t *testing.T) {
+	bytesPerRequest := har.MetricDescriptor{
+		ID:        "bytes_per_request",
+		Name:      "Bytes per Request",
+		Kind:      har.SizeMetric,
+		Direction: har.LowerIsBetter,
+		Value:     func(m *har.Metrics) float64 { return float64(m.TotalSize) / float64(m.TotalRequests) },
+	}
+	t.Cleanup(har.RegisterMetric(bytesPerRequest))
+
+	harFiles, err := har.NewParser().ParseMultipleFiles([]string{"../../example.har", "../../example2.har"})
+	if err != nil {
+		t.Fatalf("parsing example captures: %v", err)
+	}
+	generator := NewGeneratorFromHAR(harFiles, []string{"before", "after"}, har.Settings{})
+
+	report := generator.GenerateReport(false)
+	var diff *har.MetricDifference
+	for i := range report.Comparison.Differences {
+		if report.Comparison.Differences[i].ID == bytesPerRequest.ID {
+			diff = &report.Comparison.Differences[i]
+		}
+	}
+	if diff == nil {
+		t.Fatal("the registered metric was not compared")
+	}
+	if want := bytesPerRequest.Format(bytesPerRequest.Value(report.Metrics[1]), ""); diff.Values[1] != want {
+		t.Errorf("compared value = %v, want %s", diff.Values[1], want)
+	}
+
+	html, err := generator.HTMLContent()
+	if err != nil {
+		t.Fatalf("generating HTML: %v", err)
+	}
+	if row := metricRow(t, html, "<strong>Bytes per Request</strong>", "</tr>"); !strings.Contains(row, diff.Changes[1]) {
+		t.Errorf("HTML row %q lacks the change %q", row, diff.Changes[1])
+	}
+
+	var csv strings.Builder
+	if err := generator.WriteCSV(&csv); err != nil {
+		t.Fatalf("writing CSV: %v", err)
+	}
+	if header, _, _ := strings.Cut(csv.String(), "\n"); !strings.Contains(header, "Bytes per Request (MiB)") {
+		t.Errorf("CSV header lacks the registered metric: %s", header)
+	}
+}
@@ -0,0 +1,82 @@
+package report
+
+import (
+	"github.com/jlgore/hartea/internal/har"
+	"github.com/jlgore/hartea/internal/store"
+	"sort"
+	"time"
+)
+
+// GrafanaSeries is one timeseries in the shape Grafana's JSON datasource
+// expects from /query: datapoints are [value, unix milliseconds] pairs.
+type GrafanaSeries struct {
+	Target     string       `json:"target"`
+	Datapoints [][2]float64 `json:"datapoints"`
+}
+
+// GrafanaMetric is a selectable metric for the datasource's /metrics call.
+type GrafanaMetric struct {
+	Label string `json:"label"`
+	Value string `json:"value"`
+}
+
+// GrafanaMetrics lists the metric IDs recorded in the history, in registry order.
+func GrafanaMetrics(snapshots []store.Snapshot) []GrafanaMetric {
+	recorded := make(map[string]bool)
+	for _, snapshot := range snapshots {
+		for id := range snapshot.Metrics {
+			recorded[id] = true
+		}
+	}
+
+	var metrics []GrafanaMetric
+	for _, descriptor := range har.MetricDescriptors() {
+		if recorded[descriptor.ID] {
+			metrics = append(metrics, GrafanaMetric{Label: descriptor.Name, Value: descriptor.ID})
+		}
+	}
+	return metrics
+}
+
+// GrafanaTimeseries returns one series per metric and snapshot label within
+// [from, to]. A zero from or to leaves that side of the range open.
+func GrafanaTimeseries(snapshots []store.Snapshot, metricID string, from, to time.Time) []GrafanaSeries {
+	byLabel := make(map[string]*GrafanaSeries)
+	var labels []string
+
+	for _, snapshot := range snapshots {
+		if (!from.IsZero() && snapshot.Time.Before(from)) || (!to.IsZero() && snapshot.Time.After(to)) {
+			continue
+		}
+		value, ok := snapshot.Metrics[metricID]
+		if !ok {
+			continue
+		}
+
+		series, ok := byLabel[snapshot.Label]
+		if !ok {
+			series = &GrafanaSeries{Target: grafanaTarget(metricID, snapshot.Label), Datapoints: [][2]float64{}}
+			byLabel[snapshot.Label] = series
+			labels = append(labels, snapshot.Label)
+		}
+		series.Datapoints = append(series.Datapoints, [2]float64{value, float64(snapshot.Time.UnixMilli())})
+	}
+
+	sort.Strings(labels)
+	result := make([]GrafanaSeries, 0, len(labels))
+	for _, label := range labels {
+		result = append(result, *byLabel[label])
+	}
+	return result
+}
+
+func grafanaTarget(metricID, label string) string {
+	name := metricID
+	if descriptor, ok := har.LookupMetric(metricID); ok {
+		name = descriptor.Name
+	}
+	if label == "" {
+		return name
+	}
+	return name + " - " + label
+}
@@ -0,0 +1,26 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/jlgore/hartea/internal/har"
+	"io"
+)
+
+// ExportHAR writes a HAR file, e.g. with some of a capture's entries.
+func ExportHAR(filename string, harFile *har.HAR) error {
+	return exportFile(filename, "HAR", func(w io.Writer) error {
+		return WriteHAR(w, harFile)
+	})
+}
+
+// WriteHAR writes an indented HAR document. Excluded and hidden entries are
+// left out.
+func WriteHAR(w io.Writer, harFile *har.HAR) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(harFile); err != nil {
+		return fmt.Errorf("failed to encode HAR: %w", err)
+	}
+	return nil
+}
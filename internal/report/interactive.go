@@ -0,0 +1,250 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type interactiveEntry struct {
+	EntryRecord
+	OffsetMs float64 `json:"offset_ms"`
+}
+
+type interactiveFile struct {
+	Name    string             `json:"name"`
+	Entries []interactiveEntry `json:"entries"`
+}
+
+// interactiveData flattens every entry with its start offset relative to the
+// first request of its file, for the embedded request explorer.
+func (g *Generator) interactiveData() []interactiveFile {
+	files := make([]interactiveFile, len(g.harFiles))
+
+	for i, harFile := range g.harFiles {
+		name := g.FileName(i)
+		files[i] = interactiveFile{Name: name, Entries: []interactiveEntry{}}
+
+		var start time.Time
+		for _, entry := range harFile.Log.Entries {
+			if start.IsZero() || entry.StartedDateTime.Before(start) {
+				start = entry.StartedDateTime
+			}
+		}
+
+		for j, entry := range harFile.Log.Entries {
+			files[i].Entries = append(files[i].Entries, interactiveEntry{
+				EntryRecord: newEntryRecord(name, j, entry),
+				OffsetMs:    entry.StartedDateTime.Sub(start).Seconds() * 1000,
+			})
+		}
+	}
+
+	return files
+}
+
+func (g *Generator) writeInteractiveSection(html *strings.Builder) error {
+	data, err := json.Marshal(g.interactiveData())
+	if err != nil {
+		return fmt.Errorf("failed to encode entries: %w", err)
+	}
+
+	html.WriteString(`
+        <h2>🔎 Request Explorer</h2>
+        <div class="explorer-controls">
+            <select id="explorer-file"></select>
+            <input id="explorer-filter" type="search" placeholder="Filter by URL, method, status, type or tag:name...">
+            <span id="explorer-count" class="metric-label"></span>
+        </div>
+        <table id="explorer-table" data-size-base="` + fmt.Sprintf("%.0f", g.settings.SizeUnits.Base()) + `" data-size-units="` + g.settings.SizeUnits.KilobyteUnit() + " " + g.settings.SizeUnits.MegabyteUnit() + `">
+            <thead>
+                <tr>
+                    <th data-key="method">Method</th>
+                    <th data-key="status">Status</th>
+                    <th data-key="url">URL</th>
+                    <th data-key="mime_type">Type</th>
+                    <th data-key="total_time_ms">Time</th>
+                    <th data-key="content_size">Size</th>
+                    <th data-key="offset_ms" class="waterfall-col">Waterfall</th>
+                </tr>
+            </thead>
+            <tbody></tbody>
+        </table>
+        <script type="application/json" id="hartea-data">`)
+	html.Write(data)
+	html.WriteString(`</script>
+        <script>` + interactiveScript + `</script>`)
+
+	return nil
+}
+
+const interactiveStyles = `
+        .explorer-controls {
+            display: flex;
+            gap: 12px;
+            align-items: center;
+            margin: 10px 0;
+        }
+        .explorer-controls input {
+            flex: 1;
+            padding: 8px;
+            border: 1px solid #ddd;
+            border-radius: 4px;
+        }
+        .explorer-controls select {
+            padding: 8px;
+        }
+        #explorer-table th[data-key] {
+            cursor: pointer;
+            user-select: none;
+        }
+        #explorer-table th.sorted-asc::after { content: " ▲"; }
+        #explorer-table th.sorted-desc::after { content: " ▼"; }
+        #explorer-table td.url {
+            max-width: 420px;
+            overflow: hidden;
+            text-overflow: ellipsis;
+            white-space: nowrap;
+        }
+        .waterfall-col { width: 30%; }
+        .waterfall-track {
+            position: relative;
+            height: 14px;
+            background: #f1f3f5;
+            border-radius: 2px;
+        }
+        .waterfall-bar {
+            position: absolute;
+            height: 100%;
+            min-width: 2px;
+            border-radius: 2px;
+        }
+        .waterfall-bar.html { background: #007acc; }
+        .waterfall-bar.javascript { background: #e6a200; }
+        .waterfall-bar.css { background: #28a745; }
+        .waterfall-bar.image { background: #9c27b0; }
+        .waterfall-bar.json { background: #17a2b8; }
+        .waterfall-bar.font { background: #6c757d; }
+        .waterfall-bar.other { background: #adb5bd; }
+        .waterfall-bar.redirect { background: #ffc107; }
+        .waterfall-bar.error { background: #dc3545; }`
+
+const interactiveScript = `
+(function () {
+    var files = JSON.parse(document.getElementById("hartea-data").textContent);
+    var select = document.getElementById("explorer-file");
+    var filter = document.getElementById("explorer-filter");
+    var count = document.getElementById("explorer-count");
+    var table = document.getElementById("explorer-table");
+    var body = table.querySelector("tbody");
+    var sortKey = "offset_ms";
+    var sortAsc = true;
+
+    files.forEach(function (file, i) {
+        var option = document.createElement("option");
+        option.value = i;
+        option.textContent = file.name + " (" + file.entries.length + " requests)";
+        select.appendChild(option);
+    });
+
+    function category(entry) {
+        if (entry.status >= 400) return "error";
+        if (entry.status >= 300) return "redirect";
+        var types = ["html", "javascript", "css", "image", "json", "font"];
+        for (var i = 0; i < types.length; i++) {
+            if ((entry.mime_type || "").indexOf(types[i]) !== -1) return types[i];
+        }
+        return "other";
+    }
+
+    // Sizes use the units the report was exported with, KiB or kB
+    function formatSize(size) {
+        var base = Number(table.dataset.sizeBase);
+        var units = table.dataset.sizeUnits.split(" ");
+        if (size < base) return size + "B";
+        if (size < base * base) return (size / base).toFixed(1) + units[0];
+        return (size / (base * base)).toFixed(1) + units[1];
+    }
+
+    function cell(row, text, className) {
+        var td = document.createElement("td");
+        td.textContent = text;
+        if (className) td.className = className;
+        row.appendChild(td);
+        return td;
+    }
+
+    function render() {
+        var file = files[select.value || 0];
+        if (!file) return;
+        var query = filter.value.toLowerCase();
+        var entries = file.entries.filter(function (entry) {
+            if (query.indexOf("tag:") === 0) {
+                return (entry.tags || []).some(function (tag) {
+                    return tag.toLowerCase() === query.slice(4);
+                });
+            }
+            return !query || [entry.method, entry.url, String(entry.status), entry.mime_type]
+                .concat(entry.tags || []).join(" ").toLowerCase().indexOf(query) !== -1;
+        });
+
+        entries.sort(function (a, b) {
+            var x = a[sortKey], y = b[sortKey];
+            var result = typeof x === "string" ? x.localeCompare(y) : x - y;
+            return sortAsc ? result : -result;
+        });
+
+        var end = 1;
+        file.entries.forEach(function (entry) {
+            end = Math.max(end, entry.offset_ms + entry.total_time_ms);
+        });
+
+        body.innerHTML = "";
+        entries.forEach(function (entry) {
+            var row = document.createElement("tr");
+            cell(row, entry.method);
+            cell(row, entry.status, entry.status >= 400 ? "status-danger" : "");
+            cell(row, entry.url, "url").title = entry.url;
+            cell(row, entry.mime_type);
+            cell(row, entry.total_time_ms.toFixed(1) + "ms");
+            cell(row, formatSize(entry.content_size));
+
+            var track = document.createElement("div");
+            track.className = "waterfall-track";
+            var bar = document.createElement("div");
+            bar.className = "waterfall-bar " + category(entry);
+            bar.style.left = (entry.offset_ms / end * 100) + "%";
+            bar.style.width = (entry.total_time_ms / end * 100) + "%";
+            bar.title = "start +" + entry.offset_ms.toFixed(0) + "ms | blocked " + entry.blocked_ms +
+                "ms, dns " + entry.dns_ms + "ms, connect " + entry.connect_ms + "ms, ssl " + entry.ssl_ms +
+                "ms, send " + entry.send_ms + "ms, wait " + entry.wait_ms + "ms, receive " + entry.receive_ms + "ms";
+            track.appendChild(bar);
+            cell(row, "").appendChild(track);
+
+            body.appendChild(row);
+        });
+
+        count.textContent = entries.length + " of " + file.entries.length + " requests";
+        table.querySelectorAll("th[data-key]").forEach(function (th) {
+            th.classList.remove("sorted-asc", "sorted-desc");
+            if (th.dataset.key === sortKey) th.classList.add(sortAsc ? "sorted-asc" : "sorted-desc");
+        });
+    }
+
+    table.querySelectorAll("th[data-key]").forEach(function (th) {
+        th.addEventListener("click", function () {
+            if (sortKey === th.dataset.key) {
+                sortAsc = !sortAsc;
+            } else {
+                sortKey = th.dataset.key;
+                sortAsc = true;
+            }
+            render();
+        });
+    });
+    select.addEventListener("change", render);
+    filter.addEventListener("input", render);
+    render();
+})();
+`
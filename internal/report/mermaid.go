@@ -0,0 +1,128 @@
+package report
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/jlgore/hartea/internal/har"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Mermaid gets slow to lay out long diagrams, so a diagram shows at most
+// mermaidMaxEntries requests and paths are shortened to mermaidPathLength.
+const (
+	mermaidMaxEntries = 200
+	mermaidPathLength = 60
+)
+
+// ExportMermaid writes every file's requests as Mermaid sequence diagrams,
+// see WriteMermaid.
+func (g *Generator) ExportMermaid(filename string) error {
+	return exportFile(filename, "Mermaid", g.WriteMermaid)
+}
+
+// WriteMermaid writes a Markdown document with one Mermaid sequence diagram
+// per file, ready to paste into docs or pull requests.
+func (g *Generator) WriteMermaid(w io.Writer) error {
+	for i, harFile := range g.harFiles {
+		if i > 0 {
+			if _, err := io.WriteString(w, "\n"); err != nil {
+				return fmt.Errorf("failed to write Mermaid diagram: %w", err)
+			}
+		}
+		if err := WriteMermaidSequence(w, g.FileName(i), harFile.Log.Entries, g.settings.SizeUnits); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ExportMermaidSequence writes the entries as a Mermaid sequence diagram,
+// e.g. the requests a filter matched.
+func ExportMermaidSequence(filename, title string, entries []har.Entry, units har.SizeUnits) error {
+	return exportFile(filename, "Mermaid", func(w io.Writer) error {
+		return WriteMermaidSequence(w, title, entries, units)
+	})
+}
+
+// WriteMermaidSequence writes a Markdown heading and a fenced Mermaid
+// sequence diagram of the entries in capture order: the client sends each
+// request to its host, labeled with when it started, and the host answers
+// with the status, type, size in units and time. Failed requests get a
+// crossed arrow.
+func WriteMermaidSequence(w io.Writer, title string, entries []har.Entry, units har.SizeUnits) error {
+	writer := bufio.NewWriter(w)
+	fmt.Fprintf(writer, "## %s\n\n", title)
+	fmt.Fprintln(writer, "```mermaid")
+	fmt.Fprintln(writer, "sequenceDiagram")
+	fmt.Fprintln(writer, "    autonumber")
+	fmt.Fprintln(writer, "    participant C as Client")
+
+	shown := entries[:min(len(entries), mermaidMaxEntries)]
+	participants := make(map[string]string)
+	for _, entry := range shown {
+		host := har.EntryHost(entry)
+		if _, ok := participants[host]; !ok {
+			participants[host] = fmt.Sprintf("H%d", len(participants)+1)
+			fmt.Fprintf(writer, "    participant %s as %s\n", participants[host], mermaidText(host))
+		}
+	}
+
+	var start time.Time
+	for _, entry := range entries {
+		if start.IsZero() || entry.StartedDateTime.Before(start) {
+			start = entry.StartedDateTime
+		}
+	}
+	for _, entry := range shown {
+		host := participants[har.EntryHost(entry)]
+		offset := float64(entry.StartedDateTime.Sub(start).Microseconds()) / 1000
+		fmt.Fprintf(writer, "    C->>%s: %s %s (+%.0fms)\n", host, entry.Request.Method, mermaidText(mermaidPath(entry.Request.URL)), offset)
+
+		arrow := "-->>"
+		if entry.Response.Status >= 400 || entry.Response.Status == 0 {
+			arrow = "--x"
+		}
+		reply := fmt.Sprintf("%d", entry.Response.Status)
+		if mimeType := entry.Response.Content.MimeType; mimeType != "" {
+			mimeType, _, _ = strings.Cut(mimeType, ";")
+			reply += " " + mimeType
+		}
+		reply += fmt.Sprintf(", %s, %.0fms", units.FormatSize(max(entry.Response.Content.Size, 0)), entry.Time)
+		fmt.Fprintf(writer, "    %s%sC: %s\n", host, arrow, mermaidText(reply))
+	}
+	if len(entries) > len(shown) {
+		fmt.Fprintf(writer, "    Note over C: %d more requests not shown\n", len(entries)-len(shown))
+	}
+	fmt.Fprintln(writer, "```")
+
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("failed to write Mermaid diagram: %w", err)
+	}
+	return nil
+}
+
+// mermaidPath shortens a URL to its path, marking a dropped query string.
+func mermaidPath(rawURL string) string {
+	path := rawURL
+	if parsed, err := url.Parse(rawURL); err == nil && parsed.Host != "" {
+		path = parsed.EscapedPath()
+		if path == "" {
+			path = "/"
+		}
+		if parsed.RawQuery != "" {
+			path += "?…"
+		}
+	}
+	if runes := []rune(path); len(runes) > mermaidPathLength {
+		path = string(runes[:mermaidPathLength-1]) + "…"
+	}
+	return path
+}
+
+// mermaidText escapes the characters Mermaid reads as syntax in a message.
+func mermaidText(text string) string {
+	return strings.NewReplacer("#", "#35;", ";", "#59;", "\n", " ").Replace(text)
+}
@@ -2,17 +2,27 @@ package report
 
 import (
 	"fmt"
+	"github.com/jlgore/hartea/internal/har"
+	"io"
+	"math"
+	"net/url"
 	"strings"
+	"time"
 
 	"github.com/jung-kurt/gofpdf/v2"
 )
 
-func (g *Generator) generateNativePDF(report *Report, filename string) error {
+func (g *Generator) generateNativePDF(report *Report, w io.Writer) error {
 	// Create PDF with custom page size and margins
 	pdf := gofpdf.New("P", "mm", "A4", "")
 	pdf.SetMargins(20, 20, 20)
 	pdf.SetAutoPageBreak(true, 20)
 
+	// Pin metadata to the report time so identical input yields identical bytes
+	pdf.SetCatalogSort(true)
+	pdf.SetCreationDate(report.GeneratedAt)
+	pdf.SetModificationDate(report.GeneratedAt)
+
 	// Add page
 	pdf.AddPage()
 
@@ -27,7 +37,7 @@ func (g *Generator) generateNativePDF(report *Report, filename string) error {
 	pdf.SetTextColor(102, 102, 102) // Gray color
 	pdf.Cell(0, 8, "Generated: "+report.GeneratedAt.Format("January 2, 2006 at 3:04 PM"))
 	pdf.Ln(5)
-	pdf.Cell(0, 8, "Files: "+strings.Join(report.Files, ", "))
+	pdf.Cell(0, 8, "Files: "+strings.Join(report.FileLabels(), ", "))
 	pdf.Ln(15)
 
 	// Executive Summary
@@ -36,7 +46,16 @@ func (g *Generator) generateNativePDF(report *Report, filename string) error {
 	pdf.Cell(0, 10, "Executive Summary")
 	pdf.Ln(12)
 
-	// Summary metrics in a grid
+	// Score, summary paragraph, then the metrics in a grid
+	pdf.SetFont("Arial", "B", 12)
+	pdf.Cell(0, 7, scoreLine(report.ExecutiveSummary))
+	pdf.Ln(9)
+	pdf.SetFont("Arial", "", 11)
+	for _, line := range g.wrapText(strings.ReplaceAll(report.ExecutiveSummary.Text, "→", "->"), 95) {
+		pdf.Cell(0, 6, line)
+		pdf.Ln(6)
+	}
+	pdf.Ln(6)
 	g.addSummaryGrid(pdf, report)
 
 	// Detailed Metrics Table
@@ -47,6 +66,15 @@ func (g *Generator) generateNativePDF(report *Report, filename string) error {
 
 	g.addMetricsTable(pdf, report)
 
+	// Resource type breakdown
+	pdf.Ln(15)
+	pdf.SetFont("Arial", "B", 16)
+	pdf.SetTextColor(51, 51, 51)
+	pdf.Cell(0, 10, "Resource Types")
+	pdf.Ln(12)
+
+	g.addResourceTypesTable(pdf, report)
+
 	// Comparison section (if available)
 	if report.Comparison != nil {
 		pdf.Ln(15)
@@ -57,6 +85,20 @@ func (g *Generator) generateNativePDF(report *Report, filename string) error {
 		g.addComparisonSection(pdf, report)
 	}
 
+	// Waterfall chart for each capture
+	for i, harFile := range g.harFiles {
+		if len(harFile.Log.Entries) == 0 {
+			continue
+		}
+		pdf.AddPage()
+		pdf.SetFont("Arial", "B", 16)
+		pdf.SetTextColor(51, 51, 51)
+		pdf.Cell(0, 10, "Request Waterfall - "+report.Files[i])
+		pdf.Ln(12)
+
+		g.addWaterfallChart(pdf, g.analyzers[i].GenerateTimeline())
+	}
+
 	// Recommendations
 	pdf.Ln(15)
 	pdf.SetFont("Arial", "B", 16)
@@ -71,8 +113,106 @@ func (g *Generator) generateNativePDF(report *Report, filename string) error {
 	pdf.SetTextColor(128, 128, 128)
 	pdf.Cell(0, 5, "Generated by Hartea - Charting the performance seas, one treasure at a time")
 
-	// Save PDF
-	return pdf.OutputFileAndClose(filename)
+	return pdf.Output(w)
+}
+
+const maxWaterfallRows = 45
+
+func (g *Generator) addWaterfallChart(pdf *gofpdf.Fpdf, timeline []har.TimelineEvent) {
+	labelWidth := 55.0
+	chartWidth := 105.0
+	rowHeight := 4.5
+	left, _, _, _ := pdf.GetMargins()
+
+	start := timeline[0].StartTime
+	end := start
+	for _, event := range timeline {
+		if event.StartTime.Before(start) {
+			start = event.StartTime
+		}
+		eventEnd := event.StartTime.Add(time.Duration(event.Duration * float64(time.Millisecond)))
+		if eventEnd.After(end) {
+			end = eventEnd
+		}
+	}
+	totalMs := end.Sub(start).Seconds() * 1000
+	if totalMs <= 0 {
+		totalMs = 1
+	}
+
+	// Time scale
+	pdf.SetFont("Arial", "", 7)
+	pdf.SetTextColor(102, 102, 102)
+	pdf.SetDrawColor(200, 200, 200)
+	y := pdf.GetY()
+	for _, marker := range []float64{0, 0.25, 0.5, 0.75, 1} {
+		x := left + labelWidth + chartWidth*marker
+		pdf.Line(x, y+4, x, y+4+float64(min(len(timeline), maxWaterfallRows))*rowHeight)
+		pdf.SetXY(x-8, y)
+		pdf.CellFormat(16, 4, fmt.Sprintf("%.0fms", totalMs*marker), "", 0, "C", false, 0, "")
+	}
+	y += 5
+
+	for i, event := range timeline {
+		if i >= maxWaterfallRows {
+			pdf.SetXY(left, y)
+			pdf.Cell(0, rowHeight, fmt.Sprintf("... and %d more requests", len(timeline)-maxWaterfallRows))
+			y += rowHeight
+			break
+		}
+
+		pdf.SetTextColor(51, 51, 51)
+		pdf.SetXY(left, y)
+		pdf.CellFormat(labelWidth-2, rowHeight, pdfLabel(event), "", 0, "L", false, 0, "")
+
+		offset := event.StartTime.Sub(start).Seconds() * 1000
+		x := left + labelWidth + chartWidth*offset/totalMs
+		width := math.Max(chartWidth*event.Duration/totalMs, 0.5)
+
+		color := getColorForContentType(event.ContentType, event.Status)
+		pdf.SetFillColor(color[0], color[1], color[2])
+		pdf.Rect(x, y+0.8, width, rowHeight-1.6, "F")
+
+		pdf.SetXY(x+width+1, y)
+		pdf.SetTextColor(128, 128, 128)
+		pdf.CellFormat(15, rowHeight, fmt.Sprintf("%.0fms", event.Duration), "", 0, "L", false, 0, "")
+
+		y += rowHeight
+	}
+
+	// Legend
+	y += 3
+	pdf.SetXY(left, y)
+	legend := []struct {
+		label       string
+		contentType string
+		status      int
+	}{
+		{"HTML", "html", 200}, {"JS", "javascript", 200}, {"CSS", "css", 200},
+		{"Image", "image", 200}, {"JSON", "json", 200}, {"Font", "font", 200},
+		{"Other", "", 200}, {"Redirect", "", 301}, {"Error", "", 404},
+	}
+	for _, item := range legend {
+		color := getColorForContentType(item.contentType, item.status)
+		pdf.SetFillColor(color[0], color[1], color[2])
+		pdf.Rect(pdf.GetX(), y+1, 3, 3, "F")
+		pdf.SetX(pdf.GetX() + 4)
+		pdf.SetTextColor(102, 102, 102)
+		pdf.CellFormat(14, 5, item.label, "", 0, "L", false, 0, "")
+	}
+	pdf.SetY(y + 8)
+}
+
+func pdfLabel(event har.TimelineEvent) string {
+	label := event.URL
+	if parsed, err := url.Parse(event.URL); err == nil {
+		label = parsed.Host + parsed.Path
+	}
+	label = event.Method + " " + label
+	if runes := []rune(label); len(runes) > 38 {
+		label = string(runes[:35]) + "..."
+	}
+	return label
 }
 
 func (g *Generator) addSummaryGrid(pdf *gofpdf.Fpdf, report *Report) {
@@ -90,7 +230,7 @@ func (g *Generator) addSummaryGrid(pdf *gofpdf.Fpdf, report *Report) {
 		{fmt.Sprintf("%d", report.Summary.TotalRequests), "Total Requests", []int{40, 167, 69}},
 		{fmt.Sprintf("%.1fms", report.Summary.AverageLoadTime), "Avg Load Time", getColorForLoadTime(report.Summary.AverageLoadTime)},
 		{fmt.Sprintf("%.1fms", report.Summary.AverageTTFB), "Average TTFB", getColorForTTFB(report.Summary.AverageTTFB)},
-		{fmt.Sprintf("%.2fMB", report.Summary.TotalTransferMB), "Total Transfer", []int{156, 39, 176}},
+		{fmt.Sprintf("%.2f%s", report.Summary.TotalTransferMB, report.SizeUnits.MegabyteUnit()), "Total Transfer", []int{156, 39, 176}},
 		{fmt.Sprintf("%d", report.Summary.TotalErrors), "Total Errors", getColorForErrors(report.Summary.TotalErrors)},
 	}
 
@@ -128,8 +268,8 @@ func (g *Generator) addSummaryGrid(pdf *gofpdf.Fpdf, report *Report) {
 
 func (g *Generator) addMetricsTable(pdf *gofpdf.Fpdf, report *Report) {
 	// Table headers
-	headers := []string{"File", "Load Time", "TTFB", "Requests", "Errors", "Cache %", "Size (MB)"}
-	colWidths := []float64{30, 25, 20, 20, 18, 20, 25}
+	headers := []string{"File", "Load Time", "TTFB", "Requests", "Errors", "Cache %", "Size (" + report.SizeUnits.MegabyteUnit() + ")", "Repeat View"}
+	colWidths := []float64{28, 22, 18, 18, 15, 17, 20, 40}
 
 	// Header row
 	pdf.SetFont("Arial", "B", 10)
@@ -151,6 +291,14 @@ func (g *Generator) addMetricsTable(pdf *gofpdf.Fpdf, report *Report) {
 			pdf.SetFillColor(248, 249, 250)
 		}
 
+		if !metrics.HasData() {
+			pdf.SetTextColor(108, 117, 125)
+			pdf.CellFormat(colWidths[0], 7, report.Files[i], "1", 0, "L", true, 0, "")
+			pdf.CellFormat(sumWidths(colWidths[1:]), 7, "N/A - no entries in this capture", "1", 0, "C", true, 0, "")
+			pdf.Ln(-1)
+			continue
+		}
+
 		data := []string{
 			report.Files[i],
 			fmt.Sprintf("%.1fms", metrics.PageLoadTime),
@@ -158,7 +306,8 @@ func (g *Generator) addMetricsTable(pdf *gofpdf.Fpdf, report *Report) {
 			fmt.Sprintf("%d", metrics.TotalRequests),
 			fmt.Sprintf("%d", metrics.ErrorRequests),
 			fmt.Sprintf("%.1f%%", metrics.CacheHitRatio),
-			fmt.Sprintf("%.2f", float64(metrics.TotalSize)/(1024*1024)),
+			fmt.Sprintf("%.2f", report.SizeUnits.Megabytes(float64(metrics.TotalSize))),
+			repeatViewCell(metrics, report.SizeUnits),
 		}
 
 		for j, value := range data {
@@ -186,6 +335,42 @@ func (g *Generator) addMetricsTable(pdf *gofpdf.Fpdf, report *Report) {
 	}
 }
 
+func (g *Generator) addResourceTypesTable(pdf *gofpdf.Fpdf, report *Report) {
+	headers := []string{"File", "Type", "Requests", "Total Size", "Avg Size", "Total Time"}
+	colWidths := []float64{30, 28, 22, 28, 25, 27}
+
+	pdf.SetFont("Arial", "B", 10)
+	pdf.SetFillColor(248, 249, 250)
+	pdf.SetTextColor(51, 51, 51)
+	for i, header := range headers {
+		pdf.CellFormat(colWidths[i], 8, header, "1", 0, "C", true, 0, "")
+	}
+	pdf.Ln(-1)
+
+	pdf.SetFont("Arial", "", 9)
+	pdf.SetFillColor(255, 255, 255)
+	for i, breakdown := range report.ResourceTypes {
+		for _, stats := range breakdown {
+			data := []string{
+				report.Files[i],
+				stats.Type,
+				fmt.Sprintf("%d", stats.Requests),
+				report.SizeUnits.FormatSize(int(stats.Size)),
+				report.SizeUnits.FormatSize(int(stats.AverageSize())),
+				fmt.Sprintf("%.1fms", stats.Time),
+			}
+			for j, value := range data {
+				align := "L"
+				if j > 1 {
+					align = "C"
+				}
+				pdf.CellFormat(colWidths[j], 7, value, "1", 0, align, true, 0, "")
+			}
+			pdf.Ln(-1)
+		}
+	}
+}
+
 func (g *Generator) addComparisonSection(pdf *gofpdf.Fpdf, report *Report) {
 	comparison := report.Comparison
 
@@ -199,6 +384,18 @@ func (g *Generator) addComparisonSection(pdf *gofpdf.Fpdf, report *Report) {
 	pdf.Cell(0, 8, summaryText)
 	pdf.Ln(10)
 
+	for _, warning := range comparison.Warnings {
+		pdf.SetTextColor(255, 193, 7)
+		pdf.Cell(0, 6, "Warning: "+warning)
+		pdf.Ln(7)
+	}
+	pdf.SetTextColor(51, 51, 51)
+	for _, insight := range comparison.Insights {
+		// The core fonts have no arrow glyph
+		pdf.Cell(0, 6, "Insight: "+strings.ReplaceAll(insight.String(), "→", "->"))
+		pdf.Ln(7)
+	}
+
 	// Comparison table
 	if len(comparison.Files) >= 2 {
 		// Headers
@@ -239,8 +436,8 @@ func (g *Generator) addComparisonSection(pdf *gofpdf.Fpdf, report *Report) {
 				if len(diff.Improvements) > 1 {
 					if diff.Improvements[1] {
 						pdf.SetTextColor(40, 167, 69) // Green for improvement
-						change += " ✓"
-					} else if change != "No change" && change != "Baseline" {
+						change += " +"
+					} else if diff.Regressed(1) {
 						pdf.SetTextColor(220, 53, 69) // Red for regression
 						change += " !"
 					} else {
@@ -257,12 +454,13 @@ func (g *Generator) addComparisonSection(pdf *gofpdf.Fpdf, report *Report) {
 
 func (g *Generator) addRecommendations(pdf *gofpdf.Fpdf, report *Report) {
 	recommendations := g.generateRecommendations(report)
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
 
 	pdf.SetFont("Arial", "", 11)
 	pdf.SetTextColor(51, 51, 51)
 
 	if len(recommendations) == 0 {
-		pdf.Cell(0, 8, "• Performance metrics are within acceptable ranges")
+		pdf.Cell(0, 8, tr("• Performance metrics are within acceptable ranges"))
 		return
 	}
 
@@ -273,7 +471,7 @@ func (g *Generator) addRecommendations(pdf *gofpdf.Fpdf, report *Report) {
 		}
 
 		// Bullet point
-		pdf.Cell(5, 6, "•")
+		pdf.Cell(5, 6, tr("•"))
 
 		// Recommendation text (with word wrapping)
 		lines := g.wrapText(rec, 80)
@@ -314,12 +512,35 @@ func (g *Generator) generateRecommendations(report *Report) []string {
 
 	// Check individual file metrics
 	for i, metrics := range report.Metrics {
-		if metrics.CacheHitRatio < 30 {
-			recommendations = append(recommendations, fmt.Sprintf("File %d has poor cache efficiency (%.1f%%) - review caching headers and strategy", i+1, metrics.CacheHitRatio))
+		if !metrics.HasData() {
+			continue
+		}
+
+		if metrics.Environment.ChecksDelivery() && metrics.CacheHitRatio < 30 {
+			recommendations = append(recommendations, fmt.Sprintf("%s has poor cache efficiency (%.1f%%) - review caching headers and strategy", report.Files[i], metrics.CacheHitRatio))
 		}
 
 		if metrics.ThirdPartyRequests > metrics.TotalRequests/2 {
-			recommendations = append(recommendations, fmt.Sprintf("File %d has many third-party requests - consider reducing external dependencies", i+1))
+			recommendations = append(recommendations, fmt.Sprintf("%s has many third-party requests - consider reducing external dependencies", report.Files[i]))
+		}
+
+		if i < len(report.JSAudits) {
+			audit := report.JSAudits[i]
+			if large := audit.LargeBundles(); large > 0 {
+				recommendations = append(recommendations, fmt.Sprintf("%s loads %d JavaScript bundle(s) over %s - split them or drop unused code", report.Files[i], large, report.SizeUnits.FormatSize(har.LargeBundleBytes)))
+			}
+			for _, library := range audit.DuplicateLibraries {
+				recommendations = append(recommendations, fmt.Sprintf("%s loads %s %d times - serve a single copy from one host", report.Files[i], library.Library, library.Requests))
+			}
+			if len(audit.SourceMaps) > 0 {
+				recommendations = append(recommendations, fmt.Sprintf("%s serves %d source map(s) in production - stop deploying them publicly", report.Files[i], len(audit.SourceMaps)))
+			}
+		}
+
+		if i < len(report.ResourceHints) {
+			for _, hint := range report.ResourceHints[i] {
+				recommendations = append(recommendations, fmt.Sprintf("%s: add %s to save about %.0fms of %s", report.Files[i], hint.Tag(), hint.Saved, hint.Avoids()))
+			}
 		}
 	}
 
@@ -327,10 +548,10 @@ func (g *Generator) generateRecommendations(report *Report) []string {
 	if report.Comparison != nil {
 		for _, diff := range report.Comparison.Differences {
 			if len(diff.Changes) > 1 && len(diff.Improvements) > 1 {
-				if !diff.Improvements[1] && diff.Changes[1] != "No change" {
-					if diff.Name == "Total Load Time" {
+				if diff.Regressed(1) {
+					if diff.ID == har.MetricPageLoadTime {
 						recommendations = append(recommendations, "Performance regression detected in load time - investigate recent changes")
-					} else if diff.Name == "Error Requests" && strings.Contains(diff.Changes[1], "+") {
+					} else if diff.ID == har.MetricErrorRequests && strings.Contains(diff.Changes[1], "+") {
 						recommendations = append(recommendations, "Error rate increased - check for new issues or broken functionality")
 					}
 				}
@@ -371,7 +592,37 @@ func (g *Generator) wrapText(text string, maxChars int) []string {
 	return lines
 }
 
+func sumWidths(widths []float64) float64 {
+	total := 0.0
+	for _, width := range widths {
+		total += width
+	}
+	return total
+}
+
 // Color helper functions
+func getColorForContentType(contentType string, status int) []int {
+	switch {
+	case status >= 400:
+		return []int{220, 53, 69} // Red
+	case status >= 300:
+		return []int{255, 193, 7} // Yellow
+	case strings.Contains(contentType, "html"):
+		return []int{0, 122, 204} // Blue
+	case strings.Contains(contentType, "javascript"):
+		return []int{230, 162, 0} // Amber
+	case strings.Contains(contentType, "css"):
+		return []int{40, 167, 69} // Green
+	case strings.Contains(contentType, "image"):
+		return []int{156, 39, 176} // Purple
+	case strings.Contains(contentType, "json"):
+		return []int{23, 162, 184} // Teal
+	case strings.Contains(contentType, "font"):
+		return []int{108, 117, 125} // Gray
+	}
+	return []int{173, 181, 189} // Light gray
+}
+
 func getColorForLoadTime(loadTime float64) []int {
 	if loadTime <= 1500 {
 		return []int{40, 167, 69} // Green
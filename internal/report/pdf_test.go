@@ -0,0 +1,60 @@
+package report
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jlgore/hartea/internal/har"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// examplePDF renders the example captures as a PDF report generated at a
+// fixed time.
+func examplePDF(t *testing.T) []byte {
+	t.Helper()
+	harFiles, err := har.NewParser().ParseMultipleFiles([]string{"../../example.har", "../../example2.har"})
+	if err != nil {
+		t.Fatalf("parsing example captures: %v", err)
+	}
+	generator := NewGeneratorFromHAR(harFiles, []string{"example.har", "example2.har"}, har.Settings{})
+	report := generator.GenerateReport(false)
+	report.GeneratedAt = time.Date(2024, time.January, 15, 14, 30, 25, 0, time.UTC)
+
+	var buf bytes.Buffer
+	if err := generator.generateNativePDF(report, &buf); err != nil {
+		t.Fatalf("generating PDF: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestPDFIsDeterministic(t *testing.T) {
+	first, second := examplePDF(t), examplePDF(t)
+	if !bytes.Equal(first, second) {
+		t.Fatalf("two renders of the same report differ (%d and %d bytes)", len(first), len(second))
+	}
+}
+
+func TestPDFGolden(t *testing.T) {
+	got := examplePDF(t)
+	golden := filepath.Join("testdata", "example.pdf")
+	if *update {
+		if err := os.MkdirAll("testdata", 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(golden, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("reading golden file (run with -update to create it): %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("PDF differs from %s (%d bytes, want %d); run with -update if the change is intended", golden, len(got), len(want))
+	}
+}
@@ -0,0 +1,20 @@
+package report
+
+import "strings"
+
+// plainReplacer swaps emoji and symbol status icons for ASCII markers and
+// drops decorative emoji. Variation-selector forms come first so they are
+// replaced whole.
+var plainReplacer = strings.NewReplacer(
+	"⚠️", "[!]", "⚠", "[!]",
+	"✅", "[OK]", "❌", "[X]", "❓", "[?]", "🔄", "[->]", "⚡", "[~]",
+	"✓", "+", "✗", "x", "✕", "x", "↻", ">",
+	"🌡️ ", "", "🏷️ ", "",
+	"⚓ ", "", "📊 ", "", "📈 ", "", "🧩 ", "", "🎯 ", "", "📜 ", "", "💡 ", "", "🔎 ", "",
+)
+
+// Plain replaces the emoji and status icons hartea writes with ASCII, for
+// terminals without emoji fonts and for screen readers.
+func Plain(text string) string {
+	return plainReplacer.Replace(text)
+}
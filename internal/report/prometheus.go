@@ -0,0 +1,139 @@
+package report
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/jlgore/hartea/internal/har"
+	"io"
+	"math"
+	"sort"
+	"strings"
+)
+
+// ExportPrometheus writes HAR-derived metrics in the Prometheus text
+// exposition format, suitable for pushing to a Pushgateway from CI.
+func (g *Generator) ExportPrometheus(filename string) error {
+	return exportFile(filename, "Prometheus", g.WritePrometheus)
+}
+
+// WritePrometheus writes the metrics in the Prometheus text exposition format.
+func (g *Generator) WritePrometheus(w io.Writer) error {
+	writer := bufio.NewWriter(w)
+	g.writePrometheus(writer)
+
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("failed to write Prometheus metrics: %w", err)
+	}
+
+	return nil
+}
+
+type promSample struct {
+	suffix string
+	labels string
+	value  float64
+}
+
+type promFamily struct {
+	name    string
+	help    string
+	kind    string
+	samples []promSample
+}
+
+func (g *Generator) writePrometheus(w io.Writer) {
+	families := []*promFamily{
+		{name: "hartea_requests", help: "Requests by HTTP status class.", kind: "gauge"},
+		{name: "hartea_transfer_bytes", help: "Bytes transferred by resource type.", kind: "gauge"},
+		{name: "hartea_resource_requests", help: "Requests by resource type.", kind: "gauge"},
+		{name: "hartea_third_party_requests", help: "Requests to third-party hosts.", kind: "gauge"},
+		{name: "hartea_error_requests", help: "Requests with a 4xx or 5xx status.", kind: "gauge"},
+		{name: "hartea_ttfb_milliseconds", help: "Time to first byte of the capture.", kind: "gauge"},
+		{name: "hartea_page_load_time_milliseconds", help: "Page load time of the capture.", kind: "gauge"},
+		{name: "hartea_request_duration_milliseconds", help: "Total request time percentiles.", kind: "summary"},
+	}
+	requests, transfer, resources, thirdParty, errors, ttfb, pageLoad, duration :=
+		families[0], families[1], families[2], families[3], families[4], families[5], families[6], families[7]
+
+	for i, harFile := range g.harFiles {
+		file := g.FileName(i)
+		fileLabel := promLabel("file", file)
+		metrics := g.analyzers[i].CalculateMetrics()
+
+		statusClasses := map[string]int{}
+		var times []float64
+		var totalTime float64
+		for _, entry := range harFile.Log.Entries {
+			statusClasses[har.StatusClass(entry.Response.Status)]++
+			times = append(times, entry.Time)
+			totalTime += entry.Time
+		}
+		for _, class := range sortedKeys(statusClasses) {
+			requests.add(fileLabel+","+promLabel("status_class", class), float64(statusClasses[class]))
+		}
+
+		// Both resource families use the fixed ResourceTypes, keeping the
+		// type label's cardinality bounded
+		transferred := map[string]int{}
+		for _, entry := range harFile.Log.Entries {
+			transferred[har.EntryResourceType(entry)] += har.TransferSize(entry)
+		}
+		for _, stats := range g.analyzers[i].ResourceBreakdown() {
+			typeLabel := fileLabel + "," + promLabel("type", stats.Type)
+			transfer.add(typeLabel, float64(transferred[stats.Type]))
+			resources.add(typeLabel, float64(stats.Requests))
+		}
+
+		thirdParty.add(fileLabel, float64(metrics.ThirdPartyRequests))
+		errors.add(fileLabel, float64(metrics.ErrorRequests))
+
+		// Averages of a capture without entries are meaningless, so leave them out
+		if !metrics.HasData() {
+			continue
+		}
+		ttfb.add(fileLabel, metrics.TTFB)
+		pageLoad.add(fileLabel, metrics.PageLoadTime)
+
+		sort.Float64s(times)
+		for _, quantile := range []float64{0.5, 0.95, 0.99} {
+			duration.add(fileLabel+","+promLabel("quantile", fmt.Sprint(quantile)), har.Percentile(times, quantile))
+		}
+		duration.samples = append(duration.samples,
+			promSample{suffix: "_sum", labels: fileLabel, value: totalTime},
+			promSample{suffix: "_count", labels: fileLabel, value: float64(len(times))})
+	}
+
+	for _, family := range families {
+		fmt.Fprintf(w, "# HELP %s %s\n", family.name, family.help)
+		fmt.Fprintf(w, "# TYPE %s %s\n", family.name, family.kind)
+		for _, sample := range family.samples {
+			fmt.Fprintf(w, "%s%s{%s} %s\n", family.name, sample.suffix, sample.labels, promValue(sample.value))
+		}
+	}
+}
+
+func (f *promFamily) add(labels string, value float64) {
+	f.samples = append(f.samples, promSample{labels: labels, value: value})
+}
+
+func sortedKeys(counts map[string]int) []string {
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+var promLabelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func promLabel(name, value string) string {
+	return fmt.Sprintf(`%s="%s"`, name, promLabelEscaper.Replace(value))
+}
+
+func promValue(value float64) string {
+	if value == math.Trunc(value) && math.Abs(value) < 1e15 {
+		return fmt.Sprintf("%d", int64(value))
+	}
+	return fmt.Sprintf("%g", value)
+}
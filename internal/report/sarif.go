@@ -0,0 +1,141 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/jlgore/hartea/internal/audit"
+	"io"
+	"net/url"
+	"path/filepath"
+)
+
+const sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string       `json:"id"`
+	Name                 string       `json:"name"`
+	ShortDescription     sarifMessage `json:"shortDescription"`
+	DefaultConfiguration struct {
+		Level audit.Severity `json:"level"`
+	} `json:"defaultConfiguration"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID     string                 `json:"ruleId"`
+	RuleIndex  int                    `json:"ruleIndex"`
+	Level      audit.Severity         `json:"level"`
+	Message    sarifMessage           `json:"message"`
+	Locations  []sarifLocation        `json:"locations"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation struct {
+		ArtifactLocation struct {
+			URI string `json:"uri"`
+		} `json:"artifactLocation"`
+	} `json:"physicalLocation"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifLogicalLocation struct {
+	Name               string `json:"name"`
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+// ExportSARIF writes the security audit findings as SARIF 2.1.0 for GitHub
+// Code Scanning and similar tools. Each result points at the HAR file and the
+// offending entry.
+func (g *Generator) ExportSARIF(filename string) error {
+	return exportFile(filename, "SARIF", g.WriteSARIF)
+}
+
+// WriteSARIF writes the security audit findings as a SARIF log.
+func (g *Generator) WriteSARIF(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(g.sarifLog()); err != nil {
+		return fmt.Errorf("failed to encode SARIF: %w", err)
+	}
+
+	return nil
+}
+
+func (g *Generator) sarifLog() sarifLog {
+	driver := sarifDriver{
+		Name:           "hartea",
+		InformationURI: "https://github.com/jlgore/hartea",
+		Rules:          []sarifRule{},
+	}
+	ruleIndex := make(map[string]int)
+	for i, rule := range audit.Rules() {
+		sr := sarifRule{ID: rule.ID, Name: rule.Name, ShortDescription: sarifMessage{Text: rule.Description}}
+		sr.DefaultConfiguration.Level = rule.Severity
+		driver.Rules = append(driver.Rules, sr)
+		ruleIndex[rule.ID] = i
+	}
+
+	results := []sarifResult{}
+	for _, finding := range audit.Audit(g.harFiles, g.settings) {
+		var location sarifLocation
+		location.PhysicalLocation.ArtifactLocation.URI = sarifURI(g.sourceName(finding.File))
+		location.LogicalLocations = []sarifLogicalLocation{{
+			Name:               fmt.Sprintf("entries[%d]", finding.EntryIndex),
+			FullyQualifiedName: fmt.Sprintf("log.entries[%d]", finding.EntryIndex),
+			Kind:               "element",
+		}}
+
+		results = append(results, sarifResult{
+			RuleID:    finding.RuleID,
+			RuleIndex: ruleIndex[finding.RuleID],
+			Level:     finding.Severity,
+			Message:   sarifMessage{Text: fmt.Sprintf("%s (%s)", finding.Message, finding.URL)},
+			Locations: []sarifLocation{location},
+			Properties: map[string]interface{}{
+				"entryIndex": finding.EntryIndex,
+				"url":        finding.URL,
+			},
+		})
+	}
+
+	return sarifLog{
+		Schema:  sarifSchema,
+		Version: "2.1.0",
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: driver}, Results: results}},
+	}
+}
+
+// sarifURI keeps relative paths relative to the repository root, as code
+// scanning expects, and turns absolute paths into file URIs.
+func sarifURI(path string) string {
+	if filepath.IsAbs(path) {
+		return (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()
+	}
+	return filepath.ToSlash(path)
+}
@@ -0,0 +1,126 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/jlgore/hartea/internal/har"
+	"io"
+	"sort"
+	"time"
+)
+
+// traceEvent is an event of the Chrome Trace Event format. Times are in
+// microseconds.
+type traceEvent struct {
+	Name  string                 `json:"name"`
+	Cat   string                 `json:"cat,omitempty"`
+	Phase string                 `json:"ph"`
+	Ts    int64                  `json:"ts"`
+	Dur   int64                  `json:"dur,omitempty"`
+	Pid   int                    `json:"pid"`
+	Tid   int                    `json:"tid"`
+	Scope string                 `json:"s,omitempty"`
+	Args  map[string]interface{} `json:"args,omitempty"`
+}
+
+type traceFile struct {
+	TraceEvents     []traceEvent `json:"traceEvents"`
+	DisplayTimeUnit string       `json:"displayTimeUnit"`
+}
+
+// ExportTrace writes every file's requests as a Chrome trace, see WriteTrace.
+func (g *Generator) ExportTrace(filename string) error {
+	return exportFile(filename, "trace", g.WriteTrace)
+}
+
+// WriteTrace writes the requests in the Chrome Trace Event format, which
+// chrome://tracing and ui.perfetto.dev open. Each file is a process whose
+// tracks hold the requests that did not overlap, each request a slice with
+// its timing phases nested inside. DOMContentLoaded and onLoad are instant
+// events. Timestamps are wall-clock microseconds so the requests line up
+// with other traces recorded at the same time.
+func (g *Generator) WriteTrace(w io.Writer) error {
+	trace := traceFile{TraceEvents: []traceEvent{}, DisplayTimeUnit: "ms"}
+	for i, harFile := range g.harFiles {
+		trace.TraceEvents = append(trace.TraceEvents, fileTraceEvents(i+1, g.FileName(i), g.analyzers[i], harFile.Log.Entries)...)
+	}
+
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(trace); err != nil {
+		return fmt.Errorf("failed to encode trace: %w", err)
+	}
+	return nil
+}
+
+// fileTraceEvents returns the events of one file as process pid.
+func fileTraceEvents(pid int, name string, analyzer *har.Analyzer, entries []har.Entry) []traceEvent {
+	events := []traceEvent{{Name: "process_name", Phase: "M", Pid: pid, Args: map[string]interface{}{"name": name}}}
+
+	order := make([]int, len(entries))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return entries[order[i]].StartedDateTime.Before(entries[order[j]].StartedDateTime)
+	})
+
+	// Slices on one track must not overlap, so each request takes the first
+	// track that is free when it starts
+	var trackEnds []int64
+	for _, index := range order {
+		entry := entries[index]
+		start := entry.StartedDateTime.UnixMicro()
+		dur := int64(max(entry.Time, 0) * 1000)
+
+		track := 0
+		for track < len(trackEnds) && trackEnds[track] > start {
+			track++
+		}
+		if track == len(trackEnds) {
+			trackEnds = append(trackEnds, 0)
+			events = append(events, traceEvent{Name: "thread_name", Phase: "M", Pid: pid, Tid: track + 1, Args: map[string]interface{}{"name": fmt.Sprintf("Requests %d", track+1)}})
+		}
+		trackEnds[track] = start + dur
+
+		events = append(events, traceEvent{
+			Name:  entry.Request.Method + " " + dotURL(entry.Request.URL),
+			Cat:   har.ResourceType(entry.Response.Content.MimeType),
+			Phase: "X",
+			Ts:    start,
+			Dur:   dur,
+			Pid:   pid,
+			Tid:   track + 1,
+			Args: map[string]interface{}{
+				"entryIndex": index,
+				"url":        entry.Request.URL,
+				"status":     entry.Response.Status,
+				"mimeType":   entry.Response.Content.MimeType,
+				"size":       entry.Response.Content.Size,
+				"transfer":   har.TransferSize(entry),
+			},
+		})
+
+		// Phases nest inside the request, so clip them to its end
+		at := start
+		for _, phase := range waterfallPhases {
+			phaseDur := min(int64(max(phase.value(entry.Timings), 0))*1000, start+dur-at)
+			if phaseDur <= 0 {
+				continue
+			}
+			events = append(events, traceEvent{Name: phase.name, Cat: "timing", Phase: "X", Ts: at, Dur: phaseDur, Pid: pid, Tid: track + 1})
+			at += phaseDur
+		}
+	}
+
+	page := analyzer.Page()
+	for _, marker := range []struct {
+		name string
+		at   int
+	}{{"DOMContentLoaded", page.PageTimings.OnContentLoad}, {"onLoad", page.PageTimings.OnLoad}} {
+		if marker.at > 0 {
+			at := page.StartedDateTime.Add(time.Duration(marker.at) * time.Millisecond)
+			events = append(events, traceEvent{Name: marker.name, Cat: "page", Phase: "i", Ts: at.UnixMicro(), Pid: pid, Tid: 1, Scope: "p"})
+		}
+	}
+	return events
+}
@@ -0,0 +1,63 @@
+package report
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/jlgore/hartea/internal/har"
+	"io"
+	"time"
+)
+
+// trends follows every metric across the files in chronological order.
+func (g *Generator) trends() []har.Trend {
+	files := make([]string, len(g.harFiles))
+	for i := range files {
+		files[i] = g.sourceName(i)
+	}
+	return har.Trends(files, g.analyzers)
+}
+
+// WriteTrendsJSON writes each metric's values across the files, oldest
+// first, e.g. for charting nightly captures.
+func (g *Generator) WriteTrendsJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(g.trends()); err != nil {
+		return fmt.Errorf("failed to write trends JSON: %w", err)
+	}
+	return nil
+}
+
+// WriteTrendsCSV writes one row per file in chronological order with a
+// column per metric. Metrics a file did not measure are left empty.
+func (g *Generator) WriteTrendsCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+
+	descriptors := har.MetricDescriptors()
+	headers := []string{"File", "Time"}
+	for _, descriptor := range descriptors {
+		headers = append(headers, csvMetricHeader(descriptor, g.settings.SizeUnits))
+	}
+	if err := writer.Write(headers); err != nil {
+		return fmt.Errorf("failed to write CSV headers: %w", err)
+	}
+
+	for _, index := range har.ChronologicalOrder(g.analyzers) {
+		analyzer := g.analyzers[index]
+		metrics := analyzer.CalculateMetrics()
+		record := []string{g.sourceName(index), analyzer.PageStart().Format(time.RFC3339)}
+		for _, descriptor := range descriptors {
+			value := ""
+			if descriptor.Measured(metrics) {
+				value = csvMetricValue(descriptor, descriptor.Value(metrics), g.settings.SizeUnits)
+			}
+			record = append(record, value)
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("failed to write CSV record: %w", err)
+		}
+	}
+
+	return flushCSV(writer)
+}
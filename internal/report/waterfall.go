@@ -0,0 +1,286 @@
+package report
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/jlgore/hartea/internal/har"
+	"html/template"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"strconv"
+	"time"
+)
+
+// Waterfall chart layout, in SVG pixels. PNG charts are drawn at
+// waterfallPNGScale times the size.
+const (
+	waterfallWidth      = 1200.0
+	waterfallLabelChars = 52
+	waterfallLabelWidth = 360.0
+	waterfallRowHeight  = 18.0
+	waterfallBarHeight  = 10.0
+	waterfallPadding    = 16.0
+	waterfallPNGScale   = 2
+)
+
+// waterfallPhases are the timing phases of a bar, in order, with the colors
+// Chrome DevTools gives them.
+var waterfallPhases = []struct {
+	name  string
+	color string
+	value func(t har.Timings) int
+}{
+	{"Blocked", "#bdbdbd", func(t har.Timings) int { return t.Blocked }},
+	{"DNS", "#1f7c83", func(t har.Timings) int { return t.DNS }},
+	{"Connect", "#e58226", func(t har.Timings) int { return t.Connect - max(t.SSL, 0) }},
+	{"TLS", "#c141cd", func(t har.Timings) int { return t.SSL }},
+	{"Send", "#2e9cc9", func(t har.Timings) int { return t.Send }},
+	{"Wait", "#1ea446", func(t har.Timings) int { return t.Wait }},
+	{"Receive", "#1d73f5", func(t har.Timings) int { return t.Receive }},
+}
+
+// waterfallChart is a chart as shapes, so the SVG and PNG outputs draw the
+// same thing.
+type waterfallChart struct {
+	width, height float64
+	rects         []chartRect
+	lines         []chartLine
+	texts         []chartText
+}
+
+type chartRect struct {
+	x, y, width, height float64
+	color               string
+}
+
+type chartLine struct {
+	x1, y1, x2, y2 float64
+	color          string
+	dashed         bool
+}
+
+// chartText is a line of 11px monospace text whose top is at y.
+type chartText struct {
+	x, y  float64
+	text  string
+	color string
+}
+
+// ExportWaterfallSVG writes the waterfall of every file as an SVG image.
+func (g *Generator) ExportWaterfallSVG(filename string) error {
+	return exportFile(filename, "waterfall SVG", g.WriteWaterfallSVG)
+}
+
+// WriteWaterfallSVG writes one waterfall chart per file, stacked, as a
+// standalone SVG image: a bar per request split into its timing phases,
+// with DOMContentLoaded and onLoad marked.
+func (g *Generator) WriteWaterfallSVG(w io.Writer) error {
+	writer := bufio.NewWriter(w)
+	g.waterfallChart().writeSVG(writer)
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("failed to write waterfall SVG: %w", err)
+	}
+	return nil
+}
+
+// ExportWaterfallPNG writes the waterfall of every file as a PNG image.
+func (g *Generator) ExportWaterfallPNG(filename string) error {
+	return exportFile(filename, "waterfall PNG", g.WriteWaterfallPNG)
+}
+
+// WriteWaterfallPNG writes the chart of WriteWaterfallSVG as a PNG image.
+func (g *Generator) WriteWaterfallPNG(w io.Writer) error {
+	if err := png.Encode(w, g.waterfallChart().rasterize(waterfallPNGScale)); err != nil {
+		return fmt.Errorf("failed to write waterfall PNG: %w", err)
+	}
+	return nil
+}
+
+// waterfallChart lays out the waterfall of every file, one below the other.
+func (g *Generator) waterfallChart() *waterfallChart {
+	chart := &waterfallChart{width: waterfallWidth}
+	y := waterfallPadding
+	for i, analyzer := range g.analyzers {
+		y = chart.addFile(g.FileName(i), analyzer, g.harFiles[i].Log.Entries, y) + 2*waterfallPadding
+	}
+
+	// Legend
+	x := waterfallPadding
+	for _, phase := range waterfallPhases {
+		chart.rects = append(chart.rects, chartRect{x, y + 2, 10, 10, phase.color})
+		chart.texts = append(chart.texts, chartText{x + 14, y + 2, phase.name, "#333333"})
+		x += 14 + float64(len(phase.name)+3)*6.6
+	}
+	chart.lines = append(chart.lines, chartLine{x, y, x, y + 14, "#1d73f5", true})
+	chart.texts = append(chart.texts, chartText{x + 6, y + 2, "DOMContentLoaded", "#333333"})
+	x += 6 + 19*6.6
+	chart.lines = append(chart.lines, chartLine{x, y, x, y + 14, "#d32f2f", true})
+	chart.texts = append(chart.texts, chartText{x + 6, y + 2, "onLoad", "#333333"})
+
+	chart.height = y + 14 + waterfallPadding
+	return chart
+}
+
+// addFile lays out one file's waterfall from y down and returns where it ends.
+func (c *waterfallChart) addFile(name string, analyzer *har.Analyzer, entries []har.Entry, y float64) float64 {
+	timeline := analyzer.GenerateTimeline()
+	start := analyzer.PageStart()
+	page := analyzer.Page()
+
+	var span float64
+	for _, event := range timeline {
+		span = max(span, msSince(start, event.StartTime)+event.Duration)
+	}
+	pageOffset := msSince(start, page.StartedDateTime)
+	span = max(span, pageOffset+float64(page.PageTimings.OnLoad))
+
+	c.texts = append(c.texts, chartText{waterfallPadding, y, fmt.Sprintf("%s - %d requests, %s", name, len(timeline), waterfallDuration(span)), "#111111"})
+	y += 2 * waterfallRowHeight
+
+	chartX := waterfallPadding + waterfallLabelWidth
+	chartWidth := waterfallWidth - chartX - waterfallPadding - 60
+	scale := chartWidth / math.Max(span, 1)
+	top := y
+
+	// Time axis
+	step := waterfallTickStep(span)
+	for tick := 0.0; tick <= span; tick += step {
+		x := chartX + tick*scale
+		c.texts = append(c.texts, chartText{x - 3, y - 14, waterfallDuration(tick), "#666666"})
+	}
+	bottom := y + float64(len(timeline))*waterfallRowHeight
+	for tick := 0.0; tick <= span; tick += step {
+		x := chartX + tick*scale
+		c.lines = append(c.lines, chartLine{x, top, x, bottom, "#eeeeee", false})
+	}
+
+	for row, event := range timeline {
+		entry := entries[event.Index]
+		if row%2 == 1 {
+			c.rects = append(c.rects, chartRect{waterfallPadding, y, waterfallWidth - 2*waterfallPadding, waterfallRowHeight, "#f7f7f7"})
+		}
+
+		labelColor := "#333333"
+		if entry.Response.Status >= 400 || entry.Response.Status == 0 {
+			labelColor = "#d32f2f"
+		}
+		label := fmt.Sprintf("%d %s %s", entry.Response.Status, entry.Request.Method, dotURL(entry.Request.URL))
+		if runes := []rune(label); len(runes) > waterfallLabelChars {
+			label = string(runes[:waterfallLabelChars-3]) + "..."
+		}
+		c.texts = append(c.texts, chartText{waterfallPadding + 4, y + 3, label, labelColor})
+
+		x := chartX + msSince(start, event.StartTime)*scale
+		barY := y + (waterfallRowHeight-waterfallBarHeight)/2
+		phased := 0.0
+		for _, phase := range waterfallPhases {
+			duration := float64(max(phase.value(entry.Timings), 0))
+			if duration == 0 {
+				continue
+			}
+			c.rects = append(c.rects, chartRect{x + phased*scale, barY, math.Max(duration*scale, 1), waterfallBarHeight, phase.color})
+			phased += duration
+		}
+		if phased == 0 {
+			c.rects = append(c.rects, chartRect{x, barY, math.Max(event.Duration*scale, 1), waterfallBarHeight, waterfallPhases[5].color})
+		}
+		c.texts = append(c.texts, chartText{x + math.Max(phased, event.Duration)*scale + 4, y + 3, waterfallDuration(event.Duration), "#666666"})
+		y += waterfallRowHeight
+	}
+
+	for _, marker := range []struct {
+		at    int
+		color string
+	}{{page.PageTimings.OnContentLoad, "#1d73f5"}, {page.PageTimings.OnLoad, "#d32f2f"}} {
+		if marker.at > 0 {
+			x := chartX + (pageOffset+float64(marker.at))*scale
+			c.lines = append(c.lines, chartLine{x, top, x, bottom, marker.color, true})
+		}
+	}
+	return bottom
+}
+
+// waterfallTickStep picks a round axis step giving at most ten ticks.
+func waterfallTickStep(span float64) float64 {
+	for _, step := range []float64{10, 20, 50, 100, 200, 500, 1000, 2000, 5000, 10000, 30000, 60000} {
+		if span/step <= 10 {
+			return step
+		}
+	}
+	return math.Ceil(span/10/60000) * 60000
+}
+
+// waterfallDuration formats milliseconds as "450ms" or "1.25s".
+func waterfallDuration(ms float64) string {
+	if ms < 1000 {
+		return fmt.Sprintf("%.0fms", ms)
+	}
+	return strconv.FormatFloat(ms/1000, 'f', -1, 64) + "s"
+}
+
+func msSince(start, at time.Time) float64 {
+	return float64(at.Sub(start)) / float64(time.Millisecond)
+}
+
+func (c *waterfallChart) writeSVG(w io.Writer) {
+	fmt.Fprintf(w, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f" viewBox="0 0 %.0f %.0f">`+"\n", c.width, c.height, c.width, c.height)
+	fmt.Fprintln(w, `<rect width="100%" height="100%" fill="#ffffff"/>`)
+	for _, r := range c.rects {
+		fmt.Fprintf(w, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s"/>`+"\n", r.x, r.y, r.width, r.height, r.color)
+	}
+	for _, l := range c.lines {
+		dash := ""
+		if l.dashed {
+			dash = ` stroke-dasharray="4 3"`
+		}
+		fmt.Fprintf(w, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="%s"%s/>`+"\n", l.x1, l.y1, l.x2, l.y2, l.color, dash)
+	}
+	fmt.Fprintln(w, `<g font-family="Menlo, Consolas, 'DejaVu Sans Mono', monospace" font-size="11" xml:space="preserve">`)
+	for _, t := range c.texts {
+		fmt.Fprintf(w, `<text x="%.1f" y="%.1f" fill="%s">%s</text>`+"\n", t.x, t.y+9, t.color, template.HTMLEscapeString(t.text))
+	}
+	fmt.Fprintln(w, "</g>")
+	fmt.Fprintln(w, "</svg>")
+}
+
+// rasterize draws the chart at scale times its size.
+func (c *waterfallChart) rasterize(scale int) *image.RGBA {
+	s := float64(scale)
+	img := image.NewRGBA(image.Rect(0, 0, int(c.width*s), int(c.height*s)))
+	fill := func(x0, y0, x1, y1 int, c color.RGBA) {
+		for y := max(y0, 0); y < min(y1, img.Bounds().Dy()); y++ {
+			for x := max(x0, 0); x < min(x1, img.Bounds().Dx()); x++ {
+				img.SetRGBA(x, y, c)
+			}
+		}
+	}
+	fill(0, 0, img.Bounds().Dx(), img.Bounds().Dy(), color.RGBA{255, 255, 255, 255})
+
+	for _, r := range c.rects {
+		x0, y0 := int(r.x*s), int(r.y*s)
+		fill(x0, y0, max(int((r.x+r.width)*s), x0+1), max(int((r.y+r.height)*s), y0+1), hexColor(r.color))
+	}
+	for _, l := range c.lines {
+		// Chart lines are vertical
+		x := int(l.x1 * s)
+		for y := int(l.y1 * s); y < int(l.y2*s); y++ {
+			if !l.dashed || (y/(scale*4))%2 == 0 {
+				fill(x, y, x+scale/2+1, y+1, hexColor(l.color))
+			}
+		}
+	}
+	for _, t := range c.texts {
+		// Center the 7px glyphs on the 11px text line
+		drawText(img, int(t.x*s), int((t.y+2)*s), scale, t.text, hexColor(t.color))
+	}
+	return img
+}
+
+// hexColor parses "#rrggbb".
+func hexColor(hex string) color.RGBA {
+	value, _ := strconv.ParseUint(hex[1:], 16, 32)
+	return color.RGBA{uint8(value >> 16), uint8(value >> 8), uint8(value), 255}
+}
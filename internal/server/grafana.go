@@ -0,0 +1,73 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/jlgore/hartea/internal/report"
+	"net/http"
+	"time"
+)
+
+// grafanaQuery is the subset of a Grafana JSON datasource /query request we use.
+type grafanaQuery struct {
+	Range struct {
+		From time.Time `json:"from"`
+		To   time.Time `json:"to"`
+	} `json:"range"`
+	Targets []struct {
+		Target string `json:"target"`
+		Hide   bool   `json:"hide"`
+	} `json:"targets"`
+}
+
+// handleGrafanaHealth answers the datasource "Save & test" check.
+func (s *Server) handleGrafanaHealth(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprintln(w, "OK")
+}
+
+func (s *Server) handleGrafanaMetrics(w http.ResponseWriter, r *http.Request) {
+	snapshots, err := s.history.Load()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, report.GrafanaMetrics(snapshots))
+}
+
+// handleGrafanaSearch serves the legacy /search call, which expects plain metric names.
+func (s *Server) handleGrafanaSearch(w http.ResponseWriter, r *http.Request) {
+	snapshots, err := s.history.Load()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	names := []string{}
+	for _, metric := range report.GrafanaMetrics(snapshots) {
+		names = append(names, metric.Value)
+	}
+	writeJSON(w, names)
+}
+
+func (s *Server) handleGrafanaQuery(w http.ResponseWriter, r *http.Request) {
+	var query grafanaQuery
+	if err := json.NewDecoder(r.Body).Decode(&query); err != nil {
+		http.Error(w, fmt.Sprintf("invalid query: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	snapshots, err := s.history.Load()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	series := []report.GrafanaSeries{}
+	for _, target := range query.Targets {
+		if target.Hide || target.Target == "" {
+			continue
+		}
+		series = append(series, report.GrafanaTimeseries(snapshots, target.Target, query.Range.From, query.Range.To)...)
+	}
+	writeJSON(w, series)
+}
@@ -0,0 +1,141 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/jlgore/hartea/internal/har"
+	"github.com/jlgore/hartea/internal/report"
+	"github.com/jlgore/hartea/internal/store"
+	"net/http"
+	"strconv"
+)
+
+// Server hosts the interactive HTML report and a read-only JSON API for a set
+// of loaded HAR files, plus a Grafana JSON datasource over the metrics history.
+type Server struct {
+	generator *report.Generator
+	html      string
+	history   *store.Store
+}
+
+// New prepares a server for HAR files labeled with names and analyzed with
+// the settings. Without HAR files only the Grafana datasource is served.
+func New(harFiles []*har.HAR, names []string, settings har.Settings, history *store.Store) (*Server, error) {
+	if len(harFiles) == 0 {
+		return &Server{history: history}, nil
+	}
+
+	generator := report.NewGeneratorFromHAR(harFiles, names, settings)
+
+	// The captures never change while serving, so render the page once
+	html, err := generator.HTMLContent()
+	if err != nil {
+		return nil, fmt.Errorf("failed to render report: %w", err)
+	}
+
+	return &Server{generator: generator, html: html, history: history}, nil
+}
+
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	if s.generator != nil {
+		mux.HandleFunc("GET /{$}", s.handleReport)
+		mux.HandleFunc("GET /api/metrics", s.handleMetrics)
+		mux.HandleFunc("GET /api/entries", s.handleEntries)
+		mux.HandleFunc("GET /api/export/{format}", s.handleExport)
+	}
+	if s.history != nil {
+		mux.HandleFunc("GET /grafana/{$}", s.handleGrafanaHealth)
+		mux.HandleFunc("GET /grafana", s.handleGrafanaHealth)
+		mux.HandleFunc("POST /grafana/metrics", s.handleGrafanaMetrics)
+		mux.HandleFunc("POST /grafana/search", s.handleGrafanaSearch)
+		mux.HandleFunc("POST /grafana/query", s.handleGrafanaQuery)
+	}
+	return mux
+}
+
+// ListenAndServe serves the report on addr until the server fails.
+func (s *Server) ListenAndServe(addr string) error {
+	if err := http.ListenAndServe(addr, s.Handler()); err != nil {
+		return fmt.Errorf("failed to serve report: %w", err)
+	}
+	return nil
+}
+
+func (s *Server) handleReport(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprint(w, s.html)
+}
+
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, s.generator.GenerateReport(false))
+}
+
+// handleEntries returns flattened entries, optionally limited to one file
+// with ?file=N (1-based, in load order).
+func (s *Server) handleEntries(w http.ResponseWriter, r *http.Request) {
+	records := s.generator.EntryRecords()
+
+	if file := r.URL.Query().Get("file"); file != "" {
+		index, err := strconv.Atoi(file)
+		if err != nil || index < 1 {
+			http.Error(w, "file must be a positive integer", http.StatusBadRequest)
+			return
+		}
+		name := s.generator.FileName(index - 1)
+		filtered := []report.EntryRecord{}
+		for _, record := range records {
+			if record.File == name {
+				filtered = append(filtered, record)
+			}
+		}
+		records = filtered
+	}
+
+	writeJSON(w, records)
+}
+
+// exportContentTypes are the media types of the export formats.
+var exportContentTypes = map[string]string{
+	"json":        "application/json",
+	"csv":         "text/csv; charset=utf-8",
+	"entries-csv": "text/csv; charset=utf-8",
+	"ndjson":      "application/x-ndjson",
+	"html":        "text/html; charset=utf-8",
+	"pdf":         "application/pdf",
+	"prometheus":  "text/plain; version=0.0.4",
+	"sarif":       "application/sarif+json",
+	"findings":    "application/json",
+	"trends-csv":  "text/csv; charset=utf-8",
+	"trends-json": "application/json",
+}
+
+// handleExport streams the report in any export format, e.g.
+// /api/export/csv, with ?entries=true to include entries in JSON.
+func (s *Server) handleExport(w http.ResponseWriter, r *http.Request) {
+	format := r.PathValue("format")
+	contentType, ok := exportContentTypes[format]
+	if !ok {
+		http.Error(w, fmt.Sprintf("unknown export format %q", format), http.StatusNotFound)
+		return
+	}
+
+	// Render first so a failure can still be reported as an error status
+	var body bytes.Buffer
+	if err := s.generator.Write(&body, format, r.URL.Query().Get("entries") == "true"); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	body.WriteTo(w)
+}
+
+func writeJSON(w http.ResponseWriter, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		http.Error(w, fmt.Sprintf("failed to encode JSON: %v", err), http.StatusInternalServerError)
+	}
+}
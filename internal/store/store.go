@@ -0,0 +1,132 @@
+package store
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/jlgore/hartea/internal/har"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Snapshot records the metrics of one capture at the time it was taken.
+type Snapshot struct {
+	Time    time.Time          `json:"time"`
+	Label   string             `json:"label"`
+	Source  string             `json:"source,omitempty"`
+	Metrics map[string]float64 `json:"metrics"`
+}
+
+// Store is an append-only history of metric snapshots kept as NDJSON, one
+// file per workspace, so recurring captures (e.g. nightly runs) can be trended.
+type Store struct {
+	Path string
+}
+
+// Dir returns the directory holding all metric histories.
+func Dir() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate config directory: %w", err)
+	}
+	return filepath.Join(configDir, "hartea", "history"), nil
+}
+
+// Open returns the store for a workspace, or the default store when name is empty.
+func Open(name string) (*Store, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+	if name == "" {
+		name = "default"
+	}
+	return &Store{Path: filepath.Join(dir, name+".ndjson")}, nil
+}
+
+// NewSnapshot captures every measured metric of a HAR file, analyzed with
+// the settings. The capture time is taken from the first page or entry,
+// falling back to now.
+func NewSnapshot(harFile *har.HAR, label, source string, settings har.Settings) Snapshot {
+	metrics := har.NewAnalyzer(harFile, settings).CalculateMetrics()
+
+	snapshot := Snapshot{
+		Time:    captureTime(harFile),
+		Label:   label,
+		Source:  source,
+		Metrics: make(map[string]float64),
+	}
+	for _, descriptor := range har.MetricDescriptors() {
+		if descriptor.Measured(metrics) {
+			snapshot.Metrics[descriptor.ID] = descriptor.Value(metrics)
+		}
+	}
+
+	return snapshot
+}
+
+func captureTime(harFile *har.HAR) time.Time {
+	if start := harFile.Log.PrimaryPage().StartedDateTime; !start.IsZero() {
+		return start
+	}
+	return time.Now()
+}
+
+func (s *Store) Append(snapshots ...Snapshot) error {
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0o755); err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+
+	file, err := os.OpenFile(s.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open history: %w", err)
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	for _, snapshot := range snapshots {
+		if err := encoder.Encode(snapshot); err != nil {
+			return fmt.Errorf("failed to write snapshot: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Load returns all snapshots in chronological order. A missing history is empty.
+func (s *Store) Load() ([]Snapshot, error) {
+	file, err := os.Open(s.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history: %w", err)
+	}
+	defer file.Close()
+
+	var snapshots []Snapshot
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var snapshot Snapshot
+		if err := json.Unmarshal(scanner.Bytes(), &snapshot); err != nil {
+			return nil, fmt.Errorf("failed to parse history line %d: %w", line, err)
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		return snapshots[i].Time.Before(snapshots[j].Time)
+	})
+
+	return snapshots, nil
+}
@@ -0,0 +1,133 @@
+package tui
+
+import (
+	"fmt"
+	"github.com/jlgore/hartea/internal/har"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// updateComparison compares the files in comparison order, the first being
+// the baseline. There is nothing to compare with fewer than two files.
+func (m *Model) updateComparison() {
+	if len(m.comparisonOrder) < 2 {
+		m.comparison = nil
+		return
+	}
+	names := make([]string, len(m.comparisonOrder))
+	metrics := make([]*har.Metrics, len(m.comparisonOrder))
+	for i, file := range m.comparisonOrder {
+		names[i] = m.fileNames[file]
+		metrics[i] = m.analyzers[file].CalculateMetrics()
+	}
+	m.comparison = har.NewComparator(names, metrics, m.settings).Compare()
+}
+
+// baselineFile is the file the others are compared against.
+func (m Model) baselineFile() int {
+	if len(m.comparisonOrder) == 0 {
+		return 0
+	}
+	return m.comparisonOrder[0]
+}
+
+// comparisonIndex is the position of a file in the comparison, or -1 when it
+// is left out.
+func (m Model) comparisonIndex(file int) int {
+	return slices.Index(m.comparisonOrder, file)
+}
+
+// comparedFiles returns the files and analyzers in comparison order, e.g. for
+// reports, which take the first file as the baseline.
+func (m Model) comparedFiles() ([]*har.HAR, []*har.Analyzer) {
+	order := m.comparisonOrder
+	if len(order) < 2 {
+		order = make([]int, len(m.harFiles))
+		for i := range order {
+			order[i] = i
+		}
+	}
+	files := make([]*har.HAR, len(order))
+	analyzers := make([]*har.Analyzer, len(order))
+	for i, file := range order {
+		files[i], analyzers[i] = m.scopedFile(file)
+	}
+	return files, analyzers
+}
+
+// makeBaseline compares the other files against the current one.
+func (m *Model) makeBaseline() tea.Cmd {
+	index := m.comparisonIndex(m.currentFile)
+	if index == 0 {
+		return m.showToast(m.fileNames[m.currentFile]+" is already the baseline", false)
+	}
+	if index < 0 {
+		m.comparisonOrder = append([]int{m.currentFile}, m.comparisonOrder...)
+	} else {
+		m.comparisonOrder = append([]int{m.currentFile}, slices.Delete(m.comparisonOrder, index, index+1)...)
+	}
+	m.updateComparison()
+	m.comparisonSelected = 0
+	return m.showToast("Comparing against "+m.fileNames[m.currentFile], false)
+}
+
+// moveInComparison moves the current file earlier or later in the
+// comparison; moving it first makes it the baseline.
+func (m *Model) moveInComparison(delta int) tea.Cmd {
+	index := m.comparisonIndex(m.currentFile)
+	target := index + delta
+	if index < 0 || target < 0 || target >= len(m.comparisonOrder) {
+		return nil
+	}
+	m.comparisonOrder[index], m.comparisonOrder[target] = m.comparisonOrder[target], m.comparisonOrder[index]
+	m.updateComparison()
+	m.comparisonSelected = 0
+	return nil
+}
+
+// toggleInComparison leaves the current file out of the comparison, or puts
+// it back at the end. At least two files stay compared.
+func (m *Model) toggleInComparison() tea.Cmd {
+	index := m.comparisonIndex(m.currentFile)
+	if index < 0 {
+		m.comparisonOrder = append(m.comparisonOrder, m.currentFile)
+		m.updateComparison()
+		return m.showToast("Added "+m.fileNames[m.currentFile]+" to the comparison", false)
+	}
+	if len(m.comparisonOrder) <= 2 {
+		return m.showToast("A comparison needs at least two files", true)
+	}
+	m.comparisonOrder = slices.Delete(m.comparisonOrder, index, index+1)
+	m.updateComparison()
+	m.comparisonSelected = 0
+	return m.showToast("Left "+m.fileNames[m.currentFile]+" out of the comparison", false)
+}
+
+// renderComparisonOrder lists the files in comparison order, marking the
+// baseline, the current file and the files left out.
+func (m Model) renderComparisonOrder() string {
+	var files []string
+	for i, file := range m.comparisonOrder {
+		name := fmt.Sprintf("%d. %s", i+1, m.fileNames[file])
+		if i == 0 {
+			name += " (baseline)"
+		}
+		if file == m.currentFile {
+			name = timelineCursorStyle.Render(name)
+		}
+		files = append(files, name)
+	}
+	for file := range m.harFiles {
+		if m.comparisonIndex(file) >= 0 {
+			continue
+		}
+		name := m.fileNames[file] + " (left out)"
+		if file == m.currentFile {
+			name = timelineCursorStyle.Render(name)
+		}
+		files = append(files, statusStyle.Render(name))
+	}
+	return "Files: " + strings.Join(files, "  ")
+}
@@ -0,0 +1,126 @@
+package tui
+
+import (
+	"fmt"
+	"github.com/jlgore/hartea/internal/har"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbletea"
+)
+
+func newBodySearch() textinput.Model {
+	input := textinput.New()
+	input.Placeholder = "text, or /regular expression/"
+	input.CharLimit = 256
+	return input
+}
+
+// openBodySearch switches to the body search view and starts typing a
+// search.
+func (m Model) openBodySearch() (tea.Model, tea.Cmd) {
+	m.currentView = BodySearchView
+	m.bodySearching = true
+	m.bodySearch.Focus()
+	return m, textinput.Blink
+}
+
+// updateBodySearch edits the search. Enter runs it over the current file's
+// request and response bodies, Esc stops editing.
+func (m Model) updateBodySearch(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.bodySearching = false
+		m.bodySearch.Blur()
+		if m.bodySearch.Value() == "" {
+			m.currentView = TableView
+		}
+		return m, nil
+	case "enter":
+		m.bodySearching = false
+		m.bodySearch.Blur()
+		return m, m.runBodySearch()
+	}
+
+	var cmd tea.Cmd
+	m.bodySearch, cmd = m.bodySearch.Update(msg)
+	return m, cmd
+}
+
+// runBodySearch lists the bodies that match the search.
+func (m *Model) runBodySearch() tea.Cmd {
+	m.bodyMatches, m.bodyMatchSelected = nil, 0
+	query := m.bodySearch.Value()
+	if query == "" {
+		return nil
+	}
+	pattern, err := har.ParseBodySearch(query)
+	if err != nil {
+		return m.showToast(err.Error(), true)
+	}
+	m.bodyMatches = har.SearchBodies(m.harFiles[m.currentFile].Log.Entries, pattern)
+	return nil
+}
+
+// moveBodyMatchSelection moves the highlighted search result.
+func (m *Model) moveBodyMatchSelection(delta int) {
+	m.bodyMatchSelected = max(0, min(m.bodyMatchSelected+delta, len(m.bodyMatches)-1))
+}
+
+// inspectBodyMatchSelection opens the highlighted result in the detail view.
+func (m *Model) inspectBodyMatchSelection() {
+	if m.bodyMatchSelected < len(m.bodyMatches) {
+		m.inspectEntry(m.bodyMatches[m.bodyMatchSelected].EntryIndex, BodySearchView)
+	}
+}
+
+// renderBodySearchView lists the matching requests, two lines each: the
+// request and which body matched, then the first match in context.
+func (m Model) renderBodySearchView() string {
+	content := []string{titleStyle.Render("Body Search"), "", "Search: " + m.bodySearch.View(), ""}
+
+	entries := m.harFiles[m.currentFile].Log.Entries
+	switch {
+	case m.bodySearch.Value() == "" || m.bodySearching && m.bodyMatches == nil:
+		content = append(content, statusStyle.Render("Searches request and response bodies for text (ignoring case) or a /regular expression/; binary bodies are skipped"))
+	case len(m.bodyMatches) == 0:
+		content = append(content, "No bodies match")
+	default:
+		if len(m.bodyMatches) == 1 {
+			content = append(content, "1 body matches", "")
+		} else {
+			content = append(content, fmt.Sprintf("%d bodies match", len(m.bodyMatches)), "")
+		}
+
+		// Scroll the results so the selection stays in view
+		visible := max((m.height-12)/2, 1)
+		offset := max(m.bodyMatchSelected-visible+1, 0)
+		for i := offset; i < min(len(m.bodyMatches), offset+visible); i++ {
+			match := m.bodyMatches[i]
+			entry := entries[match.EntryIndex]
+			side := "request body"
+			if match.Response {
+				side = "response body"
+			}
+			if match.Count > 1 {
+				side = fmt.Sprintf("%s, %d matches", side, match.Count)
+			}
+
+			title := fmt.Sprintf("#%d %s %s", match.EntryIndex+1, entry.Request.Method, entry.Request.URL)
+			title = truncateURL(title, max(m.width-len(side)-8, 20))
+			if i == m.bodyMatchSelected {
+				content = append(content, focusedStyle.Render("> "+title)+" "+statusStyle.Render("("+side+")"))
+			} else {
+				content = append(content, "  "+title+" "+statusStyle.Render("("+side+")"))
+			}
+			content = append(content, "    "+match.Before+headerStyle.Render(match.Match)+match.After)
+		}
+	}
+
+	help := "↑↓ to choose, Enter to open the request, / to search again, Esc to go back"
+	if m.bodySearching {
+		help = "Enter to search, Esc to cancel"
+	}
+	content = append(content, "", statusStyle.Render(help))
+	return strings.Join(content, "\n")
+}
@@ -0,0 +1,49 @@
+package tui
+
+import (
+	"fmt"
+	"net/url"
+	"os/exec"
+	"runtime"
+
+	"github.com/charmbracelet/bubbletea"
+)
+
+type browserOpenedMsg struct {
+	url string
+	err error
+}
+
+// browserCommand opens a URL in the system's default browser.
+func browserCommand(target string) *exec.Cmd {
+	switch runtime.GOOS {
+	case "darwin":
+		return exec.Command("open", target)
+	case "windows":
+		return exec.Command("rundll32", "url.dll,FileProtocolHandler", target)
+	default:
+		return exec.Command("xdg-open", target)
+	}
+}
+
+// openInBrowser opens the highlighted request's URL in the default browser.
+// Only http and https URLs are opened, not data: or extension URLs.
+func (m Model) openInBrowser() (tea.Model, tea.Cmd) {
+	index := m.currentEntryIndex()
+	if index < 0 {
+		return m, nil
+	}
+	target := m.harFiles[m.currentFile].Log.Entries[index].Request.URL
+	if parsed, err := url.Parse(target); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return m, m.showToast("Only http and https URLs open in the browser", true)
+	}
+
+	return m, func() tea.Msg {
+		// Leaving stdout and stderr unset keeps the browser's output off the
+		// screen
+		if err := browserCommand(target).Run(); err != nil {
+			return browserOpenedMsg{url: target, err: fmt.Errorf("failed to open the browser: %w", err)}
+		}
+		return browserOpenedMsg{url: target}
+	}
+}
@@ -0,0 +1,183 @@
+package tui
+
+import (
+	"fmt"
+	"github.com/jlgore/hartea/internal/har"
+	"github.com/jlgore/hartea/internal/report"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbletea"
+	"github.com/muesli/termenv"
+)
+
+type bulkAction int
+
+const (
+	bulkExportHAR bulkAction = iota
+	bulkExportCSV
+	bulkCopyURLs
+	bulkHide
+	bulkDiff
+)
+
+var bulkActionNames = []string{
+	"Export as HAR",
+	"Export as CSV",
+	"Copy URLs",
+	"Hide from this session",
+	"Diff the two requests",
+}
+
+// BulkDialog picks an action for the selected requests.
+type BulkDialog struct {
+	active bool
+	cursor int
+}
+
+// toggleSelection selects or deselects the request under the cursor and
+// moves on to the next, so runs of requests are quick to select.
+func (m *Model) toggleSelection() {
+	row := m.table.Cursor()
+	if row < 0 || row >= len(m.entryIndices) {
+		return
+	}
+	if m.selectedEntries == nil {
+		m.selectedEntries = make(map[int]bool)
+	}
+	index := m.entryIndices[row]
+	if m.selectedEntries[index] {
+		delete(m.selectedEntries, index)
+	} else {
+		m.selectedEntries[index] = true
+	}
+	m.updateTableRows()
+	m.table.MoveDown(1)
+}
+
+// selectedIndices are the selected loaded entries in capture order.
+func (m Model) selectedIndices() []int {
+	indices := make([]int, 0, len(m.selectedEntries))
+	for index := range m.selectedEntries {
+		indices = append(indices, index)
+	}
+	sort.Ints(indices)
+	return indices
+}
+
+// selectionTitle counts the selected requests in the table header.
+func (m Model) selectionTitle() string {
+	if len(m.selectedEntries) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("Selected %d requests: %s for bulk actions, %s to clear", len(m.selectedEntries), m.keys.Bulk.Help().Key, m.keys.Back.Help().Key)
+}
+
+func (m Model) openBulkDialog() (tea.Model, tea.Cmd) {
+	if len(m.selectedEntries) == 0 {
+		return m, m.showToast(fmt.Sprintf("Select requests with %s first", m.keys.Select.Help().Key), true)
+	}
+	m.bulkDialog = BulkDialog{active: true}
+	return m, nil
+}
+
+func (m Model) updateBulkDialog(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	d := &m.bulkDialog
+
+	switch msg.String() {
+	case "esc":
+		d.active = false
+	case "up", "k":
+		if d.cursor > 0 {
+			d.cursor--
+		}
+	case "down", "j":
+		if d.cursor < len(bulkActionNames)-1 {
+			d.cursor++
+		}
+	case "enter":
+		d.active = false
+		return m.runBulkAction(bulkAction(d.cursor))
+	}
+	return m, nil
+}
+
+func (m Model) runBulkAction(action bulkAction) (tea.Model, tea.Cmd) {
+	indices := m.selectedIndices()
+	entries := m.harFiles[m.currentFile].Log.Entries
+
+	switch action {
+	case bulkExportHAR, bulkExportCSV:
+		return m, m.bulkExportCmd(action, indices)
+
+	case bulkCopyURLs:
+		urls := make([]string, len(indices))
+		for i, index := range indices {
+			urls[i] = entries[index].Request.URL
+		}
+		termenv.Copy(strings.Join(urls, "\n"))
+		return m, m.showToast(fmt.Sprintf("Copied %d URLs", len(urls)), false)
+
+	case bulkHide:
+		hidden := har.HideEntries(m.harFiles[m.currentFile], indices)
+		m.refreshAnalysis()
+		return m, m.showToast(fmt.Sprintf("Hid %d requests for this session", hidden), false)
+
+	case bulkDiff:
+		if len(indices) != 2 {
+			return m, m.showToast(fmt.Sprintf("Select exactly two requests to diff, not %d", len(indices)), true)
+		}
+		m.openDiff(entryRef{m.currentFile, indices[0]}, entryRef{m.currentFile, indices[1]})
+	}
+	return m, nil
+}
+
+// bulkExportCmd writes the selected requests to a new file in the working
+// directory: a HAR with the capture's pages, or the entries CSV.
+func (m Model) bulkExportCmd(action bulkAction, indices []int) tea.Cmd {
+	source := m.harFiles[m.currentFile]
+	subset := &har.HAR{Log: source.Log}
+	subset.Log.Entries = make([]har.Entry, len(indices))
+	for i, index := range indices {
+		subset.Log.Entries[i] = source.Log.Entries[index]
+	}
+	subset.Log.Excluded, subset.Log.Hidden = nil, nil
+	name := m.fileNames[m.currentFile]
+
+	basePath := fmt.Sprintf("selected-%s", time.Now().Format("2006-01-02_15-04-05"))
+	return func() tea.Msg {
+		var filename string
+		var err error
+		if action == bulkExportHAR {
+			filename = basePath + ".har"
+			err = report.ExportHAR(filename, subset)
+		} else {
+			filename = basePath + ".csv"
+			generator := report.NewGenerator([]*har.HAR{subset}, []*har.Analyzer{har.NewAnalyzer(subset, m.settings)}, nil, m.settings)
+			generator.SetFileNames([]string{name})
+			err = generator.ExportEntriesCSV(filename)
+		}
+		if err != nil {
+			return exportDoneMsg{err: fmt.Errorf("%s export failed: %w", bulkActionNames[action], err)}
+		}
+		return exportDoneMsg{files: []string{filename}}
+	}
+}
+
+func (m Model) renderBulkDialog() string {
+	d := m.bulkDialog
+	lines := []string{titleStyle.Render(fmt.Sprintf("Bulk Actions (%d selected)", len(m.selectedEntries))), ""}
+	for i, name := range bulkActionNames {
+		if bulkAction(i) == bulkDiff && len(m.selectedEntries) != 2 {
+			name = statusStyle.Render(name + " (select exactly two)")
+		}
+		if i == d.cursor {
+			lines = append(lines, focusedStyle.Render("> ")+name)
+		} else {
+			lines = append(lines, "  "+name)
+		}
+	}
+	lines = append(lines, "", statusStyle.Render("↑↓ to choose, Enter to run, Esc to cancel"))
+	return strings.Join(lines, "\n")
+}
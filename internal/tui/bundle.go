@@ -0,0 +1,193 @@
+package tui
+
+import (
+	"fmt"
+	"github.com/jlgore/hartea/internal/har"
+	"github.com/jlgore/hartea/internal/report"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbletea"
+)
+
+type bundleStep int
+
+const (
+	bundlePickBaseline bundleStep = iota
+	bundlePickCandidate
+	bundlePickRule
+	bundleConfirm
+)
+
+var matchRuleDescriptions = map[har.MatchRule]string{
+	har.MatchExact:       "Exact URL (method and full URL)",
+	har.MatchIgnoreQuery: "Ignore query strings (cache busters, tracking parameters)",
+	har.MatchPath:        "Path only (compare across hosts, e.g. staging vs production)",
+}
+
+// BundleDialog walks through exporting a before/after comparison of two files.
+type BundleDialog struct {
+	active    bool
+	step      bundleStep
+	cursor    int
+	baseline  int
+	candidate int
+	rule      har.MatchRule
+	filename  textinput.Model
+}
+
+func newBundleDialog() BundleDialog {
+	filename := textinput.New()
+	filename.Placeholder = "before-after-<timestamp>"
+	filename.CharLimit = 256
+	return BundleDialog{filename: filename}
+}
+
+func (m Model) openBundleDialog() (tea.Model, tea.Cmd) {
+	if len(m.harFiles) < 2 {
+		return m, m.showToast("Load at least two files for a before/after bundle", true)
+	}
+	m.bundleDialog = newBundleDialog()
+	m.bundleDialog.active = true
+	m.bundleDialog.cursor = m.baselineFile()
+	return m, nil
+}
+
+// bundleCandidates lists the files that can be compared against the baseline.
+func (m Model) bundleCandidates() []int {
+	var candidates []int
+	for i := range m.harFiles {
+		if i != m.bundleDialog.baseline {
+			candidates = append(candidates, i)
+		}
+	}
+	return candidates
+}
+
+func (m Model) bundleOptionCount() int {
+	switch m.bundleDialog.step {
+	case bundlePickBaseline:
+		return len(m.harFiles)
+	case bundlePickCandidate:
+		return len(m.harFiles) - 1
+	case bundlePickRule:
+		return len(har.MatchRules)
+	}
+	return 0
+}
+
+func (m Model) updateBundleDialog(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	d := &m.bundleDialog
+
+	switch msg.String() {
+	case "esc":
+		if d.step == bundlePickBaseline {
+			d.active = false
+		} else {
+			d.step--
+			d.cursor = 0
+			d.filename.Blur()
+		}
+		return m, nil
+	case "up", "k":
+		if d.step != bundleConfirm && d.cursor > 0 {
+			d.cursor--
+			return m, nil
+		}
+	case "down", "j":
+		if d.step != bundleConfirm && d.cursor < m.bundleOptionCount()-1 {
+			d.cursor++
+			return m, nil
+		}
+	case "enter":
+		switch d.step {
+		case bundlePickBaseline:
+			d.baseline = d.cursor
+		case bundlePickCandidate:
+			d.candidate = m.bundleCandidates()[d.cursor]
+		case bundlePickRule:
+			d.rule = har.MatchRules[d.cursor]
+			d.filename.SetValue("")
+			d.filename.Focus()
+		case bundleConfirm:
+			d.active = false
+			return m, m.bundleCmd()
+		}
+		d.step++
+		d.cursor = 0
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	if d.step == bundleConfirm {
+		d.filename, cmd = d.filename.Update(msg)
+	}
+	return m, cmd
+}
+
+func (m Model) renderBundleDialog() string {
+	d := m.bundleDialog
+	lines := []string{titleStyle.Render("Before/After Bundle"), ""}
+
+	option := func(index int, label string) string {
+		if index == d.cursor {
+			return focusedStyle.Render("> " + label)
+		}
+		return "  " + label
+	}
+
+	switch d.step {
+	case bundlePickBaseline:
+		lines = append(lines, headerStyle.Render("1. Pick the baseline (before)"))
+		for i, name := range m.fileNames {
+			lines = append(lines, option(i, name))
+		}
+	case bundlePickCandidate:
+		lines = append(lines, fmt.Sprintf("Baseline: %s", m.fileNames[d.baseline]), "")
+		lines = append(lines, headerStyle.Render("2. Pick the candidate (after)"))
+		for i, file := range m.bundleCandidates() {
+			lines = append(lines, option(i, m.fileNames[file]))
+		}
+	case bundlePickRule:
+		lines = append(lines, fmt.Sprintf("%s → %s", m.fileNames[d.baseline], m.fileNames[d.candidate]), "")
+		lines = append(lines, headerStyle.Render("3. Match requests by"))
+		for i, rule := range har.MatchRules {
+			lines = append(lines, option(i, matchRuleDescriptions[rule]))
+		}
+	case bundleConfirm:
+		lines = append(lines, fmt.Sprintf("%s → %s, matched by %s", m.fileNames[d.baseline], m.fileNames[d.candidate], d.rule), "")
+		lines = append(lines, headerStyle.Render("4. Write HTML, Markdown and JSON to"))
+		lines = append(lines, "Path: "+d.filename.View())
+	}
+
+	lines = append(lines, "", statusStyle.Render("↑↓ to choose, Enter to continue, Esc to go back"))
+	return strings.Join(lines, "\n")
+}
+
+func (m Model) bundleCmd() tea.Cmd {
+	d := m.bundleDialog
+	baseline, candidate := m.harFiles[d.baseline], m.harFiles[d.candidate]
+	names := [2]string{m.fileNames[d.baseline], m.fileNames[d.candidate]}
+	rule, plain := d.rule, m.plain
+
+	basePath := strings.TrimSpace(d.filename.Value())
+	if basePath == "" {
+		basePath = fmt.Sprintf("before-after-%s", time.Now().Format("2006-01-02_15-04-05"))
+	}
+
+	return func() tea.Msg {
+		if dir := filepath.Dir(basePath); dir != "." {
+			if err := os.MkdirAll(dir, 0o755); err != nil {
+				return exportDoneMsg{err: fmt.Errorf("failed to create output directory: %w", err)}
+			}
+		}
+		files, err := report.ExportBeforeAfterBundle(basePath, baseline, candidate, names, rule, m.settings, plain)
+		if err != nil {
+			return exportDoneMsg{files: files, err: fmt.Errorf("before/after export failed: %w", err)}
+		}
+		return exportDoneMsg{files: files}
+	}
+}
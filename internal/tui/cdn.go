@@ -0,0 +1,28 @@
+package tui
+
+import (
+	"fmt"
+)
+
+// renderCDNCache shows the edge cache hit ratio overall and per domain.
+func (m Model) renderCDNCache() []string {
+	domains := m.analyzers[m.currentFile].CDNCacheStats()
+	if len(domains) == 0 {
+		return nil
+	}
+
+	info := fmt.Sprintf("CDN Hit Ratio: %.1f%% of %d edge responses", m.metrics.CDNHitRatio, m.metrics.CDNResponses)
+	if m.metrics.CDNResponses == 0 {
+		info = "CDN Hit Ratio: N/A (only bypassed responses)"
+	}
+	lines := []string{info}
+	for _, stats := range domains {
+		provider := stats.Provider
+		if provider == "" {
+			provider = "unknown CDN"
+		}
+		lines = append(lines, fmt.Sprintf("  %s %-16s %5.1f%%  %3d hit  %3d miss  %3d bypass",
+			fitWidth(stats.Domain, 32), provider, stats.HitRatio(), stats.Hits, stats.Misses, stats.Bypasses))
+	}
+	return lines
+}
@@ -0,0 +1,62 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// chartBlocks are the partial block characters of one chart cell, in eighths.
+var chartBlocks = []rune{' ', '▁', '▂', '▃', '▄', '▅', '▆', '▇', '█'}
+
+const chartRows = 10
+
+// renderBarChart draws one column per value, chartRows high and scaled to
+// the largest value, which topLabel describes on the y axis. The last line
+// is the x axis.
+func renderBarChart(values []float64, topLabel string, style lipgloss.Style) []string {
+	scale := 0.0
+	for _, value := range values {
+		scale = max(scale, value)
+	}
+
+	var lines []string
+	for row := chartRows - 1; row >= 0; row-- {
+		var line strings.Builder
+		for _, value := range values {
+			eighths := 0
+			if scale > 0 {
+				eighths = int(value/scale*chartRows*8) - row*8
+			}
+			line.WriteRune(chartBlocks[min(max(eighths, 0), 8)])
+		}
+
+		var label string
+		switch row {
+		case chartRows - 1:
+			label = fmt.Sprintf("%7s ┤", topLabel)
+		case 0:
+			label = fmt.Sprintf("%7s ┤", "0")
+		default:
+			label = "        │"
+		}
+		lines = append(lines, label+style.Render(line.String()))
+	}
+	return append(lines, "        └"+strings.Repeat("─", len(values)))
+}
+
+// renderTimeAxis labels the start and end of a chart width columns wide.
+func renderTimeAxis(width int, total time.Duration) string {
+	first, last := formatOffset(0), formatOffset(total)
+	return "         " + first + strings.Repeat(" ", max(width-len(first)-len(last), 1)) + last
+}
+
+// formatOffset renders a time since the start of the capture.
+func formatOffset(offset time.Duration) string {
+	if offset < time.Second {
+		return fmt.Sprintf("%dms", offset.Milliseconds())
+	}
+	return fmt.Sprintf("%.2fs", offset.Seconds())
+}
@@ -0,0 +1,78 @@
+package tui
+
+import (
+	"fmt"
+	"github.com/jlgore/hartea/internal/har"
+
+	"github.com/charmbracelet/bubbles/table"
+)
+
+var (
+	protocolColumn = table.Column{Title: "Protocol", Width: 9}
+	ttfbColumn     = table.Column{Title: "TTFB (ms)", Width: 10}
+)
+
+// baseColumns are the table columns that are always shown.
+func baseColumns() []table.Column {
+	return []table.Column{
+		{Title: "Method", Width: 8},
+		{Title: "Status", Width: 6},
+		{Title: "URL", Width: 60},
+		{Title: "Time (ms)", Width: 10},
+		{Title: "Size", Width: 10},
+		{Title: "Type", Width: 15},
+	}
+}
+
+// setTableColumns lays out the base columns followed by the optional ones
+// that are shown, giving the URL column whatever width is left.
+func (m *Model) setTableColumns() {
+	columns := baseColumns()
+	if m.showTiming {
+		columns = append(columns, ttfbColumn, m.timeFormat().column())
+	}
+	if m.showProtocol {
+		columns = append(columns, protocolColumn)
+	}
+
+	if width := m.tableWidth(); width > 0 {
+		urlWidth := width - 60 // Reserve space for other columns
+		for _, column := range columns[len(baseColumns()):] {
+			urlWidth -= column.Width
+		}
+		if urlWidth > 30 {
+			columns[2].Width = urlWidth
+		}
+	}
+	m.table.SetColumns(columns)
+}
+
+// optionalCells returns an entry's cells for the optional columns that are
+// shown, in column order, with start times in the given format.
+func (m Model) optionalCells(entry har.Entry, times timeFormat) []string {
+	var cells []string
+	if m.showTiming {
+		ttfb := "-"
+		if entry.Timings.Wait >= 0 {
+			ttfb = fmt.Sprintf("%d", entry.Timings.Wait)
+		}
+		cells = append(cells, ttfb, times.cell(entry.StartedDateTime))
+	}
+	if m.showProtocol {
+		cells = append(cells, har.EntryProtocol(entry))
+	}
+	return cells
+}
+
+// toggleColumns shows or hides optional columns. The table renders each row
+// against the columns by index, so rows never get ahead of the columns.
+func (m *Model) toggleColumns(shown *bool) {
+	*shown = !*shown
+	if *shown {
+		m.setTableColumns()
+		m.updateTableRows()
+		return
+	}
+	m.updateTableRows()
+	m.setTableColumns()
+}
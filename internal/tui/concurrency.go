@@ -0,0 +1,63 @@
+package tui
+
+import (
+	"fmt"
+	"github.com/jlgore/hartea/internal/har"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// renderConcurrencyView plots the requests in flight over the capture, with
+// the requests that queued in the browser marked below the time axis.
+func (m Model) renderConcurrencyView() string {
+	chartWidth := max(m.width-12, 20)
+	profile := m.analyzers[m.currentFile].Concurrency(chartWidth)
+
+	content := []string{titleStyle.Render("Requests in Flight"), ""}
+	if profile.Peak == 0 {
+		content = append(content, "No requests to chart")
+		return strings.Join(content, "\n")
+	}
+
+	content = append(content, fmt.Sprintf("Peak: %d requests at %s | Average: %.1f in flight",
+		profile.Peak, formatOffset(profile.PeakAt), profile.Average))
+	if profile.Queued > 0 {
+		content = append(content, fmt.Sprintf("Queued: %d requests waited %.1fms in total before being sent (%.1fms on average)",
+			profile.Queued, profile.QueuedTime, profile.QueuedTime/float64(profile.Queued)))
+	}
+	content = append(content, "")
+
+	values := make([]float64, len(profile.InFlight))
+	scale := 0
+	for i, count := range profile.InFlight {
+		values[i] = float64(count)
+		scale = max(scale, count)
+	}
+	content = append(content, renderBarChart(values, strconv.Itoa(scale), concurrencyStyle)...)
+
+	if profile.Queued > 0 {
+		var queued strings.Builder
+		for _, count := range profile.QueuedSlice {
+			if count > 0 {
+				queued.WriteRune('▲')
+			} else {
+				queued.WriteRune(' ')
+			}
+		}
+		content = append(content, "         "+queuedStyle.Render(queued.String()))
+	}
+	content = append(content, renderTimeAxis(len(values), profile.Slice*time.Duration(len(values))))
+	if profile.Queued > 0 {
+		content = append(content, "")
+		content = append(content, queuedStyle.Render("▲")+fmt.Sprintf(" requests queued %dms or more, e.g. waiting for a connection", har.QueuedThreshold))
+	}
+
+	if queuedOrigins := m.queuedHTTP1Origins(); queuedOrigins > 0 {
+		content = append(content, fmt.Sprintf("⚠️  %d HTTP/1.x origin(s) had more than %d requests in flight; see HTTP Protocols in the metrics view", queuedOrigins, har.HTTP1ConnectionsPerOrigin))
+	}
+
+	content = append(content, "")
+	content = append(content, statusStyle.Render("Press Esc to go back"))
+	return strings.Join(content, "\n")
+}
@@ -0,0 +1,22 @@
+package tui
+
+import "fmt"
+
+// renderConnections lists connection setup and reuse per origin.
+func (m Model) renderConnections() []string {
+	origins := m.analyzers[m.currentFile].ConnectionAnalysis()
+	if len(origins) == 0 {
+		return nil
+	}
+
+	lines := []string{headerStyle.Render("Connections")}
+	lines = append(lines, fmt.Sprintf("New: %d, reuse: %.1f%%, repeated setup: %.1fms", m.metrics.NewConnections, m.metrics.ConnectionReuse, m.metrics.ConnectionSetupWaste))
+	for _, origin := range origins {
+		status := "  "
+		if origin.ExtraConnections() > 0 {
+			status = "⚠️ "
+		}
+		lines = append(lines, fmt.Sprintf("  %s %s %4d requests  %3d new  %3d reused  %8.1fms setup", status, fitWidth(origin.Origin, 40), origin.Requests, origin.NewConnections, origin.Reused, origin.SetupTime))
+	}
+	return lines
+}
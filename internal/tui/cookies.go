@@ -0,0 +1,47 @@
+package tui
+
+import (
+	"fmt"
+	"github.com/jlgore/hartea/internal/har"
+	"strings"
+)
+
+func renderCookies(cookies []har.CookieInfo, units har.SizeUnits) []string {
+	var lines []string
+	for _, cookie := range cookies {
+		direction := "Set "
+		if cookie.Sent {
+			direction = "Sent"
+		}
+		line := fmt.Sprintf("%s %s %8s", direction, fitWidth(cookie.Name, 24), units.FormatSize(cookie.Size))
+		if len(cookie.Issues) > 0 {
+			line += "  " + cookieIssueStyle.Render("⚠️  "+strings.Join(cookie.Issues, ", "))
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// renderCookieSummary shows cookie issues and the per-domain cookie overhead.
+func (m Model) renderCookieSummary() []string {
+	summary := m.analyzers[m.currentFile].CookieSummary()
+	if len(summary.Domains) == 0 {
+		return nil
+	}
+
+	lines := []string{headerStyle.Render("Cookies")}
+	lines = append(lines, fmt.Sprintf("Total Cookie Overhead: %s", m.settings.SizeUnits.FormatSize(summary.TotalBytes)))
+	lines = append(lines, fmt.Sprintf("Insecure Cookies: %d (missing Secure, HttpOnly or SameSite)", summary.Insecure))
+	lines = append(lines, fmt.Sprintf("Oversized Cookies: %d (over %s)", summary.Oversized, m.settings.SizeUnits.FormatSize(har.OversizedCookieBytes)))
+	lines = append(lines, fmt.Sprintf("Cookies Sent to Third Parties: %d", summary.ThirdParty))
+
+	limit := min(len(summary.Domains), 5)
+	for _, domain := range summary.Domains[:limit] {
+		lines = append(lines, fmt.Sprintf("  %s %8s sent  %8s set  (%d cookies)",
+			fitWidth(domain.Domain, 32), m.settings.SizeUnits.FormatSize(domain.SentBytes), m.settings.SizeUnits.FormatSize(domain.SetBytes), domain.Cookies))
+	}
+	if len(summary.Domains) > limit {
+		lines = append(lines, fmt.Sprintf("  ... and %d more domains", len(summary.Domains)-limit))
+	}
+	return lines
+}
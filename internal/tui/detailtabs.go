@@ -0,0 +1,299 @@
+package tui
+
+import (
+	"fmt"
+	"github.com/jlgore/hartea/internal/har"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/muesli/termenv"
+)
+
+// DetailTab is a page of the detail view.
+type DetailTab int
+
+const (
+	OverviewTab DetailTab = iota
+	QueryTab
+	BodyTab
+	HeadersTab
+)
+
+var detailTabNames = []string{"Overview", "Query", "Body", "Headers"}
+
+// queryValueWidth caps the decoded values shown in the query tab.
+const queryValueWidth = 60
+
+// renderDetailTabs draws the tab bar of the detail view.
+func (m Model) renderDetailTabs() string {
+	tabs := make([]string, len(detailTabNames))
+	for i, name := range detailTabNames {
+		if DetailTab(i) == m.detailTab {
+			tabs[i] = activeTabStyle.Render(name)
+		} else {
+			tabs[i] = tabStyle.Render(name)
+		}
+	}
+	return strings.Join(tabs, " ") + statusStyle.Render("  [ ] switch tabs")
+}
+
+// switchDetailTab moves to the next or previous tab, wrapping around.
+func (m *Model) switchDetailTab(delta int) {
+	m.detailTab = DetailTab((int(m.detailTab) + delta + len(detailTabNames)) % len(detailTabNames))
+	m.detailSelected = 0
+}
+
+// moveDetailSelection moves the highlighted row of the query tab, or
+// scrolls the headers tab.
+func (m *Model) moveDetailSelection(delta int) {
+	if m.selectedEntry >= len(m.entries) {
+		return
+	}
+	switch m.detailTab {
+	case QueryTab:
+		params := har.QueryParams(m.entries[m.selectedEntry])
+		m.detailSelected = min(max(m.detailSelected+delta, 0), max(len(params)-1, 0))
+	case HeadersTab:
+		lines := m.headerListLines(m.entries[m.selectedEntry])
+		m.detailSelected = min(max(m.detailSelected+delta, 0), max(len(lines)-m.headerListHeight(), 0))
+	}
+}
+
+// copyDetailSelection copies the selected row's value to the clipboard
+// through the terminal (OSC 52), so it also works over SSH.
+func (m *Model) copyDetailSelection() tea.Cmd {
+	if m.detailTab != QueryTab || m.selectedEntry >= len(m.entries) {
+		return nil
+	}
+	params := har.QueryParams(m.entries[m.selectedEntry])
+	if m.detailSelected >= len(params) {
+		return nil
+	}
+	termenv.Copy(params[m.detailSelected].Value)
+	return m.showToast(fmt.Sprintf("Copied the decoded value of %s", params[m.detailSelected].Name), false)
+}
+
+// saveResponseBody writes the shown request's response body, decoded from
+// base64, to a new file in the working directory named after the request
+// number, with an extension from the MIME type.
+func (m *Model) saveResponseBody() tea.Cmd {
+	if m.selectedEntry >= len(m.entries) {
+		return nil
+	}
+	entry := m.entries[m.selectedEntry]
+	body := har.ResponseBody(entry)
+	if body == "" {
+		return m.showToast("The capture has no response body for this request", true)
+	}
+
+	filename := fmt.Sprintf("response-%d-%s%s", m.entryIndices[m.selectedEntry]+1, time.Now().Format("2006-01-02_15-04-05"),
+		har.BodyExtension(entry.Response.Content.MimeType, entry.Request.URL))
+	return func() tea.Msg {
+		if err := os.WriteFile(filename, []byte(body), 0o644); err != nil {
+			return exportDoneMsg{err: fmt.Errorf("failed to save the response body: %w", err)}
+		}
+		return exportDoneMsg{files: []string{filename}}
+	}
+}
+
+// renderQueryTab lists the request's query parameters with their decoded
+// values, flagging duplicate, empty and suspiciously long ones.
+func (m Model) renderQueryTab(entry har.Entry) []string {
+	params := har.QueryParams(entry)
+	if len(params) == 0 {
+		return []string{"This request has no query parameters"}
+	}
+
+	nameWidth := 4
+	for _, param := range params {
+		nameWidth = min(max(nameWidth, len(param.Name)), 30)
+	}
+	lines := []string{headerStyle.Render(fmt.Sprintf("%-*s  %-*s  %s", nameWidth, "Name", queryValueWidth, "Value (decoded)", "Flags"))}
+	for i, param := range params {
+		var flags []string
+		if param.Duplicate {
+			flags = append(flags, "duplicate")
+		}
+		if param.Empty {
+			flags = append(flags, "empty")
+		}
+		if param.Long {
+			flags = append(flags, fmt.Sprintf("long (%d chars)", len(param.Value)))
+		}
+		line := fmt.Sprintf("%s  %s  %s", fitWidth(param.Name, nameWidth), fitWidth(param.Value, queryValueWidth),
+			paramFlagStyle.Render(strings.Join(flags, ", ")))
+		if i == m.detailSelected {
+			line = timelineCursorStyle.Render(line)
+		}
+		lines = append(lines, line)
+	}
+	lines = append(lines, "", statusStyle.Render("Press ↑/↓ to select a parameter, y to copy its decoded value"))
+	return lines
+}
+
+// renderBodyTab shows the request body decoded by its type: form fields and
+// multipart parts as tables, JSON indented, anything else as text. R shows
+// the raw text instead.
+func (m Model) renderBodyTab(entry har.Entry) []string {
+	body, ok := har.ParseRequestBody(entry)
+	if !ok {
+		return []string{"This request has no body"}
+	}
+
+	kind := string(body.Kind)
+	if m.bodyRaw {
+		kind = "raw"
+	}
+	lines := []string{fmt.Sprintf("Body: %s (%s), %s", kind, body.MimeType, m.settings.SizeUnits.FormatSize(len(body.Raw))), ""}
+	switch {
+	case m.bodyRaw || body.Kind == har.TextPostData:
+		lines = append(lines, strings.Split(strings.ReplaceAll(body.Raw, "\r\n", "\n"), "\n")...)
+	case body.Kind == har.JSONPostData:
+		lines = append(lines, strings.Split(body.JSON, "\n")...)
+	case body.Kind == har.FormPostData:
+		nameWidth := 4
+		for _, part := range body.Parts {
+			nameWidth = min(max(nameWidth, len(part.Name)), 30)
+		}
+		lines = append(lines, headerStyle.Render(fmt.Sprintf("%-*s  %s", nameWidth, "Name", "Value (decoded)")))
+		for _, part := range body.Parts {
+			lines = append(lines, fmt.Sprintf("%s  %s", fitWidth(part.Name, nameWidth), truncateValue(part.Value, max(m.width-nameWidth-4, 20))))
+		}
+	case body.Kind == har.MultipartPostData:
+		lines = append(lines, headerStyle.Render(fmt.Sprintf("%-24s %-28s %-28s %10s", "Name", "File or Value", "Content Type", "Size")))
+		for _, part := range body.Parts {
+			file := part.FileName
+			if file == "" {
+				file = part.Value
+			}
+			lines = append(lines, fmt.Sprintf("%s %s %s %10s", fitWidth(part.Name, 24), fitWidth(file, 28),
+				fitWidth(part.ContentType, 28), m.settings.SizeUnits.FormatSize(part.Size)))
+		}
+	}
+
+	// Keep the tab bar and footer on screen
+	if visible := max(m.height-10, 5); len(lines) > visible {
+		hidden := len(lines) - visible
+		lines = append(lines[:visible], statusStyle.Render(fmt.Sprintf("... and %d more lines", hidden)))
+	}
+	toggle := "R to show the raw text"
+	if m.bodyRaw {
+		toggle = "R to show the decoded body"
+	}
+	return append(lines, "", statusStyle.Render("Press "+toggle))
+}
+
+func newHeaderSearch() textinput.Model {
+	input := textinput.New()
+	input.Placeholder = "header name or value"
+	input.CharLimit = 128
+	return input
+}
+
+// openHeaderSearch starts typing a search in the headers tab.
+func (m Model) openHeaderSearch() (tea.Model, tea.Cmd) {
+	m.headerSearching = true
+	m.headerSearch.Focus()
+	return m, textinput.Blink
+}
+
+// updateHeaderSearch narrows the header list as the search is typed. Enter
+// keeps the search, Esc clears it.
+func (m Model) updateHeaderSearch(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.headerSearching = false
+		m.headerSearch.SetValue("")
+		m.detailSelected = 0
+		return m, nil
+	case "enter":
+		m.headerSearching = false
+		m.headerSearch.Blur()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.headerSearch, cmd = m.headerSearch.Update(msg)
+	m.detailSelected = 0
+	return m, cmd
+}
+
+// headerListHeight is how many header lines fit below the tab bar.
+func (m Model) headerListHeight() int {
+	return max(m.height-9, 5)
+}
+
+// headerListLines lists the request and response headers matching the
+// search, one line per header and wrapped to the width, under a heading per
+// side.
+func (m Model) headerListLines(entry har.Entry) []string {
+	query := strings.ToLower(m.headerSearch.Value())
+	var request, response []string
+	width := max(m.width-2, 40)
+	for _, header := range har.HeaderLines(entry) {
+		if query != "" && !strings.Contains(strings.ToLower(header.Name+": "+header.Value), query) {
+			continue
+		}
+		name := header.Name
+		if header.Derived {
+			name = statusStyle.Render(name)
+		}
+		line := name + ": " + header.Value
+		wrapped := []string{line}
+		if ansi.StringWidth(line) > width {
+			wrapped = wrapHeaderValue(name, header.Value, ansi.StringWidth(header.Name)+2, width)
+		}
+		if header.Response {
+			response = append(response, wrapped...)
+		} else {
+			request = append(request, wrapped...)
+		}
+	}
+
+	lines := []string{headerStyle.Render(fmt.Sprintf("Request Headers (%d lines)", len(request)))}
+	lines = append(lines, request...)
+	lines = append(lines, "", headerStyle.Render(fmt.Sprintf("Response Headers (%d lines)", len(response))))
+	return append(lines, response...)
+}
+
+// wrapHeaderValue breaks a long header value into lines of the given width,
+// indenting continuation lines under the value. Lines break by display
+// width, never inside a character.
+func wrapHeaderValue(name, value string, indent, width int) []string {
+	chunks := strings.Split(ansi.Hardwrap(value, max(width-indent, 20), true), "\n")
+	lines := []string{name + ": " + chunks[0]}
+	for _, chunk := range chunks[1:] {
+		lines = append(lines, strings.Repeat(" ", indent)+chunk)
+	}
+	return lines
+}
+
+// renderHeadersTab shows every request and response header, scrolling with
+// ↑/↓ and narrowed by a search started with /.
+func (m Model) renderHeadersTab(entry har.Entry) []string {
+	var lines []string
+	if m.headerSearching || m.headerSearch.Value() != "" {
+		lines = append(lines, "Search: "+m.headerSearch.View(), "")
+	}
+
+	all := m.headerListLines(entry)
+	visible := m.headerListHeight()
+	if m.headerSearching || m.headerSearch.Value() != "" {
+		visible -= 2
+	}
+	offset := min(m.detailSelected, max(len(all)-visible, 0))
+	lines = append(lines, all[offset:min(len(all), offset+visible)]...)
+
+	help := "Press ↑/↓ to scroll, / to search headers"
+	if m.headerSearching {
+		help = "Enter to keep the search, Esc to clear it"
+	}
+	if len(all) > visible {
+		help = fmt.Sprintf("Lines %d-%d of %d. ", offset+1, min(len(all), offset+visible), len(all)) + help
+	}
+	return append(lines, "", statusStyle.Render(help))
+}
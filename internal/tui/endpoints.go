@@ -0,0 +1,47 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+)
+
+const endpointLabelWidth = 56
+
+// renderEndpointsView lists the templated API endpoints with their request
+// count, error rate and p50/p95 latency, scrolling with ↑/↓.
+func (m Model) renderEndpointsView() string {
+	content := []string{titleStyle.Render("Endpoints (numeric, UUID and hex path segments collapsed to {id})"), ""}
+	endpoints := m.analyzers[m.currentFile].EndpointStats()
+	if len(endpoints) == 0 {
+		content = append(content, "No requests to group", "", statusStyle.Render("Press Esc to go back"))
+		return strings.Join(content, "\n")
+	}
+
+	content = append(content, headerStyle.Render(fmt.Sprintf("%-*s %8s %8s %10s %10s", endpointLabelWidth, "Endpoint", "Requests", "Errors", "p50", "p95")))
+	requests := 0
+	for _, endpoint := range endpoints {
+		requests += endpoint.Requests
+	}
+	visible := max(m.height-8, 5)
+	offset := min(m.endpointOffset, max(len(endpoints)-visible, 0))
+	for _, endpoint := range endpoints[offset:min(len(endpoints), offset+visible)] {
+		errors := fmt.Sprintf("%7.1f%%", endpoint.ErrorRate())
+		if endpoint.Errors > 0 {
+			errors = endpointErrorStyle.Render(errors)
+		}
+		content = append(content, fmt.Sprintf("%s %8d %s %8.1fms %8.1fms", fitWidth(endpoint.Endpoint, endpointLabelWidth),
+			endpoint.Requests, errors, endpoint.P50, endpoint.P95))
+	}
+
+	content = append(content, "")
+	content = append(content, statusStyle.Render(fmt.Sprintf("%d endpoints from %d requests (rows %d-%d). Press ↑/↓ to scroll, Esc to go back",
+		len(endpoints), requests, offset+1, min(len(endpoints), offset+visible))))
+	return strings.Join(content, "\n")
+}
+
+// scrollEndpoints moves the endpoints view by delta rows.
+func (m *Model) scrollEndpoints(delta int) {
+	endpoints := len(m.analyzers[m.currentFile].EndpointStats())
+	visible := max(m.height-8, 5)
+	m.endpointOffset = min(max(m.endpointOffset+delta, 0), max(endpoints-visible, 0))
+}
@@ -0,0 +1,185 @@
+package tui
+
+import (
+	"fmt"
+	"github.com/jlgore/hartea/internal/har"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const diffNameWidth = 24
+
+// noMark is the marked entry when no request is marked for a diff.
+const noMark = -1
+
+// entryRef points at a loaded entry of one of the files.
+type entryRef struct {
+	file  int
+	index int
+}
+
+// openDiff shows the diff of two loaded entries.
+func (m *Model) openDiff(before, after entryRef) {
+	m.diffBefore, m.diffAfter = before, after
+	m.diffReturn = m.currentView
+	m.diffOffset = 0
+	m.currentView = DiffView
+}
+
+// diffLabel names a diffed entry, with its file when the diff spans files.
+func (m Model) diffLabel(ref entryRef) string {
+	if m.diffBefore.file == m.diffAfter.file {
+		return fmt.Sprintf("#%d", ref.index+1)
+	}
+	return fmt.Sprintf("%s #%d", m.fileNames[ref.file], ref.index+1)
+}
+
+// markForDiff marks the request under the cursor, or diffs it against the
+// request marked before. Marking the marked request again clears the mark.
+// Marks refer to the loaded file's entries, so they survive filtering.
+func (m *Model) markForDiff() tea.Cmd {
+	index := m.selectedEntry
+	if m.currentView == TableView {
+		index = m.table.Cursor()
+	}
+	if index < 0 || index >= len(m.entryIndices) {
+		return nil
+	}
+	entry := m.entryIndices[index]
+
+	switch {
+	case m.markedEntry == noMark || m.markedFile != m.currentFile:
+		m.markedEntry, m.markedFile = entry, m.currentFile
+		return m.showToast(fmt.Sprintf("Marked request #%d; select another and press M to diff", entry+1), false)
+	case m.markedEntry == entry:
+		m.markedEntry = noMark
+		return m.showToast("Cleared the diff mark", false)
+	}
+	m.openDiff(entryRef{m.markedFile, m.markedEntry}, entryRef{m.currentFile, entry})
+	return nil
+}
+
+// markTitle describes the marked request in the table header.
+func (m Model) markTitle() string {
+	if m.markedEntry == noMark || m.markedFile != m.currentFile {
+		return ""
+	}
+	entry := m.harFiles[m.currentFile].Log.Entries[m.markedEntry]
+	return fmt.Sprintf("Marked #%d for diff: %s %s", m.markedEntry+1, entry.Request.Method, truncateURL(entry.Request.URL, max(m.width-60, 30)))
+}
+
+// diffLines renders the diff of two requests: changed fields side by side,
+// then the body line diffs. Identical fields are counted unless diffShowAll
+// is set.
+func (m Model) diffLines() []string {
+	before := m.harFiles[m.diffBefore.file].Log.Entries[m.diffBefore.index]
+	after := m.harFiles[m.diffAfter.file].Log.Entries[m.diffAfter.index]
+	diff := har.DiffEntries(before, after, m.settings.SizeUnits)
+	valueWidth := max((m.width-diffNameWidth-6)/2, 20)
+
+	lines := []string{fmt.Sprintf("%d differences", diff.Changes()), ""}
+	sections := []struct {
+		title  string
+		fields []har.FieldDiff
+	}{
+		{"Summary", diff.Summary},
+		{"Request Headers", diff.RequestHeaders},
+		{"Response Headers", diff.ResponseHeaders},
+		{"Query Parameters", diff.Query},
+		{"Timings", diff.Timings},
+	}
+	for _, section := range sections {
+		if len(section.fields) == 0 {
+			continue
+		}
+		lines = append(lines, headerStyle.Render(fmt.Sprintf("%-*s %-*s   %s", diffNameWidth, section.title,
+			valueWidth, m.diffLabel(m.diffBefore), m.diffLabel(m.diffAfter))))
+		identical := 0
+		for _, field := range section.fields {
+			if !field.Changed() && !m.diffShowAll {
+				identical++
+				continue
+			}
+			lines = append(lines, diffFieldLines(field, valueWidth)...)
+		}
+		if identical > 0 {
+			lines = append(lines, statusStyle.Render(fmt.Sprintf("%d identical", identical)))
+		}
+		lines = append(lines, "")
+	}
+
+	for _, body := range []struct {
+		title string
+		lines []har.LineDiff
+	}{{"Request Body", diff.RequestBody}, {"Response Body", diff.ResponseBody}} {
+		if len(body.lines) == 0 {
+			continue
+		}
+		lines = append(lines, headerStyle.Render(body.title))
+		for _, line := range body.lines {
+			text := truncateValue(string(line.Op)+" "+line.Text, max(m.width-2, 40))
+			switch line.Op {
+			case '-':
+				text = diffRemovedStyle.Render(text)
+			case '+':
+				text = diffAddedStyle.Render(text)
+			default:
+				if !m.diffShowAll {
+					continue
+				}
+			}
+			lines = append(lines, text)
+		}
+		lines = append(lines, "")
+	}
+	return lines
+}
+
+// diffFieldLines puts both values of a field next to each other, one line
+// per value line, highlighting changed fields.
+func diffFieldLines(field har.FieldDiff, width int) []string {
+	before, after := strings.Split(field.Before, "\n"), strings.Split(field.After, "\n")
+	var lines []string
+	for i := 0; i < max(len(before), len(after)); i++ {
+		name, left, right := "", "", ""
+		if i == 0 {
+			name = field.Name
+		}
+		if i < len(before) {
+			left = before[i]
+		}
+		if i < len(after) {
+			right = after[i]
+		}
+		line := fmt.Sprintf("%s %s │ %s", fitWidth(name, diffNameWidth), fitWidth(left, width), truncateValue(right, width))
+		if field.Changed() {
+			line = diffChangedStyle.Render(line)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// renderDiffView shows the diff of two requests, scrolling with ↑/↓.
+func (m Model) renderDiffView() string {
+	content := []string{titleStyle.Render("Request Diff"), ""}
+	lines := m.diffLines()
+	visible := max(m.height-6, 5)
+	offset := min(m.diffOffset, max(len(lines)-visible, 0))
+	content = append(content, lines[offset:min(len(lines), offset+visible)]...)
+
+	toggle := "= to show identical fields"
+	if m.diffShowAll {
+		toggle = "= to hide identical fields"
+	}
+	content = append(content, statusStyle.Render("Press ↑/↓ to scroll, "+toggle+", Esc to go back"))
+	return strings.Join(content, "\n")
+}
+
+// scrollDiff moves the diff view by delta lines.
+func (m *Model) scrollDiff(delta int) {
+	lines := len(m.diffLines())
+	visible := max(m.height-6, 5)
+	m.diffOffset = min(max(m.diffOffset+delta, 0), max(lines-visible, 0))
+}
@@ -0,0 +1,58 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// renderErrorClustersView lists the failed requests grouped by status, host
+// and templated path, with when each cluster first and last occurred.
+func (m Model) renderErrorClustersView() string {
+	clusters := m.analyzers[m.currentFile].ErrorClusters()
+	failed := 0
+	for _, cluster := range clusters {
+		failed += cluster.Requests
+	}
+	content := []string{titleStyle.Render(fmt.Sprintf("Error Clusters (%d failed requests in %d clusters)", failed, len(clusters))), ""}
+	if len(clusters) == 0 {
+		content = append(content, "No failed requests: every request got a 1xx, 2xx or 3xx response", "", statusStyle.Render("Press Esc to go back"))
+		return strings.Join(content, "\n")
+	}
+
+	pathWidth := max(m.width-94, 20)
+	content = append(content, headerStyle.Render(fmt.Sprintf("%-26s %s %s %8s  %-14s %s",
+		"Status", fitWidth("Domain", 28), fitWidth("Path", pathWidth), "Requests", "First", "Last")))
+	times := m.timeFormat()
+	visible := max(m.height-9, 5)
+	offset := max(m.errorClusterSelected-visible+1, 0)
+	for i := offset; i < min(len(clusters), offset+visible); i++ {
+		cluster := clusters[i]
+		status := fitWidth(strconv.Itoa(cluster.Status)+" "+statusText(cluster.Status), 26)
+		line := fmt.Sprintf("%s %s %s %8d  %-14s %s", endpointErrorStyle.Render(status), fitWidth(cluster.Domain, 28),
+			fitWidth(cluster.Path, pathWidth), cluster.Requests, times.format(cluster.First), times.format(cluster.Last))
+		if i == m.errorClusterSelected {
+			line = timelineCursorStyle.Render(line)
+		}
+		content = append(content, line)
+	}
+
+	content = append(content, "")
+	content = append(content, statusStyle.Render("Press ↑/↓ to select, Enter to open the cluster's first request, Esc to go back"))
+	return strings.Join(content, "\n")
+}
+
+// moveErrorClusterSelection moves the highlighted cluster.
+func (m *Model) moveErrorClusterSelection(delta int) {
+	clusters := len(m.analyzers[m.currentFile].ErrorClusters())
+	m.errorClusterSelected = max(0, min(m.errorClusterSelected+delta, clusters-1))
+}
+
+// inspectErrorClusterSelection opens the highlighted cluster's first request
+// in the detail view.
+func (m *Model) inspectErrorClusterSelection() {
+	clusters := m.analyzers[m.currentFile].ErrorClusters()
+	if m.errorClusterSelected < len(clusters) {
+		m.inspectEntry(clusters[m.errorClusterSelected].Indices[0], ErrorClusterView)
+	}
+}
@@ -0,0 +1,133 @@
+package tui
+
+import (
+	"fmt"
+	"github.com/jlgore/hartea/internal/har"
+
+	"github.com/charmbracelet/bubbletea"
+)
+
+// exclusionPrompt is an exclusion of the x and X keys waiting to be
+// confirmed, since it covers every matching request of all loaded files and
+// is saved to the workspace when there is one.
+type exclusionPrompt struct {
+	pattern     string
+	description string
+	matches     int
+}
+
+// excludeSelected asks to leave every request to the URL under the cursor,
+// or to its host, out of the analysis of all loaded files. Unlike a filter,
+// metrics, comparisons and exports are recomputed without the excluded
+// entries. Hiding (see hideSelected) leaves out just the selected request.
+func (m Model) excludeSelected(wholeDomain bool) (tea.Model, tea.Cmd) {
+	index := m.selectedEntry
+	if m.currentView == TableView {
+		index = m.table.Cursor()
+	}
+	if index < 0 || index >= len(m.entries) {
+		return m, nil
+	}
+
+	entry := m.entries[index]
+	prompt := exclusionPrompt{pattern: entry.Request.URL, description: "requests to " + entry.Request.URL}
+	if wholeDomain {
+		prompt.pattern = har.EntryHost(entry)
+		prompt.description = "requests to " + prompt.pattern
+	}
+	for _, harFile := range m.harFiles {
+		for _, loaded := range harFile.Log.Entries {
+			if har.MatchesExclusion(loaded, prompt.pattern) {
+				prompt.matches++
+			}
+		}
+	}
+	m.pendingExclusion = prompt
+	return m, nil
+}
+
+// updateExclusionPrompt excludes the pending pattern on y or Enter, saving it
+// to the workspace if there is one, or for this session only on s.
+func (m Model) updateExclusionPrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	prompt := m.pendingExclusion
+	switch msg.String() {
+	case "y", "enter":
+		m.pendingExclusion = exclusionPrompt{}
+		return m.exclude(prompt.pattern, prompt.description, true)
+	case "s":
+		m.pendingExclusion = exclusionPrompt{}
+		return m.exclude(prompt.pattern, prompt.description, false)
+	case "esc", "n":
+		m.pendingExclusion = exclusionPrompt{}
+	case "ctrl+c":
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+func (m Model) renderExclusionPrompt() string {
+	prompt := m.pendingExclusion
+	question := fmt.Sprintf("Exclude %d %s from all loaded files", prompt.matches, truncateURL(prompt.description, max(m.width-60, 40)))
+	if m.onExclude == nil {
+		return question + "? " + statusStyle.Render("y to exclude for this session, Esc to cancel")
+	}
+	return question + " and save it to workspace " + m.workspaceName + "? " +
+		statusStyle.Render("y to save, s for this session only, Esc to cancel")
+}
+
+// exclude leaves the requests matching pattern out of every loaded file,
+// saving the pattern to the workspace when save is set and there is one.
+func (m Model) exclude(pattern, description string, save bool) (tea.Model, tea.Cmd) {
+	excluded := 0
+	for _, harFile := range m.harFiles {
+		excluded += har.ExcludeEntries(harFile, []string{pattern})
+	}
+	m.refreshAnalysis()
+
+	if m.currentView == DetailView || m.currentView == TimingView {
+		m.currentView = TableView
+	}
+
+	scope := "this session only"
+	if save && m.onExclude != nil {
+		if err := m.onExclude(pattern); err != nil {
+			return m, m.showToast(err.Error(), true)
+		}
+		scope = "saved to workspace " + m.workspaceName
+	}
+	return m, m.showToast(fmt.Sprintf("Excluded %d %s (%s)", excluded, description, scope), false)
+}
+
+// excludeExtensions leaves all browser-extension traffic out of the analysis.
+func (m Model) excludeExtensions() (tea.Model, tea.Cmd) {
+	for _, harFile := range m.harFiles {
+		for _, entry := range harFile.Log.Entries {
+			if har.IsExtensionEntry(entry) {
+				return m.exclude(har.ExcludeExtensions, "browser-extension requests", true)
+			}
+		}
+	}
+	return m, m.showToast("No browser-extension requests detected", false)
+}
+
+// refreshAnalysis recomputes metrics and the comparison after the loaded
+// entries changed, keeping the current filter.
+func (m *Model) refreshAnalysis() {
+	// Excluding entries renumbers them
+	m.markedEntry = noMark
+	m.selectedEntries = nil
+	for i, harFile := range m.harFiles {
+		m.analyzers[i] = har.NewAnalyzer(harFile, m.settings)
+	}
+
+	m.updateComparison()
+
+	m.updateScope()
+	m.timeline = m.analyzers[m.currentFile].GenerateTimeline()
+	m.filterEntries(m.filter.Value())
+}
+
+// excludedCount returns how many entries of the current file are excluded.
+func (m Model) excludedCount() int {
+	return len(m.harFiles[m.currentFile].Log.Excluded)
+}
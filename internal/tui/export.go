@@ -0,0 +1,303 @@
+package tui
+
+import (
+	"fmt"
+	"github.com/jlgore/hartea/internal/report"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbletea"
+)
+
+type exportFormat struct {
+	name      string
+	extension string
+	selected  bool
+}
+
+type ExportDialog struct {
+	active         bool
+	formats        []exportFormat
+	includeEntries bool
+	filename       textinput.Model
+	directory      textinput.Model
+	focus          int
+}
+
+type exportDoneMsg struct {
+	files []string
+	err   error
+}
+
+type toastExpiredMsg struct {
+	id int
+}
+
+type Toast struct {
+	id      int
+	message string
+	isError bool
+}
+
+const toastDuration = 4 * time.Second
+
+func NewExportDialog() ExportDialog {
+	filename := textinput.New()
+	filename.Placeholder = "har-analysis-<timestamp>"
+	filename.CharLimit = 128
+
+	directory := textinput.New()
+	directory.Placeholder = "."
+	directory.CharLimit = 256
+
+	return ExportDialog{
+		formats: []exportFormat{
+			{name: "JSON", extension: ".json", selected: true},
+			{name: "CSV", extension: ".csv", selected: true},
+			{name: "Entries CSV", extension: "-entries.csv"},
+			{name: "NDJSON entries", extension: ".ndjson"},
+			{name: "HTML", extension: ".html", selected: true},
+			{name: "PDF", extension: ".pdf", selected: true},
+			{name: "Prometheus", extension: ".prom"},
+			{name: "SARIF security findings", extension: ".sarif"},
+			{name: "Security findings JSON", extension: "-findings.json"},
+			{name: "Dependency graph (DOT)", extension: ".dot"},
+			{name: "Mermaid sequence of the listed requests", extension: "-sequence.md"},
+			{name: "Waterfall SVG", extension: "-waterfall.svg"},
+			{name: "Waterfall PNG", extension: "-waterfall.png"},
+			{name: "Chrome trace (Perfetto)", extension: "-trace.json"},
+		},
+		filename:  filename,
+		directory: directory,
+	}
+}
+
+// Focus order: formats, include entries, filename, directory, export button
+func (d ExportDialog) entriesIndex() int   { return len(d.formats) }
+func (d ExportDialog) filenameIndex() int  { return len(d.formats) + 1 }
+func (d ExportDialog) directoryIndex() int { return len(d.formats) + 2 }
+func (d ExportDialog) buttonIndex() int    { return len(d.formats) + 3 }
+
+func (d *ExportDialog) open() {
+	d.active = true
+	d.focus = 0
+	d.filename.SetValue("")
+	d.directory.SetValue("")
+	d.updateFocus()
+}
+
+func (d *ExportDialog) moveFocus(delta int) {
+	count := d.buttonIndex() + 1
+	d.focus = (d.focus + delta + count) % count
+	d.updateFocus()
+}
+
+func (d *ExportDialog) updateFocus() {
+	d.filename.Blur()
+	d.directory.Blur()
+	switch d.focus {
+	case d.filenameIndex():
+		d.filename.Focus()
+	case d.directoryIndex():
+		d.directory.Focus()
+	}
+}
+
+func (d *ExportDialog) toggle() {
+	if d.focus < len(d.formats) {
+		d.formats[d.focus].selected = !d.formats[d.focus].selected
+	} else if d.focus == d.entriesIndex() {
+		d.includeEntries = !d.includeEntries
+	}
+}
+
+func (m Model) updateExportDialog(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	d := &m.exportDialog
+	var cmd tea.Cmd
+
+	switch msg.String() {
+	case "esc":
+		d.active = false
+		return m, nil
+	case "tab", "down":
+		d.moveFocus(1)
+		return m, nil
+	case "shift+tab", "up":
+		d.moveFocus(-1)
+		return m, nil
+	case "enter":
+		// Enter toggles options and moves on from the text fields; only the
+		// button exports
+		switch {
+		case d.focus < d.filenameIndex():
+			d.toggle()
+		case d.focus < d.buttonIndex():
+			d.moveFocus(1)
+		default:
+			d.active = false
+			return m, m.exportCmd()
+		}
+		return m, nil
+	case " ":
+		if d.focus < d.filenameIndex() {
+			d.toggle()
+			return m, nil
+		}
+	}
+
+	switch d.focus {
+	case d.filenameIndex():
+		d.filename, cmd = d.filename.Update(msg)
+	case d.directoryIndex():
+		d.directory, cmd = d.directory.Update(msg)
+	}
+	return m, cmd
+}
+
+func (m Model) renderExportDialog() string {
+	d := m.exportDialog
+	var lines []string
+
+	lines = append(lines, titleStyle.Render("Export Report"))
+	lines = append(lines, "")
+	lines = append(lines, headerStyle.Render("Formats"))
+	for i, format := range d.formats {
+		lines = append(lines, d.renderOption(i, format.name, format.selected))
+	}
+	lines = append(lines, "")
+	lines = append(lines, headerStyle.Render("Options"))
+	lines = append(lines, d.renderOption(d.entriesIndex(), "Include entries (JSON)", d.includeEntries))
+	lines = append(lines, "")
+	lines = append(lines, d.renderLabel(d.filenameIndex(), "Filename:  ")+d.filename.View())
+	lines = append(lines, d.renderLabel(d.directoryIndex(), "Directory: ")+d.directory.View())
+	lines = append(lines, "")
+	lines = append(lines, d.renderLabel(d.buttonIndex(), "[ Export ]"))
+	lines = append(lines, "")
+	lines = append(lines, statusStyle.Render("Tab/↑↓ to move, space or Enter to toggle, Enter on Export to export, Esc to cancel"))
+
+	return strings.Join(lines, "\n")
+}
+
+func (d ExportDialog) renderOption(index int, label string, checked bool) string {
+	box := "[ ]"
+	if checked {
+		box = "[x]"
+	}
+	return d.renderLabel(index, box+" "+label)
+}
+
+func (d ExportDialog) renderLabel(index int, label string) string {
+	if d.focus == index {
+		return focusedStyle.Render("> " + label)
+	}
+	return "  " + label
+}
+
+func (m Model) exportCmd() tea.Cmd {
+	d := m.exportDialog
+	files, analyzers := m.comparedFiles()
+	generator := report.NewGenerator(files, analyzers, m.comparison, m.settings)
+	if m.comparison != nil {
+		generator.SetFileNames(m.comparison.Files)
+	} else {
+		generator.SetFileNames(m.fileNames)
+	}
+	generator.SetSegmentGap(m.segmentGap)
+	generator.SetPlain(m.plain)
+	// The Mermaid sequence shows the requests the table lists, filter included
+	title, entries := m.fileNames[m.currentFile], m.entries
+
+	baseName := strings.TrimSpace(d.filename.Value())
+	if baseName == "" {
+		baseName = fmt.Sprintf("har-analysis-%s", time.Now().Format("2006-01-02_15-04-05"))
+	}
+	directory := strings.TrimSpace(d.directory.Value())
+	if directory == "" {
+		directory = "."
+	}
+	includeEntries := d.includeEntries
+
+	var formats []exportFormat
+	for _, format := range d.formats {
+		if format.selected {
+			formats = append(formats, format)
+		}
+	}
+
+	return func() tea.Msg {
+		if len(formats) == 0 {
+			return exportDoneMsg{err: fmt.Errorf("no export format selected")}
+		}
+		basePath := baseName
+		if !filepath.IsAbs(basePath) {
+			basePath = filepath.Join(directory, basePath)
+		}
+		if err := os.MkdirAll(filepath.Dir(basePath), 0o755); err != nil {
+			return exportDoneMsg{err: fmt.Errorf("failed to create output directory: %w", err)}
+		}
+
+		var files []string
+		for _, format := range formats {
+			filename := basePath + format.extension
+
+			var err error
+			switch format.extension {
+			case ".json":
+				err = generator.ExportJSON(filename, includeEntries)
+			case ".csv":
+				err = generator.ExportCSV(filename)
+			case "-entries.csv":
+				err = generator.ExportEntriesCSV(filename)
+			case ".ndjson":
+				err = generator.ExportNDJSON(filename)
+			case ".html":
+				err = generator.ExportHTML(filename)
+			case ".pdf":
+				err = generator.ExportPDF(filename)
+			case ".prom":
+				err = generator.ExportPrometheus(filename)
+			case ".sarif":
+				err = generator.ExportSARIF(filename)
+			case "-findings.json":
+				err = generator.ExportFindingsJSON(filename)
+			case ".dot":
+				err = generator.ExportDOT(filename)
+			case "-sequence.md":
+				err = report.ExportMermaidSequence(filename, title, entries, m.settings.SizeUnits)
+			case "-waterfall.svg":
+				err = generator.ExportWaterfallSVG(filename)
+			case "-waterfall.png":
+				err = generator.ExportWaterfallPNG(filename)
+			case "-trace.json":
+				err = generator.ExportTrace(filename)
+			}
+			if err != nil {
+				return exportDoneMsg{files: files, err: fmt.Errorf("%s export failed: %w", format.name, err)}
+			}
+			files = append(files, filename)
+		}
+
+		return exportDoneMsg{files: files}
+	}
+}
+
+func (m *Model) showToast(message string, isError bool) tea.Cmd {
+	m.toast = Toast{id: m.toast.id + 1, message: message, isError: isError}
+	id := m.toast.id
+	return tea.Tick(toastDuration, func(time.Time) tea.Msg {
+		return toastExpiredMsg{id: id}
+	})
+}
+
+func (m Model) renderToast() string {
+	if m.toast.message == "" {
+		return ""
+	}
+	if m.toast.isError {
+		return toastErrorStyle.Render("✗ " + m.toast.message)
+	}
+	return toastSuccessStyle.Render("✓ " + m.toast.message)
+}
@@ -0,0 +1,54 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/bubbletea"
+	"github.com/jlgore/hartea/internal/har"
+)
+
+func pressExportKey(t *testing.T, m Model, key tea.KeyMsg) (Model, tea.Cmd) {
+	t.Helper()
+	updated, cmd := m.updateExportDialog(key)
+	return updated.(Model), cmd
+}
+
+func TestExportDialogEnterTogglesCheckboxes(t *testing.T) {
+	enter := tea.KeyMsg{Type: tea.KeyEnter}
+	space := tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")}
+
+	m := NewModel([]*har.HAR{{}}, Options{})
+	m.tutorial = Tutorial{}
+	m.exportDialog.open()
+
+	tests := []struct {
+		name  string
+		focus int
+		key   tea.KeyMsg
+		check func(ExportDialog) bool
+	}{
+		{"Enter on a format", 2, enter, func(d ExportDialog) bool { return d.formats[2].selected }},
+		{"space on a format", 3, space, func(d ExportDialog) bool { return d.formats[3].selected }},
+		{"Enter on Include entries", m.exportDialog.entriesIndex(), enter, func(d ExportDialog) bool { return d.includeEntries }},
+		{"space on Include entries", m.exportDialog.entriesIndex(), space, func(d ExportDialog) bool { return !d.includeEntries }},
+		{"Enter in the filename", m.exportDialog.filenameIndex(), enter, func(d ExportDialog) bool { return d.focus == d.directoryIndex() }},
+	}
+	for _, tt := range tests {
+		m.exportDialog.focus = tt.focus
+		m.exportDialog.updateFocus()
+		var cmd tea.Cmd
+		m, cmd = pressExportKey(t, m, tt.key)
+		if cmd != nil || !m.exportDialog.active {
+			t.Errorf("%s started the export", tt.name)
+		}
+		if !tt.check(m.exportDialog) {
+			t.Errorf("%s did not toggle or move on", tt.name)
+		}
+	}
+
+	m.exportDialog.focus = m.exportDialog.buttonIndex()
+	m, cmd := pressExportKey(t, m, enter)
+	if cmd == nil || m.exportDialog.active {
+		t.Error("Enter on Export did not start the export")
+	}
+}
@@ -0,0 +1,249 @@
+package tui
+
+import (
+	"fmt"
+	"github.com/jlgore/hartea/internal/config"
+	"github.com/jlgore/hartea/internal/har"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbletea"
+)
+
+// FilePicker browses for HAR files to open, listing the recently opened
+// ones above the current directory.
+type FilePicker struct {
+	active bool
+	dir    string
+	items  []pickerItem
+	cursor int
+	// opening is the file being loaded, if any
+	opening string
+	err     error
+}
+
+type pickerItem struct {
+	label  string
+	path   string
+	dir    bool
+	recent bool
+}
+
+type fileOpenedMsg struct {
+	path    string
+	harFile *har.HAR
+	err     error
+}
+
+// isHARFile reports whether the picker offers a file: .har, or .har.gz.
+func isHARFile(name string) bool {
+	name = strings.ToLower(name)
+	return strings.HasSuffix(name, ".har") || strings.HasSuffix(name, ".har.gz")
+}
+
+// parseHARFile opens a file for the TUI when no Options.OpenFile is given.
+func parseHARFile(path string) (*har.HAR, error) {
+	parser := har.NewParser()
+	harFile, err := parser.ParseFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := parser.ValidateHAR(harFile); err != nil {
+		return nil, err
+	}
+	return harFile, nil
+}
+
+func (p *FilePicker) open() {
+	p.active = true
+	p.opening = ""
+	if p.dir == "" {
+		p.dir = "."
+		if wd, err := os.Getwd(); err == nil {
+			p.dir = wd
+		}
+	}
+	p.load()
+}
+
+// load lists the recent files that still exist, then the current
+// directory's subdirectories and HAR files, hiding dotfiles.
+func (p *FilePicker) load() {
+	p.items, p.cursor, p.err = nil, 0, nil
+
+	recent, err := config.LoadRecent()
+	if err != nil {
+		p.err = err
+	}
+	for _, path := range recent {
+		if _, err := os.Stat(path); err == nil {
+			p.items = append(p.items, pickerItem{label: path, path: path, recent: true})
+		}
+	}
+
+	if parent := filepath.Dir(p.dir); parent != p.dir {
+		p.items = append(p.items, pickerItem{label: "../", path: parent, dir: true})
+	}
+	entries, err := os.ReadDir(p.dir)
+	if err != nil {
+		p.err = fmt.Errorf("failed to read %s: %w", p.dir, err)
+		return
+	}
+	var files []pickerItem
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasPrefix(name, ".") {
+			continue
+		}
+		path := filepath.Join(p.dir, name)
+		if entry.IsDir() {
+			p.items = append(p.items, pickerItem{label: name + "/", path: path, dir: true})
+		} else if isHARFile(name) {
+			files = append(files, pickerItem{label: name, path: path})
+		}
+	}
+	p.items = append(p.items, files...)
+}
+
+// changeDir lists another directory.
+func (p *FilePicker) changeDir(dir string) {
+	p.dir = dir
+	p.load()
+}
+
+func (m Model) openFilePicker() (tea.Model, tea.Cmd) {
+	m.filePicker.open()
+	return m, nil
+}
+
+func (m Model) updateFilePicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	p := &m.filePicker
+	if p.opening != "" {
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		if len(m.harFiles) == 0 {
+			return m, tea.Quit
+		}
+		p.active = false
+	case "up", "k":
+		if p.cursor > 0 {
+			p.cursor--
+		}
+	case "down", "j":
+		if p.cursor < len(p.items)-1 {
+			p.cursor++
+		}
+	case "left", "h", "backspace":
+		p.changeDir(filepath.Dir(p.dir))
+	case "enter", "right", "l":
+		if p.cursor >= len(p.items) {
+			return m, nil
+		}
+		item := p.items[p.cursor]
+		if item.dir {
+			p.changeDir(item.path)
+			return m, nil
+		}
+		p.opening = item.path
+		return m, m.openFileCmd(item.path)
+	}
+	return m, nil
+}
+
+// openFileCmd loads a file in the background; fileOpenedMsg adds it.
+func (m Model) openFileCmd(path string) tea.Cmd {
+	openFile := m.openFile
+	return func() tea.Msg {
+		harFile, err := openFile(path)
+		return fileOpenedMsg{path: path, harFile: harFile, err: err}
+	}
+}
+
+// fileOpened adds a file opened from the picker and switches to it. A file
+// that fails to open leaves the picker open to choose another.
+func (m Model) fileOpened(msg fileOpenedMsg) (tea.Model, tea.Cmd) {
+	m.filePicker.opening = ""
+	if msg.err != nil {
+		return m, m.showToast(fmt.Sprintf("Could not open %s: %v", filepath.Base(msg.path), msg.err), true)
+	}
+	m.filePicker.active = false
+
+	name := filepath.Base(msg.path)
+	if slices.Contains(m.fileNames, name) {
+		name = msg.path
+	}
+	m.harFiles = append(m.harFiles, msg.harFile)
+	m.analyzers = append(m.analyzers, har.NewAnalyzer(msg.harFile, m.settings))
+	m.fileNames = append(m.fileNames, name)
+	m.comparisonOrder = append(m.comparisonOrder, len(m.harFiles)-1)
+	m.updateComparison()
+
+	m.currentFile = len(m.harFiles) - 1
+	m.currentView = TableView
+	m.switchFile()
+
+	// A missing recent list only costs the shortcut next time
+	config.AddRecent(msg.path)
+
+	if len(m.harFiles) == 1 && !tutorialSeen() {
+		m.startTutorial()
+	}
+	return m, m.showToast(fmt.Sprintf("Opened %s (%d entries)", name, len(msg.harFile.Log.Entries)), false)
+}
+
+func (m Model) renderFilePicker() string {
+	p := m.filePicker
+	lines := []string{titleStyle.Render("Open HAR File"), ""}
+	if p.err != nil {
+		lines = append(lines, toastErrorStyle.Render(p.err.Error()), "")
+	}
+
+	// Scroll the list so the cursor stays in view
+	var list []string
+	cursorLine := 0
+	for i, item := range p.items {
+		if i == 0 && item.recent {
+			list = append(list, headerStyle.Render("Recent files"))
+		}
+		if !item.recent && (i == 0 || p.items[i-1].recent) {
+			if i > 0 {
+				list = append(list, "")
+			}
+			list = append(list, headerStyle.Render(p.dir))
+		}
+		if i == p.cursor {
+			cursorLine = len(list)
+			list = append(list, focusedStyle.Render("> "+item.label))
+		} else if item.dir {
+			list = append(list, "  "+statusStyle.Render(item.label))
+		} else {
+			list = append(list, "  "+item.label)
+		}
+	}
+	if len(p.items) == 0 || p.items[len(p.items)-1].recent {
+		list = append(list, "", headerStyle.Render(p.dir), "  No .har or .har.gz files here")
+	}
+	visible := max(m.height-8, 5)
+	offset := max(cursorLine-visible+1, 0)
+	lines = append(lines, list[offset:min(len(list), offset+visible)]...)
+
+	help := "↑↓ to choose, Enter to open, ← or Backspace for the parent directory, Esc to cancel"
+	if len(m.harFiles) == 0 {
+		help = "↑↓ to choose, Enter to open, ← or Backspace for the parent directory, Esc to quit"
+	}
+	if p.opening != "" {
+		help = "Opening " + p.opening + "..."
+	}
+	lines = append(lines, "", statusStyle.Render(help))
+	return strings.Join(lines, "\n")
+}
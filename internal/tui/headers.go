@@ -0,0 +1,41 @@
+package tui
+
+import (
+	"fmt"
+	"github.com/jlgore/hartea/internal/har"
+	"strings"
+)
+
+// renderHeaderOverhead shows per-domain header bytes and the requests with
+// oversized or duplicated headers.
+func (m Model) renderHeaderOverhead() []string {
+	report := m.analyzers[m.currentFile].HeaderAnalysis()
+	if len(report.Domains) == 0 {
+		return nil
+	}
+
+	lines := []string{headerStyle.Render("Header Overhead")}
+	limit := min(len(report.Domains), 5)
+	for _, domain := range report.Domains[:limit] {
+		lines = append(lines, fmt.Sprintf("  %s avg %8s req  %8s resp  max %8s  %d flagged",
+			fitWidth(domain.Domain, 32),
+			m.settings.SizeUnits.FormatSize(domain.RequestBytes/domain.Requests),
+			m.settings.SizeUnits.FormatSize(domain.ResponseBytes/domain.Requests),
+			m.settings.SizeUnits.FormatSize(domain.MaxRequestBytes),
+			domain.Flagged,
+		))
+	}
+
+	limit = min(len(report.Flagged), 5)
+	for _, stats := range report.Flagged[:limit] {
+		lines = append(lines, fmt.Sprintf("  ⚠️  %s: %s", truncateURL(stats.URL, 50), strings.Join(stats.Issues, "; ")))
+	}
+	if len(report.Flagged) > limit {
+		lines = append(lines, fmt.Sprintf("  ... and %d more requests with header issues", len(report.Flagged)-limit))
+	}
+
+	if report.ExceedsCongestionWindow {
+		lines = append(lines, fmt.Sprintf("  Some header blocks exceed the initial congestion window (%s) and cost an extra round trip on new connections", m.settings.SizeUnits.FormatSize(har.InitialCongestionWindow)))
+	}
+	return lines
+}
@@ -0,0 +1,73 @@
+package tui
+
+import (
+	"fmt"
+	"github.com/jlgore/hartea/internal/har"
+	"strings"
+	"time"
+)
+
+// heatmapColors has one color per har.HeatmapLevel, green to red.
+var heatmapColors = []string{"46", "148", "220", "208", "196"}
+
+const (
+	heatmapLabelWidth   = 36
+	heatmapMaxEndpoints = 20
+	heatmapMaxBuckets   = 60
+)
+
+// renderHeatmapView shows the p95 response time of the busiest endpoints
+// over the capture, to spot endpoints that slow down as a session goes on.
+func (m Model) renderHeatmapView() string {
+	content := []string{titleStyle.Render("Response Time Heatmap (p95 by endpoint over time)"), ""}
+
+	analyzer := m.analyzers[m.currentFile]
+	buckets := min(max((m.width-heatmapLabelWidth-4)/2, 1), heatmapMaxBuckets)
+	heatmap := analyzer.LatencyHeatmap(buckets, heatmapMaxEndpoints)
+	if len(heatmap.Endpoints) == 0 {
+		content = append(content, "No endpoint was requested more than once")
+		content = append(content, "", statusStyle.Render("Press Esc to go back"))
+		return strings.Join(content, "\n")
+	}
+	if analyzer.CaptureDuration() < har.HeatmapMinDuration {
+		content = append(content, statusStyle.Render(fmt.Sprintf("The capture spans under %s; the heatmap is most useful for long sessions", har.HeatmapMinDuration)), "")
+	}
+
+	for i, endpoint := range heatmap.Endpoints {
+		var cells strings.Builder
+		for _, p95 := range heatmap.P95[i] {
+			if p95 < 0 {
+				cells.WriteString(statusStyle.Render(" ·"))
+				continue
+			}
+			cells.WriteString(foreground(heatmapColors[har.HeatmapLevel(p95)]).Render("██"))
+		}
+		line := fitWidth(endpoint, heatmapLabelWidth) + " " + cells.String()
+		if heatmap.Degrading(i) {
+			line += heatmapDegradedStyle.Render(fmt.Sprintf(" ▲ %.1fx", heatmap.Degradation(i)))
+		}
+		content = append(content, line)
+	}
+
+	// renderTimeAxis leaves room for a 9 column chart label
+	content = append(content, strings.Repeat(" ", heatmapLabelWidth+1-9)+renderTimeAxis(2*len(heatmap.P95[0]), heatmap.Duration()))
+	content = append(content, "")
+	content = append(content, m.renderHeatmapLegend(heatmap.BucketWidth))
+	content = append(content, statusStyle.Render("Press Esc to go back"))
+	return strings.Join(content, "\n")
+}
+
+func (m Model) renderHeatmapLegend(bucketWidth time.Duration) string {
+	var legend []string
+	lower := "0"
+	for level, color := range heatmapColors {
+		label := "≥" + lower + "ms"
+		if level < len(har.HeatmapThresholds) {
+			label = fmt.Sprintf("<%.0fms", har.HeatmapThresholds[level])
+			lower = fmt.Sprintf("%.0f", har.HeatmapThresholds[level])
+		}
+		legend = append(legend, foreground(color).Render("██")+" "+label)
+	}
+	return strings.Join(legend, "  ") + fmt.Sprintf("   · no requests   ▲ p95 %.1fx slower in the last third   %s per column",
+		har.DegradedRatio, bucketWidth.Round(time.Millisecond))
+}
@@ -0,0 +1,60 @@
+package tui
+
+import (
+	"fmt"
+	"github.com/jlgore/hartea/internal/har"
+
+	"github.com/charmbracelet/bubbletea"
+)
+
+// hideSelected hides the selected requests, or the request under the cursor
+// when none are selected, from the analysis for this session. Unlike an
+// exclusion it is never saved, and unhideAll brings the requests back.
+func (m Model) hideSelected() (tea.Model, tea.Cmd) {
+	indices := m.selectedIndices()
+	if len(indices) == 0 {
+		index := m.currentEntryIndex()
+		if index < 0 {
+			return m, nil
+		}
+		indices = []int{index}
+	}
+	hidden := har.HideEntries(m.harFiles[m.currentFile], indices)
+	return m.afterHide(fmt.Sprintf("Hid %d requests for this session", hidden))
+}
+
+// hideDomain hides every request to the host of the request under the
+// cursor, and its subdomains, in all loaded files.
+func (m Model) hideDomain() (tea.Model, tea.Cmd) {
+	index := m.currentEntryIndex()
+	if index < 0 {
+		return m, nil
+	}
+	host := har.EntryHost(m.harFiles[m.currentFile].Log.Entries[index])
+	hidden := 0
+	for _, harFile := range m.harFiles {
+		hidden += har.HideMatching(harFile, host)
+	}
+	return m.afterHide(fmt.Sprintf("Hid %d requests to %s for this session", hidden, host))
+}
+
+func (m Model) afterHide(message string) (tea.Model, tea.Cmd) {
+	m.refreshAnalysis()
+	if m.currentView == DetailView || m.currentView == TimingView {
+		m.currentView = TableView
+	}
+	return m, m.showToast(fmt.Sprintf("%s (%s to unhide all)", message, m.keys.UnhideAll.Help().Key), false)
+}
+
+// unhideAll restores every hidden request of all loaded files.
+func (m Model) unhideAll() (tea.Model, tea.Cmd) {
+	restored := 0
+	for _, harFile := range m.harFiles {
+		restored += har.UnhideEntries(harFile)
+	}
+	if restored == 0 {
+		return m, m.showToast("No hidden requests", false)
+	}
+	m.refreshAnalysis()
+	return m, m.showToast(fmt.Sprintf("Restored %d hidden requests", restored), false)
+}
@@ -0,0 +1,68 @@
+package tui
+
+import (
+	"fmt"
+	"github.com/jlgore/hartea/internal/har"
+	"strings"
+)
+
+// renderHistogramView shows how response times are distributed over the
+// whole capture and, when a filter is applied, over the filtered requests.
+func (m Model) renderHistogramView() string {
+	all := m.harFiles[m.currentFile].Log.Entries
+	content := []string{titleStyle.Render("Response Time Histogram"), ""}
+	if len(all) == 0 {
+		content = append(content, "No requests to chart")
+		return strings.Join(content, "\n")
+	}
+
+	content = append(content, headerStyle.Render(fmt.Sprintf("All requests (%d)", len(all))))
+	content = append(content, m.renderHistogram(har.NewLatencyHistogram(all))...)
+
+	if filter := m.filter.Value(); filter != "" && len(m.entries) != len(all) {
+		content = append(content, "")
+		content = append(content, headerStyle.Render(fmt.Sprintf("Filter %q (%d)", filter, len(m.entries))))
+		if len(m.entries) == 0 {
+			content = append(content, "No requests match the filter")
+		} else {
+			content = append(content, m.renderHistogram(har.NewLatencyHistogram(m.entries))...)
+		}
+	}
+
+	content = append(content, "")
+	content = append(content, "Buckets grow on a log scale and include their upper bound; ◀ marks the bucket holding each percentile")
+	content = append(content, statusStyle.Render("Press / to filter, Esc to go back"))
+	return strings.Join(content, "\n")
+}
+
+// renderHistogram draws one bar per bucket, scaled to the fullest bucket,
+// with the percentiles marked beside the buckets they fall in.
+func (m Model) renderHistogram(histogram har.LatencyHistogram) []string {
+	barWidth := max(m.width-60, 10)
+	fullest := 0
+	for _, count := range histogram.Counts {
+		fullest = max(fullest, count)
+	}
+
+	markers := make([][]string, len(histogram.Counts))
+	for i, value := range histogram.Percentiles {
+		bucket := har.LatencyBucket(value)
+		markers[bucket] = append(markers[bucket], fmt.Sprintf("p%.0f %.1fms", har.HistogramQuantiles[i]*100, value))
+	}
+
+	var lines []string
+	for bucket, count := range histogram.Counts {
+		length := count * barWidth / max(fullest, 1)
+		if count > 0 && length == 0 {
+			length = 1
+		}
+		share := float64(count) / float64(histogram.Total) * 100
+		line := fmt.Sprintf("%12s │%s%s %4d %5.1f%%", har.BucketLabel(bucket),
+			histogramStyle.Render(strings.Repeat("█", length)), strings.Repeat(" ", barWidth-length), count, share)
+		if len(markers[bucket]) > 0 {
+			line += "  ◀ " + strings.Join(markers[bucket], ", ")
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
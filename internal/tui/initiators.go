@@ -0,0 +1,93 @@
+package tui
+
+import (
+	"fmt"
+	"github.com/jlgore/hartea/internal/har"
+	"strings"
+)
+
+// moveInitiatorSelection moves the highlighted request of the initiator tree.
+func (m *Model) moveInitiatorSelection(delta int) {
+	rows := len(m.harFiles[m.currentFile].Log.Entries)
+	m.initiatorSelected = max(0, min(m.initiatorSelected+delta, rows-1))
+}
+
+// inspectInitiatorSelection opens the highlighted request of the tree in
+// the detail view.
+func (m *Model) inspectInitiatorSelection() {
+	order := m.analyzers[m.currentFile].InitiatorGraph().Order()
+	if m.initiatorSelected < len(order) {
+		m.inspectEntry(order[m.initiatorSelected], InitiatorView)
+	}
+}
+
+// renderInitiatorView draws the requests as a tree, each under the request
+// that started it, with what started it: the parser, a script, a redirect.
+func (m Model) renderInitiatorView() string {
+	content := []string{titleStyle.Render("Initiator Chains"), ""}
+
+	analyzer := m.analyzers[m.currentFile]
+	entries := m.harFiles[m.currentFile].Log.Entries
+	if len(entries) == 0 {
+		content = append(content, "No requests in this capture", "", statusStyle.Render("Press Esc to go back"))
+		return strings.Join(content, "\n")
+	}
+	if !analyzer.HasInitiators() {
+		content = append(content, statusStyle.Render("No initiator data in this capture (Chrome DevTools exports record it as _initiator); only redirects are linked"), "")
+	}
+
+	graph := analyzer.InitiatorGraph()
+	rows := initiatorTreeRows(graph)
+	visible := max(m.height-8, 5)
+	offset := max(0, min(m.initiatorSelected-visible/2, len(rows)-visible))
+	for i := offset; i < min(offset+visible, len(rows)); i++ {
+		row := rows[i]
+		entry := entries[row.entry]
+		cause := graph.Nodes[row.entry].Cause
+		if cause == "" {
+			cause = "unknown"
+		}
+		line := fmt.Sprintf("%s%s  %d  %.0fms  %s", row.prefix, truncateURL(entry.Request.URL, max(m.width-len([]rune(row.prefix))-30, 30)),
+			entry.Response.Status, entry.Time, statusStyle.Render("("+cause+")"))
+		if i == m.initiatorSelected {
+			line = timelineCursorStyle.Render(line)
+		}
+		content = append(content, line)
+	}
+
+	content = append(content, "")
+	content = append(content, statusStyle.Render(fmt.Sprintf("%d requests, %d started by the browser or of unknown origin", len(entries), len(graph.Roots))))
+	content = append(content, statusStyle.Render("↑/↓ select, Enter for request details, Esc to go back"))
+	return strings.Join(content, "\n")
+}
+
+// initiatorTreeRow is one line of the initiator tree.
+type initiatorTreeRow struct {
+	entry  int
+	prefix string // tree guides, e.g. "│  └─ "
+}
+
+// initiatorTreeRows lays the graph out in har.InitiatorGraph.Order with
+// box-drawing guides.
+func initiatorTreeRows(graph har.InitiatorGraph) []initiatorTreeRow {
+	var rows []initiatorTreeRow
+	var visit func(index int, indent string, last, root bool)
+	visit = func(index int, indent string, last, root bool) {
+		prefix, childIndent := "", ""
+		if !root {
+			prefix, childIndent = indent+"├─ ", indent+"│  "
+			if last {
+				prefix, childIndent = indent+"└─ ", indent+"   "
+			}
+		}
+		rows = append(rows, initiatorTreeRow{entry: index, prefix: prefix})
+		children := graph.Nodes[index].Children
+		for i, child := range children {
+			visit(child, childIndent, i == len(children)-1, false)
+		}
+	}
+	for _, root := range graph.Roots {
+		visit(root, "", false, true)
+	}
+	return rows
+}
@@ -0,0 +1,44 @@
+package tui
+
+import (
+	"fmt"
+	"github.com/jlgore/hartea/internal/har"
+	"strings"
+)
+
+// renderJSAudit shows the JavaScript payload per host, the largest bundles
+// and the duplicate libraries and leaked source maps of the current file.
+func (m Model) renderJSAudit() []string {
+	audit := m.analyzers[m.currentFile].JSAudit()
+	if !audit.HasData() && len(audit.SourceMaps) == 0 {
+		return nil
+	}
+
+	lines := []string{headerStyle.Render("JavaScript")}
+	lines = append(lines, fmt.Sprintf("Scripts: %d requests, %s decoded, %s transferred from %d hosts",
+		audit.Requests, m.settings.SizeUnits.FormatSize(int(audit.Bytes)), m.settings.SizeUnits.FormatSize(int(audit.Transfer)), len(audit.Domains)))
+
+	limit := min(len(audit.Bundles), 5)
+	for _, bundle := range audit.Bundles[:limit] {
+		line := fmt.Sprintf("  %s %s %8s", fitWidth(bundle.Name, 32), fitWidth(bundle.Domain, 24), m.settings.SizeUnits.FormatSize(int(bundle.Bytes)))
+		if bundle.Large {
+			line += "  " + cookieIssueStyle.Render("⚠️  over "+m.settings.SizeUnits.FormatSize(har.LargeBundleBytes))
+		}
+		lines = append(lines, line)
+	}
+	if len(audit.Bundles) > limit {
+		lines = append(lines, fmt.Sprintf("  ... and %d more bundles", len(audit.Bundles)-limit))
+	}
+
+	for _, library := range audit.DuplicateLibraries {
+		detail := strings.Join(library.Hosts, ", ")
+		if len(library.Versions) > 1 {
+			detail += "; versions " + strings.Join(library.Versions, ", ")
+		}
+		lines = append(lines, cookieIssueStyle.Render(fmt.Sprintf("⚠️  %s loaded %d times (%s)", library.Library, library.Requests, detail)))
+	}
+	for _, sourceMap := range audit.SourceMaps {
+		lines = append(lines, cookieIssueStyle.Render("⚠️  Source map served in production: "+truncateValue(sourceMap.URL, 80)))
+	}
+	return lines
+}
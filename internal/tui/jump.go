@@ -0,0 +1,166 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbletea"
+)
+
+func newGotoInput() textinput.Model {
+	input := textinput.New()
+	input.Prompt = ":"
+	input.Placeholder = "request number"
+	input.CharLimit = 10
+	return input
+}
+
+// openGotoInput starts typing a request number to jump to, vim style.
+func (m Model) openGotoInput() (tea.Model, tea.Cmd) {
+	m.showGotoInput = true
+	m.gotoInput.SetValue("")
+	m.gotoInput.Focus()
+	return m, textinput.Blink
+}
+
+func (m Model) updateGotoInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.showGotoInput = false
+		return m, nil
+	case "enter":
+		m.showGotoInput = false
+		value := strings.TrimSpace(m.gotoInput.Value())
+		if value == "" {
+			return m, nil
+		}
+		entries := m.harFiles[m.currentFile].Log.Entries
+		number, err := strconv.Atoi(value)
+		if err != nil || number < 1 || number > len(entries) {
+			return m, m.showToast(fmt.Sprintf("No request %s: requests are numbered 1 to %d", value, len(entries)), true)
+		}
+		m.jumpToEntry(number - 1)
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.gotoInput, cmd = m.gotoInput.Update(msg)
+	return m, cmd
+}
+
+// showEntry returns the table row of a loaded entry, clearing the quick
+// view and filter, then the time window, when they hide it.
+func (m *Model) showEntry(index int) int {
+	rowOf := func() int {
+		for row, entryIndex := range m.entryIndices {
+			if entryIndex == index {
+				return row
+			}
+		}
+		return -1
+	}
+	row := rowOf()
+	if row < 0 {
+		m.quickView = AllRequests
+		m.filter.SetValue("")
+		m.filterEntries("")
+		row = rowOf()
+	}
+	if row < 0 {
+		m.clearWindow()
+		row = index
+	}
+	return row
+}
+
+// jumpToEntry moves to a loaded entry: the table highlights it, and the
+// detail view shows it.
+func (m *Model) jumpToEntry(index int) {
+	row := m.showEntry(index)
+	m.table.SetCursor(row)
+	if m.currentView == DetailView {
+		m.selectedEntry = row
+		m.detailSelected = 0
+	}
+}
+
+// currentEntryIndex is the loaded entry highlighted in the table, or shown
+// in the detail view.
+func (m Model) currentEntryIndex() int {
+	row := m.table.Cursor()
+	if m.currentView == DetailView {
+		row = m.selectedEntry
+	}
+	if row < 0 || row >= len(m.entryIndices) {
+		return -1
+	}
+	return m.entryIndices[row]
+}
+
+// matchIndices are the loaded entries n and N cycle through, in capture
+// order: those the body search found, or else those the filter, quick view
+// or time window shows. It is nil when nothing narrows the table.
+func (m Model) matchIndices() []int {
+	if len(m.bodyMatches) > 0 {
+		var indices []int
+		for _, match := range m.bodyMatches {
+			if len(indices) == 0 || indices[len(indices)-1] != match.EntryIndex {
+				indices = append(indices, match.EntryIndex)
+			}
+		}
+		return indices
+	}
+	if m.filterActive() || m.window.active() {
+		return m.entryIndices
+	}
+	return nil
+}
+
+// cycleMatch moves to the next (or with reverse, previous) match after the
+// current entry, wrapping around at either end.
+func (m *Model) cycleMatch(reverse bool) tea.Cmd {
+	if m.currentView == BodySearchView {
+		if len(m.bodyMatches) > 0 {
+			step := 1
+			if reverse {
+				step = len(m.bodyMatches) - 1
+			}
+			m.bodyMatchSelected = (m.bodyMatchSelected + step) % len(m.bodyMatches)
+		}
+		return nil
+	}
+
+	matches := m.matchIndices()
+	if len(matches) == 0 {
+		return m.showToast(fmt.Sprintf("Nothing to cycle through: filter with %s or search bodies with %s", m.keys.Filter.Help().Key, m.keys.BodySearch.Help().Key), true)
+	}
+
+	current := m.currentEntryIndex()
+	next := 0
+	if reverse {
+		next = len(matches) - 1
+		for i := len(matches) - 1; i >= 0; i-- {
+			if matches[i] < current {
+				next = i
+				break
+			}
+		}
+	} else {
+		for i, index := range matches {
+			if index > current {
+				next = i
+				break
+			}
+		}
+	}
+
+	index := matches[next]
+	m.jumpToEntry(index)
+	return m.showToast(fmt.Sprintf("Match %d of %d: request #%d", next+1, len(matches), index+1), false)
+}
+
+func (m Model) renderGotoInput() string {
+	return m.gotoInput.View() + "  " + statusStyle.Render("Enter to jump to the request, Esc to cancel")
+}
@@ -0,0 +1,214 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/viewport"
+)
+
+type keyAction struct {
+	name    string
+	binding *key.Binding
+}
+
+// actions names every binding the way the config file spells it.
+func (k *KeyMap) actions() []keyAction {
+	return []keyAction{
+		{"up", &k.Up},
+		{"down", &k.Down},
+		{"left", &k.Left},
+		{"right", &k.Right},
+		{"enter", &k.Enter},
+		{"back", &k.Back},
+		{"filter", &k.Filter},
+		{"metrics", &k.Metrics},
+		{"timeline", &k.Timeline},
+		{"comparison", &k.Comparison},
+		{"export", &k.Export},
+		{"help", &k.Help},
+		{"quit", &k.Quit},
+		{"tab", &k.Tab},
+		{"tutorial", &k.Tutorial},
+		{"timing", &k.Timing},
+		{"tag", &k.Tag},
+		{"security", &k.Security},
+		{"exclude", &k.Exclude},
+		{"excludeAll", &k.ExcludeAll},
+		{"excludeExt", &k.ExcludeExt},
+		{"bundle", &k.Bundle},
+		{"protocol", &k.Protocol},
+		{"concurrency", &k.Concurrency},
+		{"order", &k.Order},
+		{"bandwidth", &k.Bandwidth},
+		{"timingCols", &k.TimingCols},
+		{"histogram", &k.Histogram},
+		{"heatmap", &k.Heatmap},
+		{"statuses", &k.Statuses},
+		{"slowest", &k.Slowest},
+		{"largest", &k.Largest},
+		{"errors", &k.Errors},
+		{"endpoints", &k.Endpoints},
+		{"trends", &k.Trends},
+		{"whatIf", &k.WhatIf},
+		{"initiators", &k.Initiators},
+		{"prevTab", &k.PrevTab},
+		{"nextTab", &k.NextTab},
+		{"copy", &k.Copy},
+		{"rawBody", &k.RawBody},
+		{"mark", &k.Mark},
+		{"diffAll", &k.DiffAll},
+		{"baseline", &k.Baseline},
+		{"moveEarlier", &k.MoveEarlier},
+		{"moveLater", &k.MoveLater},
+		{"include", &k.Include},
+		{"theme", &k.Theme},
+		{"split", &k.Split},
+		{"open", &k.Open},
+		{"select", &k.Select},
+		{"bulk", &k.Bulk},
+		{"bodySearch", &k.BodySearch},
+		{"goTo", &k.GoTo},
+		{"nextMatch", &k.NextMatch},
+		{"prevMatch", &k.PrevMatch},
+		{"browser", &k.Browser},
+		{"saveBody", &k.SaveBody},
+		{"times", &k.Times},
+		{"scope", &k.Scope},
+		{"hide", &k.Hide},
+		{"hideDomain", &k.HideDomain},
+		{"unhideAll", &k.UnhideAll},
+		{"clusters", &k.Clusters},
+	}
+}
+
+// navigationActions are the actions that move the way the table's and
+// viewport's own bindings of the same keys do, e.g. "up" on k.
+var navigationActions = map[string]string{
+	"up":    "up",
+	"down":  "down",
+	"left":  "move left",
+	"right": "move right",
+}
+
+// navigationKeys maps the keys the table and viewport handle themselves to
+// what they do, e.g. "d" to "½ page down".
+func navigationKeys() map[string]string {
+	tableKeys, viewportKeys := table.DefaultKeyMap(), viewport.DefaultKeyMap()
+	bindings := []key.Binding{
+		tableKeys.LineUp, tableKeys.LineDown, tableKeys.PageUp, tableKeys.PageDown,
+		tableKeys.HalfPageUp, tableKeys.HalfPageDown, tableKeys.GotoTop, tableKeys.GotoBottom,
+		viewportKeys.Up, viewportKeys.Down, viewportKeys.Left, viewportKeys.Right,
+		viewportKeys.PageUp, viewportKeys.PageDown, viewportKeys.HalfPageUp, viewportKeys.HalfPageDown,
+	}
+	keys := make(map[string]string)
+	for _, binding := range bindings {
+		for _, bound := range binding.Keys() {
+			keys[bound] = binding.Help().Desc
+		}
+	}
+	return keys
+}
+
+// Rebind replaces the keys of the named actions, e.g. {"export": ["x"]},
+// keeping their descriptions. Unknown actions, keys bound to two actions and
+// keys the table or viewport already navigate with (unless the action had
+// that key by default) are errors, and leave the KeyMap unchanged.
+func (k *KeyMap) Rebind(bindings map[string][]string) error {
+	rebound := *k
+	actions := rebound.actions()
+	byName := make(map[string]*key.Binding, len(actions))
+	for _, action := range actions {
+		byName[action.name] = action.binding
+	}
+	defaults := make(map[string][]string, len(actions))
+	original := DefaultKeyMap()
+	for _, action := range original.actions() {
+		defaults[action.name] = action.binding.Keys()
+	}
+	navigation := navigationKeys()
+
+	names := make([]string, 0, len(bindings))
+	for name := range bindings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		binding, ok := byName[name]
+		if !ok {
+			return fmt.Errorf("unknown key action %q", name)
+		}
+		keys := bindings[name]
+		if len(keys) == 0 {
+			return fmt.Errorf("no keys given for key action %q", name)
+		}
+		for _, bound := range keys {
+			moves, ok := navigation[bound]
+			if ok && moves != navigationActions[name] && !slices.Contains(defaults[name], bound) {
+				return fmt.Errorf("key %q for %s is the table's %s key", bound, name, moves)
+			}
+		}
+		*binding = key.NewBinding(key.WithKeys(keys...), key.WithHelp(keyHelp(keys), binding.Help().Desc))
+	}
+
+	owners := make(map[string]string)
+	for _, action := range actions {
+		for _, bound := range action.binding.Keys() {
+			if owner, ok := owners[bound]; ok {
+				return fmt.Errorf("key %q is bound to both %s and %s", bound, owner, action.name)
+			}
+			owners[bound] = action.name
+		}
+	}
+
+	*k = rebound
+	return nil
+}
+
+// keyHelp shows keys the way the help view writes them, e.g. "↑/k".
+func keyHelp(keys []string) string {
+	names := make([]string, len(keys))
+	for i, k := range keys {
+		switch k {
+		case "up":
+			names[i] = "↑"
+		case "down":
+			names[i] = "↓"
+		case "left":
+			names[i] = "←"
+		case "right":
+			names[i] = "→"
+		case "enter", "esc", "tab":
+			names[i] = strings.ToUpper(k[:1]) + k[1:]
+		case " ":
+			names[i] = "Space"
+		default:
+			names[i] = k
+			if function, ok := strings.CutPrefix(k, "f"); ok {
+				if _, err := strconv.Atoi(function); err == nil {
+					names[i] = strings.ToUpper(k)
+				}
+			}
+		}
+	}
+	return strings.Join(names, "/")
+}
+
+// helpKeys lists the bindings' keys, separated by sep.
+func helpKeys(sep string, bindings ...key.Binding) string {
+	keys := make([]string, len(bindings))
+	for i, binding := range bindings {
+		keys[i] = binding.Help().Key
+	}
+	return strings.Join(keys, sep)
+}
+
+// helpRow is a line of the help view: the keys, then what they do.
+func helpRow(keys, text string) string {
+	return fmt.Sprintf("%-12s %s", keys, text)
+}
@@ -0,0 +1,31 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRebindRejectsNavigationKeys(t *testing.T) {
+	tests := []struct {
+		bindings map[string][]string
+		wantErr  string
+	}{
+		{map[string][]string{"export": []string{"ctrl+e"}}, ""},
+		{map[string][]string{"up": []string{"up", "k"}, "down": []string{"down", "j"}}, ""},
+		{map[string][]string{"largest": []string{"b"}}, ""},
+		{map[string][]string{"export": []string{"d"}}, "½ page down"},
+		{map[string][]string{"initiators": []string{"g"}}, "go to start"},
+		{map[string][]string{"up": []string{"j"}}, "down"},
+		{map[string][]string{"right": []string{"pgdown"}}, "page down"},
+	}
+	for _, tt := range tests {
+		keys := DefaultKeyMap()
+		err := keys.Rebind(tt.bindings)
+		switch {
+		case tt.wantErr == "" && err != nil:
+			t.Errorf("Rebind(%v) = %v, want no error", tt.bindings, err)
+		case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+			t.Errorf("Rebind(%v) = %v, want an error mentioning %q", tt.bindings, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jlgore/hartea/internal/har"
+)
+
+// labSection renders the metrics view of the example capture, changed by
+// edit, and returns its "Lab & Visual Metrics" section.
+func labSection(t *testing.T, edit func(*har.HAR)) string {
+	t.Helper()
+	harFile, err := har.NewParser().ParseFile("../../example.har")
+	if err != nil {
+		t.Fatalf("parsing example capture: %v", err)
+	}
+	if edit != nil {
+		edit(harFile)
+	}
+	view, err := RenderView([]*har.HAR{harFile}, Options{}, "metrics", 120, 300, false)
+	if err != nil {
+		t.Fatalf("rendering metrics view: %v", err)
+	}
+	_, section, ok := strings.Cut(view, "Lab & Visual Metrics")
+	if !ok {
+		return ""
+	}
+	section, _, _ = strings.Cut(section, "\n\n")
+	return section
+}
+
+func TestLabSectionListsOnlyLabMetrics(t *testing.T) {
+	tests := []struct {
+		name   string
+		metric string
+		edit   func(*har.HAR)
+	}{
+		{"compression coverage", "Compression Coverage", nil},
+		{"brotli coverage", "Brotli Coverage", nil},
+		{"HTTP/2+ coverage", "HTTP/2+ Coverage", func(harFile *har.HAR) {
+			for i := range harFile.Log.Entries {
+				harFile.Log.Entries[i].Response.HTTPVersion = "h2"
+			}
+		}},
+		{"CDN hit ratio", "CDN Hit Ratio", func(harFile *har.HAR) {
+			for i := range harFile.Log.Entries {
+				harFile.Log.Entries[i].Response.Headers = append(harFile.Log.Entries[i].Response.Headers, har.Header{Name: "cf-cache-status", Value: "HIT"})
+			}
+		}},
+	}
+	for _, tt := range tests {
+		if section := labSection(t, tt.edit); strings.Contains(section, tt.metric) {
+			t.Errorf("%s is listed as a lab metric:%s", tt.name, section)
+		}
+	}
+
+	withLab := func(harFile *har.HAR) {
+		harFile.Log.Pages[0].FirstContentfulPaint = 900
+	}
+	if section := labSection(t, withLab); !strings.Contains(section, "First Contentful Paint") {
+		t.Errorf("First Contentful Paint is missing from the lab metrics:%q", section)
+	}
+}
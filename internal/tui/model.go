@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"github.com/jlgore/hartea/internal/har"
 	"github.com/jlgore/hartea/internal/report"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -12,6 +14,7 @@ import (
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 )
 
 type ViewMode int
@@ -23,6 +26,20 @@ const (
 	TimelineView
 	ComparisonView
 	HelpView
+	TimingView
+	SecurityView
+	ConcurrencyView
+	ThroughputView
+	HistogramView
+	HeatmapView
+	StatusView
+	EndpointsView
+	DiffView
+	TrendView
+	WhatIfView
+	InitiatorView
+	BodySearchView
+	ErrorClusterView
 )
 
 type Model struct {
@@ -31,10 +48,16 @@ type Model struct {
 	currentFile   int
 	currentView   ViewMode
 	selectedEntry int
+	// detailReturn is the view Esc returns to from the detail view
+	detailReturn ViewMode
 
 	// Components
-	table  table.Model
-	filter textinput.Model
+	table        table.Model
+	filter       textinput.Model
+	exportDialog ExportDialog
+	bundleDialog BundleDialog
+	filePicker   FilePicker
+	bulkDialog   BulkDialog
 
 	// State
 	width      int
@@ -42,15 +65,116 @@ type Model struct {
 	loading    bool
 	err        error
 	showFilter bool
+	tutorial   Tutorial
+	// showProtocol adds the HTTP version column to the table
+	showProtocol bool
+	// showTiming adds the TTFB and start offset columns to the table
+	showTiming bool
+	// timeDisplay is how start times are shown; utc shows clock times in
+	// UTC rather than local time
+	timeDisplay TimeDisplay
+	utc         bool
+	// splitPane shows the highlighted request's details with the table
+	splitPane      bool
+	waterfallOrder WaterfallOrder
+	// timelineSelected is the current file's entry highlighted in the timeline
+	timelineSelected int
+	// window limits the current file's table, metrics and exports to the
+	// requests that started within it
+	window timeWindow
+	// scopeToFilter limits the metrics, timeline and exports to the requests
+	// the filter or quick view shows as well
+	scopeToFilter bool
+	// scopeFile and scopeAnalyzer hold the requests the window and filter
+	// scope leave, while either narrows the analysis
+	scopeFile     *har.HAR
+	scopeAnalyzer *har.Analyzer
+	// statusSelected is the highlighted row of the status view
+	statusSelected int
+	// endpointOffset is the first row shown in the endpoints view
+	endpointOffset int
+	// errorClusterSelected is the highlighted cluster of the error clusters
+	// view
+	errorClusterSelected int
+	// whatIfSelected is the highlighted site of the what-if view, and
+	// whatIfRemoved the sites marked for a combined estimate
+	whatIfSelected int
+	whatIfRemoved  []string
+	// initiatorSelected is the highlighted row of the initiator tree
+	initiatorSelected int
+	// detailTab is the page of the detail view shown, and detailSelected
+	// the highlighted row or scroll offset of the tab
+	detailTab      DetailTab
+	detailSelected int
+	// bodyRaw shows request bodies as raw text in the body tab
+	bodyRaw bool
+	// headerSearch narrows the headers tab; headerSearching is set while
+	// it is typed
+	headerSearch    textinput.Model
+	headerSearching bool
+	// bodySearch searches the current file's bodies; bodySearching is set
+	// while it is typed, and bodyMatches holds the results
+	bodySearch        textinput.Model
+	bodySearching     bool
+	bodyMatches       []har.BodyMatch
+	bodyMatchSelected int
+	// gotoInput is the :42 prompt to jump to a request
+	gotoInput     textinput.Model
+	showGotoInput bool
+	// markedEntry is the loaded entry of markedFile marked for a diff, or
+	// noMark; the diff view compares diffBefore against diffAfter
+	markedEntry int
+	markedFile  int
+	diffBefore  entryRef
+	diffAfter   entryRef
+	diffReturn  ViewMode
+	// selectedEntries are the current file's loaded entries selected for a
+	// bulk action
+	selectedEntries map[int]bool
+
+	// comparisonSelected is the highlighted request of the comparison view
+	comparisonSelected int
+	diffOffset         int
+	diffShowAll        bool
+
+	quickView QuickView
+	toast     Toast
+
+	// pendingExclusion is set while an exclusion waits for confirmation
+	pendingExclusion exclusionPrompt
+
+	// Tagging
+	tagInput     textinput.Model
+	showTagInput bool
+	tagTarget    int
+
+	// Workspace settings
+	fileNames     []string
+	workspaceName string
+	savedFilters  map[string]string
+	budgets       map[string]float64
+	vendorBudgets []har.VendorBudget
+	slos          []har.SLO
+	segmentGap    time.Duration
+	settings      har.Settings
+	onExclude     func(pattern string) error
+	openFile      func(path string) (*har.HAR, error)
 
 	// Data
-	entries    []har.Entry
-	timeline   []har.TimelineEvent
-	metrics    *har.Metrics
-	comparison *har.Comparison
+	entries      []har.Entry
+	entryIndices []int // index into the current file's entries for each row
+	timeline     []har.TimelineEvent
+	metrics      *har.Metrics
+	comparison   *har.Comparison
+	// comparisonOrder lists the compared files, baseline first; files left
+	// out of the comparison are missing
+	comparisonOrder []int
 
 	// Keybindings
 	keys KeyMap
+
+	plain   bool
+	noColor bool
 }
 
 // Make render methods available
@@ -58,7 +182,7 @@ func (m Model) RenderTableView() string {
 	var header string
 
 	if len(m.harFiles) > 1 {
-		header = titleStyle.Render(fmt.Sprintf("Hartea Analysis - Treasure Map %d/%d", m.currentFile+1, len(m.harFiles)))
+		header = titleStyle.Render(fmt.Sprintf("Hartea Analysis - Treasure Map %d/%d: %s", m.currentFile+1, len(m.harFiles), m.fileNames[m.currentFile]))
 	} else {
 		header = titleStyle.Render("Hartea - Charting Digital Seas")
 	}
@@ -68,20 +192,49 @@ func (m Model) RenderTableView() string {
 			"Requests: %d | Total Time: %.1fms | Total Size: %s | Errors: %d",
 			m.metrics.TotalRequests,
 			m.metrics.TotalTime,
-			formatSize(int(m.metrics.TotalSize)),
+			m.settings.SizeUnits.FormatSize(int(m.metrics.TotalSize)),
 			m.metrics.ErrorRequests,
 		)
+		if m.metrics.Environment != "" && m.metrics.Environment != har.EnvProduction {
+			summary += fmt.Sprintf(" | Env: %s", m.metrics.Environment)
+		}
+		if excluded := m.excludedCount(); excluded > 0 {
+			summary += fmt.Sprintf(" | Excluded: %d", excluded)
+		}
+		if hidden := len(m.harFiles[m.currentFile].Log.Hidden); hidden > 0 {
+			summary += fmt.Sprintf(" | Hidden: %d", hidden)
+		}
 		header += "\n" + statusStyle.Render(summary)
 	}
+	if title := m.quickViewTitle(); title != "" {
+		header += "\n" + headerStyle.Render(title) + statusStyle.Render(" (Esc for all requests)")
+	}
+	if title := m.markTitle(); title != "" {
+		header += "\n" + statusStyle.Render(title)
+	}
+	if title := m.selectionTitle(); title != "" {
+		header += "\n" + statusStyle.Render(title)
+	}
+	if title := m.windowTitle(); title != "" {
+		header += "\n" + headerStyle.Render(title) + statusStyle.Render(" (Esc for all requests)")
+	}
+	if title := m.scopeTitle(); title != "" {
+		header += "\n" + headerStyle.Render(title)
+	}
 
-	var footer string
+	k := m.keys
+	hints := fmt.Sprintf("Press %s for help, %s to filter, %s for metrics, %s for timeline, ", k.Help.Help().Key, k.Filter.Help().Key, k.Metrics.Help().Key, k.Timeline.Help().Key)
 	if len(m.harFiles) > 1 {
-		footer = "\n" + statusStyle.Render("Press ? for help, / to filter, m for metrics, t for timeline, c for comparison, e to export, q to quit")
-	} else {
-		footer = "\n" + statusStyle.Render("Press ? for help, / to filter, m for metrics, t for timeline, e to export, q to quit")
+		hints += fmt.Sprintf("%s for comparison, ", k.Comparison.Help().Key)
 	}
+	hints += fmt.Sprintf("%s to export, %s to quit", k.Export.Help().Key, k.Quit.Help().Key)
+	footer := "\n" + statusStyle.Render(hints)
 
-	return header + "\n\n" + m.table.View() + footer
+	body := m.table.View()
+	if m.splitPane {
+		body = m.renderSplitPane()
+	}
+	return header + "\n\n" + body + footer
 }
 
 func (m Model) RenderFilter() string {
@@ -89,24 +242,88 @@ func (m Model) RenderFilter() string {
 	prompt := "\n\n" + m.filter.View()
 	help := "\n\nPress Enter to apply filter, Esc to cancel"
 
+	if len(m.savedFilters) > 0 {
+		names := make([]string, 0, len(m.savedFilters))
+		for name := range m.savedFilters {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		saved := "\n\n" + headerStyle.Render("Saved filters (type @name)")
+		for _, name := range names {
+			saved += fmt.Sprintf("\n@%-15s %s", name, m.savedFilters[name])
+		}
+		help += saved
+	}
+
 	return header + prompt + help
 }
 
 type KeyMap struct {
-	Up         key.Binding
-	Down       key.Binding
-	Left       key.Binding
-	Right      key.Binding
-	Enter      key.Binding
-	Back       key.Binding
-	Filter     key.Binding
-	Metrics    key.Binding
-	Timeline   key.Binding
-	Comparison key.Binding
-	Export     key.Binding
-	Help       key.Binding
-	Quit       key.Binding
-	Tab        key.Binding
+	Up          key.Binding
+	Down        key.Binding
+	Left        key.Binding
+	Right       key.Binding
+	Enter       key.Binding
+	Back        key.Binding
+	Filter      key.Binding
+	Metrics     key.Binding
+	Timeline    key.Binding
+	Comparison  key.Binding
+	Export      key.Binding
+	Help        key.Binding
+	Quit        key.Binding
+	Tab         key.Binding
+	Tutorial    key.Binding
+	Timing      key.Binding
+	Tag         key.Binding
+	Security    key.Binding
+	Exclude     key.Binding
+	ExcludeAll  key.Binding
+	ExcludeExt  key.Binding
+	Bundle      key.Binding
+	Protocol    key.Binding
+	Concurrency key.Binding
+	Order       key.Binding
+	Bandwidth   key.Binding
+	TimingCols  key.Binding
+	Histogram   key.Binding
+	Heatmap     key.Binding
+	Statuses    key.Binding
+	Slowest     key.Binding
+	Largest     key.Binding
+	Errors      key.Binding
+	Endpoints   key.Binding
+	Trends      key.Binding
+	WhatIf      key.Binding
+	Initiators  key.Binding
+	PrevTab     key.Binding
+	NextTab     key.Binding
+	Copy        key.Binding
+	RawBody     key.Binding
+	Mark        key.Binding
+	DiffAll     key.Binding
+	Baseline    key.Binding
+	MoveEarlier key.Binding
+	MoveLater   key.Binding
+	Include     key.Binding
+	Theme       key.Binding
+	Split       key.Binding
+	Open        key.Binding
+	Select      key.Binding
+	Bulk        key.Binding
+	BodySearch  key.Binding
+	GoTo        key.Binding
+	NextMatch   key.Binding
+	PrevMatch   key.Binding
+	Browser     key.Binding
+	SaveBody    key.Binding
+	Times       key.Binding
+	Scope       key.Binding
+	Hide        key.Binding
+	HideDomain  key.Binding
+	UnhideAll   key.Binding
+	Clusters    key.Binding
 }
 
 func DefaultKeyMap() KeyMap {
@@ -129,11 +346,11 @@ func DefaultKeyMap() KeyMap {
 		),
 		Enter: key.NewBinding(
 			key.WithKeys("enter"),
-			key.WithHelp("enter", "view details"),
+			key.WithHelp("Enter", "view details"),
 		),
 		Back: key.NewBinding(
 			key.WithKeys("esc"),
-			key.WithHelp("esc", "back"),
+			key.WithHelp("Esc", "back"),
 		),
 		Filter: key.NewBinding(
 			key.WithKeys("/"),
@@ -165,52 +382,273 @@ func DefaultKeyMap() KeyMap {
 		),
 		Tab: key.NewBinding(
 			key.WithKeys("tab"),
-			key.WithHelp("tab", "switch file"),
+			key.WithHelp("Tab", "switch file"),
+		),
+		Tutorial: key.NewBinding(
+			key.WithKeys("f1"),
+			key.WithHelp("F1", "tutorial"),
+		),
+		Timing: key.NewBinding(
+			key.WithKeys("p"),
+			key.WithHelp("p", "timing phases"),
+		),
+		Tag: key.NewBinding(
+			key.WithKeys("#"),
+			key.WithHelp("#", "tag request"),
+		),
+		Security: key.NewBinding(
+			key.WithKeys("!"),
+			key.WithHelp("!", "security findings"),
+		),
+		Exclude: key.NewBinding(
+			key.WithKeys("x"),
+			key.WithHelp("x", "exclude URL"),
+		),
+		ExcludeAll: key.NewBinding(
+			key.WithKeys("X"),
+			key.WithHelp("X", "exclude domain"),
+		),
+		ExcludeExt: key.NewBinding(
+			key.WithKeys("E"),
+			key.WithHelp("E", "exclude extension traffic"),
+		),
+		Bundle: key.NewBinding(
+			key.WithKeys("B"),
+			key.WithHelp("B", "before/after bundle"),
+		),
+		Protocol: key.NewBinding(
+			key.WithKeys("v"),
+			key.WithHelp("v", "protocol column"),
+		),
+		Concurrency: key.NewBinding(
+			key.WithKeys("C"),
+			key.WithHelp("C", "requests in flight"),
+		),
+		Order: key.NewBinding(
+			key.WithKeys("o"),
+			key.WithHelp("o", "waterfall order"),
+		),
+		Bandwidth: key.NewBinding(
+			key.WithKeys("w"),
+			key.WithHelp("w", "bandwidth over time"),
+		),
+		TimingCols: key.NewBinding(
+			key.WithKeys("T"),
+			key.WithHelp("T", "TTFB and start columns"),
+		),
+		Histogram: key.NewBinding(
+			key.WithKeys("H"),
+			key.WithHelp("H", "response time histogram"),
+		),
+		Heatmap: key.NewBinding(
+			key.WithKeys("L"),
+			key.WithHelp("L", "latency heatmap"),
+		),
+		Statuses: key.NewBinding(
+			key.WithKeys("S"),
+			key.WithHelp("S", "status codes"),
+		),
+		Slowest: key.NewBinding(
+			key.WithKeys("s"),
+			key.WithHelp("s", "slowest requests"),
+		),
+		Largest: key.NewBinding(
+			key.WithKeys("b"),
+			key.WithHelp("b", "largest responses"),
+		),
+		Errors: key.NewBinding(
+			key.WithKeys("r"),
+			key.WithHelp("r", "error responses"),
+		),
+		Endpoints: key.NewBinding(
+			key.WithKeys("a"),
+			key.WithHelp("a", "API endpoints"),
+		),
+		Trends: key.NewBinding(
+			key.WithKeys("~"),
+			key.WithHelp("~", "trends"),
+		),
+		WhatIf: key.NewBinding(
+			key.WithKeys("D"),
+			key.WithHelp("D", "what if a site were removed"),
+		),
+		Initiators: key.NewBinding(
+			key.WithKeys("I"),
+			key.WithHelp("I", "initiator chains"),
+		),
+		PrevTab: key.NewBinding(
+			key.WithKeys("["),
+			key.WithHelp("[", "previous detail tab"),
+		),
+		NextTab: key.NewBinding(
+			key.WithKeys("]"),
+			key.WithHelp("]", "next detail tab"),
+		),
+		Copy: key.NewBinding(
+			key.WithKeys("y"),
+			key.WithHelp("y", "copy value"),
+		),
+		RawBody: key.NewBinding(
+			key.WithKeys("R"),
+			key.WithHelp("R", "raw request body"),
+		),
+		Mark: key.NewBinding(
+			key.WithKeys("M"),
+			key.WithHelp("M", "mark request for diff"),
+		),
+		DiffAll: key.NewBinding(
+			key.WithKeys("="),
+			key.WithHelp("=", "show identical fields"),
+		),
+		Baseline: key.NewBinding(
+			key.WithKeys("u"),
+			key.WithHelp("u", "use as baseline"),
+		),
+		MoveEarlier: key.NewBinding(
+			key.WithKeys("<"),
+			key.WithHelp("<", "move file earlier"),
+		),
+		MoveLater: key.NewBinding(
+			key.WithKeys(">"),
+			key.WithHelp(">", "move file later"),
+		),
+		Include: key.NewBinding(
+			key.WithKeys("i"),
+			key.WithHelp("i", "include or leave out file"),
+		),
+		Theme: key.NewBinding(
+			key.WithKeys("P"),
+			key.WithHelp("P", "next color theme"),
+		),
+		Split: key.NewBinding(
+			key.WithKeys("|"),
+			key.WithHelp("|", "split pane"),
+		),
+		Open: key.NewBinding(
+			key.WithKeys("O"),
+			key.WithHelp("O", "open file"),
+		),
+		Select: key.NewBinding(
+			key.WithKeys(" "),
+			key.WithHelp("Space", "select request"),
+		),
+		Bulk: key.NewBinding(
+			key.WithKeys("A"),
+			key.WithHelp("A", "bulk actions"),
+		),
+		BodySearch: key.NewBinding(
+			key.WithKeys("F"),
+			key.WithHelp("F", "search bodies"),
+		),
+		GoTo: key.NewBinding(
+			key.WithKeys(":"),
+			key.WithHelp(":", "go to request"),
+		),
+		NextMatch: key.NewBinding(
+			key.WithKeys("n"),
+			key.WithHelp("n", "next match"),
+		),
+		PrevMatch: key.NewBinding(
+			key.WithKeys("N"),
+			key.WithHelp("N", "previous match"),
+		),
+		Browser: key.NewBinding(
+			key.WithKeys("U"),
+			key.WithHelp("U", "open URL in browser"),
+		),
+		SaveBody: key.NewBinding(
+			key.WithKeys("W"),
+			key.WithHelp("W", "save response body"),
+		),
+		Times: key.NewBinding(
+			key.WithKeys("z"),
+			key.WithHelp("z", "relative or clock times"),
+		),
+		Scope: key.NewBinding(
+			key.WithKeys("V"),
+			key.WithHelp("V", "metrics over the filter"),
+		),
+		Hide: key.NewBinding(
+			key.WithKeys("-"),
+			key.WithHelp("-", "hide request"),
+		),
+		HideDomain: key.NewBinding(
+			key.WithKeys("_"),
+			key.WithHelp("_", "hide domain"),
+		),
+		UnhideAll: key.NewBinding(
+			key.WithKeys("+"),
+			key.WithHelp("+", "unhide all"),
+		),
+		Clusters: key.NewBinding(
+			key.WithKeys("K"),
+			key.WithHelp("K", "error clusters"),
 		),
 	}
 }
 
-func NewModel(harFiles []*har.HAR) Model {
+// Options carries per-session settings, typically from a workspace.
+type Options struct {
+	FileNames     []string
+	WorkspaceName string
+	SavedFilters  map[string]string
+	Budgets       map[string]float64
+	VendorBudgets []har.VendorBudget
+	SLOs          []har.SLO
+	SegmentGap    time.Duration
+	// Settings analyze, compare and display the files, e.g. with the
+	// session's noise thresholds and size units.
+	Settings har.Settings
+
+	// OnExclude persists an exclusion made in the TUI, e.g. to the workspace.
+	OnExclude func(pattern string) error
+	// OpenFile loads a file opened from the file picker, e.g. applying the
+	// session's exclusions and tag rules; it defaults to parsing the file.
+	OpenFile func(path string) (*har.HAR, error)
+
+	// Keys overrides DefaultKeyMap, e.g. with the config file's bindings.
+	Keys *KeyMap
+	// Theme names one of Themes; empty for the default.
+	Theme string
+	// Plain shows ASCII markers instead of emoji, see report.Plain.
+	Plain bool
+	// NoColor drops every color, keeping only bold and reverse video so the
+	// selection stays visible, e.g. for NO_COLOR.
+	NoColor bool
+	// UTC shows clock times in UTC instead of local time.
+	UTC bool
+}
+
+func NewModel(harFiles []*har.HAR, options Options) Model {
 	analyzers := make([]*har.Analyzer, len(harFiles))
 	for i, harFile := range harFiles {
-		analyzers[i] = har.NewAnalyzer(harFile)
+		analyzers[i] = har.NewAnalyzer(harFile, options.Settings)
 	}
 
 	var entries []har.Entry
+	var entryIndices []int
 	var metrics *har.Metrics
 	var timeline []har.TimelineEvent
-	var comparison *har.Comparison
 
 	if len(harFiles) > 0 {
 		entries = harFiles[0].Log.Entries
+		entryIndices = identityIndices(entries)
 		metrics = analyzers[0].CalculateMetrics()
 		timeline = analyzers[0].GenerateTimeline()
 	}
 
-	// Create comparison if multiple files
-	if len(harFiles) > 1 {
-		allMetrics := make([]*har.Metrics, len(analyzers))
-		fileNames := make([]string, len(harFiles))
-		for i, analyzer := range analyzers {
-			allMetrics[i] = analyzer.CalculateMetrics()
+	fileNames := make([]string, len(harFiles))
+	for i := range harFiles {
+		if i < len(options.FileNames) && options.FileNames[i] != "" {
+			fileNames[i] = options.FileNames[i]
+		} else {
 			fileNames[i] = fmt.Sprintf("File %d", i+1)
 		}
-		comparator := har.NewComparator(fileNames, allMetrics)
-		comparison = comparator.Compare()
 	}
 
 	// Initialize table
-	columns := []table.Column{
-		{Title: "Method", Width: 8},
-		{Title: "Status", Width: 6},
-		{Title: "URL", Width: 60},
-		{Title: "Time (ms)", Width: 10},
-		{Title: "Size", Width: 10},
-		{Title: "Type", Width: 15},
-	}
-
 	t := table.New(
-		table.WithColumns(columns),
+		table.WithColumns(baseColumns()),
 		table.WithFocused(true),
 		table.WithHeight(20),
 	)
@@ -221,20 +659,72 @@ func NewModel(harFiles []*har.HAR) Model {
 	filter.CharLimit = 256
 
 	m := Model{
-		harFiles:    harFiles,
-		analyzers:   analyzers,
-		currentFile: 0,
-		currentView: TableView,
-		table:       t,
-		filter:      filter,
-		entries:     entries,
-		metrics:     metrics,
-		timeline:    timeline,
-		comparison:  comparison,
-		keys:        DefaultKeyMap(),
+		harFiles:      harFiles,
+		analyzers:     analyzers,
+		currentFile:   0,
+		currentView:   TableView,
+		table:         t,
+		filter:        filter,
+		tagInput:      newTagInput(),
+		headerSearch:  newHeaderSearch(),
+		bodySearch:    newBodySearch(),
+		gotoInput:     newGotoInput(),
+		markedEntry:   noMark,
+		exportDialog:  NewExportDialog(),
+		fileNames:     fileNames,
+		workspaceName: options.WorkspaceName,
+		savedFilters:  options.SavedFilters,
+		budgets:       options.Budgets,
+		vendorBudgets: options.VendorBudgets,
+		slos:          options.SLOs,
+		segmentGap:    options.SegmentGap,
+		settings:      options.Settings,
+		onExclude:     options.OnExclude,
+		openFile:      options.OpenFile,
+		plain:         options.Plain,
+		utc:           options.UTC,
+		noColor:       options.NoColor,
+		entries:       entries,
+		entryIndices:  entryIndices,
+		metrics:       metrics,
+		timeline:      timeline,
+		keys:          DefaultKeyMap(),
+	}
+
+	if options.Keys != nil {
+		m.keys = *options.Keys
+	}
+	if m.openFile == nil {
+		m.openFile = parseHARFile
+	}
+	m.table.KeyMap.LineUp = m.keys.Up
+	m.table.KeyMap.LineDown = m.keys.Down
+
+	active, ok := ThemeByName(options.Theme)
+	if !ok {
+		active = Themes[0]
+	}
+	if options.NoColor {
+		active, _ = ThemeByName("monochrome")
+	}
+	applyTheme(active)
+	m.table.SetStyles(tableStyles())
+
+	// Compare the files in load order, the first being the baseline
+	for i := range harFiles {
+		m.comparisonOrder = append(m.comparisonOrder, i)
+	}
+	m.updateComparison()
+	m.updateTableRows()
+
+	// Without files there is nothing to show until one is picked; the
+	// tutorial waits for it
+	if len(harFiles) == 0 {
+		m.filePicker.open()
+	} else if !tutorialSeen() {
+		m.startTutorial()
 	}
 
-	m.updateTableRows()
 	return m
 }
 
@@ -249,19 +739,75 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
-		m.table.SetHeight(msg.Height - 10)
-
-		// Update table column widths
-		columns := m.table.Columns()
-		if len(columns) > 0 {
-			urlWidth := msg.Width - 60 // Reserve space for other columns
-			if urlWidth > 30 {
-				columns[2].Width = urlWidth
-				m.table.SetColumns(columns)
-			}
+
+		// Update table height and column widths
+		m.resizeTable()
+
+	case exportDoneMsg:
+		if msg.err != nil {
+			return m, m.showToast(msg.err.Error(), true)
 		}
+		return m, m.showToast(fmt.Sprintf("Exported %s", strings.Join(msg.files, ", ")), false)
+
+	case fileOpenedMsg:
+		return m.fileOpened(msg)
+
+	case browserOpenedMsg:
+		if msg.err != nil {
+			return m, m.showToast(msg.err.Error(), true)
+		}
+		return m, m.showToast("Opened "+truncateURL(msg.url, max(m.width-10, 40))+" in the browser", false)
+
+	case toastExpiredMsg:
+		if msg.id == m.toast.id {
+			m.toast = Toast{}
+		}
+		return m, nil
 
 	case tea.KeyMsg:
+		if m.filePicker.active {
+			return m.updateFilePicker(msg)
+		}
+		if m.exportDialog.active {
+			return m.updateExportDialog(msg)
+		}
+		if m.bundleDialog.active {
+			return m.updateBundleDialog(msg)
+		}
+		if m.bulkDialog.active {
+			return m.updateBulkDialog(msg)
+		}
+
+		if m.tutorial.active {
+			switch msg.String() {
+			case "enter", " ", "n":
+				m.nextTutorialStep()
+			case "backspace", "p":
+				m.prevTutorialStep()
+			case "esc", "f1":
+				m.stopTutorial()
+			case "ctrl+c":
+				return m, tea.Quit
+			}
+			return m, nil
+		}
+
+		if m.pendingExclusion.pattern != "" {
+			return m.updateExclusionPrompt(msg)
+		}
+		if m.showTagInput {
+			return m.updateTagInput(msg)
+		}
+		if m.headerSearching {
+			return m.updateHeaderSearch(msg)
+		}
+		if m.bodySearching {
+			return m.updateBodySearch(msg)
+		}
+		if m.showGotoInput {
+			return m.updateGotoInput(msg)
+		}
+
 		if m.showFilter {
 			switch {
 			case key.Matches(msg, m.keys.Enter):
@@ -282,11 +828,48 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case key.Matches(msg, m.keys.Quit):
 			return m, tea.Quit
 
+		case key.Matches(msg, m.keys.Tutorial):
+			m.startTutorial()
+			return m, nil
+
+		case m.currentView == DetailView && m.detailTab == HeadersTab && key.Matches(msg, m.keys.Filter):
+			return m.openHeaderSearch()
+
+		case m.currentView == BodySearchView && key.Matches(msg, m.keys.Filter):
+			return m.openBodySearch()
+
 		case key.Matches(msg, m.keys.Filter):
 			m.showFilter = true
 			m.filter.Focus()
 			return m, nil
 
+		case key.Matches(msg, m.keys.Tag):
+			if m.currentView == TableView || m.currentView == DetailView {
+				return m.openTagInput()
+			}
+			return m, nil
+
+		case key.Matches(msg, m.keys.Exclude), key.Matches(msg, m.keys.ExcludeAll):
+			if m.currentView == TableView || m.currentView == DetailView {
+				return m.excludeSelected(key.Matches(msg, m.keys.ExcludeAll))
+			}
+			return m, nil
+
+		case key.Matches(msg, m.keys.ExcludeExt):
+			return m.excludeExtensions()
+
+		case key.Matches(msg, m.keys.Hide), key.Matches(msg, m.keys.HideDomain):
+			if m.currentView == TableView || m.currentView == DetailView {
+				if key.Matches(msg, m.keys.HideDomain) {
+					return m.hideDomain()
+				}
+				return m.hideSelected()
+			}
+			return m, nil
+
+		case key.Matches(msg, m.keys.UnhideAll):
+			return m.unhideAll()
+
 		case key.Matches(msg, m.keys.Tab):
 			if len(m.harFiles) > 1 {
 				m.currentFile = (m.currentFile + 1) % len(m.harFiles)
@@ -320,9 +903,191 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 
+		case key.Matches(msg, m.keys.Order):
+			if m.currentView == TimelineView {
+				m.waterfallOrder = m.waterfallOrder.next()
+			}
+			return m, nil
+
+		case key.Matches(msg, m.keys.Concurrency):
+			if m.currentView == ConcurrencyView {
+				m.currentView = TableView
+			} else {
+				m.currentView = ConcurrencyView
+			}
+			return m, nil
+
+		case key.Matches(msg, m.keys.Bandwidth):
+			if m.currentView == ThroughputView {
+				m.currentView = TableView
+			} else {
+				m.currentView = ThroughputView
+			}
+			return m, nil
+
+		case key.Matches(msg, m.keys.Histogram):
+			if m.currentView == HistogramView {
+				m.currentView = TableView
+			} else {
+				m.currentView = HistogramView
+			}
+			return m, nil
+
+		case key.Matches(msg, m.keys.Heatmap):
+			if m.currentView == HeatmapView {
+				m.currentView = TableView
+			} else {
+				m.currentView = HeatmapView
+			}
+			return m, nil
+
+		case key.Matches(msg, m.keys.Trends):
+			if len(m.harFiles) > 1 {
+				if m.currentView == TrendView {
+					m.currentView = TableView
+				} else {
+					m.currentView = TrendView
+				}
+			}
+			return m, nil
+
+		case key.Matches(msg, m.keys.WhatIf):
+			if m.currentView == WhatIfView {
+				m.currentView = TableView
+			} else {
+				m.currentView = WhatIfView
+			}
+			return m, nil
+
+		case key.Matches(msg, m.keys.Initiators):
+			if m.currentView == InitiatorView {
+				m.currentView = TableView
+			} else {
+				m.currentView = InitiatorView
+			}
+			return m, nil
+
+		case key.Matches(msg, m.keys.Browser):
+			if m.currentView == TableView || m.currentView == DetailView {
+				return m.openInBrowser()
+			}
+			return m, nil
+
+		case key.Matches(msg, m.keys.GoTo):
+			if m.currentView == TableView || m.currentView == DetailView {
+				return m.openGotoInput()
+			}
+			return m, nil
+
+		case key.Matches(msg, m.keys.NextMatch), key.Matches(msg, m.keys.PrevMatch):
+			if m.currentView == TableView || m.currentView == DetailView || m.currentView == BodySearchView {
+				return m, m.cycleMatch(key.Matches(msg, m.keys.PrevMatch))
+			}
+			return m, nil
+
+		case key.Matches(msg, m.keys.BodySearch):
+			if m.currentView == BodySearchView {
+				m.currentView = TableView
+				return m, nil
+			}
+			return m.openBodySearch()
+
+		case key.Matches(msg, m.keys.Endpoints):
+			if m.currentView == EndpointsView {
+				m.currentView = TableView
+			} else {
+				m.currentView = EndpointsView
+				m.endpointOffset = 0
+			}
+			return m, nil
+
+		case key.Matches(msg, m.keys.Clusters):
+			if m.currentView == ErrorClusterView {
+				m.currentView = TableView
+			} else {
+				m.currentView = ErrorClusterView
+				m.errorClusterSelected = 0
+			}
+			return m, nil
+
+		case key.Matches(msg, m.keys.Statuses):
+			if m.currentView == StatusView {
+				m.currentView = TableView
+			} else {
+				m.currentView = StatusView
+				m.statusSelected = 0
+				m.moveStatusSelection(1)
+			}
+			return m, nil
+
+		case key.Matches(msg, m.keys.Slowest):
+			m.toggleQuickView(SlowestRequests)
+			return m, nil
+
+		case key.Matches(msg, m.keys.Largest):
+			m.toggleQuickView(LargestRequests)
+			return m, nil
+
+		case key.Matches(msg, m.keys.Errors):
+			m.toggleQuickView(ErrorRequests)
+			return m, nil
+
+		case key.Matches(msg, m.keys.Security):
+			if m.currentView == SecurityView {
+				m.currentView = TableView
+			} else {
+				m.currentView = SecurityView
+			}
+			return m, nil
+
+		case key.Matches(msg, m.keys.Theme):
+			if m.noColor {
+				return m, m.showToast("Colors are turned off", false)
+			}
+			applyTheme(nextTheme())
+			m.table.SetStyles(tableStyles())
+			return m, m.showToast("Theme: "+theme.Name, false)
+
 		case key.Matches(msg, m.keys.Export):
-			// Export reports
-			go m.exportReports()
+			m.exportDialog.open()
+			return m, textinput.Blink
+
+		case key.Matches(msg, m.keys.Bundle):
+			return m.openBundleDialog()
+
+		case key.Matches(msg, m.keys.Open):
+			return m.openFilePicker()
+
+		case m.currentView == TableView && key.Matches(msg, m.keys.Select):
+			m.toggleSelection()
+			return m, nil
+
+		case m.currentView == TableView && key.Matches(msg, m.keys.Bulk):
+			return m.openBulkDialog()
+
+		case key.Matches(msg, m.keys.Protocol):
+			if m.currentView == TableView {
+				m.toggleColumns(&m.showProtocol)
+			}
+			return m, nil
+
+		case key.Matches(msg, m.keys.Times):
+			m.cycleTimeDisplay()
+			return m, m.showToast("Start times: "+m.timeDisplay.String(), false)
+
+		case key.Matches(msg, m.keys.Scope):
+			return m, m.toggleFilterScope()
+
+		case key.Matches(msg, m.keys.TimingCols):
+			if m.currentView == TableView {
+				m.toggleColumns(&m.showTiming)
+			}
+			return m, nil
+
+		case key.Matches(msg, m.keys.Split):
+			if m.currentView == TableView {
+				m.toggleSplitPane()
+			}
 			return m, nil
 
 		case key.Matches(msg, m.keys.Help):
@@ -333,22 +1098,183 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 
+		case key.Matches(msg, m.keys.Timing):
+			if m.currentView == DetailView {
+				m.currentView = TimingView
+			} else if m.currentView == TimingView {
+				m.currentView = DetailView
+			}
+			return m, nil
+
 		case key.Matches(msg, m.keys.Enter):
 			if m.currentView == TableView {
 				m.selectedEntry = m.table.Cursor()
+				m.detailReturn = TableView
+				m.detailSelected = 0
 				m.currentView = DetailView
+			} else if m.currentView == TimelineView {
+				m.inspectTimelineSelection()
+			} else if m.currentView == StatusView {
+				m.filterByStatusSelection()
+			} else if m.currentView == ComparisonView {
+				m.diffComparisonSelection()
+			} else if m.currentView == WhatIfView {
+				m.toggleWhatIfSelection()
+			} else if m.currentView == InitiatorView {
+				m.inspectInitiatorSelection()
+			} else if m.currentView == BodySearchView {
+				m.inspectBodyMatchSelection()
+			} else if m.currentView == ErrorClusterView {
+				m.inspectErrorClusterSelection()
 			}
 			return m, nil
 
+		case m.currentView == TimelineView && key.Matches(msg, m.keys.PrevTab):
+			return m, m.setWindowBound(false)
+
+		case m.currentView == TimelineView && key.Matches(msg, m.keys.NextTab):
+			return m, m.setWindowBound(true)
+
+		case m.currentView == TimelineView && key.Matches(msg, m.keys.Up):
+			m.moveTimelineSelection(-1)
+			return m, nil
+
+		case m.currentView == TimelineView && key.Matches(msg, m.keys.Down):
+			m.moveTimelineSelection(1)
+			return m, nil
+
+		case m.currentView == StatusView && key.Matches(msg, m.keys.Up):
+			m.moveStatusSelection(-1)
+			return m, nil
+
+		case m.currentView == StatusView && key.Matches(msg, m.keys.Down):
+			m.moveStatusSelection(1)
+			return m, nil
+
+		case (m.currentView == DetailView || m.currentView == TableView && m.splitPane) && key.Matches(msg, m.keys.PrevTab):
+			m.switchDetailTab(-1)
+			return m, nil
+
+		case (m.currentView == DetailView || m.currentView == TableView && m.splitPane) && key.Matches(msg, m.keys.NextTab):
+			m.switchDetailTab(1)
+			return m, nil
+
+		case m.currentView == DetailView && key.Matches(msg, m.keys.Up):
+			m.moveDetailSelection(-1)
+			return m, nil
+
+		case m.currentView == DetailView && key.Matches(msg, m.keys.Down):
+			m.moveDetailSelection(1)
+			return m, nil
+
+		case m.currentView == DetailView && key.Matches(msg, m.keys.Copy):
+			return m, m.copyDetailSelection()
+
+		case m.currentView == DetailView && key.Matches(msg, m.keys.SaveBody):
+			return m, m.saveResponseBody()
+
+		case m.currentView == DetailView && m.detailTab == BodyTab && key.Matches(msg, m.keys.RawBody):
+			m.bodyRaw = !m.bodyRaw
+			return m, nil
+
+		case (m.currentView == TableView || m.currentView == DetailView) && key.Matches(msg, m.keys.Mark):
+			return m, m.markForDiff()
+
+		case m.currentView == DiffView && key.Matches(msg, m.keys.DiffAll):
+			m.diffShowAll = !m.diffShowAll
+			m.diffOffset = 0
+			return m, nil
+
+		case m.currentView == DiffView && key.Matches(msg, m.keys.Up):
+			m.scrollDiff(-1)
+			return m, nil
+
+		case m.currentView == DiffView && key.Matches(msg, m.keys.Down):
+			m.scrollDiff(1)
+			return m, nil
+
+		case m.currentView == ComparisonView && key.Matches(msg, m.keys.Up):
+			m.moveComparisonSelection(-1)
+			return m, nil
+
+		case m.currentView == ComparisonView && key.Matches(msg, m.keys.Down):
+			m.moveComparisonSelection(1)
+			return m, nil
+
+		case m.currentView == ComparisonView && key.Matches(msg, m.keys.Baseline):
+			return m, m.makeBaseline()
+
+		case m.currentView == ComparisonView && key.Matches(msg, m.keys.MoveEarlier):
+			return m, m.moveInComparison(-1)
+
+		case m.currentView == ComparisonView && key.Matches(msg, m.keys.MoveLater):
+			return m, m.moveInComparison(1)
+
+		case m.currentView == ComparisonView && key.Matches(msg, m.keys.Include):
+			return m, m.toggleInComparison()
+
+		case m.currentView == WhatIfView && key.Matches(msg, m.keys.Up):
+			m.moveWhatIfSelection(-1)
+			return m, nil
+
+		case m.currentView == WhatIfView && key.Matches(msg, m.keys.Down):
+			m.moveWhatIfSelection(1)
+			return m, nil
+
+		case m.currentView == InitiatorView && key.Matches(msg, m.keys.Up):
+			m.moveInitiatorSelection(-1)
+			return m, nil
+
+		case m.currentView == InitiatorView && key.Matches(msg, m.keys.Down):
+			m.moveInitiatorSelection(1)
+			return m, nil
+
+		case m.currentView == BodySearchView && key.Matches(msg, m.keys.Up):
+			m.moveBodyMatchSelection(-1)
+			return m, nil
+
+		case m.currentView == BodySearchView && key.Matches(msg, m.keys.Down):
+			m.moveBodyMatchSelection(1)
+			return m, nil
+
+		case m.currentView == ErrorClusterView && key.Matches(msg, m.keys.Up):
+			m.moveErrorClusterSelection(-1)
+			return m, nil
+
+		case m.currentView == ErrorClusterView && key.Matches(msg, m.keys.Down):
+			m.moveErrorClusterSelection(1)
+			return m, nil
+
+		case m.currentView == EndpointsView && key.Matches(msg, m.keys.Up):
+			m.scrollEndpoints(-1)
+			return m, nil
+
+		case m.currentView == EndpointsView && key.Matches(msg, m.keys.Down):
+			m.scrollEndpoints(1)
+			return m, nil
+
 		case key.Matches(msg, m.keys.Back):
-			if m.currentView != TableView {
+			if m.currentView == TimingView {
+				m.currentView = DetailView
+			} else if m.currentView == DetailView {
+				m.currentView = m.detailReturn
+			} else if m.currentView == DiffView {
+				m.currentView = m.diffReturn
+			} else if m.currentView != TableView {
 				m.currentView = TableView
+			} else if m.quickView != AllRequests {
+				m.toggleQuickView(m.quickView)
+			} else if len(m.selectedEntries) > 0 {
+				m.selectedEntries = nil
+				m.updateTableRows()
+			} else if m.window.active() {
+				m.clearWindow()
 			}
 			return m, nil
 		}
 	}
 
-	if m.currentView == TableView && !m.showFilter {
+	if m.currentView == TableView && !m.showFilter && !m.showTagInput && !m.showGotoInput {
 		m.table, cmd = m.table.Update(msg)
 	}
 
@@ -359,7 +1285,41 @@ func (m Model) View() string {
 	if m.showFilter {
 		return m.RenderFilter()
 	}
+	if m.showTagInput {
+		return m.renderTagInput()
+	}
 
+	var view string
+	if m.filePicker.active {
+		view = m.renderFilePicker()
+	} else if m.exportDialog.active {
+		view = m.renderExportDialog()
+	} else if m.bundleDialog.active {
+		view = m.renderBundleDialog()
+	} else if m.bulkDialog.active {
+		view = m.renderBulkDialog()
+	} else {
+		view = m.renderCurrentView()
+	}
+	if m.showGotoInput {
+		view += "\n" + m.renderGotoInput()
+	}
+	if m.pendingExclusion.pattern != "" {
+		view += "\n" + m.renderExclusionPrompt()
+	}
+	if m.tutorial.active {
+		view += "\n" + m.renderTutorialOverlay()
+	}
+	if toast := m.renderToast(); toast != "" {
+		view += "\n" + toast
+	}
+	if m.plain {
+		view = report.Plain(view)
+	}
+	return view
+}
+
+func (m Model) renderCurrentView() string {
 	switch m.currentView {
 	case DetailView:
 		return m.renderDetailView()
@@ -371,6 +1331,34 @@ func (m Model) View() string {
 		return m.renderComparisonView()
 	case HelpView:
 		return m.renderHelpView()
+	case TimingView:
+		return m.renderTimingView()
+	case SecurityView:
+		return m.renderSecurityView()
+	case ConcurrencyView:
+		return m.renderConcurrencyView()
+	case ThroughputView:
+		return m.renderThroughputView()
+	case HistogramView:
+		return m.renderHistogramView()
+	case HeatmapView:
+		return m.renderHeatmapView()
+	case StatusView:
+		return m.renderStatusView()
+	case EndpointsView:
+		return m.renderEndpointsView()
+	case ErrorClusterView:
+		return m.renderErrorClustersView()
+	case DiffView:
+		return m.renderDiffView()
+	case TrendView:
+		return m.renderTrendView()
+	case WhatIfView:
+		return m.renderWhatIfView()
+	case InitiatorView:
+		return m.renderInitiatorView()
+	case BodySearchView:
+		return m.renderBodySearchView()
 	default:
 		return m.RenderTableView()
 	}
@@ -381,100 +1369,141 @@ func (m Model) renderDetailView() string {
 		return "No entry selected"
 	}
 
+	details := append(m.detailLines(), "", statusStyle.Render("Press Esc to go back"))
+	return strings.Join(details, "\n")
+}
+
+// detailLines are the selected entry's details in the current tab, which
+// the detail view and the split pane show.
+func (m Model) detailLines() []string {
 	entry := m.entries[m.selectedEntry]
 
 	var details []string
 
 	// Header
 	details = append(details, titleStyle.Render("Request Details"))
+	details = append(details, m.renderDetailTabs())
 	details = append(details, "")
+	switch m.detailTab {
+	case QueryTab:
+		details = append(details, m.renderQueryTab(entry)...)
+	case BodyTab:
+		details = append(details, m.renderBodyTab(entry)...)
+	case HeadersTab:
+		details = append(details, m.renderHeadersTab(entry)...)
+	}
+	if m.detailTab != OverviewTab {
+		return details
+	}
 
 	// Request info
 	details = append(details, headerStyle.Render("Request"))
 	details = append(details, fmt.Sprintf("Method: %s", entry.Request.Method))
 	details = append(details, fmt.Sprintf("URL: %s", entry.Request.URL))
 	details = append(details, fmt.Sprintf("HTTP Version: %s", entry.Request.HTTPVersion))
+	details = append(details, fmt.Sprintf("Tags: %s", formatTags(entry.Tags)))
+	if m.analyzers[m.currentFile].IsThirdParty(entry) {
+		party := "Third party"
+		if category, ok := har.DomainCategory(har.EntryHost(entry)); ok {
+			party += " (" + category + ")"
+		}
+		details = append(details, "Party: "+party)
+	} else {
+		details = append(details, "Party: First party")
+	}
+	if initiator := entry.InitiatorURL(); initiator != "" {
+		details = append(details, "Initiator: "+initiator)
+	}
+	if m.analyzers[m.currentFile].CriticalPath().Contains(m.entryIndices[m.selectedEntry]) {
+		details = append(details, "» On the critical path to page load")
+	}
+	for _, reason := range m.analyzers[m.currentFile].InsecureReasons(entry) {
+		details = append(details, "⚠️  Insecure: "+reason)
+	}
 	details = append(details, "")
+	details = append(details, m.renderRedirectChain(m.entryIndices[m.selectedEntry])...)
 
 	// Response info
 	details = append(details, headerStyle.Render("Response"))
 	details = append(details, fmt.Sprintf("Status: %d %s", entry.Response.Status, entry.Response.StatusText))
 	details = append(details, fmt.Sprintf("Content Type: %s", entry.Response.Content.MimeType))
-	details = append(details, fmt.Sprintf("Content Size: %s", formatSize(entry.Response.Content.Size)))
+	details = append(details, fmt.Sprintf("Content Size: %s", m.settings.SizeUnits.FormatSize(entry.Response.Content.Size)))
 	if entry.Response.Content.Compression > 0 {
-		details = append(details, fmt.Sprintf("Compression: %s saved", formatSize(entry.Response.Content.Compression)))
+		details = append(details, fmt.Sprintf("Compression: %s saved", m.settings.SizeUnits.FormatSize(entry.Response.Content.Compression)))
 	}
 	details = append(details, "")
 
 	// Timing breakdown
+	phases := timingPhases(entry, har.Timings{})
 	details = append(details, headerStyle.Render("Timing Breakdown"))
+	details = append(details, "Started: "+m.timeFormat().describe(entry.StartedDateTime))
 	details = append(details, fmt.Sprintf("Total Time: %.1fms", entry.Time))
-	if entry.Timings.Blocked > 0 {
-		details = append(details, fmt.Sprintf("Blocked: %dms", entry.Timings.Blocked))
-	}
-	if entry.Timings.DNS > 0 {
-		details = append(details, fmt.Sprintf("DNS Lookup: %dms", entry.Timings.DNS))
-	}
-	if entry.Timings.Connect > 0 {
-		details = append(details, fmt.Sprintf("TCP Connect: %dms", entry.Timings.Connect))
-	}
-	if entry.Timings.SSL > 0 {
-		details = append(details, fmt.Sprintf("SSL Handshake: %dms", entry.Timings.SSL))
-	}
-	details = append(details, fmt.Sprintf("Send: %dms", entry.Timings.Send))
-	details = append(details, fmt.Sprintf("Wait (TTFB): %dms", entry.Timings.Wait))
-	details = append(details, fmt.Sprintf("Receive: %dms", entry.Timings.Receive))
+	details = append(details, renderStackedTimingBar(phases, max(m.width-20, 40)))
+	details = append(details, renderTimingLegend(phases))
+	details = append(details, statusStyle.Render("Press p to compare phases against the host median"))
 	details = append(details, "")
 
-	// Request headers (top 5)
-	if len(entry.Request.Headers) > 0 {
-		details = append(details, headerStyle.Render("Request Headers (Top 5)"))
-		count := 0
-		for _, header := range entry.Request.Headers {
-			if count >= 5 {
-				break
-			}
-			details = append(details, fmt.Sprintf("%s: %s", header.Name, truncateValue(header.Value, 60)))
-			count++
-		}
-		if len(entry.Request.Headers) > 5 {
-			details = append(details, fmt.Sprintf("... and %d more headers", len(entry.Request.Headers)-5))
-		}
+	// Cookies
+	if cookies := m.analyzers[m.currentFile].EntryCookies(entry); len(cookies) > 0 {
+		details = append(details, headerStyle.Render("Cookies"))
+		details = append(details, renderCookies(cookies, m.settings.SizeUnits)...)
 		details = append(details, "")
 	}
 
-	// Response headers (top 5)
-	if len(entry.Response.Headers) > 0 {
-		details = append(details, headerStyle.Render("Response Headers (Top 5)"))
-		count := 0
-		for _, header := range entry.Response.Headers {
-			if count >= 5 {
-				break
-			}
-			details = append(details, fmt.Sprintf("%s: %s", header.Name, truncateValue(header.Value, 60)))
-			count++
-		}
-		if len(entry.Response.Headers) > 5 {
-			details = append(details, fmt.Sprintf("... and %d more headers", len(entry.Response.Headers)-5))
-		}
-		details = append(details, "")
+	// Header overhead
+	headerStats := har.HeaderStats(entry, m.settings.SizeUnits)
+	details = append(details, fmt.Sprintf("Header Size: %s request, %s response", m.settings.SizeUnits.FormatSize(headerStats.RequestBytes), m.settings.SizeUnits.FormatSize(headerStats.ResponseBytes)))
+	for _, issue := range headerStats.Issues {
+		details = append(details, "⚠️  Headers: "+issue)
 	}
+	details = append(details, "")
+
+	// Headers are listed in full in the headers tab
+	details = append(details, fmt.Sprintf("Headers: %d request, %d response (press [ to browse them in the Headers tab)",
+		len(entry.Request.Headers), len(entry.Response.Headers)))
 
-	// Footer
-	details = append(details, statusStyle.Render("Press Esc to go back"))
+	return details
+}
 
-	return fmt.Sprintf("%s", details[0]) + "\n" + fmt.Sprintf("%s", details[1:])
+// vitalStatus marks a Core Web Vital with its rating, like TTFB and page load
+// time in the metrics view.
+func vitalStatus(rating har.VitalRating) string {
+	switch rating {
+	case har.VitalGood:
+		return " ✅ (Good)"
+	case har.VitalNeedsImprovement:
+		return " ⚡ (Needs Improvement)"
+	}
+	return " ⚠️  (Poor)"
 }
 
 func (m Model) renderMetricsView() string {
 	if m.metrics == nil {
 		return "No metrics available"
 	}
+	if !m.metrics.HasData() {
+		return strings.Join([]string{
+			titleStyle.Render("Performance Metrics"),
+			"",
+			"N/A - this capture contains no entries, so no metrics can be computed.",
+			"",
+			statusStyle.Render("Press Esc to go back"),
+		}, "\n")
+	}
 
 	var content []string
 
 	// Header
 	content = append(content, titleStyle.Render("Performance Metrics"))
+	if title := m.windowTitle(); title != "" {
+		content = append(content, headerStyle.Render(title))
+	}
+	if title := m.scopeTitle(); title != "" {
+		content = append(content, headerStyle.Render(title))
+	}
+	content = append(content, environmentLine(m.metrics.Environment))
+	content = append(content, "")
+	content = append(content, m.renderExecutiveSummary()...)
 	content = append(content, "")
 
 	// Core Web Vitals section
@@ -500,6 +1529,24 @@ func (m Model) renderMetricsView() string {
 	content = append(content, fmt.Sprintf("Page Load Time: %.1fms%s", m.metrics.PageLoadTime, loadStatus))
 	content = append(content, "")
 
+	// Lab and visual metrics are only present in WebPageTest imports or with a Lighthouse report
+	var labLines []string
+	for _, descriptor := range har.MetricDescriptors() {
+		if descriptor.Lab && descriptor.Measured(m.metrics) {
+			value := descriptor.Value(m.metrics)
+			line := fmt.Sprintf("%s: %s", descriptor.Name, descriptor.Format(value, m.settings.SizeUnits))
+			if rating, ok := har.RateVital(descriptor.ID, value); ok {
+				line += vitalStatus(rating)
+			}
+			labLines = append(labLines, line)
+		}
+	}
+	if len(labLines) > 0 {
+		content = append(content, headerStyle.Render("Lab & Visual Metrics"))
+		content = append(content, labLines...)
+		content = append(content, "")
+	}
+
 	// Network metrics
 	content = append(content, headerStyle.Render("Network Performance"))
 	content = append(content, fmt.Sprintf("Average DNS Time: %.1fms", m.metrics.DNSTime))
@@ -511,7 +1558,11 @@ func (m Model) renderMetricsView() string {
 
 	// Request statistics
 	content = append(content, headerStyle.Render("Request Statistics"))
-	content = append(content, fmt.Sprintf("Total Requests: %d", m.metrics.TotalRequests))
+	requestInfo := fmt.Sprintf("Total Requests: %d", m.metrics.TotalRequests)
+	if excluded := m.excludedCount(); excluded > 0 {
+		requestInfo += fmt.Sprintf(" (%d excluded)", excluded)
+	}
+	content = append(content, requestInfo)
 	errorInfo := fmt.Sprintf("Error Requests: %d", m.metrics.ErrorRequests)
 	if m.metrics.ErrorRequests > 0 {
 		errorRate := float64(m.metrics.ErrorRequests) / float64(m.metrics.TotalRequests) * 100
@@ -527,7 +1578,20 @@ func (m Model) renderMetricsView() string {
 		thirdPartyRate := float64(m.metrics.ThirdPartyRequests) / float64(m.metrics.TotalRequests) * 100
 		thirdPartyInfo += fmt.Sprintf(" (%.1f%%)", thirdPartyRate)
 	}
+	if firstParty := m.scopedAnalyzer().FirstPartyDomain(); firstParty != "" {
+		thirdPartyInfo += fmt.Sprintf(" — first party: %s", firstParty)
+	}
 	content = append(content, thirdPartyInfo)
+	for _, stats := range m.scopedAnalyzer().GetCategoryStats() {
+		content = append(content, fmt.Sprintf("  %-14s %4d requests  %10s  %10.1fms", stats.Category, stats.Requests, m.settings.SizeUnits.FormatSize(int(stats.Size)), stats.Time))
+	}
+	insecureInfo := fmt.Sprintf("Insecure Requests: %d", m.metrics.InsecureRequests)
+	if !m.metrics.Environment.ChecksTransport() {
+		insecureInfo += " (not checked on local captures)"
+	} else if m.metrics.InsecureRequests > 0 {
+		insecureInfo += " ⚠️  (filter with tag:insecure)"
+	}
+	content = append(content, insecureInfo)
 	content = append(content, "")
 
 	// Cache efficiency
@@ -541,17 +1605,96 @@ func (m Model) renderMetricsView() string {
 		cacheInfo += " ✅ (Good)"
 	}
 	content = append(content, cacheInfo)
+	content = append(content, m.renderCacheAnalysis()...)
+	content = append(content, m.renderCDNCache()...)
+	content = append(content, "")
+
+	if protocolLines := m.renderProtocols(); len(protocolLines) > 0 {
+		content = append(content, protocolLines...)
+		content = append(content, "")
+	}
+
+	if connectionLines := m.renderConnections(); len(connectionLines) > 0 {
+		content = append(content, connectionLines...)
+		content = append(content, "")
+	}
+
+	if redirectLines := m.renderRedirects(); len(redirectLines) > 0 {
+		content = append(content, redirectLines...)
+		content = append(content, "")
+	}
+
+	if refetchLines := m.renderAPIRefetches(); len(refetchLines) > 0 {
+		content = append(content, refetchLines...)
+		content = append(content, "")
+	}
+
+	// Preloads and prefetches that were never used
+	if preloadLines := m.renderPreloadWaste(); len(preloadLines) > 0 {
+		content = append(content, preloadLines...)
+		content = append(content, "")
+	}
+
+	// Content-encoding of text responses
+	if encodingLines := m.renderEncodingDistribution(); len(encodingLines) > 0 {
+		content = append(content, encodingLines...)
+		content = append(content, "")
+	}
+
+	// Simulated warm-cache repeat visit
+	content = append(content, m.renderRepeatView()...)
 	content = append(content, "")
 
 	// Size analysis
 	content = append(content, headerStyle.Render("Size Analysis"))
-	content = append(content, fmt.Sprintf("Total Transfer Size: %s", formatSize(int(m.metrics.TotalSize))))
+	content = append(content, fmt.Sprintf("Total Transfer Size: %s", m.settings.SizeUnits.FormatSize(int(m.metrics.TotalSize))))
 	if m.metrics.TotalRequests > 0 {
 		avgSize := m.metrics.TotalSize / int64(m.metrics.TotalRequests)
-		content = append(content, fmt.Sprintf("Average Request Size: %s", formatSize(int(avgSize))))
+		content = append(content, fmt.Sprintf("Average Request Size: %s", m.settings.SizeUnits.FormatSize(int(avgSize))))
 	}
 	content = append(content, "")
 
+	if resourceLines := m.renderResourceTypes(); len(resourceLines) > 0 {
+		content = append(content, resourceLines...)
+		content = append(content, "")
+	}
+
+	// Header sizes and duplicates
+	if headerLines := m.renderHeaderOverhead(); len(headerLines) > 0 {
+		content = append(content, headerLines...)
+		content = append(content, "")
+	}
+
+	// Cookie overhead and issues
+	if cookieLines := m.renderCookieSummary(); len(cookieLines) > 0 {
+		content = append(content, cookieLines...)
+		content = append(content, "")
+	}
+
+	// JavaScript payload
+	if jsLines := m.renderJSAudit(); len(jsLines) > 0 {
+		content = append(content, jsLines...)
+		content = append(content, "")
+	}
+
+	// Idle-gap segments
+	if segmentLines := m.renderSegments(); len(segmentLines) > 0 {
+		content = append(content, segmentLines...)
+		content = append(content, "")
+	}
+
+	// Tag groups
+	if tagLines := m.renderTagSummary(); len(tagLines) > 0 {
+		content = append(content, tagLines...)
+		content = append(content, "")
+	}
+
+	// Workspace budgets
+	if len(m.budgets) > 0 || len(m.vendorBudgets) > 0 || len(m.slos) > 0 {
+		content = append(content, m.renderBudgets()...)
+		content = append(content, "")
+	}
+
 	// Performance recommendations
 	content = append(content, headerStyle.Render("Recommendations"))
 
@@ -561,20 +1704,101 @@ func (m Model) renderMetricsView() string {
 	if m.metrics.ErrorRequests > 0 {
 		content = append(content, "• Fix HTTP errors to improve reliability")
 	}
-	if m.metrics.CacheHitRatio < 50 {
+	delivery := m.metrics.Environment.ChecksDelivery()
+	if delivery && m.metrics.CacheHitRatio < 50 {
 		content = append(content, "• Improve caching strategy for better performance")
 	}
 	if m.metrics.ThirdPartyRequests > m.metrics.TotalRequests/2 {
 		content = append(content, "• Consider reducing third-party dependencies")
 	}
-	if m.metrics.TotalSize > 1024*1024*5 { // 5MB
+	if delivery && m.metrics.TotalSize > 1024*1024*5 { // 5MB
 		content = append(content, "• Optimize resource sizes and compression")
 	}
+	if m.scopedAnalyzer().HeaderAnalysis().ExceedsCongestionWindow {
+		content = append(content, "• Trim headers and cookies that exceed the initial congestion window")
+	}
+	if delivery && m.metrics.ConnectionSetupWaste > 0 {
+		content = append(content, fmt.Sprintf("• Enable keep-alive or HTTP/2 to avoid %.1fms of repeated connection setup", m.metrics.ConnectionSetupWaste))
+	}
+	if queued := m.queuedHTTP1Origins(); delivery && queued > 0 {
+		content = append(content, fmt.Sprintf("• Serve %d busy origin(s) over HTTP/2 or HTTP/3 so requests stop queuing for connections", queued))
+	}
+	if !delivery {
+		content = append(content, fmt.Sprintf("• Caching, compression and connection advice skipped for a %s capture", m.metrics.Environment))
+	}
+	if m.metrics.RedirectLatency > 0 {
+		content = append(content, "• Link directly to final URLs to avoid redirect round trips")
+	}
+	for _, hint := range m.scopedAnalyzer().ResourceHints() {
+		content = append(content, fmt.Sprintf("• Add %s to save ~%.0fms of %s", hint.Tag(), hint.Saved, hint.Avoids()))
+	}
 
 	content = append(content, "")
 	content = append(content, statusStyle.Render("Press Esc to go back"))
 
-	return fmt.Sprintf("%s", content[0]) + "\n" + fmt.Sprintf("%s", content[1:])
+	return strings.Join(content, "\n")
+}
+
+// environmentLine names the capture's environment and the checks it skips.
+func environmentLine(env har.Environment) string {
+	switch {
+	case !env.ChecksTransport():
+		return statusStyle.Render(fmt.Sprintf("Environment: %s (delivery and TLS checks skipped)", env))
+	case !env.ChecksDelivery():
+		return statusStyle.Render(fmt.Sprintf("Environment: %s (delivery checks skipped)", env))
+	}
+	return statusStyle.Render(fmt.Sprintf("Environment: %s", env))
+}
+
+func (m Model) renderBudgets() []string {
+	title := "Budgets"
+	if m.workspaceName != "" {
+		title = fmt.Sprintf("Budgets (workspace %s)", m.workspaceName)
+	}
+	lines := []string{headerStyle.Render(title)}
+
+	passStyle := foreground("10")
+	failStyle := foreground("9")
+
+	for _, descriptor := range har.MetricDescriptors() {
+		limit, ok := m.budgets[descriptor.ID]
+		if !ok {
+			continue
+		}
+		value := descriptor.Value(m.metrics)
+		status := passStyle.Render("✅ pass")
+		if !descriptor.WithinBudget(value, limit) {
+			status = failStyle.Render("❌ over budget")
+		}
+		lines = append(lines, fmt.Sprintf("%-22s %12s / %-12s %s", descriptor.Name, descriptor.Format(value, m.settings.SizeUnits), descriptor.Format(limit, m.settings.SizeUnits), status))
+	}
+
+	for id := range m.budgets {
+		if _, ok := har.LookupMetric(id); !ok {
+			lines = append(lines, statusStyle.Render(fmt.Sprintf("Unknown budget metric %q", id)))
+		}
+	}
+
+	for _, scorecard := range m.scopedAnalyzer().VendorScorecards(m.vendorBudgets) {
+		status := passStyle.Render("✅ pass")
+		if !scorecard.Pass() {
+			status = failStyle.Render("❌ over budget")
+		}
+		lines = append(lines, fmt.Sprintf("%s %12s / %-12s %s", fitWidth(scorecard.Budget.Vendor, 22), scorecard.Usage(m.settings.SizeUnits), scorecard.Budget.Limits(m.settings.SizeUnits), status))
+	}
+
+	for _, slo := range m.scopedAnalyzer().EvaluateSLOs(m.slos) {
+		for _, objective := range slo.Objectives {
+			status := passStyle.Render("✅ met")
+			if !objective.Pass {
+				status = failStyle.Render("❌ missed")
+			}
+			lines = append(lines, fmt.Sprintf("%s %12s / %-12s %s", fitWidth(slo.SLO.Name+" "+objective.Name, 22), objective.Actual, objective.Target, status))
+		}
+		lines = append(lines, statusStyle.Render(fmt.Sprintf("%-22s %d request(s), Apdex %.2f (T=%.0fms)", "", slo.Requests, slo.Apdex, slo.SLO.ApdexThreshold())))
+	}
+
+	return lines
 }
 
 func (m Model) renderHelpView() string {
@@ -583,32 +1807,75 @@ func (m Model) renderHelpView() string {
 	help = append(help, titleStyle.Render("Hartea - Navigator's Guide"))
 	help = append(help, "")
 
+	k := m.keys
 	help = append(help, headerStyle.Render("Navigation"))
-	help = append(help, "↑/k, ↓/j     Navigate up/down in table")
-	help = append(help, "Enter        View request details")
-	help = append(help, "Esc          Go back/cancel")
-	help = append(help, "Tab          Switch between HAR files (if multiple)")
+	help = append(help, helpRow(helpKeys(", ", k.Up, k.Down), "Navigate up/down in table"))
+	help = append(help, helpRow(k.Enter.Help().Key, "View request details"))
+	help = append(help, helpRow(k.Timing.Help().Key, "Timing phases vs host median (in details)"))
+	help = append(help, helpRow(k.Tag.Help().Key, "Add or remove tags on the request"))
+	help = append(help, helpRow(helpKeys(" / ", k.Exclude, k.ExcludeAll), "Exclude every request to the URL / its domain from analysis (asks first)"))
+	help = append(help, helpRow(k.ExcludeExt.Help().Key, "Exclude all browser-extension traffic"))
+	help = append(help, helpRow(helpKeys(" / ", k.Hide, k.HideDomain, k.UnhideAll), "Hide the selected requests / the request's domain for this session / unhide all"))
+	help = append(help, helpRow(k.Protocol.Help().Key, "Show or hide the protocol column"))
+	help = append(help, helpRow(k.TimingCols.Help().Key, "Show or hide the TTFB (wait) and start offset columns"))
+	help = append(help, helpRow(k.Times.Help().Key, "Show start times from the page start, from the first request or as clock times"))
+	help = append(help, helpRow(k.Scope.Help().Key, "Compute metrics, the timeline and exports over the filtered requests or all of them"))
+	help = append(help, helpRow(k.Split.Help().Key, "Split the table with the highlighted request's details (beside or below it)"))
+	help = append(help, helpRow(helpKeys(" / ", k.Slowest, k.Largest, k.Errors), fmt.Sprintf("Show only the slowest / largest / error requests (Esc for all; errors use %s since %s excludes)", k.Errors.Help().Key, k.Exclude.Help().Key)))
+	help = append(help, helpRow(k.Back.Help().Key, "Go back/cancel"))
+	help = append(help, helpRow(k.Browser.Help().Key, "Open the request's URL in the default browser"))
+	help = append(help, helpRow(k.GoTo.Help().Key, "Jump to a request by number, e.g. :42"))
+	help = append(help, helpRow(helpKeys(" / ", k.NextMatch, k.PrevMatch), "Next / previous body search match, or filtered request"))
+	help = append(help, helpRow(k.Tab.Help().Key, "Switch between HAR files (if multiple)"))
+	help = append(help, helpRow(k.Open.Help().Key, "Open another .har or .har.gz file, or a recent one"))
+	help = append(help, helpRow(helpKeys(" / ", k.Select, k.Bulk), "Select requests / export, copy, hide or diff the selected ones"))
 	help = append(help, "")
 
 	help = append(help, headerStyle.Render("Views"))
-	help = append(help, "m            Toggle metrics view")
-	help = append(help, "t            Toggle timeline view")
+	help = append(help, helpRow(k.Metrics.Help().Key, "Toggle metrics view"))
+	help = append(help, helpRow(k.Timeline.Help().Key, "Toggle timeline view"))
+	help = append(help, helpRow(k.Order.Help().Key, "Order the timeline by start, duration, end, domain or type"))
+	help = append(help, helpRow(helpKeys(" ", k.PrevTab, k.NextTab), "In the timeline, start / end a time window at the selected bar (Esc in the table clears it)"))
+	help = append(help, helpRow(helpKeys(" ", k.Up, k.Down, k.Enter), "Select a timeline bar and open its details"))
+	help = append(help, helpRow(k.Concurrency.Help().Key, "Toggle requests-in-flight chart"))
+	help = append(help, helpRow(k.Bandwidth.Help().Key, "Toggle bandwidth-over-time chart"))
+	help = append(help, helpRow(k.Histogram.Help().Key, "Toggle response time histogram (all and filtered requests)"))
+	help = append(help, helpRow(k.Heatmap.Help().Key, "Toggle p95 latency heatmap by endpoint over time"))
+	help = append(help, helpRow(k.Statuses.Help().Key, "Toggle status codes by class, code and domain (Enter filters)"))
+	help = append(help, helpRow(helpKeys(" ", k.PrevTab, k.NextTab), "Switch detail tabs (overview, query parameters, request body, headers)"))
+	help = append(help, helpRow(k.SaveBody.Help().Key, "Save the response body (decoded) to a file, in the detail view"))
+	help = append(help, helpRow(k.Mark.Help().Key, fmt.Sprintf("Mark a request, then press %s on another to diff them (%s shows identical fields)", k.Mark.Help().Key, k.DiffAll.Help().Key)))
+	help = append(help, helpRow(k.Endpoints.Help().Key, "Toggle API endpoints grouped by templated path, with error rate and p50/p95"))
+	help = append(help, helpRow(k.Clusters.Help().Key, "Toggle failed requests clustered by status, domain and templated path (Enter opens one)"))
+	help = append(help, helpRow(k.WhatIf.Help().Key, "Toggle what-if estimates of removing each third-party site (Enter marks several)"))
+	help = append(help, helpRow(k.Initiators.Help().Key, "Toggle the initiator tree: what started each request (Enter opens details)"))
+	help = append(help, helpRow(k.BodySearch.Help().Key, "Search request and response bodies for text or a /regular expression/ (Enter opens details)"))
+	if len(m.harFiles) > 1 {
+		help = append(help, helpRow(k.Comparison.Help().Key, "Toggle comparison view (Enter diffs the selected request across files)"))
+		help = append(help, helpRow(helpKeys(" ", k.Baseline, k.MoveEarlier, k.MoveLater, k.Include), "In the comparison, make the current file the baseline, move it, or leave it out"))
+		help = append(help, helpRow(k.Trends.Help().Key, "Toggle metric trends across files in chronological order"))
+	}
+	help = append(help, helpRow(k.Security.Help().Key, "Toggle security findings (leaked secrets first)"))
+	help = append(help, helpRow(k.Export.Help().Key, "Open export dialog (JSON/CSV/HTML/PDF)"))
 	if len(m.harFiles) > 1 {
-		help = append(help, "c            Toggle comparison view")
+		help = append(help, helpRow(k.Bundle.Help().Key, "Export a before/after bundle (HTML + Markdown + JSON)"))
 	}
-	help = append(help, "e            Export reports (JSON/CSV/HTML/PDF)")
-	help = append(help, "?            Toggle this help")
-	help = append(help, "/            Filter requests")
+	help = append(help, helpRow(k.Help.Help().Key, "Toggle this help"))
+	help = append(help, helpRow(k.Filter.Help().Key, "Filter requests"))
+	help = append(help, helpRow(k.Tutorial.Help().Key, "Start the guided tutorial"))
+	help = append(help, helpRow(k.Theme.Help().Key, "Switch color theme ("+theme.Name+")"))
 	help = append(help, "")
 
 	help = append(help, headerStyle.Render("Filtering"))
 	help = append(help, "Type to filter by URL, method, or content type")
 	help = append(help, "Examples: 'GET', 'javascript', 'api/', '404'")
+	help = append(help, "Use 'tag:auth' to show only requests tagged auth")
+	help = append(help, "Use 'status:404' or 'status:4xx' to show only those responses")
 	help = append(help, "")
 
-	help = append(help, statusStyle.Render("Press q to quit, Esc to go back"))
+	help = append(help, statusStyle.Render(fmt.Sprintf("Press %s to quit, %s to go back", k.Quit.Help().Key, k.Back.Help().Key)))
 
-	return fmt.Sprintf("%s", help[0]) + "\n" + fmt.Sprintf("%s", help[1:])
+	return strings.Join(help, "\n")
 }
 
 func (m Model) renderTimelineView() string {
@@ -617,7 +1884,15 @@ func (m Model) renderTimelineView() string {
 	}
 
 	renderer := NewTimelineRenderer(m.width-4, m.height-10)
-	return renderer.RenderWaterfall(m.entries, m.timeline)
+	renderer.SetCriticalPath(m.analyzers[m.currentFile].CriticalPath())
+	renderer.SetOrder(m.waterfallOrder)
+	renderer.SetWindow(m.window)
+	renderer.SetTimeFormat(m.timeFormat())
+	renderer.SetSizeUnits(m.settings.SizeUnits)
+	if entries := m.harFiles[m.currentFile].Log.Entries; m.timelineSelected < len(entries) {
+		renderer.SetSelection(m.timelineSelected, entries[m.timelineSelected])
+	}
+	return renderer.RenderWaterfall(m.entries, m.scopedTimeline())
 }
 
 type TimelineRenderer struct {
@@ -626,6 +1901,13 @@ type TimelineRenderer struct {
 	pixelScale float64
 	startTime  time.Time
 	endTime    time.Time
+	critical   har.CriticalPath
+	order      WaterfallOrder
+	selected   int // entry index of the highlighted bar, when selection is set
+	selection  *har.Entry
+	window     timeWindow
+	times      timeFormat
+	units      har.SizeUnits
 }
 
 func NewTimelineRenderer(width, height int) *TimelineRenderer {
@@ -635,6 +1917,38 @@ func NewTimelineRenderer(width, height int) *TimelineRenderer {
 	}
 }
 
+// SetOrder arranges the bars by start time, duration or end time, or groups
+// them by domain or type.
+func (tr *TimelineRenderer) SetOrder(order WaterfallOrder) {
+	tr.order = order
+}
+
+// SetSelection highlights one request's bar and summarises it below the chart.
+func (tr *TimelineRenderer) SetSelection(index int, entry har.Entry) {
+	tr.selected, tr.selection = index, &entry
+}
+
+// SetWindow marks the time window on the scale and dims the requests that
+// started outside it.
+func (tr *TimelineRenderer) SetWindow(window timeWindow) {
+	tr.window = window
+}
+
+// SetTimeFormat labels the scale and the selected request's start time.
+func (tr *TimelineRenderer) SetTimeFormat(times timeFormat) {
+	tr.times = times
+}
+
+// SetSizeUnits sets the units of group and selection sizes.
+func (tr *TimelineRenderer) SetSizeUnits(units har.SizeUnits) {
+	tr.units = units
+}
+
+// SetCriticalPath highlights the requests that gated page load.
+func (tr *TimelineRenderer) SetCriticalPath(path har.CriticalPath) {
+	tr.critical = path
+}
+
 func (tr *TimelineRenderer) RenderWaterfall(entries []har.Entry, timeline []har.TimelineEvent) string {
 	if len(timeline) == 0 {
 		return "No timeline data available"
@@ -668,31 +1982,57 @@ func (tr *TimelineRenderer) RenderWaterfall(entries []har.Entry, timeline []har.
 
 	var output []string
 
-	output = append(output, titleStyle.Render("Request Timeline (Waterfall Chart)"))
+	output = append(output, titleStyle.Render(fmt.Sprintf("Request Timeline (Waterfall Chart, by %s)", tr.order)))
+	if len(tr.critical.Entries) > 0 {
+		summary := fmt.Sprintf("Critical path: %d requests, %.1fms to load", len(tr.critical.Entries), tr.critical.Duration)
+		if tr.critical.Inferred {
+			summary += " (partly inferred from timing, no initiator data)"
+		}
+		output = append(output, statusStyle.Render(summary))
+	}
 	output = append(output, "")
 
 	output = append(output, tr.renderTimeScale(chartWidth))
 	output = append(output, "")
 
-	maxEntries := tr.height - 8
-	entriesToShow := len(timeline)
-	if entriesToShow > maxEntries {
-		entriesToShow = maxEntries
+	maxRows := tr.height - 8
+	rows := arrangeWaterfall(timeline, tr.order)
+	offset := 0
+	if tr.selection != nil {
+		maxRows -= 4
+		for i, row := range rows {
+			if row.event != nil && row.event.Index == tr.selected {
+				offset = max(i-maxRows+1, 0)
+			}
+		}
+	}
+	hidden := 0
+	for i, row := range rows {
+		switch {
+		case i < offset || i >= offset+maxRows:
+			if row.event != nil {
+				hidden++
+			}
+		case row.event == nil:
+			output = append(output, row.heading(tr.units))
+		default:
+			output = append(output, tr.renderRequestBar(*row.event, chartWidth, i))
+		}
 	}
 
-	for i := 0; i < entriesToShow; i++ {
-		event := timeline[i]
-		output = append(output, tr.renderRequestBar(event, chartWidth, i))
+	if hidden > 0 {
+		output = append(output, fmt.Sprintf("... and %d more requests", hidden))
 	}
 
-	if len(timeline) > maxEntries {
-		output = append(output, fmt.Sprintf("... and %d more requests", len(timeline)-maxEntries))
+	if tr.selection != nil {
+		output = append(output, "")
+		output = append(output, tr.renderSelectionStrip()...)
 	}
 
 	output = append(output, "")
 	output = append(output, tr.renderLegend())
 	output = append(output, "")
-	output = append(output, statusStyle.Render("Press Esc to go back"))
+	output = append(output, statusStyle.Render("Press ↑/↓ to select a request, Enter for its details, [ / ] to start / end a time window there, o to change the order, Esc to go back"))
 
 	return strings.Join(output, "\n")
 }
@@ -714,6 +2054,12 @@ func (tr *TimelineRenderer) renderTimeScale(chartWidth int) string {
 			scaleLine[pos] = '┬'
 		}
 	}
+	for bound, char := range map[time.Time]rune{tr.window.start: '[', tr.window.end: ']'} {
+		if !bound.IsZero() {
+			pos := int(bound.Sub(tr.startTime).Seconds() * 1000 / tr.pixelScale)
+			scaleLine[min(max(pos, 0), chartWidth-1)] = char
+		}
+	}
 
 	scale += string(scaleLine)
 	scale += "\n" + strings.Repeat(" ", 30)
@@ -725,8 +2071,7 @@ func (tr *TimelineRenderer) renderTimeScale(chartWidth int) string {
 
 	for _, marker := range markers {
 		pos := int(float64(chartWidth) * marker)
-		timeMs := totalMs * marker
-		timeLabel := fmt.Sprintf("%.0fms", timeMs)
+		timeLabel := tr.times.scaleLabel(tr.startTime, totalMs*marker)
 
 		labelStart := pos - len(timeLabel)/2
 		if labelStart < 0 {
@@ -751,11 +2096,16 @@ func (tr *TimelineRenderer) renderTimeScale(chartWidth int) string {
 
 func (tr *TimelineRenderer) renderRequestBar(event har.TimelineEvent, chartWidth, index int) string {
 	label := tr.formatRequestLabel(event)
-	if len(label) > 28 {
-		label = label[:25] + "..."
+	critical := tr.critical.Contains(event.Index)
+	if critical {
+		label = "» " + label
+	}
+	bar := fitWidth(truncateValue(label, 28), 30)
+	if tr.selection != nil && event.Index == tr.selected {
+		bar = timelineCursorStyle.Render(bar)
+	} else if !tr.window.contains(event.StartTime) {
+		bar = statusStyle.Render(bar)
 	}
-
-	bar := fmt.Sprintf("%-30s", label)
 
 	requestStart := event.StartTime.Sub(tr.startTime).Seconds() * 1000
 	requestDuration := event.Duration
@@ -780,6 +2130,9 @@ func (tr *TimelineRenderer) renderRequestBar(event har.TimelineEvent, chartWidth
 	}
 
 	barChar, barStyle := tr.getBarStyle(event)
+	if critical {
+		barChar, barStyle = '▓', barStyle.Bold(true).Underline(true)
+	}
 	for i := startPos; i < startPos+duration && i < chartWidth; i++ {
 		timeline[i] = barChar
 	}
@@ -825,28 +2178,28 @@ func (tr *TimelineRenderer) formatRequestLabel(event har.TimelineEvent) string {
 
 func (tr *TimelineRenderer) getBarStyle(event har.TimelineEvent) (rune, lipgloss.Style) {
 	if event.Status >= 400 {
-		return '█', lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
+		return '█', foreground("9")
 	}
 
 	if event.Status >= 300 {
-		return '█', lipgloss.NewStyle().Foreground(lipgloss.Color("11"))
+		return '█', foreground("11")
 	}
 
 	if strings.Contains(event.ContentType, "html") {
-		return '█', lipgloss.NewStyle().Foreground(lipgloss.Color("12"))
+		return '█', foreground("12")
 	} else if strings.Contains(event.ContentType, "javascript") {
-		return '█', lipgloss.NewStyle().Foreground(lipgloss.Color("11"))
+		return '█', foreground("11")
 	} else if strings.Contains(event.ContentType, "css") {
-		return '█', lipgloss.NewStyle().Foreground(lipgloss.Color("10"))
+		return '█', foreground("10")
 	} else if strings.Contains(event.ContentType, "image") {
-		return '█', lipgloss.NewStyle().Foreground(lipgloss.Color("13"))
+		return '█', foreground("13")
 	} else if strings.Contains(event.ContentType, "json") {
-		return '█', lipgloss.NewStyle().Foreground(lipgloss.Color("14"))
+		return '█', foreground("14")
 	} else if strings.Contains(event.ContentType, "font") {
-		return '█', lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+		return '█', foreground("8")
 	}
 
-	return '█', lipgloss.NewStyle().Foreground(lipgloss.Color("7"))
+	return '█', foreground("7")
 }
 
 func (tr *TimelineRenderer) getStatusIcon(status int) string {
@@ -865,12 +2218,12 @@ func (tr *TimelineRenderer) renderLegend() string {
 
 	legend = append(legend, headerStyle.Render("Legend:"))
 
-	htmlStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("12"))
-	jsStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("11"))
-	cssStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("10"))
-	imgStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("13"))
-	apiStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("14"))
-	fontStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	htmlStyle := foreground("12")
+	jsStyle := foreground("11")
+	cssStyle := foreground("10")
+	imgStyle := foreground("13")
+	apiStyle := foreground("14")
+	fontStyle := foreground("8")
 
 	legend = append(legend, fmt.Sprintf("%s HTML  %s JS  %s CSS  %s Images  %s API/JSON  %s Fonts",
 		htmlStyle.Render("█"),
@@ -881,6 +2234,9 @@ func (tr *TimelineRenderer) renderLegend() string {
 		fontStyle.Render("█")))
 
 	legend = append(legend, "Status: ✅ Success  🔄 Redirect  ❌ Error")
+	if len(tr.critical.Entries) > 0 {
+		legend = append(legend, "» ▓ Critical path: the chain of requests that gated page load")
+	}
 
 	return strings.Join(legend, "\n")
 }
@@ -893,24 +2249,35 @@ func (m Model) renderComparisonView() string {
 	var content []string
 
 	// Header
-	content = append(content, titleStyle.Render(fmt.Sprintf("Performance Comparison (%d files)", len(m.harFiles))))
+	content = append(content, titleStyle.Render(fmt.Sprintf("Performance Comparison (%d files)", len(m.comparison.Files))))
 	content = append(content, "")
 
 	// Summary
+	content = append(content, m.renderComparisonOrder())
+	content = append(content, statusStyle.Render("Tab selects a file: u makes it the baseline, < and > move it, i leaves it out or adds it back"))
+	content = append(content, "")
+
 	summary := m.comparison.Summary
 	summaryText := fmt.Sprintf("📊 %d Better | %d Worse | %d Unchanged (of %d metrics)",
 		summary.BetterCount, summary.WorseCount, summary.UnchangedCount, summary.TotalMetrics)
 	content = append(content, headerStyle.Render(summaryText))
+	for _, warning := range m.comparison.Warnings {
+		content = append(content, foreground("11").Render("⚠ "+warning))
+	}
 	content = append(content, "")
 
 	// Metrics table header
+	// Columns widen to fit long file names
 	header := fmt.Sprintf("%-25s", "Metric")
+	widths := make([]int, len(m.comparison.Files))
 	for i, file := range m.comparison.Files {
 		if i == 0 {
-			header += fmt.Sprintf("%-15s", file+" (Base)")
+			file += " (Base)"
+			widths[i] = max(15, len(file)+1)
 		} else {
-			header += fmt.Sprintf("%-20s", file)
+			widths[i] = max(20, len(file)+1)
 		}
+		header += fmt.Sprintf("%-*s", widths[i], file)
 	}
 	content = append(content, headerStyle.Render(header))
 	content = append(content, strings.Repeat("─", len(header)))
@@ -922,23 +2289,20 @@ func (m Model) renderComparisonView() string {
 		for i, value := range diff.Values {
 			valueStr := fmt.Sprintf("%v", value)
 			if i == 0 {
-				row += fmt.Sprintf("%-15s", valueStr)
+				row += fmt.Sprintf("%-*s", widths[i], valueStr)
 			} else {
 				change := diff.Changes[i]
-				improvement := diff.Improvements[i]
 
 				// Add styling based on improvement
 				changeStyled := change
-				if change != "Baseline" && change != "No change" {
-					if improvement {
-						changeStyled = lipgloss.NewStyle().Foreground(lipgloss.Color("10")).Render(change + " ✅")
-					} else {
-						changeStyled = lipgloss.NewStyle().Foreground(lipgloss.Color("9")).Render(change + " ⚠️")
-					}
+				if diff.Flagged(i) && diff.Improvements[i] {
+					changeStyled = foreground("10").Render(change + " ✅")
+				} else if diff.Regressed(i) {
+					changeStyled = foreground("9").Render(change + " ⚠️")
 				}
 
 				combined := fmt.Sprintf("%s (%s)", valueStr, changeStyled)
-				row += fmt.Sprintf("%-20s", combined)
+				row += fmt.Sprintf("%-*s", widths[i], combined)
 			}
 		}
 
@@ -955,6 +2319,9 @@ func (m Model) renderComparisonView() string {
 		content = append(content, "• "+insight)
 	}
 
+	content = append(content, "")
+	content = append(content, m.renderComparisonRequests()...)
+
 	content = append(content, "")
 	content = append(content, statusStyle.Render("Press Esc to go back"))
 
@@ -962,115 +2329,72 @@ func (m Model) renderComparisonView() string {
 }
 
 func (m Model) generateInsights() []string {
-	if m.comparison == nil || len(m.comparison.Differences) == 0 {
+	if m.comparison == nil || len(m.comparison.Insights) == 0 {
 		return []string{"No insights available"}
 	}
 
-	var insights []string
-
-	// Analyze load time changes
-	for _, diff := range m.comparison.Differences {
-		if diff.Name == "Total Load Time" && len(diff.Changes) > 1 {
-			change := diff.Changes[1]
-			if strings.Contains(change, "-") && diff.Improvements[1] {
-				insights = append(insights, "Page load time improved significantly")
-			} else if strings.Contains(change, "+") && !diff.Improvements[1] {
-				insights = append(insights, "Page load time regressed - investigate performance")
-			}
-		}
-
-		if diff.Name == "Error Requests" && len(diff.Changes) > 1 {
-			change := diff.Changes[1]
-			if change == "No change" || strings.Contains(change, "-") {
-				insights = append(insights, "Error rate remained stable or improved")
-			} else if strings.Contains(change, "+") {
-				insights = append(insights, "Error rate increased - check for new issues")
-			}
-		}
-
-		if diff.Name == "Cache Hit Ratio" && len(diff.Changes) > 1 {
-			change := diff.Changes[1]
-			if strings.Contains(change, "+") && diff.Improvements[1] {
-				insights = append(insights, "Cache efficiency improved")
-			} else if strings.Contains(change, "-") && !diff.Improvements[1] {
-				insights = append(insights, "Cache efficiency decreased")
-			}
-		}
-
-		if diff.Name == "Total Transfer Size" && len(diff.Changes) > 1 {
-			change := diff.Changes[1]
-			if strings.Contains(change, "-") && diff.Improvements[1] {
-				insights = append(insights, "Transfer size optimized")
-			} else if strings.Contains(change, "+") && !diff.Improvements[1] {
-				insights = append(insights, "Transfer size increased - check for new assets")
-			}
+	insights := make([]string, len(m.comparison.Insights))
+	for i, insight := range m.comparison.Insights {
+		insights[i] = insight.String()
+		switch insight.Severity {
+		case har.InsightCritical:
+			insights[i] = foreground("9").Render(insights[i])
+		case har.InsightWarning:
+			insights[i] = foreground("11").Render(insights[i])
 		}
 	}
-
-	if len(insights) == 0 {
-		insights = append(insights, "Performance appears stable across files")
-	}
-
 	return insights
 }
 
-func (m Model) exportReports() {
-	generator := report.NewGenerator(m.harFiles, m.analyzers, m.comparison)
-
-	timestamp := time.Now().Format("2006-01-02_15-04-05")
-	baseFilename := fmt.Sprintf("har-analysis-%s", timestamp)
-
-	// Export all formats
-	formats := []struct {
-		extension  string
-		exportFunc func(string) error
-	}{
-		{".json", func(filename string) error { return generator.ExportJSON(filename, false) }},
-		{".csv", generator.ExportCSV},
-		{".html", generator.ExportHTML},
-		{".pdf", generator.ExportPDF},
-	}
-
-	for _, format := range formats {
-		filename := baseFilename + format.extension
-		if err := format.exportFunc(filename); err != nil {
-			// In a real implementation, you might want to show this error in the UI
-			continue
-		}
-	}
+// truncateValue shortens a value to at most maxLen terminal cells, ending
+// in "...". CJK and other wide characters take two cells, and characters are
+// never split.
+func truncateValue(value string, maxLen int) string {
+	return ansi.Truncate(value, maxLen, "...")
 }
 
-func truncateValue(value string, maxLen int) string {
-	if len(value) <= maxLen {
-		return value
-	}
-	return value[:maxLen-3] + "..."
+// fitWidth truncates a value and pads it with spaces to exactly width
+// cells, for columns that fmt's %-*s would misalign on wide characters.
+func fitWidth(value string, width int) string {
+	value = truncateValue(value, width)
+	return value + strings.Repeat(" ", max(width-ansi.StringWidth(value), 0))
 }
 
 func (m *Model) updateTableRows() {
 	if len(m.entries) == 0 {
+		m.table.SetRows([]table.Row{})
 		return
 	}
 
+	times := m.timeFormat()
 	rows := make([]table.Row, len(m.entries))
 	for i, entry := range m.entries {
-		size := formatSize(entry.Response.Content.Size)
+		size := m.settings.SizeUnits.FormatSize(entry.Response.Content.Size)
 		contentType := entry.Response.Content.MimeType
 		if contentType == "" {
 			contentType = "unknown"
 		}
-		if len(contentType) > 15 {
-			contentType = contentType[:12] + "..."
+		contentType = truncateValue(contentType, 15)
+
+		url := truncateURL(entry.Request.URL, 60)
+		if entry.HasTag(har.TagInsecure) {
+			url = "⚠ " + truncateURL(entry.Request.URL, 58)
+		}
+
+		method := entry.Request.Method
+		if m.selectedEntries[m.entryIndices[i]] {
+			method = "✓ " + method
 		}
 
 		rows[i] = table.Row{
-			entry.Request.Method,
+			method,
 			fmt.Sprintf("%d", entry.Response.Status),
-			truncateURL(entry.Request.URL, 60),
+			url,
 			fmt.Sprintf("%.1f", entry.Time),
 			size,
 			contentType,
 		}
+		rows[i] = append(rows[i], m.optionalCells(entry, times)...)
 	}
 	m.table.SetRows(rows)
 }
@@ -1078,61 +2402,69 @@ func (m *Model) updateTableRows() {
 func (m *Model) switchFile() {
 	if m.currentFile < len(m.harFiles) {
 		m.entries = m.harFiles[m.currentFile].Log.Entries
-		m.metrics = m.analyzers[m.currentFile].CalculateMetrics()
+		m.entryIndices = identityIndices(m.entries)
+		m.quickView = AllRequests
+		m.selectedEntries = nil
+		m.window = timeWindow{}
+		m.updateScope()
 		m.timeline = m.analyzers[m.currentFile].GenerateTimeline()
 		m.updateTableRows()
 		m.selectedEntry = 0
+		m.timelineSelected = 0
+		m.comparisonSelected = 0
+		m.whatIfSelected = 0
+		m.whatIfRemoved = nil
+		m.initiatorSelected = 0
+		m.bodyMatches, m.bodyMatchSelected = nil, 0
+		m.errorClusterSelected = 0
 		m.table.GotoTop()
 	}
 }
 
 func (m *Model) filterEntries(filterText string) {
-	if filterText == "" {
-		m.entries = m.harFiles[m.currentFile].Log.Entries
+	if saved, ok := m.savedFilters[strings.TrimPrefix(filterText, "@")]; ok && strings.HasPrefix(filterText, "@") {
+		filterText = saved
+	}
+
+	entries := m.harFiles[m.currentFile].Log.Entries
+	candidates := m.quickViewIndices()
+	if filterText == "" && candidates == nil && !m.window.active() {
+		m.entries = entries
+		m.entryIndices = identityIndices(m.entries)
 	} else {
+		if candidates == nil {
+			candidates = identityIndices(entries)
+		}
 		var filtered []har.Entry
-		for _, entry := range m.harFiles[m.currentFile].Log.Entries {
-			if matchesFilter(entry, filterText) {
-				filtered = append(filtered, entry)
+		var indices []int
+		for _, i := range candidates {
+			if m.window.contains(entries[i].StartedDateTime) && (filterText == "" || matchesFilter(entries[i], filterText)) {
+				filtered = append(filtered, entries[i])
+				indices = append(indices, i)
 			}
 		}
 		m.entries = filtered
+		m.entryIndices = indices
+	}
+	if m.scopeToFilter {
+		m.updateScope()
 	}
 	m.updateTableRows()
 	m.table.GotoTop()
 }
 
-var (
-	titleStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("205"))
-
-	headerStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("86"))
-
-	statusStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("242"))
-)
-
-func formatSize(size int) string {
-	if size < 1024 {
-		return fmt.Sprintf("%dB", size)
-	} else if size < 1024*1024 {
-		return fmt.Sprintf("%.1fKB", float64(size)/1024)
-	} else {
-		return fmt.Sprintf("%.1fMB", float64(size)/(1024*1024))
-	}
-}
-
 func truncateURL(url string, maxLen int) string {
-	if len(url) <= maxLen {
-		return url
-	}
-	return url[:maxLen-3] + "..."
+	return truncateValue(url, maxLen)
 }
 
 func matchesFilter(entry har.Entry, filter string) bool {
+	if tag, ok := strings.CutPrefix(filter, "tag:"); ok {
+		return entry.HasTag(tag)
+	}
+	if status, ok := strings.CutPrefix(filter, "status:"); ok {
+		return strconv.Itoa(entry.Response.Status) == status || har.StatusClass(entry.Response.Status) == strings.ToLower(status)
+	}
+
 	// Simple case-insensitive matching
 	filter = fmt.Sprintf("%s", filter)
 	url := fmt.Sprintf("%s", entry.Request.URL)
@@ -1177,3 +2509,125 @@ func toLower(c byte) byte {
 	}
 	return c
 }
+
+// renderSegments lists the bursts of activity in the current file, which
+// approximate user actions when the capture has no page markers.
+func (m Model) renderSegments() []string {
+	segments := m.scopedAnalyzer().Segments(m.segmentGap)
+	if len(segments) < 2 {
+		return nil
+	}
+
+	gap := m.segmentGap
+	if gap <= 0 {
+		gap = har.DefaultSegmentGap
+	}
+	lines := []string{headerStyle.Render(fmt.Sprintf("Segments (idle gaps over %s)", gap))}
+
+	start := segments[0].Start
+	for _, segment := range segments {
+		lines = append(lines, fmt.Sprintf("%-12s +%-9s %4d requests  %10.1fms  %10s  %d errors",
+			segment.Label,
+			segment.Start.Sub(start).Round(time.Millisecond),
+			segment.Metrics.TotalRequests,
+			float64(segment.Duration().Microseconds())/1000,
+			m.settings.SizeUnits.FormatSize(int(segment.Metrics.TotalSize)),
+			segment.Metrics.ErrorRequests,
+		))
+	}
+	return lines
+}
+
+func (m Model) renderCacheAnalysis() []string {
+	report := m.scopedAnalyzer().CacheAnalysis(m.metrics)
+
+	lines := []string{
+		fmt.Sprintf("Cacheable: %d, uncacheable: %d, short TTL: %d, heuristic: %d", report.Cacheable, report.Uncacheable, report.ShortTTL, report.Heuristic),
+		fmt.Sprintf("Revalidations (304): %d (%.1fms)", report.Revalidations, report.RevalidationTime),
+		fmt.Sprintf("Repeat-visit savings: %s, %.1fms", m.settings.SizeUnits.FormatSize(int(report.SavedBytes)), report.SavedTime),
+	}
+	for i, issue := range report.Issues {
+		if i == 10 {
+			lines = append(lines, fmt.Sprintf("  ... and %d more", len(report.Issues)-i))
+			break
+		}
+		lines = append(lines, fmt.Sprintf("  ⚠️  %8s  %s (%s)", m.settings.SizeUnits.FormatSize(issue.Size), truncateURL(issue.URL, max(m.width-60, 40)), issue.Issue))
+	}
+	return lines
+}
+
+func (m Model) renderRepeatView() []string {
+	view := m.scopedAnalyzer().SimulateRepeatView(0, m.metrics)
+
+	lines := []string{headerStyle.Render("Repeat View (simulated from cache headers)")}
+	lines = append(lines, fmt.Sprintf("Load Time: %.1fms (first view %.1fms)", view.LoadTime, m.metrics.PageLoadTime))
+	lines = append(lines, fmt.Sprintf("Transfer Size: %s (first view %s)", m.settings.SizeUnits.FormatSize(int(view.Size)), m.settings.SizeUnits.FormatSize(int(m.metrics.TotalSize))))
+	lines = append(lines, fmt.Sprintf("Requests: %d cached, %d revalidated, %d refetched", view.Cached, view.Revalidated, view.Refetched))
+	return lines
+}
+
+func (m Model) renderAPIRefetches() []string {
+	groups := m.scopedAnalyzer().APIRefetches(har.DefaultRefetchWindow)
+	if len(groups) == 0 {
+		return nil
+	}
+
+	var wastedBytes int64
+	for _, group := range groups {
+		wastedBytes += group.WastedBytes
+	}
+
+	lines := []string{headerStyle.Render("API Over-fetching")}
+	lines = append(lines, fmt.Sprintf("Redundant Requests: %d (%s of identical responses within %s)", m.metrics.RedundantAPIRequests, m.settings.SizeUnits.FormatSize(int(wastedBytes)), har.DefaultRefetchWindow))
+	for _, group := range groups {
+		lines = append(lines, fmt.Sprintf("  ⚠️  %dx of %d  %8s  %s %s (shortest gap %s)", group.Redundant, group.Fetches, m.settings.SizeUnits.FormatSize(int(group.WastedBytes)), group.Method, truncateURL(group.URL, max(m.width-60, 40)), group.Shortest.Round(time.Millisecond)))
+	}
+	lines = append(lines, "  Cache these responses on the server or use stale-while-revalidate on the client")
+	return lines
+}
+
+func (m Model) renderPreloadWaste() []string {
+	hints := m.scopedAnalyzer().PreloadHints()
+	if len(hints) == 0 {
+		return nil
+	}
+
+	var wasted []har.PreloadHint
+	for _, hint := range hints {
+		if hint.Wasted() {
+			wasted = append(wasted, hint)
+		}
+	}
+
+	lines := []string{headerStyle.Render("Preload & Prefetch")}
+	lines = append(lines, fmt.Sprintf("Hints: %d, unused: %d (%s wasted)", len(hints), len(wasted), m.settings.SizeUnits.FormatSize(int(m.metrics.PreloadWastedBytes))))
+	for _, hint := range wasted {
+		lines = append(lines, fmt.Sprintf("  ⚠️  %-13s %8s  %s (%s)", hint.Rel, m.settings.SizeUnits.FormatSize(hint.Size), truncateURL(hint.URL, max(m.width-50, 40)), hint.Source))
+	}
+	return lines
+}
+
+func (m Model) renderEncodingDistribution() []string {
+	overall, domains := m.scopedAnalyzer().EncodingDistribution()
+	if overall.Total == 0 {
+		return nil
+	}
+
+	format := func(stats har.EncodingStats) string {
+		var shares []string
+		for _, encoding := range har.ContentEncodings {
+			if stats.ByEncoding[encoding] > 0 {
+				shares = append(shares, fmt.Sprintf("%s %.0f%%", encoding, stats.Share(encoding)))
+			}
+		}
+		return strings.Join(shares, ", ")
+	}
+
+	lines := []string{headerStyle.Render("Content Encoding (text responses)")}
+	lines = append(lines, fmt.Sprintf("All %d: %s", overall.Total, format(overall)))
+	limit := min(len(domains), 5)
+	for _, stats := range domains[:limit] {
+		lines = append(lines, fmt.Sprintf("  %s %3d  %s", fitWidth(stats.Domain, 32), stats.Total, format(stats)))
+	}
+	return lines
+}
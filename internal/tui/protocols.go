@@ -0,0 +1,44 @@
+package tui
+
+import (
+	"fmt"
+	"github.com/jlgore/hartea/internal/har"
+	"strings"
+)
+
+// renderProtocols shows the HTTP version distribution and the HTTP/1.x
+// origins that queued requests for lack of connections.
+func (m Model) renderProtocols() []string {
+	stats := m.analyzers[m.currentFile].ProtocolDistribution()
+	if stats.Known() == 0 {
+		return nil
+	}
+
+	var shares []string
+	for _, protocol := range har.HTTPProtocols {
+		if stats.ByProtocol[protocol] > 0 {
+			shares = append(shares, fmt.Sprintf("%s %.0f%% (%d)", protocol, stats.Share(protocol), stats.ByProtocol[protocol]))
+		}
+	}
+	lines := []string{headerStyle.Render("HTTP Protocols"), strings.Join(shares, ", ")}
+
+	for _, origin := range m.analyzers[m.currentFile].HTTP1Origins() {
+		if !origin.Queued() {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("  ⚠️  %s %s, %d requests, up to %d in parallel", fitWidth(origin.Origin, 40), origin.Protocol, origin.Requests, origin.PeakParallel))
+	}
+	return lines
+}
+
+// queuedHTTP1Origins counts the HTTP/1.x origins with more requests in flight
+// than connections.
+func (m Model) queuedHTTP1Origins() int {
+	count := 0
+	for _, origin := range m.analyzers[m.currentFile].HTTP1Origins() {
+		if origin.Queued() {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,62 @@
+package tui
+
+import (
+	"fmt"
+)
+
+// QuickView replaces the table contents with a ranked subset of the current
+// file's requests.
+type QuickView int
+
+const (
+	AllRequests QuickView = iota
+	SlowestRequests
+	LargestRequests
+	ErrorRequests
+)
+
+// quickViewLimit caps the slowest and largest quick views.
+const quickViewLimit = 25
+
+// quickViewIndices returns the entries the quick view shows, as indices into
+// the current file's entries, or nil when every request is shown.
+func (m Model) quickViewIndices() []int {
+	analyzer := m.analyzers[m.currentFile]
+	switch m.quickView {
+	case SlowestRequests:
+		return analyzer.SlowestRequestIndices(quickViewLimit)
+	case LargestRequests:
+		return analyzer.LargestRequestIndices(quickViewLimit)
+	case ErrorRequests:
+		indices := analyzer.ErrorRequestIndices()
+		if indices == nil {
+			indices = []int{}
+		}
+		return indices
+	}
+	return nil
+}
+
+// quickViewTitle describes the active quick view for the table header.
+func (m Model) quickViewTitle() string {
+	switch m.quickView {
+	case SlowestRequests:
+		return fmt.Sprintf("Quick view: %d slowest requests", len(m.quickViewIndices()))
+	case LargestRequests:
+		return fmt.Sprintf("Quick view: %d largest responses", len(m.quickViewIndices()))
+	case ErrorRequests:
+		return fmt.Sprintf("Quick view: %d error responses (4xx/5xx)", len(m.quickViewIndices()))
+	}
+	return ""
+}
+
+// toggleQuickView shows the quick view in the table, or all requests again
+// when it is already shown. The current filter still applies.
+func (m *Model) toggleQuickView(view QuickView) {
+	if m.quickView == view {
+		view = AllRequests
+	}
+	m.quickView = view
+	m.currentView = TableView
+	m.filterEntries(m.filter.Value())
+}
@@ -0,0 +1,82 @@
+package tui
+
+import (
+	"fmt"
+	"github.com/jlgore/hartea/internal/har"
+	"strings"
+	"time"
+)
+
+// quotaSparklineWidth caps the samples drawn per quota trend.
+const quotaSparklineWidth = 24
+
+var sparklineLevels = []rune("▁▂▃▄▅▆▇█")
+
+// rateLimitRows lists the throttled endpoints and the remaining quota trend
+// of every host that reported one, for the status view.
+func (m Model) rateLimitRows() []statusRow {
+	analysis := m.analyzers[m.currentFile].RateLimits()
+	if !analysis.HasData() {
+		return nil
+	}
+
+	rows := []statusRow{{}, {text: headerStyle.Render(fmt.Sprintf("Rate Limiting (%d throttled, %s lost to backoff)",
+		analysis.Throttled, analysis.Backoff.Round(time.Millisecond)))}}
+	for _, endpoint := range analysis.Endpoints {
+		retry := ""
+		if endpoint.RetryAfter > 0 {
+			retry = fmt.Sprintf("  Retry-After %s", endpoint.RetryAfter)
+		}
+		// The endpoint's host and path are a substring of its URLs
+		_, path, _ := strings.Cut(endpoint.Endpoint, " ")
+		rows = append(rows, statusRow{
+			text: fmt.Sprintf("  429×%-3d of %-4d %s backoff %s%s", endpoint.Throttled, endpoint.Requests,
+				fitWidth(endpoint.Endpoint, 44), endpoint.Backoff.Round(time.Millisecond), retry),
+			filter: path,
+		})
+	}
+
+	if len(analysis.Quotas) > 0 {
+		rows = append(rows, statusRow{text: "Remaining quota"})
+	}
+	for _, quota := range analysis.Quotas {
+		first, last := quota.Samples[0].Remaining, quota.Samples[len(quota.Samples)-1].Remaining
+		limit := ""
+		if quota.Limit() >= 0 {
+			limit = fmt.Sprintf(" of %d", quota.Limit())
+		}
+		rows = append(rows, statusRow{
+			text: fmt.Sprintf("  %s %s  %d → %d%s (min %d)", fitWidth(quota.Host, 32), quotaSparkline(quota),
+				first, last, limit, quota.MinRemaining()),
+			filter: quota.Host,
+		})
+	}
+	return rows
+}
+
+// quotaSparkline draws the remaining quota over the capture, scaled to the
+// limit or, without one, to the highest sample.
+func quotaSparkline(quota har.HostQuota) string {
+	samples := quota.Samples
+	if len(samples) > quotaSparklineWidth {
+		sampled := make([]har.QuotaSample, quotaSparklineWidth)
+		for i := range sampled {
+			sampled[i] = samples[i*(len(samples)-1)/(quotaSparklineWidth-1)]
+		}
+		samples = sampled
+	}
+
+	top := quota.Limit()
+	for _, sample := range samples {
+		top = max(top, sample.Remaining)
+	}
+	var line strings.Builder
+	for _, sample := range samples {
+		level := 0
+		if top > 0 {
+			level = sample.Remaining * (len(sparklineLevels) - 1) / top
+		}
+		line.WriteRune(sparklineLevels[level])
+	}
+	return fmt.Sprintf("%-*s", quotaSparklineWidth, line.String())
+}
@@ -0,0 +1,54 @@
+package tui
+
+import (
+	"fmt"
+	"github.com/jlgore/hartea/internal/har"
+	"strings"
+)
+
+// renderRedirectChain draws the redirect chain the entry belongs to, marking
+// the entry itself.
+func (m Model) renderRedirectChain(entryIndex int) []string {
+	chain, ok := m.analyzers[m.currentFile].RedirectChainFor(entryIndex)
+	if !ok {
+		return nil
+	}
+
+	lines := []string{headerStyle.Render(fmt.Sprintf("Redirect Chain (%d redirects, %.1fms)", chain.Redirects(), chain.Latency()))}
+	for i, hop := range chain.Hops {
+		line := fmt.Sprintf("  %3d  %s (%.1fms)", hop.Status, truncateURL(hop.URL, max(m.width-40, 40)), hop.Time)
+		if hop.EntryIndex == entryIndex {
+			line += "  ◀ this request"
+		}
+		lines = append(lines, line)
+		if i < len(chain.Hops)-1 {
+			lines = append(lines, "   ↓  "+hop.Kind)
+		}
+	}
+	return append(lines, "")
+}
+
+// renderRedirects summarizes redirect chains for the metrics view.
+func (m Model) renderRedirects() []string {
+	chains := m.analyzers[m.currentFile].RedirectChains()
+	if len(chains) == 0 {
+		return nil
+	}
+
+	lines := []string{headerStyle.Render("Redirects")}
+	lines = append(lines, fmt.Sprintf("Chains: %d, total latency: %.1fms", len(chains), m.metrics.RedirectLatency))
+	for _, chain := range chains {
+		var kinds []string
+		for _, hop := range chain.Hops {
+			if hop.Kind != "" {
+				kinds = append(kinds, hop.Kind)
+			}
+		}
+		status := "  "
+		if chain.Redirects() >= har.LongRedirectChain {
+			status = "⚠️ "
+		}
+		lines = append(lines, fmt.Sprintf("  %s %d redirects  %8.1fms  %s (%s)", status, chain.Redirects(), chain.Latency(), truncateURL(chain.Hops[0].URL, max(m.width-70, 40)), strings.Join(kinds, ", ")))
+	}
+	return lines
+}
@@ -0,0 +1,229 @@
+package tui
+
+import (
+	"fmt"
+	"github.com/jlgore/hartea/internal/har"
+	"html"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+var viewNames = map[string]ViewMode{
+	"table":       TableView,
+	"detail":      DetailView,
+	"metrics":     MetricsView,
+	"timeline":    TimelineView,
+	"comparison":  ComparisonView,
+	"help":        HelpView,
+	"timing":      TimingView,
+	"security":    SecurityView,
+	"concurrency": ConcurrencyView,
+	"bandwidth":   ThroughputView,
+	"histogram":   HistogramView,
+	"heatmap":     HeatmapView,
+	"status":      StatusView,
+	"endpoints":   EndpointsView,
+	"trends":      TrendView,
+	"whatif":      WhatIfView,
+	"initiators":  InitiatorView,
+	"errors":      ErrorClusterView,
+}
+
+// RenderView renders a single view offscreen, without a TTY, at the given size.
+// When color is false all styling is stripped so the output is plain text.
+func RenderView(harFiles []*har.HAR, options Options, view string, width, height int, color bool) (string, error) {
+	mode, ok := viewNames[view]
+	if !ok {
+		return "", fmt.Errorf("unknown view %q (expected table, detail, metrics, timeline, comparison, help, timing, security, concurrency, bandwidth, histogram, heatmap, status, endpoints, trends, whatif, initiators or errors)", view)
+	}
+	if (mode == ComparisonView || mode == TrendView) && len(harFiles) < 2 {
+		return "", fmt.Errorf("%s view requires at least two HAR files", view)
+	}
+
+	if color {
+		lipgloss.SetColorProfile(termenv.ANSI256)
+	} else {
+		lipgloss.SetColorProfile(termenv.Ascii)
+	}
+
+	m := NewModel(harFiles, options)
+	m.tutorial = Tutorial{}
+
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: width, Height: height})
+	m = updated.(Model)
+	if mode == DetailView || mode == TimingView {
+		m.selectedEntry = m.table.Cursor()
+	}
+	m.currentView = mode
+
+	return m.View(), nil
+}
+
+const (
+	svgCharWidth  = 8.4
+	svgLineHeight = 17
+	svgFontSize   = 14
+	svgPadding    = 10
+)
+
+type svgStyle struct {
+	fg   string
+	bg   string
+	bold bool
+}
+
+// ANSIToSVG converts ANSI-styled terminal output into a standalone SVG image
+// that preserves colors and bold text.
+func ANSIToSVG(text string) string {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+
+	maxCols := 0
+	for _, line := range lines {
+		if w := lipgloss.Width(line); w > maxCols {
+			maxCols = w
+		}
+	}
+
+	width := float64(maxCols)*svgCharWidth + 2*svgPadding
+	height := float64(len(lines)*svgLineHeight) + 2*svgPadding
+
+	var svg strings.Builder
+	svg.WriteString(fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f" viewBox="0 0 %.0f %.0f">`+"\n", width, height, width, height))
+	svg.WriteString(`<rect width="100%" height="100%" fill="#1e1e1e"/>` + "\n")
+	svg.WriteString(fmt.Sprintf(`<g font-family="Menlo, Consolas, 'DejaVu Sans Mono', monospace" font-size="%d" fill="#d4d4d4" xml:space="preserve">`+"\n", svgFontSize))
+
+	var style svgStyle
+	for row, line := range lines {
+		y := float64(svgPadding + (row+1)*svgLineHeight - 4)
+		col := 0
+		var segment strings.Builder
+		segmentStart := 0
+
+		flush := func() {
+			if segment.Len() == 0 {
+				return
+			}
+			x := float64(svgPadding) + float64(segmentStart)*svgCharWidth
+			segmentWidth := float64(col-segmentStart) * svgCharWidth
+			if style.bg != "" {
+				svg.WriteString(fmt.Sprintf(`<rect x="%.1f" y="%.1f" width="%.1f" height="%d" fill="%s"/>`+"\n",
+					x, y-svgLineHeight+4, segmentWidth, svgLineHeight, style.bg))
+			}
+			attrs := ""
+			if style.fg != "" {
+				attrs += fmt.Sprintf(` fill="%s"`, style.fg)
+			}
+			if style.bold {
+				attrs += ` font-weight="bold"`
+			}
+			svg.WriteString(fmt.Sprintf(`<text x="%.1f" y="%.1f"%s>%s</text>`+"\n", x, y, attrs, html.EscapeString(segment.String())))
+			segment.Reset()
+		}
+
+		runes := []rune(line)
+		for i := 0; i < len(runes); i++ {
+			if runes[i] == '\x1b' && i+1 < len(runes) && runes[i+1] == '[' {
+				end := i + 2
+				for end < len(runes) && !(runes[end] >= '@' && runes[end] <= '~') {
+					end++
+				}
+				if end < len(runes) && runes[end] == 'm' {
+					flush()
+					segmentStart = col
+					style = applySGR(style, string(runes[i+2:end]))
+				}
+				i = end
+				continue
+			}
+			if segment.Len() == 0 {
+				segmentStart = col
+			}
+			segment.WriteRune(runes[i])
+			col += lipgloss.Width(string(runes[i]))
+		}
+		flush()
+	}
+
+	svg.WriteString("</g>\n</svg>\n")
+	return svg.String()
+}
+
+func applySGR(style svgStyle, params string) svgStyle {
+	if params == "" {
+		return svgStyle{}
+	}
+
+	codes := strings.Split(params, ";")
+	for i := 0; i < len(codes); i++ {
+		code, err := strconv.Atoi(codes[i])
+		if err != nil {
+			continue
+		}
+		switch {
+		case code == 0:
+			style = svgStyle{}
+		case code == 1:
+			style.bold = true
+		case code == 22:
+			style.bold = false
+		case code >= 30 && code <= 37:
+			style.fg = xtermColor(code - 30)
+		case code >= 90 && code <= 97:
+			style.fg = xtermColor(code - 90 + 8)
+		case code == 39:
+			style.fg = ""
+		case code >= 40 && code <= 47:
+			style.bg = xtermColor(code - 40)
+		case code >= 100 && code <= 107:
+			style.bg = xtermColor(code - 100 + 8)
+		case code == 49:
+			style.bg = ""
+		case (code == 38 || code == 48) && i+2 < len(codes) && codes[i+1] == "5":
+			n, _ := strconv.Atoi(codes[i+2])
+			if code == 38 {
+				style.fg = xtermColor(n)
+			} else {
+				style.bg = xtermColor(n)
+			}
+			i += 2
+		case (code == 38 || code == 48) && i+4 < len(codes) && codes[i+1] == "2":
+			r, _ := strconv.Atoi(codes[i+2])
+			g, _ := strconv.Atoi(codes[i+3])
+			b, _ := strconv.Atoi(codes[i+4])
+			hex := fmt.Sprintf("#%02x%02x%02x", r, g, b)
+			if code == 38 {
+				style.fg = hex
+			} else {
+				style.bg = hex
+			}
+			i += 4
+		}
+	}
+	return style
+}
+
+var basicColors = []string{
+	"#000000", "#cd3131", "#0dbc79", "#e5e510", "#2472c8", "#bc3fbc", "#11a8cd", "#e5e5e5",
+	"#666666", "#f14c4c", "#23d18b", "#f5f543", "#3b8eea", "#d670d6", "#29b8db", "#ffffff",
+}
+
+func xtermColor(n int) string {
+	switch {
+	case n < 0:
+		return ""
+	case n < 16:
+		return basicColors[n]
+	case n < 232:
+		n -= 16
+		levels := []int{0, 95, 135, 175, 215, 255}
+		return fmt.Sprintf("#%02x%02x%02x", levels[n/36], levels[(n/6)%6], levels[n%6])
+	case n < 256:
+		gray := 8 + (n-232)*10
+		return fmt.Sprintf("#%02x%02x%02x", gray, gray, gray)
+	}
+	return ""
+}
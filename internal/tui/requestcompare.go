@@ -0,0 +1,101 @@
+package tui
+
+import (
+	"fmt"
+	"github.com/jlgore/hartea/internal/har"
+)
+
+// comparisonRequestRows is how many matched requests the comparison view
+// lists around the selection.
+const comparisonRequestRows = 10
+
+// comparisonTarget is the file whose requests are compared against the
+// baseline: the current file, or the next compared one while the current file
+// is the baseline or left out.
+func (m Model) comparisonTarget() int {
+	if m.comparisonIndex(m.currentFile) > 0 {
+		return m.currentFile
+	}
+	return m.comparisonOrder[1]
+}
+
+// comparisonMatches pairs the requests of the baseline and the target,
+// ignoring query strings, and keeps those found in both files, biggest time
+// change first.
+func (m Model) comparisonMatches() []har.EntryMatch {
+	if len(m.comparisonOrder) < 2 {
+		return nil
+	}
+	var matches []har.EntryMatch
+	for _, match := range har.MatchEntries(m.harFiles[m.baselineFile()], m.harFiles[m.comparisonTarget()], har.MatchIgnoreQuery) {
+		if !match.Added() && !match.Removed() {
+			matches = append(matches, match)
+		}
+	}
+	return matches
+}
+
+// moveComparisonSelection moves the highlighted request of the comparison
+// view.
+func (m *Model) moveComparisonSelection(delta int) {
+	matches := len(m.comparisonMatches())
+	m.comparisonSelected = min(max(m.comparisonSelected+delta, 0), max(matches-1, 0))
+}
+
+// diffComparisonSelection opens the diff of the highlighted request between
+// the baseline and the target file.
+func (m *Model) diffComparisonSelection() {
+	matches := m.comparisonMatches()
+	if m.comparisonSelected >= len(matches) {
+		return
+	}
+	match := matches[m.comparisonSelected]
+	m.openDiff(entryRef{m.baselineFile(), match.Before}, entryRef{m.comparisonTarget(), match.After})
+}
+
+// renderComparisonRequests lists the requests found in both the baseline and
+// the target file with their status, time and size changes.
+func (m Model) renderComparisonRequests() []string {
+	if len(m.comparisonOrder) < 2 {
+		return nil
+	}
+	baseline, target := m.baselineFile(), m.comparisonTarget()
+	lines := []string{headerStyle.Render(fmt.Sprintf("Requests: %s vs %s", m.fileNames[baseline], m.fileNames[target]))}
+	matches := m.comparisonMatches()
+	if len(matches) == 0 {
+		return append(lines, "No requests appear in both files")
+	}
+
+	keyWidth := max(m.width-50, 30)
+	lines = append(lines, fmt.Sprintf("%-*s %-11s %12s %12s", keyWidth, "Request", "Status", "Time", "Size"))
+	selected := min(m.comparisonSelected, len(matches)-1)
+	offset := min(max(selected-comparisonRequestRows/2, 0), max(len(matches)-comparisonRequestRows, 0))
+	before, after := m.harFiles[baseline].Log.Entries, m.harFiles[target].Log.Entries
+	for i := offset; i < min(len(matches), offset+comparisonRequestRows); i++ {
+		match := matches[i]
+		status := fmt.Sprintf("%d", before[match.Before].Response.Status)
+		if changed := after[match.After].Response.Status; changed != before[match.Before].Response.Status {
+			status += fmt.Sprintf("→%d", changed)
+		}
+		line := fmt.Sprintf("%s %-11s %+10.1fms %12s", fitWidth(match.Key, keyWidth), status,
+			match.TimeDelta, signedSize(match.SizeDelta, m.settings.SizeUnits))
+		if i == selected {
+			line = timelineCursorStyle.Render(line)
+		}
+		lines = append(lines, line)
+	}
+	help := fmt.Sprintf("%d requests in both files (rows %d-%d). Press ↑/↓ to select one, Enter to diff it",
+		len(matches), offset+1, min(len(matches), offset+comparisonRequestRows))
+	if len(m.harFiles) > 2 {
+		help += ", Tab to compare another file"
+	}
+	return append(lines, statusStyle.Render(help))
+}
+
+// signedSize formats a size change with its sign.
+func signedSize(delta int, units har.SizeUnits) string {
+	if delta < 0 {
+		return "-" + units.FormatSize(-delta)
+	}
+	return "+" + units.FormatSize(delta)
+}
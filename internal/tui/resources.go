@@ -0,0 +1,42 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+)
+
+// resourceColors match the waterfall legend.
+var resourceColors = map[string]string{
+	"html": "12", "javascript": "11", "css": "10", "image": "13", "json": "14", "font": "8", "other": "7",
+}
+
+const resourceBarWidth = 20
+
+// renderResourceTypes lists requests, size and time per resource type with a
+// bar of each type's share of the bytes.
+func (m Model) renderResourceTypes() []string {
+	breakdown := m.analyzers[m.currentFile].ResourceBreakdown()
+	if len(breakdown) == 0 {
+		return nil
+	}
+
+	var totalSize int64
+	for _, stats := range breakdown {
+		totalSize += stats.Size
+	}
+
+	lines := []string{headerStyle.Render("Resource Types")}
+	lines = append(lines, fmt.Sprintf("%-11s %5s %10s %10s %11s  %s", "Type", "Reqs", "Size", "Avg Size", "Time", "Share of bytes"))
+	for _, stats := range breakdown {
+		share := 0.0
+		if totalSize > 0 {
+			share = float64(stats.Size) / float64(totalSize)
+		}
+		filled := int(share * resourceBarWidth)
+		bar := foreground(resourceColors[stats.Type]).Render(strings.Repeat("█", filled)) +
+			strings.Repeat("░", resourceBarWidth-filled)
+		lines = append(lines, fmt.Sprintf("%-11s %5d %10s %10s %9.1fms  %s %3.0f%%", stats.Type, stats.Requests,
+			m.settings.SizeUnits.FormatSize(int(stats.Size)), m.settings.SizeUnits.FormatSize(int(stats.AverageSize())), stats.Time, bar, share*100))
+	}
+	return lines
+}
@@ -0,0 +1,79 @@
+package tui
+
+import (
+	"fmt"
+	"github.com/jlgore/hartea/internal/audit"
+	"github.com/jlgore/hartea/internal/har"
+	"strings"
+)
+
+// renderSecurityView lists leaked secrets first, then the other audit
+// findings of the current file.
+func (m Model) renderSecurityView() string {
+	findings := audit.Audit([]*har.HAR{m.harFiles[m.currentFile]}, m.settings)
+
+	var secrets, others []audit.Finding
+	for _, finding := range findings {
+		if audit.IsSecretRule(finding.RuleID) {
+			secrets = append(secrets, finding)
+		} else {
+			others = append(others, finding)
+		}
+	}
+
+	content := []string{titleStyle.Render("Security Findings"), ""}
+
+	content = append(content, headerStyle.Render(fmt.Sprintf("Leaked Secrets (%d)", len(secrets))))
+	if len(secrets) == 0 {
+		content = append(content, "No credentials or tokens found in URLs, headers or bodies ✅")
+	}
+	content = append(content, m.renderFindings(secrets)...)
+	content = append(content, "")
+
+	content = append(content, m.renderHSTSReadiness()...)
+	content = append(content, "")
+
+	content = append(content, headerStyle.Render(fmt.Sprintf("Other Findings (%d)", len(others))))
+	content = append(content, m.renderFindings(others)...)
+	content = append(content, "")
+
+	content = append(content, statusStyle.Render("Export with e (SARIF or findings JSON). Press Esc to go back"))
+	return strings.Join(content, "\n")
+}
+
+// renderHSTSReadiness lists the preload readiness of the first-party hosts.
+func (m Model) renderHSTSReadiness() []string {
+	var hosts []har.HostHSTS
+	for _, host := range m.analyzers[m.currentFile].HSTSReadiness() {
+		if host.FirstParty {
+			hosts = append(hosts, host)
+		}
+	}
+
+	lines := []string{headerStyle.Render(fmt.Sprintf("HSTS Preload Readiness (%d first-party hosts)", len(hosts)))}
+	for _, host := range hosts {
+		status := "✅ preload-ready"
+		if !host.PreloadReady() {
+			status = "⚠ " + strings.Join(host.Problems, ", ")
+		}
+		lines = append(lines, fitWidth(host.Host, 30)+" "+status)
+		if host.Header != "" {
+			lines = append(lines, statusStyle.Render("        "+truncateValue(host.Header, max(m.width-10, 40))))
+		}
+		if host.RedirectVulnerable {
+			lines = append(lines, severityStyles[audit.SeverityWarning].Render(fmt.Sprintf(
+				"        first visit can be over HTTP unless %s is preloaded", har.RegistrableDomain(host.Host))))
+		}
+	}
+	return lines
+}
+
+func (m Model) renderFindings(findings []audit.Finding) []string {
+	var lines []string
+	for _, finding := range findings {
+		severity := severityStyles[finding.Severity].Render(fmt.Sprintf("%-7s", finding.Severity))
+		lines = append(lines, fmt.Sprintf("%s %s", severity, finding.Message))
+		lines = append(lines, statusStyle.Render(fmt.Sprintf("        #%d %s", finding.EntryIndex+1, truncateURL(finding.URL, max(m.width-20, 40)))))
+	}
+	return lines
+}
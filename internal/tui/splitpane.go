@@ -0,0 +1,71 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// splitSideBySideWidth is the terminal width from which the split pane puts
+// the details beside the table rather than below it.
+const splitSideBySideWidth = 160
+
+// toggleSplitPane shows or hides the highlighted request's details next to
+// the table.
+func (m *Model) toggleSplitPane() {
+	m.splitPane = !m.splitPane
+	m.resizeTable()
+}
+
+// splitSideBySide reports whether the split pane's details go to the right
+// of the table; on narrower terminals they go below it.
+func (m Model) splitSideBySide() bool {
+	return m.width >= splitSideBySideWidth
+}
+
+// tableWidth is the width the table gets: all of it, or three fifths when
+// the details share the row.
+func (m Model) tableWidth() int {
+	if m.splitPane && m.splitSideBySide() {
+		return m.width * 3 / 5
+	}
+	return m.width
+}
+
+// resizeTable fits the table to the window, leaving half of its rows to the
+// details when the split pane stacks them.
+func (m *Model) resizeTable() {
+	height := m.height - 10
+	if m.splitPane && !m.splitSideBySide() {
+		height /= 2
+	}
+	m.table.SetHeight(height)
+	m.setTableColumns()
+}
+
+// renderSplitPane draws the table with the highlighted request's details
+// beside or below it, clipped to the space left.
+func (m Model) renderSplitPane() string {
+	tableView := m.table.View()
+	if len(m.entries) == 0 {
+		return tableView
+	}
+
+	detail := m
+	detail.selectedEntry = m.table.Cursor()
+	detail.detailSelected = 0
+
+	if m.splitSideBySide() {
+		height := lipgloss.Height(tableView)
+		detail.width = max(m.width-lipgloss.Width(tableView)-3, 20)
+		detail.height = height + 10
+		pane := lipgloss.NewStyle().MaxWidth(detail.width).MaxHeight(height).Render(strings.Join(detail.detailLines(), "\n"))
+		divider := statusStyle.Render(strings.TrimSuffix(strings.Repeat("│\n", height), "\n"))
+		return lipgloss.JoinHorizontal(lipgloss.Top, tableView, " ", divider, " ", pane)
+	}
+
+	height := max(m.height-10-lipgloss.Height(tableView), 3)
+	detail.height = height + 10
+	pane := lipgloss.NewStyle().MaxWidth(m.width).MaxHeight(height).Render(strings.Join(detail.detailLines(), "\n"))
+	return tableView + "\n" + statusStyle.Render(strings.Repeat("─", max(m.width, 1))) + "\n" + pane
+}
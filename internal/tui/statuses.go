@@ -0,0 +1,137 @@
+package tui
+
+import (
+	"fmt"
+	"github.com/jlgore/hartea/internal/har"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// maxStatusDomains caps the hosts listed in the status view.
+const maxStatusDomains = 20
+
+var statusClassColors = map[string]string{"2xx": "10", "3xx": "11", "4xx": "9", "5xx": "9", "other": "8"}
+
+// statusRow is one line of the status view. Rows with a filter can be
+// selected, and Enter filters the table by it.
+type statusRow struct {
+	text   string
+	filter string
+}
+
+// statusRows lists the status classes with their codes, then a class
+// breakdown of the busiest hosts and any rate limiting.
+func (m Model) statusRows() []statusRow {
+	distribution := m.analyzers[m.currentFile].StatusDistribution()
+	if distribution.Requests == 0 {
+		return nil
+	}
+
+	var classes []string
+	for _, class := range har.StatusClasses {
+		if distribution.ByClass[class] > 0 {
+			classes = append(classes, class)
+		}
+	}
+
+	rows := []statusRow{{text: headerStyle.Render(fmt.Sprintf("By Status (%d requests)", distribution.Requests))}}
+	for _, class := range classes {
+		count := distribution.ByClass[class]
+		bar := foreground(statusClassColors[class]).
+			Render(fmt.Sprintf("%-30s", strings.Repeat("█", max(count*30/distribution.Requests, 1))))
+		rows = append(rows, statusRow{
+			text:   fmt.Sprintf("%-6s %s %5d %5.1f%%", class, bar, count, float64(count)/float64(distribution.Requests)*100),
+			filter: "status:" + class,
+		})
+		for _, code := range distribution.Codes(class) {
+			rows = append(rows, statusRow{
+				text:   fmt.Sprintf("  %-4d %-31s %5d", code, statusText(code), distribution.ByCode[code]),
+				filter: "status:" + strconv.Itoa(code),
+			})
+		}
+	}
+
+	rows = append(rows, statusRow{}, statusRow{text: headerStyle.Render("By Domain")})
+	heading := fmt.Sprintf("%-38s", "Domain")
+	for _, class := range classes {
+		heading += fmt.Sprintf(" %5s", class)
+	}
+	rows = append(rows, statusRow{text: heading + "  Errors"})
+	for _, domain := range distribution.Domains[:min(len(distribution.Domains), maxStatusDomains)] {
+		line := fitWidth(domain.Domain, 38)
+		for _, class := range classes {
+			if count := domain.ByClass[class]; count > 0 {
+				line += fmt.Sprintf(" %5d", count)
+			} else {
+				line += fmt.Sprintf(" %5s", "·")
+			}
+		}
+		var errors []string
+		for _, class := range []string{"4xx", "5xx"} {
+			for _, code := range domain.Codes(class) {
+				errors = append(errors, fmt.Sprintf("%d×%d", code, domain.ByCode[code]))
+			}
+		}
+		rows = append(rows, statusRow{text: line + "  " + strings.Join(errors, ", "), filter: domain.Domain})
+	}
+	if hidden := len(distribution.Domains) - maxStatusDomains; hidden > 0 {
+		rows = append(rows, statusRow{text: fmt.Sprintf("... and %d more domains", hidden)})
+	}
+	return append(rows, m.rateLimitRows()...)
+}
+
+func statusText(code int) string {
+	if code == 0 {
+		return "No response"
+	}
+	return http.StatusText(code)
+}
+
+// renderStatusView shows the status code breakdown with the selected row
+// highlighted, scrolling to keep it in view.
+func (m Model) renderStatusView() string {
+	content := []string{titleStyle.Render("Status Codes"), ""}
+	rows := m.statusRows()
+	if len(rows) == 0 {
+		content = append(content, "No requests to break down")
+		return strings.Join(content, "\n")
+	}
+
+	visible := max(m.height-8, 5)
+	offset := max(m.statusSelected-visible+1, 0)
+	for i, row := range rows[offset:min(len(rows), offset+visible)] {
+		text := row.text
+		if offset+i == m.statusSelected && row.filter != "" {
+			text = timelineCursorStyle.Render(text)
+		}
+		content = append(content, text)
+	}
+
+	content = append(content, "")
+	content = append(content, statusStyle.Render("Press ↑/↓ to select, Enter to filter the table by the status, class, domain or endpoint, Esc to go back"))
+	return strings.Join(content, "\n")
+}
+
+// moveStatusSelection moves to the next selectable row in the given direction.
+func (m *Model) moveStatusSelection(delta int) {
+	rows := m.statusRows()
+	for i := m.statusSelected + delta; i >= 0 && i < len(rows); i += delta {
+		if rows[i].filter != "" {
+			m.statusSelected = i
+			return
+		}
+	}
+}
+
+// filterByStatusSelection filters the table by the selected row.
+func (m *Model) filterByStatusSelection() {
+	rows := m.statusRows()
+	if m.statusSelected >= len(rows) || rows[m.statusSelected].filter == "" {
+		return
+	}
+	m.quickView = AllRequests
+	m.filter.SetValue(rows[m.statusSelected].filter)
+	m.filterEntries(rows[m.statusSelected].filter)
+	m.currentView = TableView
+}
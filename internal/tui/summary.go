@@ -0,0 +1,35 @@
+package tui
+
+import (
+	"fmt"
+	"github.com/jlgore/hartea/internal/har"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// renderGrade colours a letter grade; N/A stays plain.
+func renderGrade(grade string) string {
+	if style, ok := gradeStyles[grade]; ok {
+		return style.Render(grade)
+	}
+	return grade
+}
+
+// renderExecutiveSummary shows the current file's score and category grades,
+// then its top issues and biggest change against the baseline as one
+// wrapped paragraph.
+func (m Model) renderExecutiveSummary() []string {
+	summary := har.Summarize(m.metrics, m.comparison, m.comparisonIndex(m.currentFile), m.settings.SizeUnits)
+	paragraph := lipgloss.NewStyle().Width(max(m.width-4, 40)).Render(summary.Text)
+
+	score := fmt.Sprintf("Score %.0f/100  Grade %s", summary.Score, renderGrade(summary.Grade))
+	var categories []string
+	for _, category := range summary.Categories {
+		categories = append(categories, category.Name+" "+renderGrade(category.Grade))
+	}
+	if len(categories) > 0 {
+		score += "   " + strings.Join(categories, "  ")
+	}
+	return []string{headerStyle.Render("Summary"), score, paragraph}
+}
@@ -0,0 +1,130 @@
+package tui
+
+import (
+	"fmt"
+	"github.com/jlgore/hartea/internal/har"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbletea"
+)
+
+func newTagInput() textinput.Model {
+	input := textinput.New()
+	input.Placeholder = "auth api (prefix with - to remove)"
+	input.CharLimit = 128
+	return input
+}
+
+// openTagInput starts tagging the entry under the cursor (table) or on screen (details).
+func (m Model) openTagInput() (tea.Model, tea.Cmd) {
+	index := m.selectedEntry
+	if m.currentView == TableView {
+		index = m.table.Cursor()
+	}
+	if index < 0 || index >= len(m.entries) {
+		return m, nil
+	}
+
+	m.tagTarget = index
+	m.showTagInput = true
+	m.tagInput.SetValue("")
+	m.tagInput.Focus()
+	return m, textinput.Blink
+}
+
+func (m Model) updateTagInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.showTagInput = false
+		return m, nil
+	case "enter":
+		m.showTagInput = false
+		m.applyTagInput(m.tagInput.Value())
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.tagInput, cmd = m.tagInput.Update(msg)
+	return m, cmd
+}
+
+// applyTagInput adds each word as a tag, or removes it when prefixed with "-".
+// Tags are stored on the loaded HAR entry so they carry into exports.
+func (m *Model) applyTagInput(value string) {
+	if m.tagTarget >= len(m.entryIndices) {
+		return
+	}
+	entry := &m.harFiles[m.currentFile].Log.Entries[m.entryIndices[m.tagTarget]]
+
+	for _, word := range strings.FieldsFunc(value, func(r rune) bool { return r == ' ' || r == ',' }) {
+		word = strings.TrimPrefix(word, "tag:")
+		if tag, remove := strings.CutPrefix(word, "-"); remove {
+			entry.RemoveTag(tag)
+		} else {
+			entry.AddTag(word)
+		}
+	}
+
+	m.entries[m.tagTarget] = *entry
+	m.updateTableRows()
+}
+
+func (m Model) renderTagInput() string {
+	entry := m.entries[m.tagTarget]
+
+	lines := []string{
+		titleStyle.Render("Tag Request"),
+		"",
+		truncateURL(entry.Request.URL, max(m.width-4, 40)),
+		fmt.Sprintf("Current tags: %s", formatTags(entry.Tags)),
+		"",
+		m.tagInput.View(),
+		"",
+		statusStyle.Render("Enter to apply, Esc to cancel. Filter with tag:name"),
+	}
+	return strings.Join(lines, "\n")
+}
+
+func formatTags(tags []string) string {
+	if len(tags) == 0 {
+		return "none"
+	}
+	return strings.Join(tags, ", ")
+}
+
+// renderTagSummary groups the current file's entries by tag.
+func (m Model) renderTagSummary() []string {
+	byTag := m.analyzers[m.currentFile].GetEntriesByTag()
+	if len(byTag) == 0 {
+		return nil
+	}
+
+	tags := make([]string, 0, len(byTag))
+	for tag := range byTag {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+
+	lines := []string{headerStyle.Render("Tags")}
+	for _, tag := range tags {
+		var totalTime float64
+		var totalSize int
+		for _, entry := range byTag[tag] {
+			totalTime += entry.Time
+			totalSize += entry.Response.Content.Size
+		}
+		lines = append(lines, fmt.Sprintf("%-16s %4d requests  %10.1fms  %10s", tag, len(byTag[tag]), totalTime, m.settings.SizeUnits.FormatSize(totalSize)))
+	}
+	return lines
+}
+
+// identityIndices maps every entry of a file to itself, for unfiltered views.
+func identityIndices(entries []har.Entry) []int {
+	indices := make([]int, len(entries))
+	for i := range indices {
+		indices[i] = i
+	}
+	return indices
+}
@@ -0,0 +1,180 @@
+package tui
+
+import (
+	"github.com/jlgore/hartea/internal/audit"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Theme recolors the TUI. Styles are written with the default theme's
+// terminal colors and a theme maps each of them to its own; colors it does
+// not map stay as they are.
+type Theme struct {
+	Name       string
+	colors     map[string]string
+	monochrome bool // drop every color, leaving bold, reverse and borders
+}
+
+// Themes are the selectable themes, the default first.
+var Themes = []Theme{
+	{Name: "default"},
+	{Name: "light", colors: map[string]string{
+		"7": "240", "8": "245", "9": "160", "10": "28", "11": "130", "12": "26", "13": "127", "14": "30",
+		"46": "28", "148": "64", "220": "136", "208": "166", "196": "160",
+		"86": "30", "205": "162", "214": "166", "242": "244",
+	}},
+	{Name: "solarized", colors: map[string]string{
+		"0": "#002b36", "7": "#93a1a1", "8": "#586e75", "9": "#dc322f", "10": "#859900", "11": "#b58900",
+		"12": "#268bd2", "13": "#d33682", "14": "#2aa198", "15": "#fdf6e3",
+		"46": "#2aa198", "148": "#859900", "220": "#b58900", "208": "#cb4b16", "196": "#dc322f",
+		"57": "#268bd2", "86": "#2aa198", "205": "#d33682", "214": "#cb4b16", "229": "#fdf6e3", "240": "#586e75", "242": "#586e75",
+	}},
+	{Name: "monochrome", monochrome: true},
+	// Okabe-Ito colors, which stay distinct with the common color vision
+	// deficiencies: good is blue and bad is vermillion rather than green and
+	// red
+	{Name: "colorblind", colors: map[string]string{
+		"7": "#bbbbbb", "8": "#888888", "9": "#d55e00", "10": "#56b4e9", "11": "#f0e442", "12": "#0072b2",
+		"13": "#cc79a7", "14": "#009e73",
+		"46": "#0072b2", "148": "#56b4e9", "220": "#f0e442", "208": "#e69f00", "196": "#d55e00",
+		"86": "#56b4e9", "205": "#cc79a7", "214": "#e69f00", "242": "#888888",
+	}},
+}
+
+// theme is the active theme; applyTheme changes it.
+var theme Theme
+
+var (
+	titleStyle          lipgloss.Style
+	headerStyle         lipgloss.Style
+	statusStyle         lipgloss.Style
+	timelineCursorStyle lipgloss.Style
+	focusedStyle        lipgloss.Style
+	toastSuccessStyle   lipgloss.Style
+	toastErrorStyle     lipgloss.Style
+	tutorialBoxStyle    lipgloss.Style
+
+	activeTabStyle lipgloss.Style
+	tabStyle       lipgloss.Style
+	paramFlagStyle lipgloss.Style
+
+	concurrencyStyle     lipgloss.Style
+	queuedStyle          lipgloss.Style
+	throughputStyle      lipgloss.Style
+	stallStyle           lipgloss.Style
+	histogramStyle       lipgloss.Style
+	heatmapDegradedStyle lipgloss.Style
+	endpointErrorStyle   lipgloss.Style
+	cookieIssueStyle     lipgloss.Style
+
+	diffChangedStyle lipgloss.Style
+	diffRemovedStyle lipgloss.Style
+	diffAddedStyle   lipgloss.Style
+
+	severityStyles map[audit.Severity]lipgloss.Style
+	// gradeStyles colour letter grades from green to red.
+	gradeStyles map[string]lipgloss.Style
+)
+
+func init() {
+	applyTheme(Themes[0])
+}
+
+// ThemeByName finds a theme of Themes.
+func ThemeByName(name string) (Theme, bool) {
+	for _, t := range Themes {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return Theme{}, false
+}
+
+// nextTheme is the theme after the active one, wrapping around.
+func nextTheme() Theme {
+	for i, t := range Themes {
+		if t.Name == theme.Name {
+			return Themes[(i+1)%len(Themes)]
+		}
+	}
+	return Themes[0]
+}
+
+// themeColor is the active theme's color for one of the default theme's.
+func themeColor(color string) lipgloss.Color {
+	if theme.monochrome {
+		return lipgloss.Color("")
+	}
+	if mapped, ok := theme.colors[color]; ok {
+		return lipgloss.Color(mapped)
+	}
+	return lipgloss.Color(color)
+}
+
+// foreground is a style with only a themed foreground color.
+func foreground(color string) lipgloss.Style {
+	return lipgloss.NewStyle().Foreground(themeColor(color))
+}
+
+// applyTheme makes t the active theme and rebuilds the shared styles with it.
+func applyTheme(t Theme) {
+	theme = t
+
+	titleStyle = lipgloss.NewStyle().Bold(true).Foreground(themeColor("205"))
+	headerStyle = lipgloss.NewStyle().Bold(true).Foreground(themeColor("86"))
+	statusStyle = foreground("242")
+	timelineCursorStyle = lipgloss.NewStyle().Reverse(true)
+	focusedStyle = lipgloss.NewStyle().Bold(true).Foreground(themeColor("205"))
+	toastSuccessStyle = lipgloss.NewStyle().Foreground(themeColor("0")).Background(themeColor("10")).Padding(0, 1)
+	toastErrorStyle = lipgloss.NewStyle().Foreground(themeColor("15")).Background(themeColor("9")).Padding(0, 1)
+	if t.monochrome {
+		toastSuccessStyle = toastSuccessStyle.Reverse(true)
+		toastErrorStyle = toastErrorStyle.Reverse(true).Bold(true)
+	}
+	tutorialBoxStyle = lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(themeColor("205")).
+		Padding(0, 1)
+
+	activeTabStyle = lipgloss.NewStyle().Bold(true).Reverse(true).Padding(0, 1)
+	tabStyle = lipgloss.NewStyle().Padding(0, 1)
+	paramFlagStyle = foreground("214")
+
+	concurrencyStyle = foreground("14")
+	queuedStyle = foreground("9")
+	throughputStyle = foreground("10")
+	stallStyle = foreground("11")
+	histogramStyle = foreground("12")
+	heatmapDegradedStyle = foreground("214")
+	endpointErrorStyle = foreground("9")
+	cookieIssueStyle = foreground("214")
+
+	diffChangedStyle = foreground("11")
+	diffRemovedStyle = foreground("9")
+	diffAddedStyle = foreground("10")
+
+	severityStyles = map[audit.Severity]lipgloss.Style{
+		audit.SeverityError:   foreground("9"),
+		audit.SeverityWarning: foreground("214"),
+		audit.SeverityNote:    foreground("242"),
+	}
+	gradeStyles = map[string]lipgloss.Style{
+		"A": foreground("10").Bold(true),
+		"B": foreground("10").Bold(true),
+		"C": foreground("11").Bold(true),
+		"D": foreground("214").Bold(true),
+		"F": foreground("9").Bold(true),
+	}
+}
+
+// tableStyles are the request table's styles in the active theme.
+func tableStyles() table.Styles {
+	styles := table.DefaultStyles()
+	styles.Header = styles.Header.BorderForeground(themeColor("240"))
+	styles.Selected = styles.Selected.Foreground(themeColor("229")).Background(themeColor("57"))
+	if theme.monochrome {
+		styles.Selected = styles.Selected.Reverse(true)
+	}
+	return styles
+}
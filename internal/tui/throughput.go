@@ -0,0 +1,90 @@
+package tui
+
+import (
+	"fmt"
+	"github.com/jlgore/hartea/internal/har"
+	"strings"
+	"time"
+)
+
+// maxLongTail caps the long-tail downloads listed below the chart.
+const maxLongTail = 5
+
+// renderThroughputView plots the bytes received over the capture, marking
+// stalls where requests were in flight but nothing arrived, and lists the
+// slowest downloads.
+func (m Model) renderThroughputView() string {
+	chartWidth := max(m.width-12, 20)
+	analyzer := m.analyzers[m.currentFile]
+	profile := analyzer.Throughput(chartWidth)
+
+	content := []string{titleStyle.Render("Bandwidth over Time"), ""}
+	if profile.Total == 0 {
+		content = append(content, "No response bytes to chart")
+		return strings.Join(content, "\n")
+	}
+
+	peakSlice := int(profile.PeakAt / profile.Slice)
+	capture := profile.Slice * time.Duration(len(profile.Bytes))
+	content = append(content, fmt.Sprintf("Received: %s in %s | Peak: %s at %s | Average: %s",
+		m.settings.SizeUnits.FormatSize(int(profile.Total)), formatOffset(capture),
+		formatRate(profile.Rate(peakSlice), m.settings.SizeUnits), formatOffset(profile.PeakAt),
+		formatRate(float64(profile.Total)/capture.Seconds(), m.settings.SizeUnits)))
+	if profile.StallTime > 0 {
+		content = append(content, fmt.Sprintf("Stalled: %s with requests in flight but no bytes received", formatOffset(profile.StallTime)))
+	}
+	content = append(content, "")
+
+	values := make([]float64, len(profile.Bytes))
+	for i := range profile.Bytes {
+		values[i] = profile.Rate(i)
+	}
+	content = append(content, renderBarChart(values, formatRate(profile.Rate(peakSlice), m.settings.SizeUnits), throughputStyle)...)
+
+	if profile.StallTime > 0 {
+		var stalls strings.Builder
+		for i, bytes := range profile.Bytes {
+			if profile.Busy[i] && bytes == 0 {
+				stalls.WriteRune('·')
+			} else {
+				stalls.WriteRune(' ')
+			}
+		}
+		content = append(content, "         "+stallStyle.Render(stalls.String()))
+	}
+	content = append(content, renderTimeAxis(len(values), capture))
+	if profile.StallTime > 0 {
+		content = append(content, "")
+		content = append(content, stallStyle.Render("·")+" stalled: requests in flight, nothing received")
+	}
+
+	if len(profile.LongTail) > 0 {
+		entries := m.harFiles[m.currentFile].Log.Entries
+		content = append(content, "")
+		content = append(content, headerStyle.Render(fmt.Sprintf("Long-tail Downloads (receiving for %s or more)", formatOffset(har.LongReceive))))
+		for _, i := range profile.LongTail[:min(len(profile.LongTail), maxLongTail)] {
+			entry := entries[i]
+			content = append(content, fmt.Sprintf("  %8dms  %9s  %s", entry.Timings.Receive,
+				m.settings.SizeUnits.FormatSize(har.TransferSize(entry)), truncateURL(entry.Request.URL, max(m.width-26, 30))))
+		}
+		if hidden := len(profile.LongTail) - maxLongTail; hidden > 0 {
+			content = append(content, fmt.Sprintf("  ... and %d more", hidden))
+		}
+	}
+
+	content = append(content, "")
+	content = append(content, statusStyle.Render("Press Esc to go back"))
+	return strings.Join(content, "\n")
+}
+
+// formatRate renders bytes per second compactly enough for a chart's y axis.
+func formatRate(rate float64, units har.SizeUnits) string {
+	base := units.Base()
+	switch {
+	case rate >= base*base:
+		return fmt.Sprintf("%.1f%s/s", units.Megabytes(rate), units.MegabyteUnit())
+	case rate >= base:
+		return fmt.Sprintf("%.0f%s/s", units.Kilobytes(rate), units.KilobyteUnit())
+	}
+	return fmt.Sprintf("%.0fB/s", rate)
+}
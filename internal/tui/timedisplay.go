@@ -0,0 +1,138 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+)
+
+// TimeDisplay is how the table, detail view and timeline show when requests
+// started.
+type TimeDisplay int
+
+const (
+	// FromPageStart shows offsets from the page start, or the first request
+	// when the capture has no page
+	FromPageStart TimeDisplay = iota
+	// FromFirstRequest shows offsets from the earliest request
+	FromFirstRequest
+	// AbsoluteTime shows clock times, local or UTC
+	AbsoluteTime
+)
+
+func (d TimeDisplay) String() string {
+	switch d {
+	case FromFirstRequest:
+		return "from the first request"
+	case AbsoluteTime:
+		return "clock time"
+	default:
+		return "from the page start"
+	}
+}
+
+// clockFormat shows clock times to the millisecond; captures rarely span a
+// day, and the detail view gives the date.
+const clockFormat = "15:04:05.000"
+
+// timeFormat formats start times the way the current TimeDisplay asks for.
+type timeFormat struct {
+	display TimeDisplay
+	// origin is the page start or first request offsets count from
+	origin time.Time
+	utc    bool
+}
+
+// timeFormat is the current file's time format.
+func (m Model) timeFormat() timeFormat {
+	f := timeFormat{display: m.timeDisplay, utc: m.utc}
+	analyzer := m.analyzers[m.currentFile]
+	if m.timeDisplay != FromFirstRequest {
+		f.origin = analyzer.PageStart()
+		return f
+	}
+	for _, entry := range m.harFiles[m.currentFile].Log.Entries {
+		if f.origin.IsZero() || entry.StartedDateTime.Before(f.origin) {
+			f.origin = entry.StartedDateTime
+		}
+	}
+	return f
+}
+
+// clock converts a time to the configured zone.
+func (f timeFormat) clock(t time.Time) time.Time {
+	if f.utc {
+		return t.UTC()
+	}
+	return t.Local()
+}
+
+func (f timeFormat) offset(t time.Time) float64 {
+	return float64(t.Sub(f.origin)) / float64(time.Millisecond)
+}
+
+// format shows a start time on its own, e.g. +120.5ms or 14:03:07.250.
+func (f timeFormat) format(t time.Time) string {
+	if f.display == AbsoluteTime {
+		return f.clock(t).Format(clockFormat)
+	}
+	return fmt.Sprintf("+%.1fms", f.offset(t))
+}
+
+// describe shows a start time in full for the detail view, with the offset
+// from the page start alongside a clock time.
+func (f timeFormat) describe(t time.Time) string {
+	switch f.display {
+	case AbsoluteTime:
+		zone := "local time"
+		if f.utc {
+			zone = "UTC"
+		}
+		return fmt.Sprintf("%s (%s)", f.clock(t).Format("2006-01-02 "+clockFormat), zone)
+	case FromFirstRequest:
+		return fmt.Sprintf("+%.1fms after the first request", f.offset(t))
+	default:
+		return fmt.Sprintf("+%.1fms after the page start", f.offset(t))
+	}
+}
+
+// scaleLabel labels a point of the timeline scale, ms after start.
+func (f timeFormat) scaleLabel(start time.Time, ms float64) string {
+	at := start.Add(time.Duration(ms * float64(time.Millisecond)))
+	if f.display == AbsoluteTime {
+		return f.clock(at).Format(clockFormat)
+	}
+	return fmt.Sprintf("%.0fms", f.offset(at))
+}
+
+// column is the table's start column.
+func (f timeFormat) column() table.Column {
+	switch f.display {
+	case FromFirstRequest:
+		return table.Column{Title: "After 1st (ms)", Width: 14}
+	case AbsoluteTime:
+		if f.utc {
+			return table.Column{Title: "Started (UTC)", Width: 13}
+		}
+		return table.Column{Title: "Started", Width: 13}
+	default:
+		return table.Column{Title: "Start (ms)", Width: 10}
+	}
+}
+
+// cell is a start time in the table's start column.
+func (f timeFormat) cell(t time.Time) string {
+	if f.display == AbsoluteTime {
+		return f.clock(t).Format(clockFormat)
+	}
+	return fmt.Sprintf("%.1f", f.offset(t))
+}
+
+// cycleTimeDisplay switches between offsets from the page start, offsets
+// from the first request and clock times.
+func (m *Model) cycleTimeDisplay() {
+	m.timeDisplay = (m.timeDisplay + 1) % (AbsoluteTime + 1)
+	m.setTableColumns()
+	m.updateTableRows()
+}
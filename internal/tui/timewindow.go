@@ -0,0 +1,170 @@
+package tui
+
+import (
+	"fmt"
+	"github.com/jlgore/hartea/internal/har"
+	"time"
+
+	"github.com/charmbracelet/bubbletea"
+)
+
+// timeWindow keeps the requests that started between start and end, both
+// included. A zero start or end leaves that side open.
+type timeWindow struct {
+	start time.Time
+	end   time.Time
+}
+
+func (w timeWindow) active() bool {
+	return !w.start.IsZero() || !w.end.IsZero()
+}
+
+func (w timeWindow) contains(t time.Time) bool {
+	return (w.start.IsZero() || !t.Before(w.start)) && (w.end.IsZero() || !t.After(w.end))
+}
+
+// setWindowBound starts or ends the time window when the highlighted
+// timeline bar started.
+func (m *Model) setWindowBound(end bool) tea.Cmd {
+	entries := m.harFiles[m.currentFile].Log.Entries
+	if m.timelineSelected >= len(entries) {
+		return nil
+	}
+	at := entries[m.timelineSelected].StartedDateTime
+	if end {
+		m.window.end = at
+	} else {
+		m.window.start = at
+	}
+	if !m.window.start.IsZero() && !m.window.end.IsZero() && m.window.start.After(m.window.end) {
+		m.window.start, m.window.end = m.window.end, m.window.start
+	}
+	m.updateScope()
+	m.filterEntries(m.filter.Value())
+	return m.showToast(m.windowTitle(), false)
+}
+
+// clearWindow shows the whole file again.
+func (m *Model) clearWindow() {
+	m.window = timeWindow{}
+	m.updateScope()
+	m.filterEntries(m.filter.Value())
+}
+
+// updateScope collects the current file's requests within the time window,
+// or those the table lists when scoped to the filter, and recomputes the
+// metrics over them.
+func (m *Model) updateScope() {
+	m.scopeFile, m.scopeAnalyzer = nil, nil
+	source := m.harFiles[m.currentFile]
+	switch {
+	case m.filterScoped():
+		// The table's entries are already limited to the time window
+		m.scopeFile = &har.HAR{Log: source.Log}
+		m.scopeFile.Log.Entries = m.entries
+	case m.window.active():
+		m.scopeFile = &har.HAR{Log: source.Log}
+		m.scopeFile.Log.Entries = nil
+		for _, entry := range source.Log.Entries {
+			if m.window.contains(entry.StartedDateTime) {
+				m.scopeFile.Log.Entries = append(m.scopeFile.Log.Entries, entry)
+			}
+		}
+	}
+	if m.scopeFile != nil {
+		m.scopeAnalyzer = har.NewAnalyzer(m.scopeFile, m.settings)
+	}
+	m.metrics = m.scopedAnalyzer().CalculateMetrics()
+}
+
+// scopedAnalyzer analyzes the current file's requests within the time
+// window or filter scope, or all of them without one.
+func (m Model) scopedAnalyzer() *har.Analyzer {
+	if m.scopeAnalyzer != nil {
+		return m.scopeAnalyzer
+	}
+	return m.analyzers[m.currentFile]
+}
+
+// scopedFile is a file with its analyzer, limited to the time window or
+// filter scope for the current file.
+func (m Model) scopedFile(file int) (*har.HAR, *har.Analyzer) {
+	if file == m.currentFile && m.scopeFile != nil {
+		return m.scopeFile, m.scopeAnalyzer
+	}
+	return m.harFiles[file], m.analyzers[file]
+}
+
+// filterActive reports whether the filter or a quick view narrows the table.
+func (m Model) filterActive() bool {
+	return m.filter.Value() != "" || m.quickView != AllRequests
+}
+
+// filterScoped reports whether the metrics, timeline and exports cover only
+// the requests the table lists.
+func (m Model) filterScoped() bool {
+	return m.scopeToFilter && m.filterActive()
+}
+
+// toggleFilterScope switches the metrics, timeline and exports between the
+// requests the filter shows and the whole file (or time window).
+func (m *Model) toggleFilterScope() tea.Cmd {
+	m.scopeToFilter = !m.scopeToFilter
+	m.updateScope()
+	switch {
+	case !m.scopeToFilter:
+		return m.showToast("Metrics, timeline and exports cover every request", false)
+	case !m.filterActive():
+		return m.showToast("Metrics, timeline and exports will follow the filter", false)
+	}
+	return m.showToast(m.scopeTitle(), false)
+}
+
+// scopeTitle describes the filter scope while it narrows the analysis.
+func (m Model) scopeTitle() string {
+	if !m.filterScoped() {
+		return ""
+	}
+	return fmt.Sprintf("Filtered: metrics, timeline and exports cover the %d requests shown (%s for all)", len(m.entries), m.keys.Scope.Help().Key)
+}
+
+// scopedTimeline is the current file's timeline, limited to the requests the
+// table lists when scoped to the filter.
+func (m Model) scopedTimeline() []har.TimelineEvent {
+	if !m.filterScoped() {
+		return m.timeline
+	}
+	shown := make(map[int]bool, len(m.entryIndices))
+	for _, index := range m.entryIndices {
+		shown[index] = true
+	}
+	var events []har.TimelineEvent
+	for _, event := range m.timeline {
+		if shown[event.Index] {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// windowTitle describes the time window in the current time format.
+func (m Model) windowTitle() string {
+	if !m.window.active() {
+		return ""
+	}
+	times := m.timeFormat()
+	from, to := "the start", "the end"
+	if !m.window.start.IsZero() {
+		from = times.format(m.window.start)
+	}
+	if !m.window.end.IsZero() {
+		to = times.format(m.window.end)
+	}
+	count := 0
+	for _, entry := range m.harFiles[m.currentFile].Log.Entries {
+		if m.window.contains(entry.StartedDateTime) {
+			count++
+		}
+	}
+	return fmt.Sprintf("Time window: requests started from %s to %s (%d requests)", from, to, count)
+}
@@ -0,0 +1,146 @@
+package tui
+
+import (
+	"fmt"
+	"github.com/jlgore/hartea/internal/har"
+	"strings"
+)
+
+type timingPhase struct {
+	name   string
+	value  int
+	median int
+	color  string
+}
+
+func timingPhases(entry har.Entry, median har.Timings) []timingPhase {
+	return []timingPhase{
+		{"Blocked", max(entry.Timings.Blocked, 0), median.Blocked, "8"},
+		{"DNS", max(entry.Timings.DNS, 0), median.DNS, "14"},
+		{"Connect", max(entry.Timings.Connect, 0), median.Connect, "11"},
+		{"SSL", max(entry.Timings.SSL, 0), median.SSL, "13"},
+		{"Send", max(entry.Timings.Send, 0), median.Send, "12"},
+		{"Wait", max(entry.Timings.Wait, 0), median.Wait, "10"},
+		{"Receive", max(entry.Timings.Receive, 0), median.Receive, "9"},
+	}
+}
+
+// renderStackedTimingBar draws all phases as one horizontal bar scaled to width.
+func renderStackedTimingBar(phases []timingPhase, width int) string {
+	total := 0
+	for _, phase := range phases {
+		total += phase.value
+	}
+	if total == 0 || width <= 0 {
+		return statusStyle.Render(strings.Repeat("·", max(width, 0)))
+	}
+
+	var bar strings.Builder
+	used := 0
+	for i, phase := range phases {
+		if phase.value == 0 {
+			continue
+		}
+		segment := phase.value * width / total
+		if segment == 0 {
+			segment = 1
+		}
+		if i == len(phases)-1 || used+segment > width {
+			segment = max(width-used, 0)
+		}
+		used += segment
+		bar.WriteString(foreground(phase.color).Render(strings.Repeat("█", segment)))
+	}
+	if used < width {
+		bar.WriteString(strings.Repeat(" ", width-used))
+	}
+	return bar.String()
+}
+
+func renderTimingLegend(phases []timingPhase) string {
+	var parts []string
+	for _, phase := range phases {
+		if phase.value == 0 {
+			continue
+		}
+		swatch := foreground(phase.color).Render("█")
+		parts = append(parts, fmt.Sprintf("%s %s %dms", swatch, phase.name, phase.value))
+	}
+	return strings.Join(parts, "  ")
+}
+
+// renderTimingBreakdown renders each phase as its own bar next to the host median.
+func renderTimingBreakdown(entry har.Entry, median har.Timings, width int) []string {
+	phases := timingPhases(entry, median)
+
+	longest := 1
+	for _, phase := range phases {
+		longest = max(longest, phase.value, phase.median)
+	}
+
+	barWidth := width - 40
+	if barWidth < 10 {
+		barWidth = 10
+	}
+
+	var lines []string
+	lines = append(lines, renderStackedTimingBar(phases, barWidth+20))
+	lines = append(lines, renderTimingLegend(phases))
+	lines = append(lines, "")
+	lines = append(lines, statusStyle.Render(fmt.Sprintf("%-9s %-*s %8s %8s %9s", "Phase", barWidth, "This request ▌ host median", "ms", "median", "delta")))
+
+	for _, phase := range phases {
+		length := phase.value * barWidth / longest
+		medianPos := phase.median * barWidth / longest
+		if medianPos >= barWidth {
+			medianPos = barWidth - 1
+		}
+
+		cells := make([]rune, barWidth)
+		for i := range cells {
+			cells[i] = ' '
+			if i < length {
+				cells[i] = '█'
+			}
+		}
+		if phase.median > 0 {
+			cells[medianPos] = '▌'
+		}
+
+		delta := ""
+		if diff := phase.value - phase.median; diff > 0 {
+			delta = foreground("9").Render(fmt.Sprintf("%+8dms", diff))
+		} else if diff < 0 {
+			delta = foreground("10").Render(fmt.Sprintf("%+8dms", diff))
+		} else {
+			delta = statusStyle.Render(fmt.Sprintf("%10s", "="))
+		}
+
+		bar := foreground(phase.color).Render(string(cells))
+		lines = append(lines, fmt.Sprintf("%-9s %s %6dms %6dms %s", phase.name, bar, phase.value, phase.median, delta))
+	}
+
+	return lines
+}
+
+func (m Model) renderTimingView() string {
+	if m.selectedEntry >= len(m.entries) {
+		return "No entry selected"
+	}
+
+	entry := m.entries[m.selectedEntry]
+	host := har.EntryHost(entry)
+	median := m.analyzers[m.currentFile].GetHostMedianTimings(host)
+
+	var content []string
+	content = append(content, titleStyle.Render("Request Timing Breakdown"))
+	content = append(content, "")
+	content = append(content, fmt.Sprintf("%s %s", entry.Request.Method, truncateURL(entry.Request.URL, max(m.width-10, 40))))
+	content = append(content, fmt.Sprintf("Total Time: %.1fms | Host: %s (median of all requests to this host)", entry.Time, host))
+	content = append(content, "")
+	content = append(content, renderTimingBreakdown(entry, median, max(m.width, 80))...)
+	content = append(content, "")
+	content = append(content, statusStyle.Render("Press Esc to go back to request details"))
+
+	return strings.Join(content, "\n")
+}
@@ -0,0 +1,68 @@
+package tui
+
+import (
+	"fmt"
+	"github.com/jlgore/hartea/internal/har"
+	"strings"
+)
+
+// trendSparklineWidth caps the sparkline; longer series are sampled.
+const trendSparklineWidth = 40
+
+// renderTrendView plots every metric across the loaded files in
+// chronological order, e.g. a series of nightly captures.
+func (m Model) renderTrendView() string {
+	content := []string{titleStyle.Render(fmt.Sprintf("Trends across %d files (oldest to newest)", len(m.harFiles))), ""}
+	if len(m.harFiles) < 2 {
+		content = append(content, "Load two or more captures to see trends", "", statusStyle.Render("Press Esc to go back"))
+		return strings.Join(content, "\n")
+	}
+
+	order := har.ChronologicalOrder(m.analyzers)
+	first, last := order[0], order[len(order)-1]
+	content = append(content, fmt.Sprintf("From %s (%s) to %s (%s)",
+		m.fileNames[first], m.analyzers[first].PageStart().Format("2006-01-02 15:04"),
+		m.fileNames[last], m.analyzers[last].PageStart().Format("2006-01-02 15:04")), "")
+
+	content = append(content, headerStyle.Render(fmt.Sprintf("%-24s %-*s %12s %12s %12s %12s", "Metric", trendSparklineWidth, "Trend", "First", "Last", "Min", "Max")))
+	for _, trend := range har.Trends(m.fileNames, m.analyzers) {
+		descriptor, _ := har.LookupMetric(trend.ID)
+		values := trend.Values()
+		low, high := values[0], values[0]
+		for _, value := range values {
+			low, high = min(low, value), max(high, value)
+		}
+		content = append(content, fmt.Sprintf("%-24s %-*s %12s %12s %12s %12s", trend.Name, trendSparklineWidth, sparkline(values, trendSparklineWidth),
+			descriptor.Format(values[0], m.settings.SizeUnits), descriptor.Format(values[len(values)-1], m.settings.SizeUnits), descriptor.Format(low, m.settings.SizeUnits), descriptor.Format(high, m.settings.SizeUnits)))
+	}
+
+	content = append(content, "")
+	content = append(content, statusStyle.Render("Files are ordered by page start time. Export trends-csv or trends-json for charts. Press Esc to go back"))
+	return strings.Join(content, "\n")
+}
+
+// sparkline draws values as bars scaled between their minimum and maximum,
+// sampling them down to width.
+func sparkline(values []float64, width int) string {
+	if len(values) > width {
+		sampled := make([]float64, width)
+		for i := range sampled {
+			sampled[i] = values[i*(len(values)-1)/(width-1)]
+		}
+		values = sampled
+	}
+
+	low, high := values[0], values[0]
+	for _, value := range values {
+		low, high = min(low, value), max(high, value)
+	}
+	var line strings.Builder
+	for _, value := range values {
+		level := 0
+		if high > low {
+			level = int((value - low) / (high - low) * float64(len(sparklineLevels)-1))
+		}
+		line.WriteRune(sparklineLevels[level])
+	}
+	return line.String()
+}
@@ -0,0 +1,166 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type tutorialStep struct {
+	view  ViewMode
+	title string
+	body  func(m Model) []string
+}
+
+type Tutorial struct {
+	active bool
+	step   int
+}
+
+func (m Model) tutorialSteps() []tutorialStep {
+	steps := []tutorialStep{
+		{
+			view:  TableView,
+			title: "Request Table",
+			body: func(m Model) []string {
+				return []string{
+					fmt.Sprintf("This capture contains %d requests, one per row.", len(m.entries)),
+					fmt.Sprintf("Use %s and %s to move, %s to filter by URL, method or type.", m.keys.Up.Help().Key, m.keys.Down.Help().Key, m.keys.Filter.Help().Key),
+				}
+			},
+		},
+		{
+			view:  DetailView,
+			title: "Request Details",
+			body: func(m Model) []string {
+				lines := []string{m.keys.Enter.Help().Key + " on a row opens its headers, response and timing breakdown."}
+				if m.selectedEntry < len(m.entries) {
+					lines = append(lines, fmt.Sprintf("Showing: %s %s",
+						m.entries[m.selectedEntry].Request.Method,
+						truncateURL(m.entries[m.selectedEntry].Request.URL, 50)))
+				}
+				return lines
+			},
+		},
+		{
+			view:  MetricsView,
+			title: "Performance Metrics",
+			body: func(m Model) []string {
+				lines := []string{fmt.Sprintf("Press %s for TTFB, load time, network, cache and size analysis.", m.keys.Metrics.Help().Key)}
+				if m.metrics != nil {
+					lines = append(lines, fmt.Sprintf("This file: TTFB %.1fms, load time %.1fms.",
+						m.metrics.TTFB, m.metrics.PageLoadTime))
+				}
+				return lines
+			},
+		},
+		{
+			view:  TimelineView,
+			title: "Timeline",
+			body: func(m Model) []string {
+				return []string{
+					fmt.Sprintf("Press %s for a DevTools-style waterfall of every request.", m.keys.Timeline.Help().Key),
+					"Bars are colored by content type; the legend is at the bottom.",
+				}
+			},
+		},
+	}
+
+	if len(m.harFiles) > 1 {
+		steps = append(steps, tutorialStep{
+			view:  ComparisonView,
+			title: "Comparison",
+			body: func(m Model) []string {
+				return []string{
+					fmt.Sprintf("Press %s to compare the other loaded files against the baseline, %s.", m.keys.Comparison.Help().Key, m.fileNames[m.baselineFile()]),
+					fmt.Sprintf("In the comparison, %s makes the current file the baseline.", m.keys.Baseline.Help().Key),
+					m.keys.Tab.Help().Key + " switches the file shown in the other views.",
+				}
+			},
+		})
+	}
+
+	return steps
+}
+
+func (m *Model) startTutorial() {
+	m.tutorial = Tutorial{active: true}
+	m.showFilter = false
+	m.applyTutorialStep()
+}
+
+func (m *Model) applyTutorialStep() {
+	steps := m.tutorialSteps()
+	if m.tutorial.step >= len(steps) {
+		m.stopTutorial()
+		return
+	}
+	step := steps[m.tutorial.step]
+	if step.view == DetailView {
+		m.selectedEntry = m.table.Cursor()
+	}
+	m.currentView = step.view
+}
+
+func (m *Model) nextTutorialStep() {
+	m.tutorial.step++
+	m.applyTutorialStep()
+}
+
+func (m *Model) prevTutorialStep() {
+	if m.tutorial.step > 0 {
+		m.tutorial.step--
+	}
+	m.applyTutorialStep()
+}
+
+func (m *Model) stopTutorial() {
+	m.tutorial = Tutorial{}
+	m.currentView = TableView
+	markTutorialSeen()
+}
+
+func (m Model) renderTutorialOverlay() string {
+	steps := m.tutorialSteps()
+	if m.tutorial.step >= len(steps) {
+		return ""
+	}
+	step := steps[m.tutorial.step]
+
+	var lines []string
+	lines = append(lines, headerStyle.Render(fmt.Sprintf("Tutorial %d/%d: %s", m.tutorial.step+1, len(steps), step.title)))
+	lines = append(lines, step.body(m)...)
+	lines = append(lines, "")
+	lines = append(lines, statusStyle.Render(fmt.Sprintf("Enter/space next, backspace previous, Esc to dismiss, %s to replay later", m.keys.Tutorial.Help().Key)))
+
+	return tutorialBoxStyle.Render(strings.Join(lines, "\n"))
+}
+
+func tutorialMarkerPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "hartea", "tutorial_seen")
+}
+
+func tutorialSeen() bool {
+	path := tutorialMarkerPath()
+	if path == "" {
+		return true
+	}
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func markTutorialSeen() {
+	path := tutorialMarkerPath()
+	if path == "" {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return
+	}
+	os.WriteFile(path, nil, 0o644)
+}
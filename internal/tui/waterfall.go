@@ -0,0 +1,172 @@
+package tui
+
+import (
+	"fmt"
+	"github.com/jlgore/hartea/internal/har"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// WaterfallOrder is how the timeline arranges its bars.
+type WaterfallOrder int
+
+const (
+	OrderByStart WaterfallOrder = iota
+	OrderByDuration
+	OrderByEnd
+	GroupByDomain
+	GroupByType
+)
+
+var waterfallOrderNames = []string{"start time", "duration", "end time", "domain", "type"}
+
+func (o WaterfallOrder) String() string {
+	return waterfallOrderNames[o]
+}
+
+// next cycles through the orders.
+func (o WaterfallOrder) next() WaterfallOrder {
+	return (o + 1) % WaterfallOrder(len(waterfallOrderNames))
+}
+
+// waterfallRow is either a bar or, when event is nil, the subtotal heading
+// of a group.
+type waterfallRow struct {
+	event    *har.TimelineEvent
+	group    string
+	requests int
+	size     int
+	duration float64 // ms, summed over the group's requests
+}
+
+// arrangeWaterfall orders the timeline. Grouped orders put the largest groups
+// by total time first and keep requests within a group in start order.
+func arrangeWaterfall(timeline []har.TimelineEvent, order WaterfallOrder) []waterfallRow {
+	events := make([]har.TimelineEvent, len(timeline))
+	copy(events, timeline)
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].StartTime.Before(events[j].StartTime)
+	})
+
+	switch order {
+	case OrderByDuration:
+		sort.SliceStable(events, func(i, j int) bool { return events[i].Duration > events[j].Duration })
+	case OrderByEnd:
+		sort.SliceStable(events, func(i, j int) bool { return eventEnd(events[i]).Before(eventEnd(events[j])) })
+	case GroupByDomain, GroupByType:
+		return groupWaterfall(events, order)
+	}
+
+	rows := make([]waterfallRow, len(events))
+	for i := range events {
+		rows[i] = waterfallRow{event: &events[i]}
+	}
+	return rows
+}
+
+func groupWaterfall(events []har.TimelineEvent, order WaterfallOrder) []waterfallRow {
+	groups := make(map[string]*waterfallRow)
+	members := make(map[string][]int)
+	var names []string
+	for i, event := range events {
+		name := har.ResourceType(event.ContentType)
+		if order == GroupByDomain {
+			name = "unknown"
+			if parsed, err := url.Parse(event.URL); err == nil && parsed.Host != "" {
+				name = parsed.Hostname()
+			}
+		}
+		group, ok := groups[name]
+		if !ok {
+			group = &waterfallRow{group: name}
+			groups[name] = group
+			names = append(names, name)
+		}
+		group.requests++
+		group.size += event.Size
+		group.duration += event.Duration
+		members[name] = append(members[name], i)
+	}
+
+	sort.SliceStable(names, func(i, j int) bool {
+		return groups[names[i]].duration > groups[names[j]].duration
+	})
+
+	rows := make([]waterfallRow, 0, len(events)+len(names))
+	for _, name := range names {
+		rows = append(rows, *groups[name])
+		for _, i := range members[name] {
+			rows = append(rows, waterfallRow{event: &events[i]})
+		}
+	}
+	return rows
+}
+
+// heading renders the subtotal line of a group, with its size in units.
+func (row waterfallRow) heading(units har.SizeUnits) string {
+	return headerStyle.Render(fmt.Sprintf("▾ %s — %d requests, %s, %.1fms total", row.group, row.requests, units.FormatSize(row.size), row.duration))
+}
+
+func eventEnd(event har.TimelineEvent) time.Time {
+	return event.StartTime.Add(time.Duration(event.Duration * float64(time.Millisecond)))
+}
+
+// moveTimelineSelection moves the highlighted bar up or down the waterfall,
+// in its current order.
+func (m *Model) moveTimelineSelection(delta int) {
+	var order []int
+	position := 0
+	for _, row := range arrangeWaterfall(m.scopedTimeline(), m.waterfallOrder) {
+		if row.event == nil {
+			continue
+		}
+		if row.event.Index == m.timelineSelected {
+			position = len(order)
+		}
+		order = append(order, row.event.Index)
+	}
+	if len(order) > 0 {
+		m.timelineSelected = order[min(max(position+delta, 0), len(order)-1)]
+	}
+}
+
+// inspectTimelineSelection opens the highlighted bar in the detail view,
+// clearing the filter if it hides the request. Esc returns to the timeline.
+func (m *Model) inspectTimelineSelection() {
+	if m.timelineSelected >= len(m.harFiles[m.currentFile].Log.Entries) {
+		return
+	}
+	m.inspectEntry(m.timelineSelected, TimelineView)
+}
+
+// inspectEntry opens an entry in the detail view, clearing the filter when it
+// hides the entry, and returns to from on Esc.
+func (m *Model) inspectEntry(index int, from ViewMode) {
+	row := m.showEntry(index)
+	m.table.SetCursor(row)
+	m.selectedEntry = row
+	m.detailReturn = from
+	m.detailSelected = 0
+	m.currentView = DetailView
+}
+
+// renderSelectionStrip summarises the highlighted bar below the waterfall.
+func (tr *TimelineRenderer) renderSelectionStrip() []string {
+	entry := tr.selection
+	strip := []string{headerStyle.Render("▶ " + truncateURL(entry.Request.Method+" "+entry.Request.URL, tr.width-2))}
+	strip = append(strip, fmt.Sprintf("%d %s · %s · started %s · %.1fms · %s",
+		entry.Response.Status, entry.Response.StatusText, tr.units.FormatSize(entry.Response.Content.Size),
+		tr.times.format(entry.StartedDateTime), entry.Time, har.ResourceType(entry.Response.Content.MimeType)))
+
+	phases := timingPhases(*entry, har.Timings{})
+	var labels []string
+	for _, phase := range phases {
+		if phase.value > 0 {
+			labels = append(labels, foreground(phase.color).Render(phase.name)+fmt.Sprintf(" %dms", phase.value))
+		}
+	}
+	strip = append(strip, renderStackedTimingBar(phases, 30)+"  "+strings.Join(labels, "  "))
+	return strip
+}
@@ -0,0 +1,86 @@
+package tui
+
+import (
+	"fmt"
+	"github.com/jlgore/hartea/internal/har"
+	"slices"
+	"strings"
+)
+
+// whatIfRows caps the third-party sites listed in the what-if view.
+const whatIfRows = 20
+
+// moveWhatIfSelection moves the highlighted site of the what-if view.
+func (m *Model) moveWhatIfSelection(delta int) {
+	rows := min(len(m.analyzers[m.currentFile].DomainRemovals()), whatIfRows)
+	m.whatIfSelected = max(0, min(m.whatIfSelected+delta, rows-1))
+}
+
+// toggleWhatIfSelection adds the highlighted site to the combined estimate,
+// or takes it out again.
+func (m *Model) toggleWhatIfSelection() {
+	removals := m.analyzers[m.currentFile].DomainRemovals()
+	if m.whatIfSelected >= len(removals) {
+		return
+	}
+	domain := removals[m.whatIfSelected].Domains[0]
+	if index := slices.Index(m.whatIfRemoved, domain); index >= 0 {
+		m.whatIfRemoved = slices.Delete(m.whatIfRemoved, index, index+1)
+	} else {
+		m.whatIfRemoved = append(m.whatIfRemoved, domain)
+	}
+}
+
+// renderWhatIfView estimates what the page would save without each
+// third-party site, and without the sites marked with Enter together.
+func (m Model) renderWhatIfView() string {
+	content := []string{titleStyle.Render("What If: Removing Third-party Sites"), ""}
+
+	analyzer := m.analyzers[m.currentFile]
+	removals := analyzer.DomainRemovals()
+	if len(removals) == 0 {
+		content = append(content, "No third-party requests in this capture")
+		content = append(content, "", statusStyle.Render("Press Esc to go back"))
+		return strings.Join(content, "\n")
+	}
+
+	content = append(content, headerStyle.Render(fmt.Sprintf("  %-32s %10s %10s %12s %14s", "Site", "Requests", "Size", "Load Saved", "Critical Path")))
+	for i, removal := range removals[:min(len(removals), whatIfRows)] {
+		mark := " "
+		if slices.Contains(m.whatIfRemoved, removal.Domains[0]) {
+			mark = "✕"
+		}
+		line := mark + " " + whatIfLine(removal.Domains[0], removal, m.settings.SizeUnits)
+		if i == m.whatIfSelected {
+			line = timelineCursorStyle.Render(line)
+		}
+		content = append(content, line)
+	}
+	if len(removals) > whatIfRows {
+		content = append(content, statusStyle.Render(fmt.Sprintf("  ... and %d more sites", len(removals)-whatIfRows)))
+	}
+
+	if len(m.whatIfRemoved) > 0 {
+		combined := analyzer.SimulateDomainRemoval(m.whatIfRemoved...)
+		content = append(content, "", headerStyle.Render("Without all marked sites"))
+		content = append(content, "  "+whatIfLine(fmt.Sprintf("%d sites", len(m.whatIfRemoved)), combined, m.settings.SizeUnits))
+		if m.metrics.PageLoadTime > 0 {
+			content = append(content, fmt.Sprintf("  Estimated page load: %.0fms → %.0fms",
+				m.metrics.PageLoadTime, max(m.metrics.PageLoadTime-combined.LoadTimeSaved, 0)))
+		}
+	}
+
+	content = append(content, "")
+	content = append(content, statusStyle.Render("Requests include those the site's scripts started. Load time saved counts only time no other request was in flight."))
+	content = append(content, statusStyle.Render("↑/↓ select, Enter mark for a combined estimate, Esc to go back"))
+	return strings.Join(content, "\n")
+}
+
+// whatIfLine formats the savings of a removal under the what-if columns.
+func whatIfLine(label string, removal har.DomainRemoval, units har.SizeUnits) string {
+	requests := fmt.Sprintf("%d", removal.Requests)
+	if removal.Dependents > 0 {
+		requests += fmt.Sprintf("+%d", removal.Dependents)
+	}
+	return fmt.Sprintf("%s %10s %10s %10.0fms %12.0fms", fitWidth(label, 32), requests, units.FormatSize(int(removal.Bytes)), removal.LoadTimeSaved, removal.CriticalPathSaved)
+}
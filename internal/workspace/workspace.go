@@ -0,0 +1,167 @@
+package workspace
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Workspace holds per-project settings so analysts working on several sites
+// don't share one global configuration.
+type Workspace struct {
+	Name          string             `json:"-"`
+	Path          string             `json:"-"`
+	Labels        map[string]string  `json:"labels,omitempty"`
+	Baseline      string             `json:"baseline,omitempty"`
+	Filters       map[string]string  `json:"filters,omitempty"`
+	Budgets       map[string]float64 `json:"budgets,omitempty"`
+	VendorBudgets []string           `json:"vendorBudgets,omitempty"`
+	SLOs          []string           `json:"slos,omitempty"`
+	Noise         []string           `json:"noise,omitempty"`
+	Tags          []string           `json:"tags,omitempty"`
+	Secrets       map[string]string  `json:"secrets,omitempty"`
+	Blocklists    map[string]string  `json:"blocklists,omitempty"`
+	FirstParty    string             `json:"firstParty,omitempty"`
+	Exclude       []string           `json:"exclude,omitempty"`
+	Environment   string             `json:"environment,omitempty"`
+}
+
+var validName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// Dir returns the directory holding all workspaces.
+func Dir() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate config directory: %w", err)
+	}
+	return filepath.Join(configDir, "hartea", "workspaces"), nil
+}
+
+// Load reads the named workspace, returning an empty workspace if it does not exist yet.
+func Load(name string) (*Workspace, error) {
+	if !validName.MatchString(name) {
+		return nil, fmt.Errorf("invalid workspace name %q", name)
+	}
+
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+
+	ws := &Workspace{
+		Name: name,
+		Path: filepath.Join(dir, name+".json"),
+	}
+
+	data, err := os.ReadFile(ws.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return ws, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read workspace %s: %w", name, err)
+	}
+
+	if err := json.Unmarshal(data, ws); err != nil {
+		return nil, fmt.Errorf("failed to parse workspace %s: %w", name, err)
+	}
+
+	// Baselines shared through sync are stored relative to the hartea config directory
+	if ws.Baseline != "" && !filepath.IsAbs(ws.Baseline) {
+		shared := filepath.Join(filepath.Dir(dir), filepath.FromSlash(ws.Baseline))
+		if _, err := os.Stat(ws.Baseline); errors.Is(err, os.ErrNotExist) {
+			if _, err := os.Stat(shared); err == nil {
+				ws.Baseline = shared
+			}
+		}
+	}
+
+	return ws, nil
+}
+
+// Save writes the workspace back to disk.
+func (w *Workspace) Save() error {
+	if err := os.MkdirAll(filepath.Dir(w.Path), 0o755); err != nil {
+		return fmt.Errorf("failed to create workspace directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(w, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode workspace: %w", err)
+	}
+
+	if err := os.WriteFile(w.Path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write workspace %s: %w", w.Name, err)
+	}
+
+	return nil
+}
+
+// AddExclusion records an exclusion pattern and saves the workspace.
+func (w *Workspace) AddExclusion(pattern string) error {
+	for _, existing := range w.Exclude {
+		if existing == pattern {
+			return nil
+		}
+	}
+	w.Exclude = append(w.Exclude, pattern)
+	return w.Save()
+}
+
+// LabelFor returns the label mapped to a HAR file path. Keys may be exact
+// paths, base names, or glob patterns; when several patterns match, the most
+// specific (most literal characters) wins.
+func (w *Workspace) LabelFor(path string) (string, bool) {
+	if w == nil {
+		return "", false
+	}
+	if label, ok := w.Labels[path]; ok {
+		return label, true
+	}
+	base := filepath.Base(path)
+	if label, ok := w.Labels[base]; ok {
+		return label, true
+	}
+	for _, pattern := range labelPatterns(w.Labels) {
+		if matched, _ := filepath.Match(pattern, base); matched {
+			return w.Labels[pattern], true
+		}
+	}
+	return "", false
+}
+
+// labelPatterns orders the label keys most specific first, then
+// alphabetically, so overlapping globs resolve the same way every run.
+func labelPatterns(labels map[string]string) []string {
+	literal := func(pattern string) int {
+		return len(pattern) - strings.Count(pattern, "*") - strings.Count(pattern, "?")
+	}
+	patterns := make([]string, 0, len(labels))
+	for pattern := range labels {
+		patterns = append(patterns, pattern)
+	}
+	sort.Slice(patterns, func(i, j int) bool {
+		if literal(patterns[i]) != literal(patterns[j]) {
+			return literal(patterns[i]) > literal(patterns[j])
+		}
+		return patterns[i] < patterns[j]
+	})
+	return patterns
+}
+
+// IsBaseline reports whether path refers to the workspace's baseline capture.
+func (w *Workspace) IsBaseline(path string) bool {
+	if w == nil || w.Baseline == "" {
+		return false
+	}
+	if w.Baseline == path || w.Baseline == filepath.Base(path) {
+		return true
+	}
+	absBaseline, err1 := filepath.Abs(w.Baseline)
+	absPath, err2 := filepath.Abs(path)
+	return err1 == nil && err2 == nil && absBaseline == absPath
+}